@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/jedib0t/go-pretty/v6/table"
+)
+
+func main() {
+	up := flag.Bool("up", false, "Apply all pending migrations")
+	status := flag.Bool("status", false, "Show applied vs. pending migrations")
+	to := flag.Int("to", 0, "Apply pending migrations up to and including this version")
+	flag.Parse()
+
+	if !*up && !*status && *to == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *up && *to != 0 {
+		log.Fatal("use either -up or -to, not both")
+	}
+	if *to < 0 {
+		log.Fatal("-to must be > 0")
+	}
+
+	ctx := context.Background()
+	pool, err := db.Connect(ctx)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if *up || *to > 0 {
+		if err := db.ApplyMigrationsTo(ctx, pool, *to); err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+	}
+
+	migrations, err := db.MigrationStatus(ctx, pool)
+	if err != nil {
+		log.Fatalf("Unable to read migration status: %v", err)
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Version", "Filename", "State", "Applied At"})
+
+	pending := 0
+	for _, m := range migrations {
+		state, appliedAt := "pending", ""
+		if m.Applied {
+			state = "applied"
+			appliedAt = m.AppliedAt.Format("2006-01-02 15:04:05")
+		} else {
+			pending++
+		}
+		t.AppendRow(table.Row{m.Version, m.Filename, state, appliedAt})
+	}
+	t.AppendFooter(table.Row{"", "TOTAL", len(migrations), ""})
+	t.Render()
+
+	log.Printf("%d applied, %d pending", len(migrations)-pending, pending)
+}
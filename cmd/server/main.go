@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/david/grant-finder/internal/api"
 	"github.com/david/grant-finder/internal/db"
@@ -22,7 +23,10 @@ func main() {
 	}
 	defer pool.Close()
 
-	if err := db.ApplyMigrations(ctx, pool); err != nil {
+	// AUTO_MIGRATE=false leaves schema changes to cmd/migrate.
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("AUTO_MIGRATE")), "false") {
+		log.Println("AUTO_MIGRATE=false: skipping migrations at startup")
+	} else if err := db.ApplyMigrations(ctx, pool); err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
 
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/david/grant-finder/internal/ai"
+	"github.com/david/grant-finder/internal/db"
+	"github.com/david/grant-finder/internal/ingest"
+)
+
+func main() {
+	batchSize := flag.Int("batch-size", 100, "Rows embedded per Ollama call")
+	afterID := flag.String("after-id", "", "Resume after this opportunity id (printed by a previous run)")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	pool, err := db.Connect(ctx)
+	if err != nil {
+		log.Fatalf("db connect failed: %v", err)
+	}
+	defer pool.Close()
+
+	ollamaHost := os.Getenv("OLLAMA_HOST")
+	if ollamaHost == "" {
+		ollamaHost = "http://localhost:11434"
+	}
+	pipeline := ingest.NewPipeline(pool, nil, nil, ai.NewOllamaClient(ollamaHost, ai.ModelConfigFromEnv()))
+
+	stats, err := pipeline.BackfillEmbeddings(ctx, *batchSize, *afterID)
+	log.Printf("scanned=%d updated=%d failed=%d batches=%d last_id=%s",
+		stats.ItemsScanned, stats.ItemsUpdated, stats.ItemsFailed, stats.Batches, stats.LastID)
+	if err != nil {
+		log.Fatalf("backfill stopped: %v (resume with -after-id=%s)", err, stats.LastID)
+	}
+}
@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -10,7 +13,36 @@ import (
 	"github.com/jedib0t/go-pretty/v6/table"
 )
 
+type runRow struct {
+	RunID         string     `json:"run_id"`
+	SourceID      string     `json:"source_id"`
+	Status        string     `json:"status"`
+	ItemsFound    int        `json:"items_found"`
+	ItemsSaved    int        `json:"items_saved"`
+	ItemsInserted int        `json:"items_inserted"`
+	ItemsUpdated  int        `json:"items_updated"`
+	Errors        int        `json:"errors"`
+	StartedAt     time.Time  `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
 func main() {
+	source := flag.String("source", "", "Only show runs for this source ID")
+	status := flag.String("status", "", "Only show runs with this status (running, completed, failed)")
+	limit := flag.Int("limit", 10, "Maximum number of runs to show")
+	since := flag.Duration("since", 0, "Only show runs started within this window (e.g. 24h)")
+	asJSON := flag.Bool("json", false, "Print runs as JSON instead of a table")
+	flag.Parse()
+
+	switch *status {
+	case "", "running", "completed", "failed":
+	default:
+		log.Fatalf("invalid -status %q: use running, completed or failed", *status)
+	}
+	if *limit <= 0 {
+		log.Fatal("-limit must be > 0")
+	}
+
 	ctx := context.Background()
 	pool, err := db.Connect(ctx)
 	if err != nil {
@@ -18,33 +50,66 @@ func main() {
 	}
 	defer pool.Close()
 
-	rows, err := pool.Query(ctx, "SELECT run_id, source_id, status, items_found, items_saved, errors, started_at, completed_at FROM ingest_runs ORDER BY started_at DESC LIMIT 10")
+	where := "WHERE 1=1"
+	var args []interface{}
+	argIdx := 1
+	if *source != "" {
+		where += fmt.Sprintf(" AND source_id = $%d", argIdx)
+		args = append(args, *source)
+		argIdx++
+	}
+	if *status != "" {
+		where += fmt.Sprintf(" AND status = $%d", argIdx)
+		args = append(args, *status)
+		argIdx++
+	}
+	if *since > 0 {
+		where += fmt.Sprintf(" AND started_at >= $%d", argIdx)
+		args = append(args, time.Now().Add(-*since))
+		argIdx++
+	}
+	query := fmt.Sprintf("SELECT run_id, source_id, status, items_found, items_saved, items_inserted, items_updated, errors, started_at, completed_at FROM ingest_runs %s ORDER BY started_at DESC LIMIT $%d", where, argIdx)
+	args = append(args, *limit)
+
+	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer rows.Close()
 
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(table.Row{"Source", "Status", "Found", "Saved", "Errors", "Duration", "Started At"})
-
+	runs := []runRow{}
 	for rows.Next() {
-		var runID, sourceID, status string
-		var found, saved, errs int
-		var startedAt time.Time
-		var completedAt *time.Time
-
-		if err := rows.Scan(&runID, &sourceID, &status, &found, &saved, &errs, &startedAt, &completedAt); err != nil {
+		var r runRow
+		if err := rows.Scan(&r.RunID, &r.SourceID, &r.Status, &r.ItemsFound, &r.ItemsSaved, &r.ItemsInserted, &r.ItemsUpdated, &r.Errors, &r.StartedAt, &r.CompletedAt); err != nil {
 			log.Printf("Scan error: %v", err)
 			continue
 		}
+		runs = append(runs, r)
+	}
+	if err := rows.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(runs); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	t := table.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(table.Row{"Source", "Status", "Found", "Saved", "New", "Updated", "Errors", "Duration", "Started At"})
 
+	for _, r := range runs {
 		duration := "Running..."
-		if completedAt != nil {
-			duration = completedAt.Sub(startedAt).Round(time.Second).String()
+		if r.CompletedAt != nil {
+			duration = r.CompletedAt.Sub(r.StartedAt).Round(time.Second).String()
 		}
 
-		t.AppendRow(table.Row{sourceID, status, found, saved, errs, duration, startedAt.Format("15:04:05")})
+		t.AppendRow(table.Row{r.SourceID, r.Status, r.ItemsFound, r.ItemsSaved, r.ItemsInserted, r.ItemsUpdated, r.Errors, duration, r.StartedAt.Format("15:04:05")})
 	}
 	t.Render()
 }
@@ -1,10 +1,12 @@
 package main
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
@@ -15,7 +17,6 @@ import (
 )
 
 type enrichResponse struct {
-	Message              string         `json:"message"`
 	Domain               string         `json:"domain"`
 	OnlyMissingDeadlines bool           `json:"only_missing_deadlines"`
 	BatchSizeUsed        int            `json:"batch_size_used"`
@@ -53,11 +54,19 @@ func main() {
 	batchSize := flag.Int("batch-size", 200, "Batch size per request")
 	maxItems := flag.Int("max-items", 1000, "Max items per domain")
 	confidenceThreshold := flag.Float64("confidence-threshold", 0.6, "Confidence threshold [0,1]")
-	rateLimitMs := flag.Int("rate-limit-ms", 1000, "Delay between domain calls in milliseconds")
+	rateLimitMs := flag.Int("rate-limit-ms", 1000, "Minimum spacing between calls in milliseconds")
 	timeoutSec := flag.Int("timeout-sec", 120, "HTTP timeout in seconds")
+	busyWaitSec := flag.Int("busy-wait-sec", 600, "How long to wait for another background job to finish before giving up on a domain")
 	dryRun := flag.Bool("dry-run", false, "Print planned calls only; do not execute")
+	output := flag.String("output", "table", "Report format: table, json or csv")
 	flag.Parse()
 
+	switch *output {
+	case "table", "json", "csv":
+	default:
+		exitErr(fmt.Errorf("invalid -output %q: use table, json or csv", *output))
+	}
+
 	adminSecret := strings.TrimSpace(*adminSecretFlag)
 	if adminSecret == "" {
 		adminSecret = strings.TrimSpace(os.Getenv("ADMIN_SECRET"))
@@ -83,42 +92,74 @@ func main() {
 	if *timeoutSec <= 0 {
 		exitErr(errors.New("timeout-sec must be > 0"))
 	}
+	if *busyWaitSec < 0 {
+		exitErr(errors.New("busy-wait-sec must be >= 0"))
+	}
 
 	client := &http.Client{Timeout: time.Duration(*timeoutSec) * time.Second}
-	metrics := make([]domainMetric, 0, len(domains))
+	rateLimit := time.Duration(*rateLimitMs) * time.Millisecond
+	busyWait := time.Duration(*busyWaitSec) * time.Second
 
-	for idx, domain := range domains {
-		metric := domainMetric{Domain: domain, DryRun: *dryRun}
-		start := time.Now()
+	// Domains run one at a time: the server has a single background job
+	// slot, so parallel enrich calls would only queue behind each other.
+	metrics := make([]domainMetric, 0, len(domains))
+	var lastCall time.Time
+	for _, domain := range domains {
+		// Honor -rate-limit-ms as minimum spacing after the previous call.
+		if !lastCall.IsZero() && rateLimit > 0 {
+			if wait := rateLimit - time.Since(lastCall); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
 
 		reqURL := buildURL(*baseURL, domain, *onlyMissingDeadlines, *batchSize, *maxItems, *confidenceThreshold)
-		if *dryRun {
-			metric.Duration = time.Since(start)
+		metrics = append(metrics, enrichDomain(client, reqURL, domain, adminSecret, busyWait, *dryRun, *output))
+		lastCall = time.Now()
+	}
+
+	switch *output {
+	case "json":
+		err = writeJSONReport(os.Stdout, metrics)
+	case "csv":
+		err = writeCSVReport(os.Stdout, metrics)
+	default:
+		printReport(metrics)
+	}
+	if err != nil {
+		exitErr(err)
+	}
+}
+
+// enrichDomain runs (or, with dryRun, prints) the enrich call for one domain.
+func enrichDomain(client *http.Client, reqURL, domain, adminSecret string, busyWait time.Duration, dryRun bool, output string) domainMetric {
+	metric := domainMetric{Domain: domain, DryRun: dryRun}
+	start := time.Now()
+
+	if dryRun {
+		metric.Duration = time.Since(start)
+		// Keep stdout clean for structured output.
+		if output == "table" {
 			fmt.Printf("[DRY-RUN] %s\n", reqURL)
-			metrics = append(metrics, metric)
 		} else {
-			response, statusCode, callErr := callEnrich(client, reqURL, adminSecret)
-			metric.Duration = time.Since(start)
-			metric.HTTPStatus = statusCode
-			if callErr != nil {
-				metric.Error = callErr.Error()
-			} else {
-				metric.ItemsScanned = response.ItemsScanned
-				metric.ItemsUpdated = response.ItemsUpdated
-				metric.PDFsParsed = response.PDFsParsed
-				metric.DeadlinesAdded = response.DeadlinesAdded
-				metric.StatusChanges = response.StatusChanges
-				metric.StatusUpdated = response.StatusUpdated
-			}
-			metrics = append(metrics, metric)
-		}
-
-		if idx < len(domains)-1 && *rateLimitMs > 0 {
-			time.Sleep(time.Duration(*rateLimitMs) * time.Millisecond)
+			fmt.Fprintf(os.Stderr, "[DRY-RUN] %s\n", reqURL)
 		}
+		return metric
 	}
 
-	printReport(metrics)
+	response, statusCode, callErr := callEnrich(client, reqURL, adminSecret, busyWait)
+	metric.Duration = time.Since(start)
+	metric.HTTPStatus = statusCode
+	if callErr != nil {
+		metric.Error = callErr.Error()
+		return metric
+	}
+	metric.ItemsScanned = response.ItemsScanned
+	metric.ItemsUpdated = response.ItemsUpdated
+	metric.PDFsParsed = response.PDFsParsed
+	metric.DeadlinesAdded = response.DeadlinesAdded
+	metric.StatusChanges = response.StatusChanges
+	metric.StatusUpdated = response.StatusUpdated
+	return metric
 }
 
 func loadDomains(csv, filePath string) ([]string, error) {
@@ -165,56 +206,182 @@ func buildURL(baseURL, domain string, onlyMissing bool, batchSize, maxItems int,
 	return u.String()
 }
 
-func callEnrich(client *http.Client, reqURL, adminSecret string) (*enrichResponse, int, error) {
-	req, err := http.NewRequest(http.MethodPost, reqURL, nil)
+// jobPollInterval spaces the GET /admin/job/:id polls while an enrich job
+// runs, and the retries while another background job holds the server.
+const jobPollInterval = 2 * time.Second
+
+// jobResponse is the 202/409 answer of a job-starting endpoint and the body of
+// GET /admin/job/:id.
+type jobResponse struct {
+	Message string          `json:"message"`
+	JobID   string          `json:"job_id"`
+	Poll    string          `json:"poll"`
+	Status  string          `json:"status"`
+	Error   string          `json:"error"`
+	Result  *enrichResponse `json:"result"`
+}
+
+// callEnrich starts an enrich job and polls it until it finishes. The server
+// runs one background job at a time, so a 409 is retried after a pause until
+// busyWait has passed.
+func callEnrich(client *http.Client, reqURL, adminSecret string, busyWait time.Duration) (*enrichResponse, int, error) {
+	var started jobResponse
+	busyUntil := time.Now().Add(busyWait)
+	waiting := false
+	for {
+		statusCode, err := doJSON(client, http.MethodPost, reqURL, adminSecret, &started)
+		if err != nil {
+			return nil, statusCode, err
+		}
+		if statusCode == http.StatusConflict {
+			if time.Now().Add(jobPollInterval).After(busyUntil) {
+				return nil, statusCode, fmt.Errorf("server still busy with job %s after %s", started.JobID, busyWait)
+			}
+			if !waiting {
+				fmt.Fprintf(os.Stderr, "server busy with job %s; waiting up to %s\n", started.JobID, busyWait)
+				waiting = true
+			}
+			time.Sleep(jobPollInterval)
+			continue
+		}
+		if statusCode != http.StatusAccepted {
+			return nil, statusCode, httpError(statusCode, started)
+		}
+		break
+	}
+
+	base, err := url.Parse(reqURL)
 	if err != nil {
 		return nil, 0, err
 	}
+	pollPath, err := url.Parse(started.Poll)
+	if err != nil {
+		return nil, 0, fmt.Errorf("bad poll url %q: %w", started.Poll, err)
+	}
+	pollURL := base.ResolveReference(pollPath).String()
+
+	for {
+		time.Sleep(jobPollInterval)
+		var job jobResponse
+		statusCode, err := doJSON(client, http.MethodGet, pollURL, adminSecret, &job)
+		if err != nil {
+			return nil, statusCode, err
+		}
+		if statusCode != http.StatusOK {
+			return nil, statusCode, httpError(statusCode, job)
+		}
+		switch job.Status {
+		case "running":
+			continue
+		case "completed":
+			if job.Result == nil {
+				return nil, statusCode, fmt.Errorf("job %s completed without a result", job.JobID)
+			}
+			return job.Result, statusCode, nil
+		default:
+			return job.Result, statusCode, fmt.Errorf("job %s %s: %s", started.JobID, job.Status, job.Error)
+		}
+	}
+}
+
+func doJSON(client *http.Client, method, reqURL, adminSecret string, out any) (int, error) {
+	req, err := http.NewRequest(method, reqURL, nil)
+	if err != nil {
+		return 0, err
+	}
 	req.Header.Set("X-Admin-Secret", adminSecret)
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, 0, err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-	var payload enrichResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
-		return nil, resp.StatusCode, fmt.Errorf("decode failed: %w", err)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return resp.StatusCode, fmt.Errorf("decode failed: %w", err)
 	}
+	return resp.StatusCode, nil
+}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		if payload.Message == "" {
-			return &payload, resp.StatusCode, fmt.Errorf("http %d", resp.StatusCode)
-		}
-		return &payload, resp.StatusCode, fmt.Errorf("http %d: %s", resp.StatusCode, payload.Message)
+func httpError(statusCode int, payload jobResponse) error {
+	msg := payload.Error
+	if msg == "" {
+		msg = payload.Message
+	}
+	if msg == "" {
+		return fmt.Errorf("http %d", statusCode)
 	}
+	return fmt.Errorf("http %d: %s", statusCode, msg)
+}
 
-	return &payload, resp.StatusCode, nil
+// reportTotals aggregates domainMetrics for the report footer.
+type reportTotals struct {
+	Domains        int     `json:"domains"`
+	ItemsScanned   int     `json:"items_scanned"`
+	ItemsUpdated   int     `json:"items_updated"`
+	PDFsParsed     int     `json:"pdfs_parsed"`
+	DeadlinesAdded int     `json:"deadlines_added"`
+	StatusChanges  int     `json:"status_changes"`
+	StatusUpdated  int     `json:"status_updated"`
+	DurationSec    float64 `json:"duration_sec"`
+	Errors         int     `json:"errors"`
 }
 
-func printReport(metrics []domainMetric) {
-	fmt.Println("\n=== Enrichment Batch Report ===")
-	fmt.Printf("%-28s %-6s %-6s %-8s %-8s %-8s %-10s %-8s %-8s %s\n",
-		"domain", "dry", "http", "scanned", "updated", "pdfs", "deadlines", "changes", "sec", "error")
+// reportRow is the structured (json/csv) form of a domainMetric. Durations are
+// seconds as float, matching the "sec" column of the table report.
+type reportRow struct {
+	Domain         string  `json:"domain"`
+	DryRun         bool    `json:"dry_run"`
+	HTTPStatus     int     `json:"http_status"`
+	DurationSec    float64 `json:"duration_sec"`
+	ItemsScanned   int     `json:"items_scanned"`
+	ItemsUpdated   int     `json:"items_updated"`
+	PDFsParsed     int     `json:"pdfs_parsed"`
+	DeadlinesAdded int     `json:"deadlines_added"`
+	StatusChanges  int     `json:"status_changes"`
+	StatusUpdated  int     `json:"status_updated"`
+	Error          string  `json:"error,omitempty"`
+}
 
-	totalScanned := 0
-	totalUpdated := 0
-	totalPDFs := 0
-	totalDeadlines := 0
-	totalChanges := 0
-	errors := 0
+func toReportRow(m domainMetric) reportRow {
+	return reportRow{
+		Domain:         m.Domain,
+		DryRun:         m.DryRun,
+		HTTPStatus:     m.HTTPStatus,
+		DurationSec:    m.Duration.Seconds(),
+		ItemsScanned:   m.ItemsScanned,
+		ItemsUpdated:   m.ItemsUpdated,
+		PDFsParsed:     m.PDFsParsed,
+		DeadlinesAdded: m.DeadlinesAdded,
+		StatusChanges:  m.StatusChanges,
+		StatusUpdated:  m.StatusUpdated,
+		Error:          m.Error,
+	}
+}
 
+func summarize(metrics []domainMetric) reportTotals {
+	totals := reportTotals{Domains: len(metrics)}
 	for _, m := range metrics {
 		if m.Error != "" {
-			errors++
+			totals.Errors++
 		}
-		totalScanned += m.ItemsScanned
-		totalUpdated += m.ItemsUpdated
-		totalPDFs += m.PDFsParsed
-		totalDeadlines += m.DeadlinesAdded
-		totalChanges += m.StatusChanges
+		totals.ItemsScanned += m.ItemsScanned
+		totals.ItemsUpdated += m.ItemsUpdated
+		totals.PDFsParsed += m.PDFsParsed
+		totals.DeadlinesAdded += m.DeadlinesAdded
+		totals.StatusChanges += m.StatusChanges
+		totals.StatusUpdated += m.StatusUpdated
+		totals.DurationSec += m.Duration.Seconds()
+	}
+	return totals
+}
 
+func printReport(metrics []domainMetric) {
+	fmt.Println("\n=== Enrichment Batch Report ===")
+	fmt.Printf("%-28s %-6s %-6s %-8s %-8s %-8s %-10s %-8s %-8s %s\n",
+		"domain", "dry", "http", "scanned", "updated", "pdfs", "deadlines", "changes", "sec", "error")
+
+	for _, m := range metrics {
 		fmt.Printf("%-28s %-6t %-6d %-8d %-8d %-8d %-10d %-8d %-8.2f %s\n",
 			m.Domain,
 			m.DryRun,
@@ -229,8 +396,73 @@ func printReport(metrics []domainMetric) {
 		)
 	}
 
+	totals := summarize(metrics)
 	fmt.Printf("\nTotals: scanned=%d updated=%d pdfs=%d deadlines_added=%d status_changes=%d errors=%d\n",
-		totalScanned, totalUpdated, totalPDFs, totalDeadlines, totalChanges, errors)
+		totals.ItemsScanned, totals.ItemsUpdated, totals.PDFsParsed, totals.DeadlinesAdded, totals.StatusChanges, totals.Errors)
+}
+
+func writeJSONReport(w io.Writer, metrics []domainMetric) error {
+	rows := make([]reportRow, 0, len(metrics))
+	for _, m := range metrics {
+		rows = append(rows, toReportRow(m))
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Domains []reportRow  `json:"domains"`
+		Totals  reportTotals `json:"totals"`
+	}{Domains: rows, Totals: summarize(metrics)})
+}
+
+// writeCSVReport writes one row per domain followed by a "TOTAL" row whose
+// error column holds the error count.
+func writeCSVReport(w io.Writer, metrics []domainMetric) error {
+	cw := csv.NewWriter(w)
+	header := []string{"domain", "dry_run", "http_status", "duration_sec", "items_scanned", "items_updated",
+		"pdfs_parsed", "deadlines_added", "status_changes", "status_updated", "error"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, m := range metrics {
+		r := toReportRow(m)
+		if err := cw.Write([]string{
+			r.Domain,
+			strconv.FormatBool(r.DryRun),
+			strconv.Itoa(r.HTTPStatus),
+			strconv.FormatFloat(r.DurationSec, 'f', 3, 64),
+			strconv.Itoa(r.ItemsScanned),
+			strconv.Itoa(r.ItemsUpdated),
+			strconv.Itoa(r.PDFsParsed),
+			strconv.Itoa(r.DeadlinesAdded),
+			strconv.Itoa(r.StatusChanges),
+			strconv.Itoa(r.StatusUpdated),
+			r.Error,
+		}); err != nil {
+			return err
+		}
+	}
+
+	t := summarize(metrics)
+	if err := cw.Write([]string{
+		"TOTAL",
+		"",
+		"",
+		strconv.FormatFloat(t.DurationSec, 'f', 3, 64),
+		strconv.Itoa(t.ItemsScanned),
+		strconv.Itoa(t.ItemsUpdated),
+		strconv.Itoa(t.PDFsParsed),
+		strconv.Itoa(t.DeadlinesAdded),
+		strconv.Itoa(t.StatusChanges),
+		strconv.Itoa(t.StatusUpdated),
+		strconv.Itoa(t.Errors),
+	}); err != nil {
+		return err
+	}
+
+	cw.Flush()
+	return cw.Error()
 }
 
 func exitErr(err error) {
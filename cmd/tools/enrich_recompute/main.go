@@ -42,6 +42,7 @@ func main() {
 	threshold := flag.Float64("confidence-threshold", 0.6, "status confidence threshold")
 	recomputeBatch := flag.Int("recompute-batch", 500, "recompute status batch size")
 	perDomainTimeoutSec := flag.Int("domain-timeout-sec", 180, "timeout per domain enrichment")
+	incremental := flag.Bool("incremental", false, "recompute only rows changed since the last successful recompute")
 	flag.Parse()
 
 	ctx := context.Background()
@@ -64,7 +65,7 @@ func main() {
 			continue
 		}
 		domainCtx, cancel := context.WithTimeout(ctx, time.Duration(*perDomainTimeoutSec)*time.Second)
-		stats, err := pipeline.EnrichOpportunities(domainCtx, domain, *onlyMissing, *batchSize, *maxItems, *threshold)
+		stats, err := pipeline.EnrichOpportunities(domainCtx, domain, *onlyMissing, *batchSize, *maxItems, *threshold, nil)
 		cancel()
 
 		domainErr := ""
@@ -86,7 +87,19 @@ func main() {
 		})
 	}
 
-	statusCounts, updated, err := pipeline.RecomputeStatuses(ctx, *recomputeBatch)
+	var since *time.Time
+	if *incremental {
+		if since, err = pipeline.LastRecomputeAt(ctx); err != nil {
+			log.Fatalf("reading recompute watermark failed: %v", err)
+		}
+	}
+	var statusCounts map[string]int
+	var updated int
+	if since != nil {
+		statusCounts, updated, err = pipeline.RecomputeStatusesSince(ctx, *since, *recomputeBatch, nil)
+	} else {
+		statusCounts, updated, err = pipeline.RecomputeStatuses(ctx, *recomputeBatch, nil)
+	}
 	if err != nil {
 		log.Fatalf("recompute failed: %v", err)
 	}
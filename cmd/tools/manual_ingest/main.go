@@ -40,5 +40,5 @@ func main() {
 		log.Fatalf("Ingestion failed: %v", err)
 	}
 
-	log.Printf("Ingestion finished for %s. Found: %d, Saved: %d, Errors: %d", *sourceID, stats.TotalFound, stats.TotalSaved, stats.Errors)
+	log.Printf("Ingestion finished for %s. Found: %d, Saved: %d (%d new, %d updated), Skipped: %d %v, Errors: %d", *sourceID, stats.TotalFound, stats.TotalSaved, stats.Inserted, stats.Updated, stats.Skipped, stats.SkipReasons, stats.Errors)
 }
@@ -1,21 +1,38 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 )
 
 func main() {
-	adminSecret := strings.TrimSpace(os.Getenv("ADMIN_SECRET"))
+	baseURL := flag.String("base-url", "http://localhost:8081", "API base URL")
+	source := flag.String("source", "prociencia_concursos_abiertos", "Registry source ID to ingest")
+	adminSecretFlag := flag.String("admin-secret", "", "Admin secret (or use ADMIN_SECRET env)")
+	flag.Parse()
+
+	adminSecret := strings.TrimSpace(*adminSecretFlag)
+	if adminSecret == "" {
+		adminSecret = strings.TrimSpace(os.Getenv("ADMIN_SECRET"))
+	}
 	if adminSecret == "" {
-		fmt.Println("Missing ADMIN_SECRET environment variable")
+		fmt.Println("Missing admin secret: use -admin-secret or ADMIN_SECRET env")
+		os.Exit(1)
+	}
+	if strings.TrimSpace(*source) == "" {
+		fmt.Println("Missing -source")
 		os.Exit(1)
 	}
 
-	url := "http://localhost:8081/api/v1/ingest/source/prociencia_concursos_abiertos"
-	req, err := http.NewRequest("POST", url, nil)
+	reqURL := strings.TrimRight(*baseURL, "/") + "/api/v1/ingest/source/" + url.PathEscape(*source)
+	req, err := http.NewRequest("POST", reqURL, nil)
 	if err != nil {
 		fmt.Printf("Error creating request: %v\n", err)
 		os.Exit(1)
@@ -32,6 +49,19 @@ func main() {
 	defer resp.Body.Close()
 
 	fmt.Printf("Response Status: %s\n", resp.Status)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		fmt.Printf("Error reading response: %v\n", err)
+		os.Exit(1)
+	}
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, body, "", "  "); err == nil {
+		fmt.Println(pretty.String())
+	} else if len(body) > 0 {
+		fmt.Println(string(body))
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		os.Exit(1)
 	}
@@ -15,6 +15,7 @@ export interface Opportunity {
     agency_name: string;
     agency_code: string;
     funder_type: string;
+    funder_type_label?: string; // Source's own wording; funder_type is the canonical value
     amount_min: number;
     amount_max: number;
     currency: string;
@@ -39,8 +40,22 @@ export interface Opportunity {
     cfda_list?: string[];
     close_date_raw?: string; // Original deadline text
     created_at: string;
+    explain?: RankExplanation; // Only when the search asked for explain
 }
 
+export interface RankExplanation {
+    vector_similarity: number | null;
+    text_rank: number | null;
+    matched_filters: string[];
+}
+
+export interface SavedNote {
+    tags: string[]; // Lowercased, at most 10 of up to 32 characters
+    note: string;
+}
+
+export type SavedOpportunity = Opportunity & SavedNote;
+
 export interface ListResult {
     opportunities: Opportunity[];
     total: number;
@@ -50,6 +65,7 @@ export interface ListResult {
 
 export interface SearchFilters {
     q?: string;
+    search_mode?: 'boolean'; // q supports "phrases", OR and -term
     source?: string;
     region?: string;
     funder_type?: string;
@@ -64,6 +80,8 @@ export interface SearchFilters {
     eligibility?: string[];
     sort?: string;
     status?: string;
+    hide_dead_links?: boolean; // Skip calls whose link kept failing checks
+    explain?: boolean; // Attach ranking scores and matched filters to each result
 }
 
 export interface Aggregation {
@@ -77,6 +95,10 @@ export interface AggregationResult {
     countries: Aggregation[];
 }
 
+export interface SearchResult extends ListResult {
+    aggregations: AggregationResult;
+}
+
 @Injectable({
     providedIn: 'root'
 })
@@ -86,8 +108,20 @@ export class OpportunityService {
     constructor(private http: HttpClient) { }
 
     search(filters: SearchFilters = {}): Observable<ListResult> {
+        return this.http.get<ListResult>(`${this.apiUrl}/opportunities`, { params: this.listParams(filters) });
+    }
+
+    // One page plus facet counts for the same filters, from one DB snapshot
+    searchWithFacets(filters: SearchFilters = {}): Observable<SearchResult> {
+        return this.http.get<SearchResult>(`${this.apiUrl}/search`, { params: this.listParams(filters) });
+    }
+
+    private listParams(filters: SearchFilters): HttpParams {
         let params = new HttpParams();
         if (filters.q) params = params.set('q', filters.q);
+        if (filters.search_mode) params = params.set('search_mode', filters.search_mode);
+        if (filters.hide_dead_links) params = params.set('hide_dead_links', 'true');
+        if (filters.explain) params = params.set('explain', 'true');
         if (filters.source) params = params.set('source', filters.source);
         if (filters.region) params = params.set('region', filters.region);
         if (filters.funder_type) params = params.set('funder_type', filters.funder_type);
@@ -107,14 +141,19 @@ export class OpportunityService {
         if (filters.eligibility) {
             filters.eligibility.forEach(e => params = params.append('eligibility', e));
         }
-
-        return this.http.get<ListResult>(`${this.apiUrl}/opportunities`, { params });
+        return params;
     }
 
     getOpportunity(id: string): Observable<Opportunity> {
         return this.http.get<Opportunity>(`${this.apiUrl}/opportunities/${id}`);
     }
 
+    // Open opportunities added in the last `days` days, newest first
+    getRecentOpportunities(days = 7, limit = 20): Observable<ListResult> {
+        const params = new HttpParams().set('days', days).set('limit', limit);
+        return this.http.get<ListResult>(`${this.apiUrl}/opportunities/recent`, { params });
+    }
+
     getSources(): Observable<string[]> {
         return this.http.get<string[]>(`${this.apiUrl}/sources`);
     }
@@ -125,6 +164,8 @@ export class OpportunityService {
 
     getAggregations(filters: SearchFilters = {}): Observable<AggregationResult> {
         let params = new HttpParams();
+        if (filters.q) params = params.set('q', filters.q);
+        if (filters.search_mode) params = params.set('search_mode', filters.search_mode);
         if (filters.status) params = params.set('status', filters.status);
         if (filters.region) params = params.set('region', filters.region);
         if (filters.funder_type) params = params.set('funder_type', filters.funder_type);
@@ -142,7 +183,13 @@ export class OpportunityService {
         return this.http.delete<void>(`${this.apiUrl}/saved/${id}`);
     }
 
-    getSavedOpportunities(): Observable<Opportunity[]> {
-        return this.http.get<Opportunity[]>(`${this.apiUrl}/saved`);
+    getSavedOpportunities(tag?: string): Observable<SavedOpportunity[]> {
+        let params = new HttpParams();
+        if (tag) params = params.set('tag', tag);
+        return this.http.get<SavedOpportunity[]>(`${this.apiUrl}/saved`, { params });
+    }
+
+    setSavedNote(id: string, tags: string[], note: string): Observable<SavedNote> {
+        return this.http.put<SavedNote>(`${this.apiUrl}/saved/${id}/note`, { tags, note });
     }
 }
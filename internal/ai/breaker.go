@@ -0,0 +1,177 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Circuit breaker defaults; override with AI_BREAKER_THRESHOLD and
+// AI_BREAKER_COOLDOWN.
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrUnavailable is returned without contacting Ollama while the circuit
+// breaker is open, i.e. after repeated failures to reach it.
+var ErrUnavailable = errors.New("ollama unavailable: circuit breaker open")
+
+// Health is a snapshot of the client's circuit breaker for readiness checks.
+type Health struct {
+	Status              string     `json:"status"` // "ok", "degraded" (recent failures) or "unavailable" (circuit open)
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// circuitBreaker trips after threshold consecutive failures and rejects calls
+// until the cooldown passes. It is then half-open: a single probe is let
+// through and everyone else is still rejected until that probe resolves. If
+// the probe fails the circuit opens again straight away, so a dead backend
+// costs one request per cooldown rather than one per caller.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	failures  int
+	openUntil time.Time
+	lastErr   string
+	probing   bool // half-open and the probe is in flight
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// breakerFromEnv reads AI_BREAKER_THRESHOLD (failures) and
+// AI_BREAKER_COOLDOWN ("30s"); invalid values fall back to the defaults.
+func breakerFromEnv() *circuitBreaker {
+	threshold := DefaultBreakerThreshold
+	if v, err := strconv.Atoi(os.Getenv("AI_BREAKER_THRESHOLD")); err == nil && v > 0 {
+		threshold = v
+	}
+	cooldown := DefaultBreakerCooldown
+	if d, err := time.ParseDuration(os.Getenv("AI_BREAKER_COOLDOWN")); err == nil && d > 0 {
+		cooldown = d
+	}
+	return newCircuitBreaker(threshold, cooldown)
+}
+
+// allow admits a call, claiming the probe when the circuit is half-open.
+// Every admitted call must end in record or abandon.
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.admits() {
+		return ErrUnavailable
+	}
+	if b.failures >= b.threshold {
+		b.probing = true
+	}
+	return nil
+}
+
+// available reports whether allow would admit a call, without claiming the
+// probe.
+func (b *circuitBreaker) available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.admits()
+}
+
+func (b *circuitBreaker) admits() bool {
+	if b.now().Before(b.openUntil) {
+		return false
+	}
+	return b.failures < b.threshold || !b.probing
+}
+
+// abandon ends an admitted call that neither succeeded nor failed, e.g. one
+// its caller cancelled, freeing the probe for the next caller.
+func (b *circuitBreaker) abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		b.openUntil = time.Time{}
+		b.lastErr = ""
+		return
+	}
+	b.failures++
+	b.lastErr = err.Error()
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+func (b *circuitBreaker) health() Health {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := Health{Status: "ok", ConsecutiveFailures: b.failures, LastError: b.lastErr}
+	if now := b.now(); now.Before(b.openUntil) {
+		until := b.openUntil
+		h.Status = "unavailable"
+		h.OpenUntil = &until
+	} else if b.failures > 0 {
+		h.Status = "degraded"
+	}
+	return h
+}
+
+// Available reports whether calls are currently let through to Ollama.
+// Callers with a cheaper fallback (keyword search, no embedding) check it
+// to skip the call and its log line while the breaker is open.
+func (c *OllamaClient) Available() bool {
+	if c == nil {
+		return false
+	}
+	return c.breaker == nil || c.breaker.available()
+}
+
+// Health reports the circuit breaker state.
+func (c *OllamaClient) Health() Health {
+	if c == nil || c.breaker == nil {
+		return Health{Status: "ok"}
+	}
+	return c.breaker.health()
+}
+
+// do sends req through the circuit breaker. Transport errors, timeouts and
+// 5xx responses count as failures; a cancelled caller context doesn't.
+func (c *OllamaClient) do(req *http.Request) (*http.Response, error) {
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			return nil, err
+		}
+	}
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if c.breaker != nil {
+		switch {
+		case err != nil && errors.Is(err, context.Canceled):
+			c.breaker.abandon()
+		case err != nil:
+			c.breaker.record(err)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			c.breaker.record(fmt.Errorf("ollama returned status: %d", resp.StatusCode))
+		default:
+			c.breaker.record(nil)
+		}
+	}
+	return resp, err
+}
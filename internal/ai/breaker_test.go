@@ -0,0 +1,115 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOllamaClient_BreakerOpensWhenUnreachable(t *testing.T) {
+	// A server that is started and closed leaves a URL nothing listens on.
+	server := httptest.NewServer(http.NotFoundHandler())
+	unreachable := server.URL
+	server.Close()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	client := NewOllamaClient(unreachable, ModelConfig{})
+	client.breaker = newCircuitBreaker(3, time.Minute)
+	client.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GenerateEmbedding(ctx, "soil"); err == nil || errors.Is(err, ErrUnavailable) {
+			t.Fatalf("call %d: expected a connection error, got %v", i+1, err)
+		}
+	}
+	if client.Available() {
+		t.Fatal("expected the circuit to be open after 3 failures")
+	}
+	if _, err := client.GenerateCompletion(ctx, "extract", true); !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable while open, got %v", err)
+	}
+	h := client.Health()
+	if h.Status != "unavailable" || h.ConsecutiveFailures != 3 || h.OpenUntil == nil || h.LastError == "" {
+		t.Fatalf("unexpected health %+v", h)
+	}
+
+	// After the cooldown one probe goes through; failing, it reopens at once.
+	now = now.Add(time.Minute)
+	if !client.Available() {
+		t.Fatal("expected a probe to be allowed after the cooldown")
+	}
+	if _, err := client.GenerateEmbedding(ctx, "soil"); err == nil || errors.Is(err, ErrUnavailable) {
+		t.Fatalf("expected the probe to reach the backend, got %v", err)
+	}
+	if client.Available() {
+		t.Fatal("expected a failed probe to reopen the circuit")
+	}
+}
+
+func TestOllamaClient_BreakerClosesOnSuccess(t *testing.T) {
+	var calls, failing atomic.Int32
+	failing.Store(1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failing.Load() == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"embedding":[0.1,0.2]}`))
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL, ModelConfig{})
+	client.breaker = newCircuitBreaker(2, time.Millisecond)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, _ = client.GenerateEmbedding(ctx, "soil")
+	}
+	if _, err := client.GenerateEmbedding(ctx, "soil"); !errors.Is(err, ErrUnavailable) || calls.Load() != 2 {
+		t.Fatalf("expected the third call short-circuited, got %v after %d calls", err, calls.Load())
+	}
+
+	failing.Store(0)
+	time.Sleep(2 * time.Millisecond)
+	if _, err := client.GenerateEmbedding(ctx, "soil"); err != nil {
+		t.Fatalf("expected the backend to recover, got %v", err)
+	}
+	if h := client.Health(); h.Status != "ok" || h.ConsecutiveFailures != 0 {
+		t.Fatalf("expected a healthy client after success, got %+v", h)
+	}
+}
+
+func TestCircuitBreaker_HalfOpenAdmitsOneProbe(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+	b.record(errors.New("connection refused"))
+	b.record(errors.New("connection refused"))
+
+	now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected the probe admitted after the cooldown, got %v", err)
+	}
+	if b.available() || b.allow() == nil {
+		t.Fatal("expected other callers rejected while the probe is in flight")
+	}
+
+	// A cancelled probe frees the slot without counting either way.
+	b.abandon()
+	if err := b.allow(); err != nil {
+		t.Fatalf("expected a new probe after the first was abandoned, got %v", err)
+	}
+	b.record(nil)
+	for i := 0; i < 3; i++ {
+		if err := b.allow(); err != nil {
+			t.Fatalf("expected the closed circuit to admit every call, got %v", err)
+		}
+		b.record(nil)
+	}
+}
@@ -6,35 +6,112 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 )
 
 type Embedder interface {
 	GenerateEmbedding(ctx context.Context, text string) ([]float32, error)
 }
 
+// DefaultEmbeddingDim matches the opportunities.embedding vector(768) column
+// (nomic-embed-text). Override with EMBEDDING_DIM when the column is migrated.
+const DefaultEmbeddingDim = 768
+
+// Default Ollama models for each operation.
+const (
+	DefaultExtractionModel = "qwen2.5:14b"
+	DefaultEmbeddingModel  = "nomic-embed-text"
+)
+
+// ModelConfig names the Ollama model used for each kind of call. Status
+// classification is short and frequent, so it can run on a smaller model
+// than full extraction.
+type ModelConfig struct {
+	Extraction string // OLLAMA_EXTRACT_MODEL, default DefaultExtractionModel
+	Status     string // OLLAMA_STATUS_MODEL, default: the extraction model
+	Embedding  string // OLLAMA_EMBED_MODEL, default DefaultEmbeddingModel
+}
+
+// ModelConfigFromEnv reads the per-operation model names from the environment.
+// Unset variables fall back to the defaults.
+func ModelConfigFromEnv() ModelConfig {
+	return ModelConfig{
+		Extraction: strings.TrimSpace(os.Getenv("OLLAMA_EXTRACT_MODEL")),
+		Status:     strings.TrimSpace(os.Getenv("OLLAMA_STATUS_MODEL")),
+		Embedding:  strings.TrimSpace(os.Getenv("OLLAMA_EMBED_MODEL")),
+	}.withDefaults()
+}
+
+func (m ModelConfig) withDefaults() ModelConfig {
+	if m.Extraction == "" {
+		m.Extraction = DefaultExtractionModel
+	}
+	if m.Status == "" {
+		m.Status = m.Extraction
+	}
+	if m.Embedding == "" {
+		m.Embedding = DefaultEmbeddingModel
+	}
+	return m
+}
+
 type OllamaClient struct {
-	BaseURL    string
-	EmbedModel string
-	GenModel   string
+	BaseURL     string
+	EmbedModel  string
+	GenModel    string
+	StatusModel string
+
+	// EmbeddingDim is the vector length the database column expects.
+	EmbeddingDim int
+
+	// breaker short-circuits calls while Ollama is unreachable; nil
+	// disables it.
+	breaker *circuitBreaker
+}
+
+// EmbeddingDimensionError reports a vector whose length does not match the
+// configured column dimension, usually because OLLAMA_HOST serves a different
+// embed model.
+type EmbeddingDimensionError struct {
+	Model    string
+	Expected int
+	Actual   int
+}
+
+func (e *EmbeddingDimensionError) Error() string {
+	return fmt.Sprintf("embedding dimension mismatch for model %q: expected %d, got %d", e.Model, e.Expected, e.Actual)
 }
 
-func NewOllamaClient(baseURL, embedModel, genModel string) *OllamaClient {
+func NewOllamaClient(baseURL string, models ModelConfig) *OllamaClient {
 	if baseURL == "" {
 		baseURL = "http://localhost:11434"
 	}
-	if embedModel == "" {
-		embedModel = "nomic-embed-text"
-	}
-	if genModel == "" {
-		genModel = "llama3.2:latest" // Default generation model
+	models = models.withDefaults()
+	dim := DefaultEmbeddingDim
+	if v, err := strconv.Atoi(os.Getenv("EMBEDDING_DIM")); err == nil && v > 0 {
+		dim = v
 	}
 	return &OllamaClient{
-		BaseURL:    baseURL,
-		EmbedModel: embedModel,
-		GenModel:   genModel,
+		BaseURL:      baseURL,
+		EmbedModel:   models.Embedding,
+		GenModel:     models.Extraction,
+		StatusModel:  models.Status,
+		EmbeddingDim: dim,
+		breaker:      breakerFromEnv(),
 	}
 }
 
+// CheckEmbedding returns an *EmbeddingDimensionError when vec does not have
+// the configured dimension. A zero EmbeddingDim disables the check.
+func (c *OllamaClient) CheckEmbedding(vec []float32) error {
+	if c.EmbeddingDim <= 0 || len(vec) == c.EmbeddingDim {
+		return nil
+	}
+	return &EmbeddingDimensionError{Model: c.EmbedModel, Expected: c.EmbeddingDim, Actual: len(vec)}
+}
+
 type embeddingRequest struct {
 	Model  string `json:"model"`
 	Prompt string `json:"prompt"`
@@ -60,8 +137,7 @@ func (c *OllamaClient) GenerateEmbedding(ctx context.Context, text string) ([]fl
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("ollama request failed: %w", err)
 	}
@@ -79,6 +155,57 @@ func (c *OllamaClient) GenerateEmbedding(ctx context.Context, text string) ([]fl
 	return parsedResp.Embedding, nil
 }
 
+type batchEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type batchEmbeddingResponse struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// GenerateEmbeddings embeds several texts in one call via /api/embed.
+// The result is index-aligned with texts.
+func (c *OllamaClient) GenerateEmbeddings(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+	reqBody := batchEmbeddingRequest{
+		Model: c.EmbedModel,
+		Input: texts,
+	}
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/api/embed", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ollama request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama returned status: %d", resp.StatusCode)
+	}
+
+	var parsedResp batchEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&parsedResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(parsedResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("ollama returned %d embeddings for %d inputs", len(parsedResp.Embeddings), len(texts))
+	}
+
+	return parsedResp.Embeddings, nil
+}
+
 type generateRequest struct {
 	Model  string `json:"model"`
 	Prompt string `json:"prompt"`
@@ -91,9 +218,24 @@ type generateResponse struct {
 	Done     bool   `json:"done"`
 }
 
+// GenerateCompletion runs prompt on the extraction model.
 func (c *OllamaClient) GenerateCompletion(ctx context.Context, prompt string, jsonMode bool) (string, error) {
+	return c.generate(ctx, c.GenModel, prompt, jsonMode)
+}
+
+// GenerateStatusCompletion runs prompt on the status classification model,
+// falling back to the extraction model when none is set.
+func (c *OllamaClient) GenerateStatusCompletion(ctx context.Context, prompt string, jsonMode bool) (string, error) {
+	model := c.StatusModel
+	if model == "" {
+		model = c.GenModel
+	}
+	return c.generate(ctx, model, prompt, jsonMode)
+}
+
+func (c *OllamaClient) generate(ctx context.Context, model, prompt string, jsonMode bool) (string, error) {
 	reqBody := generateRequest{
-		Model:  c.GenModel,
+		Model:  model,
 		Prompt: prompt,
 		Stream: false,
 	}
@@ -112,8 +254,7 @@ func (c *OllamaClient) GenerateCompletion(ctx context.Context, prompt string, js
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return "", fmt.Errorf("ollama request failed: %w", err)
 	}
@@ -0,0 +1,88 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestOllamaClient_ModelPerOperation(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		seen[r.URL.Path+" "+body.Model] = true
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/api/embeddings":
+			_, _ = w.Write([]byte(`{"embedding":[0.1,0.2]}`))
+		default:
+			_, _ = w.Write([]byte(`{"response":"{\"status\":\"closed\"}","done":true}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewOllamaClient(server.URL, ModelConfig{Extraction: "big-extract", Status: "small-status", Embedding: "embed-x"})
+	ctx := context.Background()
+
+	if _, err := client.GenerateCompletion(ctx, "extract", true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := AnalyzeStatus(ctx, client, "Call", "Closed."); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.GenerateEmbedding(ctx, "text"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"/api/generate big-extract", "/api/generate small-status", "/api/embeddings embed-x"} {
+		if !seen[want] {
+			t.Fatalf("expected request %q, saw %v", want, seen)
+		}
+	}
+}
+
+func TestModelConfigFromEnv_Defaults(t *testing.T) {
+	t.Setenv("OLLAMA_EXTRACT_MODEL", "")
+	t.Setenv("OLLAMA_STATUS_MODEL", "")
+	t.Setenv("OLLAMA_EMBED_MODEL", "")
+
+	got := ModelConfigFromEnv()
+	want := ModelConfig{Extraction: DefaultExtractionModel, Status: DefaultExtractionModel, Embedding: DefaultEmbeddingModel}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	t.Setenv("OLLAMA_EXTRACT_MODEL", "llama3.1:70b")
+	t.Setenv("OLLAMA_STATUS_MODEL", "llama3.2:3b")
+	got = ModelConfigFromEnv()
+	if got.Extraction != "llama3.1:70b" || got.Status != "llama3.2:3b" || got.Embedding != DefaultEmbeddingModel {
+		t.Fatalf("unexpected config %+v", got)
+	}
+}
+
+func TestAnalyzeStatus_Funded(t *testing.T) {
+	for _, raw := range []string{"funded", "Awarded"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			resp, _ := json.Marshal(map[string]interface{}{"response": `{"status":"` + raw + `"}`, "done": true})
+			_, _ = w.Write(resp)
+		}))
+		client := NewOllamaClient(server.URL, ModelConfig{Extraction: "m", Status: "m", Embedding: "e"})
+		got, err := AnalyzeStatus(context.Background(), client, "Resultados", "Proyectos adjudicados.")
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "funded" {
+			t.Fatalf("%q: expected funded, got %q", raw, got)
+		}
+	}
+}
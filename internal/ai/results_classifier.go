@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// maxResultsPageChars caps the page text sent to the model to keep prompts small.
+const maxResultsPageChars = 4000
+
+// ClassifyResultsPage asks the LLM whether a page is an active call for proposals
+// or a results/awarded-projects listing (e.g. a table of funded PIs). It returns
+// whether the page is a results page and the model's confidence in [0,1].
+func ClassifyResultsPage(ctx context.Context, client *OllamaClient, title, pageText string) (bool, float64, error) {
+	if len(pageText) > maxResultsPageChars {
+		pageText = pageText[:maxResultsPageChars]
+	}
+
+	prompt := fmt.Sprintf(`You are an expert grant analyst. Decide whether this web page is an ACTIVE CALL for applications or a RESULTS page listing awarded/funded projects.
+
+PAGE TITLE: %s
+PAGE TEXT:
+%s
+
+- A results page lists winners, beneficiaries, funded projects, awarded PIs or selected proposals, often as a table, and does not invite new applications.
+- An active call describes who can apply, how to apply, and when applications close.
+
+Return ONLY a JSON object:
+{
+  "page_type": "active_call" | "results",
+  "confidence": 0.0-1.0,
+  "reason": "brief explanation"
+}
+`, title, pageText)
+
+	resp, err := client.GenerateStatusCompletion(ctx, prompt, true)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var result struct {
+		PageType   string  `json:"page_type"`
+		Confidence float64 `json:"confidence"`
+		Reason     string  `json:"reason"`
+	}
+	if err := json.Unmarshal([]byte(resp), &result); err != nil {
+		return false, 0, fmt.Errorf("failed to parse results page json: %w", err)
+	}
+
+	confidence := result.Confidence
+	if confidence < 0 {
+		confidence = 0
+	}
+	if confidence > 1 {
+		confidence = 1
+	}
+
+	switch strings.ToLower(strings.TrimSpace(result.PageType)) {
+	case "results", "awarded", "funded_projects":
+		return true, confidence, nil
+	}
+	return false, confidence, nil
+}
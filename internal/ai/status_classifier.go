@@ -7,7 +7,7 @@ import (
 	"strings"
 )
 
-// AnalyzeStatus uses the LLM to determine if a grant is open, closed, or forthcoming
+// AnalyzeStatus uses the LLM to determine if a grant is open, closed, forthcoming or funded
 // based on its text content. This is useful for ambiguous cases where no date is present.
 func AnalyzeStatus(ctx context.Context, client *OllamaClient, title, summary string) (string, error) {
 	prompt := fmt.Sprintf(`You are an expert grant analyst. Determine the status of this grant opportunity based on the text below.
@@ -18,17 +18,18 @@ GRANT SUMMARY: %s
 Is this grant currently open for applications?
 - If the text explicitly says "closed", "expired", "past", "no longer accepting", or similar, return "closed".
 - If the text mentions a past year (e.g. 2020, 2023) and no future year, return "closed".
+- If the text announces winners, awarded or funded projects, or "adjudicado" results, return "funded".
 - If the text says "coming soon", "future", "anticipated", return "forthcoming".
 - If it seems active, open, or rolling, return "posted".
 
 Return ONLY a JSON object:
 {
-  "status": "posted" | "closed" | "forthcoming",
+  "status": "posted" | "closed" | "forthcoming" | "funded",
   "reason": "brief explanation"
 }
 `, title, summary)
 
-	resp, err := client.GenerateCompletion(ctx, prompt, true)
+	resp, err := client.GenerateStatusCompletion(ctx, prompt, true)
 	if err != nil {
 		return "posted", err // Default to posted on error to be safe
 	}
@@ -44,6 +45,8 @@ Return ONLY a JSON object:
 
 	status := strings.ToLower(strings.TrimSpace(result.Status))
 	switch status {
+	case "funded", "awarded":
+		return "funded", nil
 	case "closed", "expired", "archived":
 		return "closed", nil
 	case "forthcoming", "upcoming":
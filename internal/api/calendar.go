@@ -0,0 +1,176 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/david/grant-finder/internal/auth"
+	"github.com/david/grant-finder/internal/db"
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/david/grant-finder/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const icsContentType = "text/calendar; charset=utf-8"
+
+func (s *Server) handleSavedCalendar(c echo.Context) error {
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	opps, err := s.Store.GetSavedOpportunities(c.Request().Context(), userID.String())
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch saved opportunities"})
+	}
+
+	now := time.Now().UTC()
+	withDeadline := opps[:0]
+	for _, o := range opps {
+		if o.NextDeadlineAt != nil && o.NextDeadlineAt.After(now) {
+			withDeadline = append(withDeadline, o)
+		}
+	}
+
+	c.Response().Header().Set("Content-Disposition", `inline; filename="saved-deadlines.ics"`)
+	return c.Blob(http.StatusOK, icsContentType, []byte(buildICS(withDeadline, now)))
+}
+
+func (s *Server) handleOpportunityCalendar(c echo.Context) error {
+	opp, err := s.Store.GetOpportunity(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	}
+
+	c.Response().Header().Set("Content-Disposition", fmt.Sprintf(`inline; filename="%s.ics"`, opp.ID))
+	return c.Blob(http.StatusOK, icsContentType, []byte(buildICS([]models.Opportunity{*opp}, time.Now().UTC())))
+}
+
+// buildICS renders one VEVENT per future deadline of each opportunity.
+func buildICS(opps []models.Opportunity, now time.Time) string {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//Grant Finder//Deadlines//EN")
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+	writeICSLine(&b, "METHOD:PUBLISH")
+
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, o := range opps {
+		description := o.ExternalURL
+		if o.AgencyName != "" {
+			description = o.AgencyName + "\n" + description
+		}
+
+		loc := deadlineLocation(o)
+		for _, deadline := range futureDeadlines(o, now, loc) {
+			writeICSLine(&b, "BEGIN:VEVENT")
+			writeICSLine(&b, fmt.Sprintf("UID:%s-%s@grant-finder", o.ID, deadline.Format("20060102T150405Z")))
+			writeICSLine(&b, "DTSTAMP:"+stamp)
+			if hasTimeComponent(deadline, loc) {
+				writeICSLine(&b, "DTSTART:"+deadline.Format("20060102T150405Z"))
+			} else {
+				day := deadline.In(loc)
+				writeICSLine(&b, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+				writeICSLine(&b, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+			}
+			writeICSLine(&b, "SUMMARY:"+escapeICSText("Deadline: "+o.Title))
+			writeICSLine(&b, "DESCRIPTION:"+escapeICSText(description))
+			if o.ExternalURL != "" {
+				writeICSLine(&b, "URL:"+o.ExternalURL)
+			}
+			writeICSLine(&b, "END:VEVENT")
+		}
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// deadlineLocation is the timezone the opportunity's date-only deadlines were
+// stored in, so all-day detection and the event date follow the source.
+func deadlineLocation(o models.Opportunity) *time.Location {
+	return ingest.SourceLocation(o.SourceDomain + " " + o.ExternalURL)
+}
+
+// futureDeadlines merges next_deadline_at with every cycle deadline, keeping
+// distinct instants after now in ascending order. Date-only cycle deadlines
+// are read as midnight in loc.
+func futureDeadlines(o models.Opportunity, now time.Time, loc *time.Location) []time.Time {
+	seen := map[time.Time]bool{}
+	var out []time.Time
+	add := func(t time.Time) {
+		t = t.UTC()
+		if !t.After(now) || seen[t] {
+			return
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+
+	if o.NextDeadlineAt != nil {
+		add(*o.NextDeadlineAt)
+	}
+	for _, raw := range o.Deadlines {
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			add(t)
+		} else if t, err := time.ParseInLocation("2006-01-02", raw, loc); err == nil {
+			add(t)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return out
+}
+
+// hasTimeComponent treats midnight and the 23:59:59 end-of-day convention
+// used for date-only deadlines as all-day, read on the clock in loc.
+func hasTimeComponent(t time.Time, loc *time.Location) bool {
+	h, m, s := t.In(loc).Clock()
+	return !(h == 0 && m == 0 && s == 0) && !(h == 23 && m == 59 && s == 59)
+}
+
+func escapeICSText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeICSLine writes a CRLF-terminated content line folded at 75 octets
+// (RFC 5545 §3.1), without splitting multi-byte characters.
+func writeICSLine(b *strings.Builder, line string) {
+	const limit = 75
+	first := true
+	for len(line) > 0 {
+		max := limit
+		if !first {
+			max = limit - 1 // continuation lines start with a space
+		}
+		if len(line) <= max {
+			if !first {
+				b.WriteString(" ")
+			}
+			b.WriteString(line)
+			break
+		}
+		cut := max
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if !first {
+			b.WriteString(" ")
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n")
+		line = line[cut:]
+		first = false
+	}
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/models"
+)
+
+func TestHasTimeComponent_UsesSourceTimezone(t *testing.T) {
+	lima, err := time.LoadLocation("America/Lima")
+	if err != nil {
+		t.Skip("tzdata unavailable")
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		loc  *time.Location
+		want bool
+	}{
+		{"utc midnight", time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC), time.UTC, false},
+		{"utc end of day", time.Date(2027, 3, 1, 23, 59, 59, 0, time.UTC), time.UTC, false},
+		{"utc afternoon", time.Date(2027, 3, 1, 17, 0, 0, 0, time.UTC), time.UTC, true},
+		{"lima end of day", time.Date(2027, 3, 2, 4, 59, 59, 0, time.UTC), lima, false},
+		{"lima midnight", time.Date(2027, 3, 1, 5, 0, 0, 0, time.UTC), lima, false},
+		{"utc end of day seen from lima", time.Date(2027, 3, 1, 23, 59, 59, 0, time.UTC), lima, true},
+	}
+	for _, tt := range tests {
+		if got := hasTimeComponent(tt.at, tt.loc); got != tt.want {
+			t.Errorf("%s: hasTimeComponent(%s) = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBuildICS_LimaDeadlineIsAllDayOnLocalDate(t *testing.T) {
+	if _, err := time.LoadLocation("America/Lima"); err != nil {
+		t.Skip("tzdata unavailable")
+	}
+
+	deadline := time.Date(2027, 3, 2, 4, 59, 59, 0, time.UTC) // 2027-03-01 23:59:59 in Lima
+	opp := models.Opportunity{
+		Title:          "Fondo semilla",
+		SourceDomain:   "proinnovate.gob.pe",
+		ExternalURL:    "https://proinnovate.gob.pe/convocatorias/semilla",
+		NextDeadlineAt: &deadline,
+		Deadlines:      []string{"2027-04-15"},
+	}
+	ics := buildICS([]models.Opportunity{opp}, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20270301\r\n",
+		"DTEND;VALUE=DATE:20270302\r\n",
+		"DTSTART;VALUE=DATE:20270415\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("missing %q in:\n%s", want, ics)
+		}
+	}
+	if strings.Contains(ics, "DTSTART:2027") {
+		t.Errorf("date-only Lima deadlines rendered as timed events:\n%s", ics)
+	}
+}
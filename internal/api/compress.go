@@ -0,0 +1,20 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// gzipMinLength keeps small responses (errors, counts, health) uncompressed;
+// list and aggregation payloads are well above it.
+const gzipMinLength = 1024
+
+// compressionMiddleware gzips responses for clients that send
+// Accept-Encoding: gzip. The gzip writer implements http.Flusher, so
+// handlers that stream and call Flush keep working.
+func compressionMiddleware() echo.MiddlewareFunc {
+	return middleware.GzipWithConfig(middleware.GzipConfig{
+		Level:     5,
+		MinLength: gzipMinLength,
+	})
+}
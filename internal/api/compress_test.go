@@ -0,0 +1,71 @@
+package api
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newCompressionTestServer() *echo.Echo {
+	e := echo.New()
+	e.Use(compressionMiddleware())
+	e.GET("/list", func(c echo.Context) error {
+		items := make([]map[string]string, 200)
+		for i := range items {
+			items[i] = map[string]string{"title": "Convocatoria de innovación", "summary": strings.Repeat("texto ", 20)}
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{"data": items, "total": len(items)})
+	})
+	e.GET("/count", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]int{"total": 3})
+	})
+	return e
+}
+
+func TestCompression_LargeListIsGzipped(t *testing.T) {
+	e := newCompressionTestServer()
+	req := httptest.NewRequest(http.MethodGet, "/list", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(echo.HeaderContentEncoding); got != "gzip" {
+		t.Fatalf("expected gzip Content-Encoding, got %q", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	raw, _ := io.ReadAll(zr)
+	var payload struct {
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.Total != 200 {
+		t.Fatalf("unexpected decompressed payload (err=%v): %.80s", err, raw)
+	}
+}
+
+func TestCompression_SmallOrUnrequestedIsPlain(t *testing.T) {
+	e := newCompressionTestServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/count", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Header().Get(echo.HeaderContentEncoding) != "" || !strings.Contains(rec.Body.String(), `"total":3`) {
+		t.Fatalf("expected small response uncompressed, got encoding %q", rec.Header().Get(echo.HeaderContentEncoding))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/list", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Header().Get(echo.HeaderContentEncoding) != "" {
+		t.Fatal("expected no compression without Accept-Encoding")
+	}
+}
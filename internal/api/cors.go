@@ -0,0 +1,115 @@
+package api
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// devCORSOrigin is the Angular dev server, allowed only with CORS_DEV=true.
+const devCORSOrigin = "http://localhost:4200"
+
+var (
+	defaultCORSMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	defaultCORSHeaders = []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Admin-Secret"}
+)
+
+// loadCORSConfig builds the CORS policy from the environment:
+//
+//	CORS_ORIGINS            comma-separated origins; "https://*.example.com" or
+//	                        "*.example.com" match any subdomain; add ":port"
+//	                        for origins served on a non-default port
+//	CORS_METHODS            overrides the default method list
+//	CORS_HEADERS            overrides the default request header list
+//	CORS_ALLOW_CREDENTIALS  "true" to allow cookies/credentials
+//	CORS_DEV                "true" to also allow the local frontend dev server
+func loadCORSConfig() middleware.CORSConfig {
+	origins := splitEnvList("CORS_ORIGINS")
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("CORS_DEV")), "true") {
+		origins = append(origins, devCORSOrigin)
+	}
+
+	methods := splitEnvList("CORS_METHODS")
+	if len(methods) == 0 {
+		methods = defaultCORSMethods
+	}
+	headers := splitEnvList("CORS_HEADERS")
+	if len(headers) == 0 {
+		headers = defaultCORSHeaders
+	}
+
+	credentials := strings.EqualFold(strings.TrimSpace(os.Getenv("CORS_ALLOW_CREDENTIALS")), "true")
+	for _, o := range origins {
+		if o == "*" && credentials {
+			log.Print("CORS_ALLOW_CREDENTIALS ignored: not allowed together with a \"*\" origin")
+			credentials = false
+		}
+	}
+
+	return middleware.CORSConfig{
+		AllowOriginFunc:  corsOriginMatcher(origins),
+		AllowMethods:     methods,
+		AllowHeaders:     headers,
+		AllowCredentials: credentials,
+		// Link carries list pagination; browsers hide it from JS unless exposed.
+		ExposeHeaders: []string{"Link"},
+	}
+}
+
+// corsOriginMatcher accepts exact origins, "*" and "*.suffix" patterns. A
+// pattern with a scheme ("https://*.example.com") also requires that scheme,
+// and one with a port ("http://*.example.com:8080") that port; a pattern
+// without a port only matches origins without one.
+func corsOriginMatcher(patterns []string) func(origin string) (bool, error) {
+	return func(origin string) (bool, error) {
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" {
+			return false, nil
+		}
+		host, port := strings.ToLower(u.Hostname()), u.Port()
+
+		for _, pattern := range patterns {
+			if pattern == "*" || strings.EqualFold(pattern, origin) {
+				return true, nil
+			}
+
+			scheme, hostPattern := "", pattern
+			if i := strings.Index(pattern, "://"); i >= 0 {
+				scheme, hostPattern = pattern[:i], pattern[i+3:]
+			}
+			if !strings.HasPrefix(hostPattern, "*.") {
+				continue
+			}
+			if scheme != "" && !strings.EqualFold(scheme, u.Scheme) {
+				continue
+			}
+			patternPort := ""
+			if h, p, err := net.SplitHostPort(hostPattern); err == nil {
+				hostPattern, patternPort = h, p
+			}
+			if patternPort != port {
+				continue
+			}
+			if strings.HasSuffix(host, strings.ToLower(hostPattern[1:])) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func splitEnvList(name string) []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv(name), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+func corsPreflight(e *echo.Echo, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/opportunities", nil)
+	req.Header.Set(echo.HeaderOrigin, origin)
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORS_AllowedAndDisallowedOrigins(t *testing.T) {
+	t.Setenv("CORS_ORIGINS", "https://app.example.org, https://*.grants.example.com")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	t.Setenv("CORS_DEV", "")
+
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(loadCORSConfig()))
+	e.GET("/api/v1/opportunities", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	for _, origin := range []string{"https://app.example.org", "https://eu.grants.example.com"} {
+		rec := corsPreflight(e, origin)
+		if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != origin {
+			t.Errorf("%s: expected origin to be allowed, got %q", origin, got)
+		}
+		if rec.Header().Get(echo.HeaderAccessControlAllowCredentials) != "true" {
+			t.Errorf("%s: expected credentials to be allowed", origin)
+		}
+	}
+
+	for _, origin := range []string{"https://evil.example.org", "http://eu.grants.example.com", "https://grants.example.com.evil.io", "http://localhost:4200"} {
+		rec := corsPreflight(e, origin)
+		if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != "" {
+			t.Errorf("%s: expected origin to be rejected, got %q", origin, got)
+		}
+	}
+}
+
+func TestCORS_DevFlagAllowsLocalFrontend(t *testing.T) {
+	t.Setenv("CORS_ORIGINS", "")
+	t.Setenv("CORS_DEV", "true")
+
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(loadCORSConfig()))
+	e.GET("/api/v1/opportunities", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	rec := corsPreflight(e, devCORSOrigin)
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowOrigin); got != devCORSOrigin {
+		t.Errorf("expected dev origin to be allowed with CORS_DEV, got %q", got)
+	}
+}
+
+func TestCORS_PreflightAllowsSavedNotePut(t *testing.T) {
+	t.Setenv("CORS_ORIGINS", "https://app.example.org")
+	t.Setenv("CORS_METHODS", "")
+	t.Setenv("CORS_DEV", "")
+
+	e := echo.New()
+	e.Use(middleware.CORSWithConfig(loadCORSConfig()))
+	e.PUT("/api/v1/saved/:id/note", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/saved/42/note", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.org")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPut)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(echo.HeaderAccessControlAllowMethods); !strings.Contains(got, http.MethodPut) {
+		t.Fatalf("expected PUT in the preflight's allowed methods, got %q", got)
+	}
+}
+
+func TestCORSOriginMatcher_Ports(t *testing.T) {
+	match := corsOriginMatcher([]string{"http://*.staging.example.org:8080", "https://*.example.com", "http://localhost:4200"})
+	cases := map[string]bool{
+		"http://app.staging.example.org:8080": true,
+		"http://app.staging.example.org:9090": false,
+		"http://app.staging.example.org":      false,
+		"https://eu.example.com":              true,
+		"https://eu.example.com:8443":         false,
+		"http://localhost:4200":               true,
+		"http://localhost:4300":               false,
+	}
+	for origin, want := range cases {
+		if got, _ := match(origin); got != want {
+			t.Errorf("%s: allowed = %v, want %v", origin, got, want)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// forbiddenURLError marks URLs that are well-formed but may not be fetched:
+// internal hosts, or hosts outside INGEST_URL_ALLOWLIST.
+type forbiddenURLError struct {
+	reason string
+}
+
+func (e *forbiddenURLError) Error() string { return e.reason }
+
+// validateFetchURL checks a URL an admin asked us to fetch. It must pass
+// validatePublicURL and, when INGEST_URL_ALLOWLIST is set, have a host
+// matching one of its domain suffixes.
+func validateFetchURL(rawURL string) error {
+	if u, err := url.Parse(rawURL); err == nil {
+		host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+		if allowlist := ingestURLAllowlist(); host != "" && len(allowlist) > 0 && !hostMatchesSuffix(host, allowlist) {
+			return &forbiddenURLError{fmt.Sprintf("Host %s is not in the ingest URL allowlist", host)}
+		}
+	}
+	return validatePublicURL(rawURL)
+}
+
+// validatePublicURL requires an HTTP(S) URL whose host resolves only to
+// public addresses.
+func validatePublicURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("Invalid URL scheme")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if host == "" {
+		return fmt.Errorf("URL host is required")
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || host == "127.0.0.1" || host == "::1" || strings.HasSuffix(host, ".local") {
+		return &forbiddenURLError{"Internal network access forbidden"}
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("Unable to resolve URL host")
+	}
+	if len(ips) == 0 {
+		return fmt.Errorf("URL host resolved to no addresses")
+	}
+	for _, ip := range ips {
+		if isPrivateOrSpecialIP(ip) {
+			return &forbiddenURLError{"Internal network access forbidden"}
+		}
+	}
+	return nil
+}
+
+// fetchURLErrorStatus maps a validateFetchURL error to an HTTP status.
+func fetchURLErrorStatus(err error) int {
+	var forbidden *forbiddenURLError
+	if errors.As(err, &forbidden) {
+		return http.StatusForbidden
+	}
+	return http.StatusBadRequest
+}
+
+// ingestURLAllowlist parses INGEST_URL_ALLOWLIST (comma-separated domain
+// suffixes). Empty means every public host is allowed.
+func ingestURLAllowlist() []string {
+	var out []string
+	for _, part := range strings.Split(os.Getenv("INGEST_URL_ALLOWLIST"), ",") {
+		suffix := strings.Trim(strings.ToLower(strings.TrimSpace(part)), ".")
+		if suffix != "" {
+			out = append(out, suffix)
+		}
+	}
+	return out
+}
+
+// hostMatchesSuffix reports whether host equals a suffix or is a subdomain of one.
+func hostMatchesSuffix(host string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if host == suffix || strings.HasSuffix(host, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
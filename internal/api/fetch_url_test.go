@@ -0,0 +1,80 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestHostMatchesSuffix(t *testing.T) {
+	allowlist := []string{"example.org", "gob.pe"}
+	for host, want := range map[string]bool{
+		"example.org":             true,
+		"www.example.org":         true,
+		"a.b.example.org":         true,
+		"evilexample.org":         false,
+		"example.org.evil.com":    false,
+		"proinnovate.gob.pe":      true,
+		"gob.pe.attacker.net":     false,
+		"notgob.pe":               false,
+		"":                        false,
+		"example.organization.io": false,
+	} {
+		if got := hostMatchesSuffix(host, allowlist); got != want {
+			t.Errorf("hostMatchesSuffix(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+func TestValidateFetchURL_Allowlist(t *testing.T) {
+	t.Setenv("INGEST_URL_ALLOWLIST", " Example.org. , gob.pe")
+	for _, rawURL := range []string{
+		"https://evilexample.org/calls",
+		"https://example.org.evil.com/calls",
+		"http://gob.pe.attacker.net/",
+	} {
+		var forbidden *forbiddenURLError
+		if err := validateFetchURL(rawURL); !errors.As(err, &forbidden) {
+			t.Errorf("%s: expected allowlist rejection, got %v", rawURL, err)
+		}
+	}
+}
+
+func TestValidateFetchURL_RejectsInternalHosts(t *testing.T) {
+	for _, rawURL := range []string{
+		"http://localhost/admin",
+		"http://LOCALHOST:8080/",
+		"http://localhost./",
+		"http://api.localhost/",
+		"http://printer.local/",
+		"http://127.0.0.1/",
+		"http://127.0.0.2:5432/",
+		"http://[::1]/",
+		"http://0.0.0.0/",
+		"http://10.0.0.5/",
+		"http://192.168.1.1/",
+		"http://169.254.169.254/latest/meta-data/",
+	} {
+		err := validateFetchURL(rawURL)
+		var forbidden *forbiddenURLError
+		if !errors.As(err, &forbidden) {
+			t.Errorf("%s: expected forbidden, got %v", rawURL, err)
+		}
+		if status := fetchURLErrorStatus(err); status != http.StatusForbidden {
+			t.Errorf("%s: expected 403, got %d", rawURL, status)
+		}
+	}
+}
+
+func TestValidateFetchURL_RejectsBadScheme(t *testing.T) {
+	for _, rawURL := range []string{"ftp://example.org/file", "file:///etc/passwd", "example.org/calls", "https:///nohost"} {
+		err := validateFetchURL(rawURL)
+		if err == nil {
+			t.Errorf("%s: expected an error", rawURL)
+			continue
+		}
+		if status := fetchURLErrorStatus(err); status != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d (%v)", rawURL, status, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/david/grant-finder/internal/models"
+)
+
+// opportunityFields is the set of JSON keys a models.Opportunity can carry,
+// derived from its struct tags so ?fields= stays in sync with the model.
+var opportunityFields = jsonFieldNames(reflect.TypeOf(models.Opportunity{}))
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	out := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// parseFieldsParam returns the requested opportunity fields from a CSV
+// ?fields= value, dropping unknown names. nil means "return everything".
+func parseFieldsParam(raw string) []string {
+	var fields []string
+	seen := map[string]bool{}
+	for _, f := range strings.Split(raw, ",") {
+		f = strings.TrimSpace(f)
+		if !opportunityFields[f] || seen[f] {
+			continue
+		}
+		seen[f] = true
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// projectOpportunity keeps only the given JSON fields of an opportunity.
+func projectOpportunity(opp models.Opportunity, fields []string) (map[string]json.RawMessage, error) {
+	body, err := json.Marshal(opp)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(body, &all); err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			out[f] = v
+		}
+	}
+	return out, nil
+}
+
+// projectedListResult is a db.ListResult whose opportunities are trimmed to
+// the requested fields; its own Opportunities shadow the embedded ones in
+// JSON, and every other key comes from the list result unchanged.
+type projectedListResult struct {
+	Opportunities []map[string]json.RawMessage `json:"opportunities"`
+	*db.ListResult
+}
+
+func projectListResult(result *db.ListResult, fields []string) (*projectedListResult, error) {
+	out := &projectedListResult{
+		Opportunities: make([]map[string]json.RawMessage, 0, len(result.Opportunities)),
+		ListResult:    result,
+	}
+	for _, opp := range result.Opportunities {
+		p, err := projectOpportunity(opp, fields)
+		if err != nil {
+			return nil, err
+		}
+		out.Opportunities = append(out.Opportunities, p)
+	}
+	return out, nil
+}
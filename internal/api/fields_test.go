@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/david/grant-finder/internal/models"
+)
+
+func TestParseFieldsParam_IgnoresUnknownAndDuplicates(t *testing.T) {
+	got := parseFieldsParam(" title,next_deadline_at,bogus,title,amount_max ")
+	want := []string{"title", "next_deadline_at", "amount_max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if parseFieldsParam("") != nil || parseFieldsParam("nope") != nil {
+		t.Error("expected nil when no valid fields are requested")
+	}
+}
+
+func TestProjectOpportunity(t *testing.T) {
+	opp := models.Opportunity{Title: "Seed grant", AmountMax: 5000, Description: "<p>long</p>"}
+	got, err := projectOpportunity(opp, []string{"title", "amount_max"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || string(got["title"]) != `"Seed grant"` || string(got["amount_max"]) != "5000" {
+		t.Errorf("unexpected projection: %v", got)
+	}
+}
+
+func TestProjectListResult_KeepsListResultKeys(t *testing.T) {
+	result := &db.ListResult{
+		Opportunities: []models.Opportunity{{Title: "Seed grant", AmountMax: 5000}},
+		Total:         41,
+		Limit:         20,
+		Offset:        20,
+		HasMore:       true,
+	}
+	projected, err := projectListResult(result, []string{"title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := json.Marshal(projected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"opportunities":[{"title":"Seed grant"}],"total":41,"limit":20,"offset":20,"has_more":true}`
+	if string(body) != want {
+		t.Errorf("got %s, want %s", body, want)
+	}
+}
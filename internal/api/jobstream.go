@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+)
+
+// jobStreamBuffer is how many progress events a slow stream client may fall
+// behind by before further events are dropped for it.
+const jobStreamBuffer = 16
+
+// jobStreamKeepalive spaces SSE comments so idle proxies don't close the
+// connection between batches.
+const jobStreamKeepalive = 15 * time.Second
+
+// publishJobProgress records p as the job's latest progress and fans it out to
+// stream subscribers. Sends never block: a subscriber whose buffer is full
+// misses the event, so a stalled client can't hold up the job.
+func (s *Server) publishJobProgress(job *backgroundJob, p ingest.Progress) {
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+
+	job.progress = &p
+	for ch := range job.subscribers {
+		select {
+		case ch <- p:
+		default:
+		}
+	}
+}
+
+// handleJobStream streams a job's progress as Server-Sent Events: a
+// "progress" event per report and a final "done" event carrying the same
+// payload as GET /admin/job/:id.
+func (s *Server) handleJobStream(c echo.Context) error {
+	queried := c.Param("id")
+	s.jobMu.Lock()
+	job := s.runningJob
+	if job == nil || job.ID != queried {
+		s.jobMu.Unlock()
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "job not found"})
+	}
+	updates := make(chan ingest.Progress, jobStreamBuffer)
+	job.subscribers[updates] = struct{}{}
+	latest := job.progress
+	s.jobMu.Unlock()
+
+	defer func() {
+		s.jobMu.Lock()
+		delete(job.subscribers, updates)
+		s.jobMu.Unlock()
+	}()
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set(echo.HeaderCacheControl, "no-cache")
+	resp.Header().Set(echo.HeaderConnection, "keep-alive")
+	resp.WriteHeader(http.StatusOK)
+	if latest != nil {
+		if err := writeSSE(resp, "progress", latest); err != nil {
+			return nil
+		}
+	}
+	resp.Flush()
+
+	keepalive := time.NewTicker(jobStreamKeepalive)
+	defer keepalive.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			// Client went away; the job carries on.
+			return nil
+		case p := <-updates:
+			if err := writeSSE(resp, "progress", p); err != nil {
+				return nil
+			}
+		case <-keepalive.C:
+			if _, err := fmt.Fprint(resp, ": keepalive\n\n"); err != nil {
+				return nil
+			}
+			resp.Flush()
+		case <-job.done:
+			s.jobMu.Lock()
+			final := jobSnapshot(job)
+			s.jobMu.Unlock()
+			_ = writeSSE(resp, "done", final)
+			return nil
+		}
+	}
+}
+
+// writeSSE writes one event with a JSON data line and flushes it.
+func writeSSE(resp *echo.Response, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(resp, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	resp.Flush()
+	return nil
+}
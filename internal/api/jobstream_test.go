@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+)
+
+func TestJobStream_EmitsProgressThenDone(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.GET("/job/:id/stream", s.handleJobStream)
+	server := httptest.NewServer(s.Echo)
+	defer server.Close()
+
+	start := make(chan struct{})
+	finish := make(chan struct{})
+	rec := httptest.NewRecorder()
+	c := s.Echo.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), rec)
+	if err := s.startJob(c, "test", time.Minute, func(ctx context.Context, report ingest.ProgressFunc) (any, error) {
+		<-start
+		report(ingest.Progress{Processed: 500, Updated: 12, StatusCounts: map[string]int{"open": 400, "closed": 100}})
+		<-finish
+		return map[string]int{"status_updated": 12}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	jobID := s.runningJob.ID
+
+	resp, err := http.Get(server.URL + "/job/" + jobID + "/stream")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get(echo.HeaderContentType); ct != "text/event-stream" {
+		t.Fatalf("expected text/event-stream, got %q", ct)
+	}
+
+	events := make(chan string, 8)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		var event string
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				events <- event + " " + strings.TrimPrefix(line, "data: ")
+			}
+		}
+		close(events)
+	}()
+
+	close(start)
+	progress := nextEvent(t, events)
+	if !strings.HasPrefix(progress, "progress ") || !strings.Contains(progress, `"processed":500`) || !strings.Contains(progress, `"closed":100`) {
+		t.Fatalf("unexpected progress event %q", progress)
+	}
+
+	close(finish)
+	done := nextEvent(t, events)
+	if !strings.HasPrefix(done, "done ") || !strings.Contains(done, `"status":"completed"`) || !strings.Contains(done, `"status_updated":12`) {
+		t.Fatalf("unexpected done event %q", done)
+	}
+}
+
+func TestJobStatus_ReportsProgressMidRun(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.GET("/job/:id", s.handleJobStatus)
+
+	reported := make(chan struct{})
+	finish := make(chan struct{})
+	defer close(finish)
+	c := s.Echo.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+	if err := s.startJob(c, "test", time.Minute, func(ctx context.Context, report ingest.ProgressFunc) (any, error) {
+		report(ingest.Progress{Processed: 1000, TotalEstimate: 50000, Percent: 2, Updated: 40})
+		close(reported)
+		<-finish
+		return nil, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-reported
+
+	rec := httptest.NewRecorder()
+	s.Echo.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/job/"+s.runningJob.ID, nil))
+	body := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.Contains(body, `"status":"running"`) {
+		t.Fatalf("expected a running job, got %d %s", rec.Code, body)
+	}
+	if !strings.Contains(body, `"processed":1000`) || !strings.Contains(body, `"total_estimate":50000`) || !strings.Contains(body, `"percent":2`) {
+		t.Fatalf("expected processed/total_estimate in poll response, got %s", body)
+	}
+}
+
+func TestJobStream_UnknownJob(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.GET("/job/:id/stream", s.handleJobStream)
+
+	rec := httptest.NewRecorder()
+	s.Echo.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/job/nope/stream", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestPublishJobProgress_StalledSubscriberDoesNotBlock(t *testing.T) {
+	s := &Server{}
+	stalled := make(chan ingest.Progress) // never read
+	job := &backgroundJob{subscribers: map[chan ingest.Progress]struct{}{stalled: {}}}
+
+	published := make(chan struct{})
+	go func() {
+		for i := 1; i <= 100; i++ {
+			s.publishJobProgress(job, ingest.Progress{Processed: i})
+		}
+		close(published)
+	}()
+
+	select {
+	case <-published:
+	case <-time.After(2 * time.Second):
+		t.Fatal("publishing blocked on a subscriber that stopped reading")
+	}
+	if job.progress == nil || job.progress.Processed != 100 {
+		t.Fatalf("expected latest progress to be recorded, got %+v", job.progress)
+	}
+}
+
+func nextEvent(t *testing.T, events <-chan string) string {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("stream closed early")
+		}
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for an SSE event")
+	}
+	return ""
+}
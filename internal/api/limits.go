@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultMaxBodyBytes     int64 = 1 << 20 // 1MB is plenty for JSON endpoints
+	defaultRequestTimeout         = 30 * time.Second
+	defaultLongRouteTimeout       = 60 * time.Minute
+)
+
+// longRunningPrefixes are admin routes that run ingestion synchronously and
+// legitimately exceed the default request timeout.
+var longRunningPrefixes = []string{
+	"/api/v1/ingest",
+	"/api/v1/seed",
+	"/api/v1/admin/",
+}
+
+type requestLimits struct {
+	MaxBodyBytes     int64
+	Timeout          time.Duration
+	LongRouteTimeout time.Duration
+}
+
+// loadRequestLimits reads REQUEST_MAX_BODY_BYTES, REQUEST_TIMEOUT and
+// ADMIN_REQUEST_TIMEOUT, falling back to defaults on missing or invalid values.
+func loadRequestLimits() requestLimits {
+	limits := requestLimits{
+		MaxBodyBytes:     defaultMaxBodyBytes,
+		Timeout:          defaultRequestTimeout,
+		LongRouteTimeout: defaultLongRouteTimeout,
+	}
+	if raw := strings.TrimSpace(os.Getenv("REQUEST_MAX_BODY_BYTES")); raw != "" {
+		if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil && parsed > 0 {
+			limits.MaxBodyBytes = parsed
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("REQUEST_TIMEOUT")); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			limits.Timeout = parsed
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("ADMIN_REQUEST_TIMEOUT")); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			limits.LongRouteTimeout = parsed
+		}
+	}
+	return limits
+}
+
+// bodyLimitMiddleware rejects declared oversized bodies up front and caps
+// streamed (chunked) bodies with http.MaxBytesReader.
+func bodyLimitMiddleware(maxBytes int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.ContentLength > maxBytes {
+				return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+			}
+			if req.Body != nil {
+				req.Body = http.MaxBytesReader(c.Response(), req.Body, maxBytes)
+			}
+			return next(c)
+		}
+	}
+}
+
+// timeoutMiddleware bounds the request context. Handlers that fail because the
+// deadline passed get their 5xx rewritten to 408. Background jobs detach with
+// context.WithoutCancel and are unaffected.
+func timeoutMiddleware(limits requestLimits) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			timeout := limits.Timeout
+			if isLongRunningRoute(c.Path()) {
+				timeout = limits.LongRouteTimeout
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			resp := c.Response()
+			resp.Before(func() {
+				if resp.Status >= http.StatusInternalServerError && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					resp.Status = http.StatusRequestTimeout
+				}
+			})
+
+			err := next(c)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !resp.Committed {
+				return c.JSON(http.StatusRequestTimeout, map[string]string{"error": "Request timed out"})
+			}
+			return err
+		}
+	}
+}
+
+func isLongRunningRoute(path string) bool {
+	for _, prefix := range longRunningPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBodyTooLarge reports whether a Bind error was caused by the body limit.
+func isBodyTooLarge(err error) bool {
+	var maxErr *http.MaxBytesError
+	return errors.As(err, &maxErr)
+}
+
+// queryTimeoutRetryAfter is the Retry-After hint, in seconds, sent when a read
+// query runs past the store's read timeout.
+const queryTimeoutRetryAfter = "5"
+
+// queryTimedOut answers a read that hit db.ErrQueryTimeout with a 503 the
+// client can retry, instead of the generic 500.
+func queryTimedOut(c echo.Context) error {
+	c.Response().Header().Set("Retry-After", queryTimeoutRetryAfter)
+	return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Query timed out; narrow the filters or retry shortly"})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/labstack/echo/v4"
+)
+
+func (s *Server) handlePoolStats(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.Store.PoolStats())
+}
+
+// handleMetrics serves pool stats in the Prometheus text exposition format so
+// a scraper can read them without pulling in the client library.
+func (s *Server) handleMetrics(c echo.Context) error {
+	return c.String(http.StatusOK, formatPoolMetrics(s.Store.PoolStats()))
+}
+
+func formatPoolMetrics(st db.PoolStats) string {
+	var b strings.Builder
+	metric := func(name, kind, help string, value any) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+	}
+	metric("grantfinder_db_pool_max_conns", "gauge", "Maximum size of the connection pool.", st.MaxConns)
+	metric("grantfinder_db_pool_total_conns", "gauge", "Connections currently open, including those being established.", st.TotalConns)
+	metric("grantfinder_db_pool_acquired_conns", "gauge", "Connections currently checked out.", st.AcquiredConns)
+	metric("grantfinder_db_pool_idle_conns", "gauge", "Connections currently idle.", st.IdleConns)
+	metric("grantfinder_db_pool_constructing_conns", "gauge", "Connections currently being established.", st.ConstructingConns)
+	metric("grantfinder_db_pool_acquire_total", "counter", "Successful connection acquires.", st.AcquireCount)
+	metric("grantfinder_db_pool_empty_acquire_total", "counter", "Acquires that waited because the pool had no idle connection.", st.EmptyAcquireCount)
+	metric("grantfinder_db_pool_canceled_acquire_total", "counter", "Acquires canceled by their context.", st.CanceledAcquireCount)
+	metric("grantfinder_db_pool_acquire_duration_seconds_total", "counter", "Total time spent acquiring connections.", st.AcquireDurationSec)
+	return b.String()
+}
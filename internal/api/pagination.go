@@ -0,0 +1,47 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// setPaginationLinks writes an RFC 5988 Link header with next/prev/last
+// relations for an offset-paginated result. Links keep the request's filter
+// query string and only rewrite limit/offset. The list endpoints only page by
+// offset today; a cursor-based "next" would replace the offset link here.
+func setPaginationLinks(c echo.Context, total, limit, offset int) {
+	if limit <= 0 {
+		return
+	}
+
+	link := func(off int, rel string) string {
+		q := c.Request().URL.Query()
+		q.Set("limit", strconv.Itoa(limit))
+		q.Set("offset", strconv.Itoa(off))
+		u := url.URL{Path: c.Request().URL.Path, RawQuery: q.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
+
+	var links []string
+	if offset+limit < total {
+		links = append(links, link(offset+limit, "next"))
+	}
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if total > 0 {
+		links = append(links, link(((total-1)/limit)*limit, "last"))
+	}
+
+	if len(links) > 0 {
+		c.Response().Header().Set("Link", strings.Join(links, ", "))
+	}
+}
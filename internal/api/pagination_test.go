@@ -0,0 +1,47 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestSetPaginationLinks(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/opportunities?region=Europe&limit=20&offset=20", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	setPaginationLinks(c, 95, 20, 20)
+
+	link := rec.Header().Get("Link")
+	for _, want := range []string{
+		`</api/v1/opportunities?limit=20&offset=40&region=Europe>; rel="next"`,
+		`</api/v1/opportunities?limit=20&offset=0&region=Europe>; rel="prev"`,
+		`</api/v1/opportunities?limit=20&offset=80&region=Europe>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Errorf("Link header missing %s\ngot: %s", want, link)
+		}
+	}
+}
+
+func TestSetPaginationLinks_LastPageHasNoNext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/opportunities?offset=80", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	setPaginationLinks(c, 95, 20, 80)
+
+	link := rec.Header().Get("Link")
+	if strings.Contains(link, `rel="next"`) {
+		t.Errorf("expected no next link on the last page, got %s", link)
+	}
+	if !strings.Contains(link, `rel="prev"`) {
+		t.Errorf("expected prev link, got %s", link)
+	}
+}
@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	previewMaxPages       = 2
+	previewMaxDetailItems = 10
+	previewMaxItems       = 50
+)
+
+// previewStrategies are the strategies that save through Pipeline.SaveRaw and
+// can therefore run in dry-run mode.
+var previewStrategies = map[string]bool{
+	"html_generic":   true,
+	"wordpress_rest": true,
+}
+
+// handlePreviewIngest runs a source config supplied in the request body
+// without saving anything, so selectors can be tuned without a redeploy.
+// The body uses the same field names as sources.yaml (JSON is valid YAML).
+func (s *Server) handlePreviewIngest(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unable to read request body"})
+	}
+
+	var config ingest.SourceConfig
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid source config: %v", err)})
+	}
+	if strings.TrimSpace(config.BaseURL) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "base_url is required"})
+	}
+	if !previewStrategies[config.Strategy] {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "preview supports html_generic and wordpress_rest strategies"})
+	}
+	for _, target := range append([]string{config.BaseURL}, config.Seeds...) {
+		if err := validateFetchURL(target); err != nil {
+			return c.JSON(fetchURLErrorStatus(err), map[string]string{"error": err.Error()})
+		}
+	}
+
+	if config.ID == "" {
+		config.ID = "preview"
+	}
+	if config.MaxPages <= 0 || config.MaxPages > previewMaxPages {
+		config.MaxPages = previewMaxPages
+	}
+	if config.Detail.MaxItems <= 0 || config.Detail.MaxItems > previewMaxDetailItems {
+		config.Detail.MaxItems = previewMaxDetailItems
+	}
+
+	strategy, err := ingest.GlobalStrategyFactory.Get(config.Strategy)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	var mu sync.Mutex
+	items := []ingest.RawOpportunity{}
+	found := 0
+	pipeline := ingest.NewPipeline(nil, nil, nil, nil)
+	pipeline.Saver = func(_ context.Context, raw ingest.RawOpportunity) (ingest.SaveOutcome, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		found++
+		if len(items) < previewMaxItems {
+			items = append(items, raw)
+		}
+		return ingest.OutcomeSaved, nil
+	}
+
+	stats, runErr := strategy.Run(c.Request().Context(), config, pipeline)
+
+	mu.Lock()
+	defer mu.Unlock()
+	resp := map[string]interface{}{
+		"source_id":   config.ID,
+		"strategy":    config.Strategy,
+		"max_pages":   config.MaxPages,
+		"items_found": found,
+		"errors":      stats.Errors,
+		"truncated":   found > len(items),
+		"items":       items,
+	}
+	if runErr != nil {
+		resp["error"] = runErr.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/ai"
+)
+
+func TestReadiness_AIOutageDegradesButStaysReady(t *testing.T) {
+	openUntil := time.Date(2026, 10, 18, 12, 0, 30, 0, time.UTC)
+	down := ai.Health{Status: "unavailable", ConsecutiveFailures: 5, OpenUntil: &openUntil, LastError: "connection refused"}
+
+	code, body := readiness(nil, down)
+	if code != http.StatusOK || body["status"] != "degraded" {
+		t.Fatalf("expected 200 degraded with AI down, got %d %v", code, body["status"])
+	}
+	aiBody := body["ai"].(map[string]interface{})
+	if aiBody["status"] != "unavailable" || aiBody["consecutive_failures"] != 5 {
+		t.Fatalf("expected AI state and failure count reported, got %v", body["ai"])
+	}
+
+	code, body = readiness(errors.New("dial tcp 10.0.3.7:5432: connection refused"), ai.Health{Status: "ok"})
+	if code != http.StatusServiceUnavailable || body["status"] != "unavailable" {
+		t.Fatalf("expected 503 without the database, got %d %v", code, body["status"])
+	}
+
+	// Errors can name internal hosts; they are logged, not returned.
+	raw, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, body = readiness(nil, down)
+	rawDown, _ := json.Marshal(body)
+	for _, leak := range []string{"10.0.3.7", "connection refused", "open_until", "last_error"} {
+		if strings.Contains(string(raw), leak) || strings.Contains(string(rawDown), leak) {
+			t.Errorf("readiness body exposes %q: %s %s", leak, raw, rawDown)
+		}
+	}
+
+	if code, body = readiness(nil, ai.Health{Status: "ok"}); code != http.StatusOK || body["status"] != "ok" {
+		t.Fatalf("expected 200 ok, got %d %v", code, body["status"])
+	}
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultRecentDays = 7
+	maxRecentDays     = 90
+	defaultRecentSize = 20
+	// recentCacheTTL is how long a "new this week" list is served from
+	// memory; ingestion adds rows a few times a day at most.
+	recentCacheTTL = 5 * time.Minute
+)
+
+// recentCache holds recent-opportunity lists by window and limit. The zero
+// value is ready to use.
+type recentCache struct {
+	mu      sync.Mutex
+	entries map[string]recentCacheEntry
+}
+
+type recentCacheEntry struct {
+	result  *db.ListResult
+	expires time.Time
+}
+
+func (rc *recentCache) get(key string, now time.Time) (*db.ListResult, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	entry, ok := rc.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (rc *recentCache) put(key string, result *db.ListResult, now time.Time) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.entries == nil {
+		rc.entries = make(map[string]recentCacheEntry)
+	}
+	// Keys are bounded by maxRecentDays x the list limit, so expired entries
+	// are just overwritten rather than swept.
+	rc.entries[key] = recentCacheEntry{result: result, expires: now.Add(recentCacheTTL)}
+}
+
+// handleRecentOpportunities lists open opportunities first ingested within
+// the last ?days= (default 7), newest first.
+func (s *Server) handleRecentOpportunities(c echo.Context) error {
+	days := defaultRecentDays
+	if raw := c.QueryParam("days"); raw != "" {
+		d, err := strconv.Atoi(raw)
+		if err != nil || d <= 0 || d > maxRecentDays {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("days must be between 1 and %d", maxRecentDays)})
+		}
+		days = d
+	}
+	limit := defaultRecentSize
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%d:%d", days, limit)
+	result, ok := s.recent.get(key, now)
+	if !ok {
+		since := now.AddDate(0, 0, -days)
+		var err error
+		result, err = s.Store.ListOpportunities(c.Request().Context(), db.ListParams{
+			Status:       "open",
+			CreatedAfter: &since,
+			SortBy:       "recent",
+			Limit:        limit,
+		})
+		if errors.Is(err, db.ErrQueryTimeout) {
+			return queryTimedOut(c)
+		}
+		if err != nil {
+			c.Logger().Errorf("Failed to list recent opportunities: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+		}
+		result.HasMore = len(result.Opportunities) < result.Total
+		s.recent.put(key, result, now)
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", int(recentCacheTTL/time.Second)))
+	return c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+)
+
+func TestRecentCache_ExpiresAfterTTL(t *testing.T) {
+	var rc recentCache
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	if _, ok := rc.get("7:20", now); ok {
+		t.Fatal("empty cache returned a hit")
+	}
+
+	want := &db.ListResult{Total: 3}
+	rc.put("7:20", want, now)
+	if got, ok := rc.get("7:20", now.Add(recentCacheTTL-time.Second)); !ok || got != want {
+		t.Fatalf("expected a hit within the TTL, got %v %v", got, ok)
+	}
+	if _, ok := rc.get("14:20", now); ok {
+		t.Error("a different window must not share the entry")
+	}
+	if _, ok := rc.get("7:20", now.Add(recentCacheTTL)); ok {
+		t.Error("entry still served after the TTL")
+	}
+}
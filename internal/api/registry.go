@@ -0,0 +1,32 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+)
+
+// handleReloadRegistry re-parses and validates the source registry and swaps
+// it in for later ingests. It never starts a crawl.
+func (s *Server) handleReloadRegistry(c echo.Context) error {
+	_, validation, err := ingest.ReloadRegistry()
+	if err != nil {
+		c.Logger().Errorf("Failed to reload registry: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+
+	invalid := 0
+	for _, v := range validation {
+		if !v.Valid {
+			invalid++
+		}
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Registry reloaded",
+		"total":   len(validation),
+		"invalid": invalid,
+		"sources": validation,
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRoutes_ByNumberNotShadowedByID(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.routes()
+
+	c := s.Echo.NewContext(nil, nil)
+	s.Echo.Router().Find(http.MethodGet, "/api/v1/opportunities/by-number/RFA-NS-27-001", c)
+	if c.Path() != "/api/v1/opportunities/by-number/:number" {
+		t.Fatalf("expected the by-number route, got %q", c.Path())
+	}
+	if c.Param("number") != "RFA-NS-27-001" {
+		t.Fatalf("expected number param, got %q", c.Param("number"))
+	}
+}
+
+func TestListParamsFromQuery_CFDA(t *testing.T) {
+	e := echo.New()
+	for query, want := range map[string]int{
+		"/api/v1/opportunities?cfda=93.242":                1,
+		"/api/v1/opportunities?cfda=93.242,+93.853,47.041": 3,
+		"/api/v1/opportunities":                            0,
+	} {
+		req := httptest.NewRequest(http.MethodGet, query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		params, err := listParamsFromQuery(c)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := params.CFDA; len(got) != want {
+			t.Fatalf("%s: expected %d codes, got %v", query, want, got)
+		}
+	}
+}
+
+func TestListParamsFromQuery_ExplainIsOptIn(t *testing.T) {
+	e := echo.New()
+	for query, want := range map[string]bool{
+		"/api/v1/opportunities?q=soil&explain=true": true,
+		"/api/v1/opportunities?q=soil&explain=1":    false,
+		"/api/v1/opportunities?q=soil":              false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		params, err := listParamsFromQuery(c)
+		if err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		if got := params.Explain; got != want {
+			t.Fatalf("%s: expected explain=%v, got %v", query, want, got)
+		}
+	}
+}
+
+func TestListParamsFromQuery_RejectsMalformedOpenDates(t *testing.T) {
+	e := echo.New()
+	for query, wantErr := range map[string]bool{
+		"/api/v1/opportunities?open_after=2026-10-01&open_before=2026-11-01T00:00:00Z": false,
+		"/api/v1/opportunities?open_after=last+week":                                   true,
+		"/api/v1/opportunities?open_before=2026-13-01":                                 true,
+	} {
+		req := httptest.NewRequest(http.MethodGet, query, nil)
+		c := e.NewContext(req, httptest.NewRecorder())
+		if _, err := listParamsFromQuery(c); (err != nil) != wantErr {
+			t.Fatalf("%s: expected error=%v, got %v", query, wantErr, err)
+		}
+	}
+}
+
+func TestParseMaxAgeParam(t *testing.T) {
+	for raw, want := range map[string]time.Duration{"": 0, "36h": 36 * time.Hour, "7d": 7 * 24 * time.Hour} {
+		if got, err := parseMaxAgeParam(raw); err != nil || got != want {
+			t.Fatalf("%q: expected %v, got %v (%v)", raw, want, got, err)
+		}
+	}
+	for _, raw := range []string{"7days", "-1d", "0d", "-2h", "0", "soon"} {
+		if _, err := parseMaxAgeParam(raw); err == nil {
+			t.Fatalf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestParseMinConfidenceParam(t *testing.T) {
+	for raw, want := range map[string]float64{"": 0, "0.8": 0.8, "1": 1} {
+		if got, err := parseMinConfidenceParam(raw); err != nil || got != want {
+			t.Fatalf("%q: expected %v, got %v (%v)", raw, want, got, err)
+		}
+	}
+	for _, raw := range []string{"80", "0", "-0.2", "abc", "NaN"} {
+		if _, err := parseMinConfidenceParam(raw); err == nil {
+			t.Fatalf("%q: expected an error", raw)
+		}
+	}
+}
+
+func TestFormatPoolMetrics(t *testing.T) {
+	out := formatPoolMetrics(db.PoolStats{MaxConns: 10, AcquiredConns: 3, EmptyAcquireCount: 7})
+	for _, want := range []string{
+		"# TYPE grantfinder_db_pool_max_conns gauge\ngrantfinder_db_pool_max_conns 10\n",
+		"grantfinder_db_pool_acquired_conns 3\n",
+		"# TYPE grantfinder_db_pool_empty_acquire_total counter\ngrantfinder_db_pool_empty_acquire_total 7\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestQueryTimedOut(t *testing.T) {
+	e := echo.New()
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/opportunities", nil), rec)
+	if err := queryTimedOut(c); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != queryTimeoutRetryAfter {
+		t.Errorf("Retry-After = %q, want %q", got, queryTimeoutRetryAfter)
+	}
+}
+
+func TestHandlePurgeSource_RequiresConfirmation(t *testing.T) {
+	e := echo.New()
+	s := &Server{Echo: e}
+	for _, tc := range []struct{ domain, body string }{
+		{"grants.gov", `{}`},
+		{"grants.gov", `{"confirm": "grants.go"}`},
+		{"%.gov", `{"confirm": "%.gov"}`},
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/sources/x", strings.NewReader(tc.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("domain")
+		c.SetParamValues(tc.domain)
+		// No DB is configured, so reaching the purge would panic.
+		if err := s.handlePurgeSource(c); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: status = %d, want 400", tc.domain, tc.body, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/david/grant-finder/internal/auth"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	maxSavedTags     = 10
+	maxSavedTagChars = 32
+	maxSavedNoteLen  = 4000
+)
+
+type savedNoteRequest struct {
+	Tags []string `json:"tags"`
+	Note string   `json:"note"`
+}
+
+// handleSetSavedNote replaces the caller's tags and note on a saved
+// opportunity ("submitted", "shortlisted"), saving it if needed.
+func (s *Server) handleSetSavedNote(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
+	}
+
+	oppID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid opportunity ID"})
+	}
+
+	var req savedNoteRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	tags, err := normalizeSavedTags(req.Tags)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxSavedNoteLen {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("note must be at most %d characters", maxSavedNoteLen)})
+	}
+
+	err = s.AuthService.SetSavedNote(ctx, userID, oppID, tags, note)
+	if errors.Is(err, auth.ErrOpportunityNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to save note"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{"tags": tags, "note": note})
+}
+
+// normalizeSavedTags trims and lowercases tags and drops blanks and
+// duplicates, keeping the caller's order. It rejects more than maxSavedTags
+// tags or one longer than maxSavedTagChars.
+func normalizeSavedTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		t = strings.ToLower(strings.Join(strings.Fields(t), " "))
+		if t == "" || seen[t] {
+			continue
+		}
+		if utf8.RuneCountInString(t) > maxSavedTagChars {
+			return nil, fmt.Errorf("tag %q is longer than %d characters", t, maxSavedTagChars)
+		}
+		seen[t] = true
+		tags = append(tags, t)
+	}
+	if len(tags) > maxSavedTags {
+		return nil, fmt.Errorf("at most %d tags are allowed", maxSavedTags)
+	}
+	return tags, nil
+}
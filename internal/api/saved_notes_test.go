@@ -0,0 +1,33 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSavedTags(t *testing.T) {
+	got, err := normalizeSavedTags([]string{" Submitted ", "shortlisted", "SUBMITTED", "", "follow  up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[submitted shortlisted follow up]" {
+		t.Errorf("got %q", got)
+	}
+
+	if _, err := normalizeSavedTags([]string{strings.Repeat("x", maxSavedTagChars+1)}); err == nil {
+		t.Error("expected an error for an overlong tag")
+	}
+
+	many := make([]string, maxSavedTags+1)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag%d", i)
+	}
+	if _, err := normalizeSavedTags(many); err == nil {
+		t.Errorf("expected an error for %d tags", len(many))
+	}
+	// Duplicates don't count towards the limit.
+	if _, err := normalizeSavedTags(append(many[:maxSavedTags], "tag0")); err != nil {
+		t.Errorf("duplicate tag counted towards the limit: %v", err)
+	}
+}
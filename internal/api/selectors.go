@@ -0,0 +1,84 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// selectorTestMaxBytes caps how much of the page is read; list pages
+	// worth debugging are far smaller.
+	selectorTestMaxBytes   = 5 << 20
+	selectorTestMaxSamples = 5
+)
+
+// selectorTestRequest names its fields as sources.yaml does, so a source's
+// selectors block can be pasted in as is.
+type selectorTestRequest struct {
+	URL       string                `yaml:"url"`
+	Selectors ingest.SelectorConfig `yaml:"selectors"`
+}
+
+// handleTestSelectors fetches a live list page and reports, per configured
+// selector, how many elements matched along with the first few items as
+// html_generic would read them. Nothing is saved.
+func (s *Server) handleTestSelectors(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unable to read request body"})
+	}
+
+	var req selectorTestRequest
+	if err := yaml.Unmarshal(body, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("Invalid request: %v", err)})
+	}
+	req.URL = strings.TrimSpace(req.URL)
+	if req.URL == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "url is required"})
+	}
+	if strings.TrimSpace(req.Selectors.Container) == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "selectors.container is required"})
+	}
+	if err := validateFetchURL(req.URL); err != nil {
+		return c.JSON(fetchURLErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+
+	doc, err := ingest.NewHTTPFetcher().Fetch(c.Request().Context(), req.URL)
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	defer doc.Body.Close()
+	page, err := io.ReadAll(io.LimitReader(doc.Body, selectorTestMaxBytes+1))
+	if err != nil {
+		return c.JSON(http.StatusBadGateway, map[string]string{"error": err.Error()})
+	}
+	truncated := len(page) > selectorTestMaxBytes
+	if truncated {
+		page = page[:selectorTestMaxBytes]
+	}
+	parsed, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "Unable to parse page"})
+	}
+
+	check := ingest.CheckSelectors(parsed, req.URL, req.Selectors, selectorTestMaxSamples)
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"url":            req.URL,
+		"page_bytes":     len(page),
+		"page_truncated": truncated,
+		"items":          check.Items,
+		"selectors":      check.Selectors,
+		"samples":        check.Samples,
+	})
+}
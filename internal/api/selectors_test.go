@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestTestSelectors_RejectsBadRequests(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.POST("/admin/selectors/test", s.handleTestSelectors)
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"selectors": {"container": "div.call"}}`, http.StatusBadRequest},
+		{`{"url": "https://grants.example.org/calls"}`, http.StatusBadRequest},
+		{`{"url": "ftp://grants.example.org/calls", "selectors": {"container": "div.call"}}`, http.StatusBadRequest},
+		{`{"url": "http://localhost:8080/admin", "selectors": {"container": "div.call"}}`, http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodPost, "/admin/selectors/test", strings.NewReader(tc.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		s.Echo.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d: %s", tc.body, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+}
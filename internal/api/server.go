@@ -4,11 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -19,7 +19,7 @@ import (
 	"github.com/david/grant-finder/internal/auth"
 	"github.com/david/grant-finder/internal/db"
 	"github.com/david/grant-finder/internal/ingest"
-	"github.com/david/grant-finder/internal/models"
+	"github.com/david/grant-finder/internal/webhook"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/labstack/echo/v4"
@@ -32,10 +32,14 @@ type Server struct {
 	Echo        *echo.Echo
 	DB          *pgxpool.Pool
 	AI          *ai.OllamaClient
+	Webhooks    *webhook.Dispatcher
 
 	// Background job tracking
 	jobMu      sync.Mutex
 	runningJob *backgroundJob
+
+	// recent caches GET /opportunities/recent briefly.
+	recent recentCache
 }
 
 type backgroundJob struct {
@@ -46,6 +50,11 @@ type backgroundJob struct {
 	Result    any                `json:"result,omitempty"`
 	Error     string             `json:"error,omitempty"`
 	Cancel    context.CancelFunc `json:"-"`
+
+	// Live progress for GET /admin/job/:id/stream; guarded by Server.jobMu.
+	progress    *ingest.Progress
+	subscribers map[chan ingest.Progress]struct{}
+	done        chan struct{}
 }
 
 var (
@@ -59,21 +68,16 @@ func NewServer(pool *pgxpool.Pool) *Server {
 	e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
 
-	// CORS: allow frontend origins from env or default to localhost
-	allowedOrigins := []string{"http://localhost:4200"}
-	if extra := os.Getenv("CORS_ORIGINS"); extra != "" {
-		for _, o := range strings.Split(extra, ",") {
-			o = strings.TrimSpace(o)
-			if o != "" {
-				allowedOrigins = append(allowedOrigins, o)
-			}
-		}
-	}
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: allowedOrigins,
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Admin-Secret"},
-	}))
+	// Bound request bodies and handler time so a slow or oversized client
+	// can't tie up a worker.
+	limits := loadRequestLimits()
+	e.Server.ReadHeaderTimeout = 10 * time.Second
+	e.Use(bodyLimitMiddleware(limits.MaxBodyBytes))
+	e.Use(timeoutMiddleware(limits))
+	e.Use(compressionMiddleware())
+
+	// CORS policy is env-driven; see loadCORSConfig.
+	e.Use(middleware.CORSWithConfig(loadCORSConfig()))
 
 	store := db.NewStore(pool)
 	authService := auth.NewService(pool)
@@ -83,7 +87,11 @@ func NewServer(pool *pgxpool.Pool) *Server {
 	if ollamaHost == "" {
 		ollamaHost = "http://localhost:11434"
 	}
-	aiClient := ai.NewOllamaClient(ollamaHost, "", "qwen2.5:14b")
+	aiClient := ai.NewOllamaClient(ollamaHost, ai.ModelConfigFromEnv())
+
+	// Webhook deliveries run for the life of the process.
+	webhooks := webhook.NewDispatcher(pool, ingest.NewSafeHTTPClient(15*time.Second))
+	webhooks.Start(context.Background())
 
 	s := &Server{
 		DB:          pool,
@@ -91,6 +99,7 @@ func NewServer(pool *pgxpool.Pool) *Server {
 		AuthService: authService,
 		Echo:        e,
 		AI:          aiClient,
+		Webhooks:    webhooks,
 	}
 
 	s.routes()
@@ -99,13 +108,20 @@ func NewServer(pool *pgxpool.Pool) *Server {
 
 func (s *Server) routes() {
 	s.Echo.GET("/health", s.handleHealth)
+	s.Echo.GET("/health/ready", s.handleReady)
 	api := s.Echo.Group("/api/v1")
 	api.GET("/opportunities", s.handleListOpportunities)
+	api.GET("/opportunities/count", s.handleCountOpportunities)
+	api.GET("/opportunities/recent", s.handleRecentOpportunities)
+	api.GET("/opportunities/by-number/:number", s.handleGetOpportunityByNumber)
 	api.GET("/opportunities/:id", s.handleGetOpportunity)
+	api.GET("/opportunities/:id/calendar.ics", s.handleOpportunityCalendar)
 	api.GET("/sources", s.handleGetSources)
 	// Public Stats
 	api.GET("/stats", s.handleGetStats)
 	api.GET("/aggregations", s.handleGetAggregations)
+	api.GET("/search", s.handleSearch)
+	api.GET("/cfda", s.handleGetCFDA)
 
 	// Admin Routes (Ingest & Seed)
 	admin := api.Group("")
@@ -118,11 +134,30 @@ func (s *Server) routes() {
 	admin.POST("/ingest/ukri", s.handleIngestUKRI)
 	admin.POST("/ingest/source/:id", s.handleIngestSourceByID)
 	admin.POST("/ingest/all", s.handleIngestAll)
+	admin.POST("/admin/ingest/preview", s.handlePreviewIngest)
+	admin.POST("/admin/selectors/test", s.handleTestSelectors)
+	admin.POST("/admin/registry/reload", s.handleReloadRegistry)
 	admin.POST("/seed", s.handleSeed)
 	admin.POST("/admin/refine-data", s.handleRefineData)
 	admin.POST("/admin/recompute-status", s.handleRecomputeStatus)
+	admin.POST("/admin/status/preview", s.handlePreviewStatus)
+	admin.POST("/admin/backfill-embeddings", s.handleBackfillEmbeddings)
+	admin.POST("/admin/archive-stale", s.handleArchiveStale)
+	admin.POST("/admin/backfill-funder-types", s.handleBackfillFunderTypes)
+	admin.POST("/admin/backfill-regions", s.handleBackfillRegions)
+	admin.DELETE("/admin/sources/:domain", s.handlePurgeSource)
+	admin.POST("/admin/check-links", s.handleCheckLinks)
+	admin.GET("/admin/webhooks", s.handleListWebhooks)
+	admin.POST("/admin/webhooks", s.handleCreateWebhook)
+	admin.DELETE("/admin/webhooks/:id", s.handleDeleteWebhook)
+	admin.GET("/admin/webhooks/:id/deliveries", s.handleListWebhookDeliveries)
 	admin.GET("/admin/job/:id", s.handleJobStatus)
+	admin.GET("/admin/job/:id/stream", s.handleJobStream)
+	admin.POST("/admin/job/:id/cancel", s.handleCancelJob)
 	admin.POST("/admin/enrich-opportunities", s.handleEnrichOpportunities)
+	admin.GET("/admin/db/pool", s.handlePoolStats)
+	admin.GET("/admin/metrics", s.handleMetrics)
+	admin.GET("/admin/opportunities/status-changed", s.handleStatusChanges)
 
 	// Auth Routes
 	api.POST("/auth/signup", s.handleSignup)
@@ -134,11 +169,16 @@ func (s *Server) routes() {
 	saved.POST("/:id", s.handleSaveOpportunity)
 	saved.DELETE("/:id", s.handleUnsaveOpportunity)
 	saved.GET("", s.handleGetSavedOpportunities)
+	saved.PUT("/:id/note", s.handleSetSavedNote)
+	saved.GET("/calendar.ics", s.handleSavedCalendar)
 }
 
 func (s *Server) handleSignup(c echo.Context) error {
 	var req auth.SignupRequest
 	if err := c.Bind(&req); err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 	}
 	// TODO: Add strict validation here
@@ -157,6 +197,9 @@ func (s *Server) handleSignup(c echo.Context) error {
 func (s *Server) handleLogin(c echo.Context) error {
 	var req auth.LoginRequest
 	if err := c.Bind(&req); err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 	}
 
@@ -172,8 +215,49 @@ func (s *Server) handleLogin(c echo.Context) error {
 }
 
 func (s *Server) handleGetAggregations(c echo.Context) error {
+	params, err := aggregationParamsFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	aggs, err := s.Store.GetAggregations(c.Request().Context(), params)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, aggs)
+}
+
+// handleGetCFDA lists CFDA/ALN numbers with counts under the facet filters;
+// ?prefix= narrows to a program family and ?limit= caps the list (default 100).
+func (s *Server) handleGetCFDA(c echo.Context) error {
+	limit := 100
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 1000 {
+		limit = l
+	}
+	params, err := aggregationParamsFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	facet, err := s.Store.GetCFDAFacet(c.Request().Context(), params, c.QueryParam("prefix"), limit)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to list CFDA numbers: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{"cfda": facet})
+}
+
+// aggregationParamsFromQuery parses the facet filter query params shared by
+// the aggregation and CFDA endpoints.
+func aggregationParamsFromQuery(c echo.Context) (db.AggregationParams, error) {
 	params := db.AggregationParams{
-		Status: c.QueryParam("status"),
+		Query:      c.QueryParam("q"),
+		SearchMode: searchModeFromQuery(c),
+		Status:     c.QueryParam("status"),
 	}
 	if v := c.QueryParam("region"); v != "" {
 		params.Region = splitCSV(v)
@@ -187,11 +271,22 @@ func (s *Server) handleGetAggregations(c echo.Context) error {
 	if v := c.QueryParam("agency_name"); v != "" {
 		params.AgencyName = splitCSV(v)
 	}
-	aggs, err := s.Store.GetAggregations(c.Request().Context(), params)
+	if v := c.QueryParam("type"); v != "" {
+		params.Type = splitCSV(v)
+	}
+	if v := c.QueryParam("doc_type"); v != "" {
+		params.DocType = splitCSV(v)
+	}
+	if v := c.QueryParam("cfda"); v != "" {
+		params.CFDA = splitCSV(v)
+	}
+	params.ExcludeTenders = c.QueryParam("exclude_tenders") == "true"
+	minConfidence, err := parseMinConfidenceParam(c.QueryParam("min_confidence"))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return db.AggregationParams{}, err
 	}
-	return c.JSON(http.StatusOK, aggs)
+	params.MinConfidence = minConfidence
+	return params, nil
 }
 
 // splitCSV splits a comma-separated query parameter into trimmed non-empty strings.
@@ -210,7 +305,148 @@ func (s *Server) handleHealth(c echo.Context) error {
 	return c.String(http.StatusOK, "OK")
 }
 
+// handleReady answers GET /health/ready. The database is required; the AI
+// backend is not, since search falls back to keywords without it, so an
+// open AI circuit reports "degraded" but stays ready. The endpoint is public,
+// so it answers with states and counts only; the errors go to the log.
+func (s *Server) handleReady(c echo.Context) error {
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 2*time.Second)
+	defer cancel()
+	dbErr, aiHealth := s.DB.Ping(ctx), s.AI.Health()
+	if dbErr != nil {
+		log.Printf("readiness: database: %v", dbErr)
+	}
+	if aiHealth.LastError != "" {
+		log.Printf("readiness: ai %s after %d failures: %s", aiHealth.Status, aiHealth.ConsecutiveFailures, aiHealth.LastError)
+	}
+	code, body := readiness(dbErr, aiHealth)
+	return c.JSON(code, body)
+}
+
+func readiness(dbErr error, aiHealth ai.Health) (int, map[string]interface{}) {
+	code, status := http.StatusOK, "ok"
+	database := "ok"
+	if dbErr != nil {
+		code, status, database = http.StatusServiceUnavailable, "unavailable", "unavailable"
+	} else if aiHealth.Status != "ok" {
+		status = "degraded"
+	}
+	return code, map[string]interface{}{
+		"status":   status,
+		"database": map[string]string{"status": database},
+		"ai": map[string]interface{}{
+			"status":               aiHealth.Status,
+			"consecutive_failures": aiHealth.ConsecutiveFailures,
+		},
+	}
+}
+
 func (s *Server) handleListOpportunities(c echo.Context) error {
+	params, err := listParamsFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	s.applyQueryEmbedding(c, &params)
+
+	result, err := s.Store.ListOpportunities(c.Request().Context(), params)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to list opportunities: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+	}
+
+	result.HasMore = result.Offset+len(result.Opportunities) < result.Total
+	setPaginationLinks(c, result.Total, result.Limit, result.Offset)
+
+	if fields := parseFieldsParam(c.QueryParam("fields")); fields != nil {
+		projected, err := projectListResult(result, fields)
+		if err != nil {
+			c.Logger().Errorf("Failed to project opportunity fields: %v", err)
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+		}
+		return c.JSON(http.StatusOK, projected)
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// applyQueryEmbedding adds the semantic-search embedding for params.Query.
+// When the AI call fails the search falls back to keyword ranking.
+func (s *Server) applyQueryEmbedding(c echo.Context, params *db.ListParams) {
+	if params.Query == "" {
+		return
+	}
+	// While the AI circuit is open, go straight to keyword search rather
+	// than wait out another failing call.
+	if !s.AI.Available() {
+		return
+	}
+	// Create a context with timeout for AI operation
+	aiCtx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	vec, err := s.AI.GenerateEmbedding(aiCtx, params.Query)
+	if err == nil {
+		err = s.AI.CheckEmbedding(vec)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to generate query embedding: %v", err)
+		// Apply fallback: proceed with keyword search (QueryEmbedding remains nil)
+		return
+	}
+	params.QueryEmbedding = vec
+}
+
+// handleSearch answers GET /search: one page of opportunities plus the
+// sidebar facet counts for the same filters, read from one snapshot. It
+// takes the /opportunities query params and saves the UI a second request
+// to /aggregations.
+func (s *Server) handleSearch(c echo.Context) error {
+	params, err := listParamsFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	s.applyQueryEmbedding(c, &params)
+
+	result, err := s.Store.Search(c.Request().Context(), params)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to search opportunities: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+	}
+
+	result.HasMore = result.Offset+len(result.Opportunities) < result.Total
+	setPaginationLinks(c, result.Total, result.Limit, result.Offset)
+	return c.JSON(http.StatusOK, result)
+}
+
+// handleCountOpportunities returns only the total for a filter set, skipping
+// the row fetch and the query embedding.
+func (s *Server) handleCountOpportunities(c echo.Context) error {
+	params, err := listParamsFromQuery(c)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+	total, err := s.Store.CountOpportunities(c.Request().Context(), params)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to count opportunities: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+	}
+
+	return c.JSON(http.StatusOK, map[string]int{"total": total})
+}
+
+// listParamsFromQuery parses the shared opportunity filter query params used by
+// the list and count endpoints. Malformed open_after/open_before dates are an
+// error rather than a silently dropped filter.
+func listParamsFromQuery(c echo.Context) (db.ListParams, error) {
 	q := c.QueryParam("q")
 	source := c.QueryParam("source")
 	region := c.QueryParam("region")
@@ -219,6 +455,9 @@ func (s *Server) handleListOpportunities(c echo.Context) error {
 	country := c.QueryParam("country")
 	agencyCode := c.QueryParam("agency_code")
 	agencyName := c.QueryParam("agency_name")
+	oppType := c.QueryParam("type")
+	docType := c.QueryParam("doc_type")
+	cfda := c.QueryParam("cfda")
 	limitStr := c.QueryParam("limit")
 	offsetStr := c.QueryParam("offset")
 	minAmountStr := c.QueryParam("min_amount")
@@ -229,6 +468,7 @@ func (s *Server) handleListOpportunities(c echo.Context) error {
 	eligibility := c.QueryParams()["eligibility"]
 	sortBy := c.QueryParam("sort")
 	status := c.QueryParam("status")
+	excludeTenders := c.QueryParam("exclude_tenders") == "true"
 
 	limit := 20
 	offset := 0
@@ -256,31 +496,38 @@ func (s *Server) handleListOpportunities(c echo.Context) error {
 		isRolling = &val
 	}
 
-	// Generate embedding for semantic search
-	var queryEmbedding []float32
-	if q != "" {
-		// Create a context with timeout for AI operation
-		aiCtx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
-		defer cancel()
-
-		vec, err := s.AI.GenerateEmbedding(aiCtx, q)
-		if err != nil {
-			c.Logger().Errorf("Failed to generate query embedding: %v", err)
-			// Apply fallback: proceed with keyword search (queryEmbedding remains nil)
-		} else {
-			queryEmbedding = vec
+	var openAfter, openBefore *time.Time
+	if raw := c.QueryParam("open_after"); raw != "" {
+		if openAfter = parseDateParam(raw); openAfter == nil {
+			return db.ListParams{}, fmt.Errorf("open_after must be an RFC3339 timestamp or YYYY-MM-DD date")
 		}
 	}
+	if raw := c.QueryParam("open_before"); raw != "" {
+		if openBefore = parseDateParam(raw); openBefore == nil {
+			return db.ListParams{}, fmt.Errorf("open_before must be an RFC3339 timestamp or YYYY-MM-DD date")
+		}
+	}
+	maxAge, err := parseMaxAgeParam(c.QueryParam("max_age"))
+	if err != nil {
+		return db.ListParams{}, err
+	}
+	minConfidence, err := parseMinConfidenceParam(c.QueryParam("min_confidence"))
+	if err != nil {
+		return db.ListParams{}, err
+	}
 
-	result, err := s.Store.ListOpportunities(c.Request().Context(), db.ListParams{
+	return db.ListParams{
 		Query:          q,
-		QueryEmbedding: queryEmbedding,
+		SearchMode:     searchModeFromQuery(c),
 		Source:         source,
 		Region:         splitCSV(region),
 		FunderType:     splitCSV(funderType),
 		Country:        splitCSV(country),
 		AgencyCode:     agencyCode,
 		AgencyName:     splitCSV(agencyName),
+		Type:           splitCSV(oppType),
+		DocType:        splitCSV(docType),
+		CFDA:           splitCSV(cfda),
 		MinAmount:      minAmount,
 		MaxAmount:      maxAmount,
 		DeadlineDays:   deadlineDays,
@@ -291,17 +538,75 @@ func (s *Server) handleListOpportunities(c echo.Context) error {
 		Eligibility:    eligibility,
 		SortBy:         sortBy,
 		Status:         status,
-	})
-	if err != nil {
-		c.Logger().Errorf("Failed to list opportunities: %v", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+		OpenAfter:      openAfter,
+		OpenBefore:     openBefore,
+		MaxAge:         maxAge,
+		ExcludeTenders: excludeTenders,
+		MinConfidence:  minConfidence,
+		HideDeadLinks:  c.QueryParam("hide_dead_links") == "true",
+		Explain:        c.QueryParam("explain") == "true",
+	}, nil
+}
+
+// searchModeFromQuery reads ?search_mode=; anything but "boolean" is a plain
+// search.
+func searchModeFromQuery(c echo.Context) string {
+	if c.QueryParam("search_mode") == db.SearchModeBoolean {
+		return db.SearchModeBoolean
 	}
+	return ""
+}
 
-	return c.JSON(http.StatusOK, result)
+// parseDateParam accepts RFC3339 timestamps or plain YYYY-MM-DD dates (UTC).
+func parseDateParam(raw string) *time.Time {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// parseMinConfidenceParam accepts a status_confidence threshold in (0, 1].
+// Empty means no filter; anything else is an error.
+func parseMinConfidenceParam(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || !(v > 0 && v <= 1) {
+		return 0, fmt.Errorf("min_confidence must be a number in (0, 1]")
+	}
+	return v, nil
+}
+
+// parseMaxAgeParam accepts positive Go durations ("36h") or whole days
+// ("7d"). Empty means no filter; anything else is an error.
+func parseMaxAgeParam(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	} else if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+		return d, nil
+	}
+	return 0, fmt.Errorf("max_age must be a positive duration such as 36h or 7d")
 }
 
 func (s *Server) handleGetSources(c echo.Context) error {
 	sources, err := s.Store.GetSources(c.Request().Context())
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -310,6 +615,9 @@ func (s *Server) handleGetSources(c echo.Context) error {
 
 func (s *Server) handleGetStats(c echo.Context) error {
 	stats, err := s.Store.GetStats(c.Request().Context())
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
 	}
@@ -319,46 +627,56 @@ func (s *Server) handleGetStats(c echo.Context) error {
 func (srv *Server) handleGetOpportunity(c echo.Context) error {
 	id := c.Param("id")
 	opp, err := srv.Store.GetOpportunity(c.Request().Context(), id)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
 	}
+	if fields := parseFieldsParam(c.QueryParam("fields")); fields != nil {
+		projected, err := projectOpportunity(*opp, fields)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+		}
+		return c.JSON(http.StatusOK, projected)
+	}
 	return c.JSON(http.StatusOK, opp)
 }
 
-func (s *Server) handleTriggerIngest(c echo.Context) error {
-	urlStr := c.QueryParam("url")
-	if urlStr == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "url param required"})
+// handleGetOpportunityByNumber looks opportunities up by their official
+// number. The response is always a list, most recent first, since several
+// sources can list the same number. Echo has already unescaped the param.
+func (srv *Server) handleGetOpportunityByNumber(c echo.Context) error {
+	number := strings.TrimSpace(c.Param("number"))
+	if number == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "number is required"})
 	}
-
-	u, err := url.Parse(urlStr)
-	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid URL scheme"})
+	opps, err := srv.Store.GetOpportunityByNumber(c.Request().Context(), number)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
 	}
-	host := strings.ToLower(u.Hostname())
-	if host == "" {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "URL host is required"})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
 	}
-	if host == "localhost" || host == "127.0.0.1" || host == "::1" || strings.HasSuffix(host, ".local") {
-		return c.JSON(http.StatusForbidden, map[string]string{"error": "Internal network access forbidden"})
+	if len(opps) == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Not found"})
 	}
+	return c.JSON(http.StatusOK, opps)
+}
 
-	ips, err := net.LookupIP(host)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unable to resolve URL host"})
-	}
-	if len(ips) == 0 {
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "URL host resolved to no addresses"})
+func (s *Server) handleTriggerIngest(c echo.Context) error {
+	urlStr := c.QueryParam("url")
+	if urlStr == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "url param required"})
 	}
-	for _, ip := range ips {
-		if isPrivateOrSpecialIP(ip) {
-			return c.JSON(http.StatusForbidden, map[string]string{"error": "Internal network access forbidden"})
-		}
+
+	if err := validateFetchURL(urlStr); err != nil {
+		return c.JSON(fetchURLErrorStatus(err), map[string]string{"error": err.Error()})
 	}
 
 	fetcher := ingest.NewHTTPFetcher()
-	parser := ingest.NewOllamaParser("qwen2.5:14b")
-	pipeline := ingest.NewPipeline(s.DB, fetcher, parser, s.AI)
+	parser := ingest.NewOllamaParser(s.AI.GenModel)
+	pipeline := s.newPipeline(fetcher, parser)
 
 	// Run synchronously for MVP debugging
 	if err := pipeline.Run(c.Request().Context(), urlStr); err != nil {
@@ -397,7 +715,7 @@ func (s *Server) handleIngestSourceByID(c echo.Context) error {
 }
 
 func (s *Server) handleIngestAll(c echo.Context) error {
-	pipeline := ingest.NewPipeline(s.DB, nil, nil, s.AI)
+	pipeline := s.newPipeline(nil, nil)
 	ctx := c.Request().Context()
 
 	results, err := pipeline.IngestAll(ctx)
@@ -413,7 +731,7 @@ func (s *Server) handleIngestAll(c echo.Context) error {
 
 // Helper to run a specific source from registry
 func (s *Server) runIngestionForSource(c echo.Context, sourceID string) error {
-	pipeline := ingest.NewPipeline(s.DB, nil, nil, s.AI)
+	pipeline := s.newPipeline(nil, nil)
 
 	stats, err := pipeline.IngestSource(c.Request().Context(), sourceID)
 	if err != nil {
@@ -426,43 +744,50 @@ func (s *Server) runIngestionForSource(c echo.Context, sourceID string) error {
 	})
 }
 
+// handleRefineData re-normalizes every row as a background job, ?batch_size=
+// rows at a time (default 200).
 func (s *Server) handleRefineData(c echo.Context) error {
-	pipeline := ingest.NewPipeline(s.DB, nil, nil, s.AI)
-	ctx := c.Request().Context()
-
-	updated, err := pipeline.RefineAllData(ctx)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	batchSize := 200
+	if raw := strings.TrimSpace(c.QueryParam("batch_size")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 5000 {
+			batchSize = parsed
+		}
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Data refinement complete",
-		"updated": updated,
+	return s.startJob(c, "refine", 60*time.Minute, func(ctx context.Context, report ingest.ProgressFunc) (any, error) {
+		pipeline := s.newPipeline(nil, nil)
+
+		stats, err := pipeline.RefineAllData(ctx, batchSize, report)
+		if err != nil {
+			return stats, err
+		}
+		return map[string]interface{}{
+			"stats":           stats,
+			"batch_size_used": batchSize,
+		}, nil
 	})
 }
 
-func (s *Server) handleRecomputeStatus(c echo.Context) error {
+// startJob runs fn as the server's single background job and answers 202
+// with a poll URL, or 409 if another job is still running. The job context is
+// detached from the request and bounded by timeout; it is cancelled via
+// POST /admin/job/:id/cancel. Progress passed to the report func is streamed
+// to GET /admin/job/:id/stream subscribers.
+func (s *Server) startJob(c echo.Context, name string, timeout time.Duration, fn func(ctx context.Context, report ingest.ProgressFunc) (any, error)) error {
 	s.jobMu.Lock()
 	if s.runningJob != nil && s.runningJob.Status == "running" {
 		job := s.runningJob
 		s.jobMu.Unlock()
 		return c.JSON(http.StatusConflict, map[string]interface{}{
-			"error":  "A recompute job is already running",
+			"error":  "A background job is already running",
 			"job_id": job.ID,
 		})
 	}
 
-	batchSize := 500
-	if raw := strings.TrimSpace(c.QueryParam("batch_size")); raw != "" {
-		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 5000 {
-			batchSize = parsed
-		}
-	}
-
 	// context.WithoutCancel detaches from HTTP lifecycle but preserves
 	// trace values. We add our own timeout for safety.
 	jobCtx, jobCancel := context.WithTimeout(
-		context.WithoutCancel(c.Request().Context()), 30*time.Minute,
+		context.WithoutCancel(c.Request().Context()), timeout,
 	)
 
 	jobID := uuid.New().String()[:8]
@@ -471,6 +796,9 @@ func (s *Server) handleRecomputeStatus(c echo.Context) error {
 		Status:    "running",
 		StartedAt: time.Now(),
 		Cancel:    jobCancel,
+
+		subscribers: map[chan ingest.Progress]struct{}{},
+		done:        make(chan struct{}),
 	}
 	s.runningJob = job
 	s.jobMu.Unlock()
@@ -478,38 +806,254 @@ func (s *Server) handleRecomputeStatus(c echo.Context) error {
 	// Run in background goroutine — returns 202 immediately.
 	go func() {
 		defer jobCancel()
-		pipeline := ingest.NewPipeline(s.DB, nil, nil, s.AI)
+		result, err := fn(jobCtx, func(p ingest.Progress) { s.publishJobProgress(job, p) })
 
-		statusCounts, statusUpdated, err := pipeline.RecomputeStatuses(jobCtx, batchSize)
+		s.jobMu.Lock()
+		job.EndedAt = time.Now()
+		job.Result = result
 		if err != nil {
-			s.jobMu.Lock()
 			job.Status = "failed"
 			job.Error = err.Error()
-			job.EndedAt = time.Now()
-			s.jobMu.Unlock()
-			log.Printf("[recompute-job %s] failed: %v", jobID, err)
+		} else {
+			job.Status = "completed"
+		}
+		close(job.done)
+		s.jobMu.Unlock()
+
+		if err != nil {
+			log.Printf("[%s-job %s] failed: %v", name, jobID, err)
 			return
 		}
+		log.Printf("[%s-job %s] completed", name, jobID)
+	}()
 
-		arraysUpdated, _ := pipeline.BackfillCleanArrays(jobCtx)
+	return c.JSON(http.StatusAccepted, map[string]interface{}{
+		"message": fmt.Sprintf("%s job started", name),
+		"job_id":  jobID,
+		"poll":    fmt.Sprintf("/api/v1/admin/job/%s", jobID),
+		"stream":  fmt.Sprintf("/api/v1/admin/job/%s/stream", jobID),
+	})
+}
 
-		s.jobMu.Lock()
-		job.Status = "completed"
-		job.EndedAt = time.Now()
-		job.Result = map[string]interface{}{
+func (s *Server) handleRecomputeStatus(c echo.Context) error {
+	batchSize := 500
+	if raw := strings.TrimSpace(c.QueryParam("batch_size")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 5000 {
+			batchSize = parsed
+		}
+	}
+
+	// mode=incremental only revisits rows that may have changed since ?since=
+	// (default: the last successful recompute); without a watermark it falls
+	// back to a full run.
+	incremental := c.QueryParam("mode") == "incremental"
+	since := parseDateParam(c.QueryParam("since"))
+	if raw := c.QueryParam("since"); raw != "" && since == nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "since must be RFC3339 or YYYY-MM-DD"})
+	}
+
+	return s.startJob(c, "recompute", 30*time.Minute, func(ctx context.Context, report ingest.ProgressFunc) (any, error) {
+		pipeline := s.newPipeline(nil, nil)
+
+		if incremental && since == nil {
+			watermark, err := pipeline.LastRecomputeAt(ctx)
+			if err != nil {
+				return nil, err
+			}
+			since = watermark
+		}
+
+		var last ingest.Progress
+		track := func(p ingest.Progress) {
+			last = p
+			report(p)
+		}
+		var statusCounts map[string]int
+		var statusUpdated int
+		var err error
+		mode := "full"
+		if incremental && since != nil {
+			mode = "incremental"
+			statusCounts, statusUpdated, err = pipeline.RecomputeStatusesSince(ctx, *since, batchSize, track)
+		} else {
+			statusCounts, statusUpdated, err = pipeline.RecomputeStatuses(ctx, batchSize, track)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		arraysUpdated, _ := pipeline.BackfillCleanArrays(ctx)
+		result := map[string]interface{}{
+			"mode":            mode,
 			"status_updated":  statusUpdated,
 			"status_counts":   statusCounts,
 			"arrays_updated":  arraysUpdated,
 			"batch_size_used": batchSize,
+			"total_estimate":  last.TotalEstimate,
 		}
-		s.jobMu.Unlock()
-		log.Printf("[recompute-job %s] completed: updated=%d", jobID, statusUpdated)
-	}()
+		if mode == "incremental" {
+			result["since"] = since.UTC().Format(time.RFC3339)
+		}
+		return result, nil
+	})
+}
 
-	return c.JSON(http.StatusAccepted, map[string]interface{}{
-		"message": "Recompute job started",
-		"job_id":  jobID,
-		"poll":    fmt.Sprintf("/api/v1/admin/job/%s", jobID),
+func (s *Server) handleBackfillEmbeddings(c echo.Context) error {
+	if !s.AI.Available() {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "AI service unavailable"})
+	}
+
+	batchSize := 100
+	if raw := strings.TrimSpace(c.QueryParam("batch_size")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 1000 {
+			batchSize = parsed
+		}
+	}
+	afterID := strings.TrimSpace(c.QueryParam("after_id"))
+
+	return s.startJob(c, "embedding-backfill", 60*time.Minute, func(ctx context.Context, _ ingest.ProgressFunc) (any, error) {
+		pipeline := s.newPipeline(nil, nil)
+
+		stats, err := pipeline.BackfillEmbeddings(ctx, batchSize, afterID)
+		// Partial counts are reported on failure too; last_id resumes the run.
+		return map[string]interface{}{
+			"items_scanned":   stats.ItemsScanned,
+			"items_updated":   stats.ItemsUpdated,
+			"items_failed":    stats.ItemsFailed,
+			"batches":         stats.Batches,
+			"last_id":         stats.LastID,
+			"batch_size_used": batchSize,
+		}, err
+	})
+}
+
+// handleCheckLinks re-checks one batch of external links as a cancellable
+// background job.
+func (s *Server) handleCheckLinks(c echo.Context) error {
+	batchSize := 200
+	if raw := strings.TrimSpace(c.QueryParam("batch_size")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 2000 {
+			batchSize = parsed
+		}
+	}
+
+	return s.startJob(c, "link-health", 60*time.Minute, func(ctx context.Context, _ ingest.ProgressFunc) (any, error) {
+		pipeline := ingest.NewPipeline(s.DB, nil, nil, nil)
+		stats, err := pipeline.CheckLinkHealth(ctx, batchSize)
+		// Rows checked before a cancel keep their result, so report them.
+		return map[string]interface{}{
+			"stats":           stats,
+			"batch_size_used": batchSize,
+		}, err
+	})
+}
+
+// defaultStaleArchiveDays is used when neither older_than_days nor
+// STALE_ARCHIVE_DAYS is set.
+const defaultStaleArchiveDays = 90
+
+func (s *Server) handleArchiveStale(c echo.Context) error {
+	days := defaultStaleArchiveDays
+	if raw := strings.TrimSpace(os.Getenv("STALE_ARCHIVE_DAYS")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+	if raw := strings.TrimSpace(c.QueryParam("older_than_days")); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "older_than_days must be a positive integer"})
+		}
+		days = parsed
+	}
+
+	return s.startJob(c, "archive-stale", 10*time.Minute, func(ctx context.Context, _ ingest.ProgressFunc) (any, error) {
+		pipeline := ingest.NewPipeline(s.DB, nil, nil, nil)
+
+		archived, err := pipeline.ArchiveStale(ctx, time.Duration(days)*24*time.Hour)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"archived":        archived,
+			"older_than_days": days,
+		}, nil
+	})
+}
+
+// handleBackfillFunderTypes rewrites stored funder types onto the canonical
+// taxonomy. Re-running it is harmless: canonical values are left alone.
+func (s *Server) handleBackfillFunderTypes(c echo.Context) error {
+	return s.startJob(c, "funder-type-backfill", 10*time.Minute, func(ctx context.Context, _ ingest.ProgressFunc) (any, error) {
+		pipeline := ingest.NewPipeline(s.DB, nil, nil, nil)
+		return pipeline.BackfillFunderTypes(ctx)
+	})
+}
+
+// handleBackfillRegions rewrites stored regions onto the canonical set and
+// derives missing ones from the country.
+func (s *Server) handleBackfillRegions(c echo.Context) error {
+	return s.startJob(c, "region-backfill", 10*time.Minute, func(ctx context.Context, _ ingest.ProgressFunc) (any, error) {
+		pipeline := ingest.NewPipeline(s.DB, nil, nil, nil)
+		return pipeline.BackfillRegions(ctx)
+	})
+}
+
+type purgeSourceRequest struct {
+	// Confirm must repeat the domain from the path.
+	Confirm string `json:"confirm"`
+}
+
+// handlePurgeSource archives (or with ?hard=true deletes) every opportunity of
+// a retired source_domain. ?dry_run=true reports what would change; a real run
+// needs {"confirm": "<domain>"} so a mistyped request can't wipe a source.
+func (s *Server) handlePurgeSource(c echo.Context) error {
+	domain := strings.TrimSpace(c.Param("domain"))
+	if domain == "" || strings.ContainsAny(domain, "%* ") {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "domain must be an exact source_domain"})
+	}
+	hard := c.QueryParam("hard") == "true"
+	dryRun := c.QueryParam("dry_run") == "true"
+
+	if !dryRun {
+		var req purgeSourceRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+		}
+		if req.Confirm != domain {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": fmt.Sprintf(`confirm must be %q to purge this source`, domain)})
+		}
+	}
+
+	pipeline := ingest.NewPipeline(s.DB, nil, nil, nil)
+	stats, err := pipeline.PurgeSource(c.Request().Context(), domain, hard, dryRun)
+	if err != nil {
+		c.Logger().Errorf("Failed to purge source %s: %v", domain, err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to purge source"})
+	}
+	if stats.Matched == 0 {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "No opportunities for this source_domain"})
+	}
+	return c.JSON(http.StatusOK, stats)
+}
+
+func (s *Server) handleCancelJob(c echo.Context) error {
+	queried := c.Param("id")
+	s.jobMu.Lock()
+	defer s.jobMu.Unlock()
+
+	job := s.runningJob
+	if job == nil || job.ID != queried {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "job not found"})
+	}
+	if job.Status != "running" {
+		return c.JSON(http.StatusConflict, map[string]string{"error": "job is not running"})
+	}
+
+	job.Cancel()
+	return c.JSON(http.StatusAccepted, map[string]string{
+		"message": "Cancellation requested",
+		"job_id":  job.ID,
 	})
 }
 
@@ -524,6 +1068,15 @@ func (s *Server) handleJobStatus(c echo.Context) error {
 	}
 
 	s.jobMu.Lock()
+	resp := jobSnapshot(job)
+	s.jobMu.Unlock()
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// jobSnapshot renders job for the poll and stream endpoints. The caller must
+// hold jobMu.
+func jobSnapshot(job *backgroundJob) map[string]interface{} {
 	resp := map[string]interface{}{
 		"id":         job.ID,
 		"status":     job.Status,
@@ -533,21 +1086,19 @@ func (s *Server) handleJobStatus(c echo.Context) error {
 		resp["ended_at"] = job.EndedAt
 		resp["duration"] = job.EndedAt.Sub(job.StartedAt).String()
 	}
+	if job.progress != nil {
+		resp["progress"] = job.progress
+	}
 	if job.Result != nil {
 		resp["result"] = job.Result
 	}
 	if job.Error != "" {
 		resp["error"] = job.Error
 	}
-	s.jobMu.Unlock()
-
-	return c.JSON(http.StatusOK, resp)
+	return resp
 }
 
 func (s *Server) handleEnrichOpportunities(c echo.Context) error {
-	pipeline := ingest.NewPipeline(s.DB, nil, nil, s.AI)
-	ctx := c.Request().Context()
-
 	domain := strings.TrimSpace(c.QueryParam("domain"))
 	onlyMissingDeadlines := true
 	if raw := strings.TrimSpace(c.QueryParam("only_missing_deadlines")); raw != "" {
@@ -575,30 +1126,34 @@ func (s *Server) handleEnrichOpportunities(c echo.Context) error {
 		}
 	}
 
-	enrichStats, err := pipeline.EnrichOpportunities(ctx, domain, onlyMissingDeadlines, batchSize, maxItems, confidenceThreshold)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
+	return s.startJob(c, "enrich", 60*time.Minute, func(ctx context.Context, report ingest.ProgressFunc) (any, error) {
+		pipeline := s.newPipeline(nil, nil)
 
-	statusCounts, statusUpdated, err := pipeline.RecomputeStatuses(ctx, batchSize)
-	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
-	}
+		enrichStats, err := pipeline.EnrichOpportunities(ctx, domain, onlyMissingDeadlines, batchSize, maxItems, confidenceThreshold, report)
+		if err != nil {
+			return nil, err
+		}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message":                "Selective enrichment complete",
-		"domain":                 domain,
-		"only_missing_deadlines": onlyMissingDeadlines,
-		"batch_size_used":        batchSize,
-		"max_items":              maxItems,
-		"confidence_threshold":   confidenceThreshold,
-		"items_scanned":          enrichStats.ItemsScanned,
-		"items_updated":          enrichStats.ItemsUpdated,
-		"pdfs_parsed":            enrichStats.PDFsParsed,
-		"deadlines_added":        enrichStats.DeadlinesAdded,
-		"status_changes":         enrichStats.StatusChanges,
-		"status_updated":         statusUpdated,
-		"status_counts":          statusCounts,
+		statusCounts, statusUpdated, err := pipeline.RecomputeStatuses(ctx, batchSize, report)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"domain":                 domain,
+			"only_missing_deadlines": onlyMissingDeadlines,
+			"batch_size_used":        batchSize,
+			"max_items":              maxItems,
+			"confidence_threshold":   confidenceThreshold,
+			"items_scanned":          enrichStats.ItemsScanned,
+			"items_updated":          enrichStats.ItemsUpdated,
+			"pdfs_parsed":            enrichStats.PDFsParsed,
+			"deadlines_added":        enrichStats.DeadlinesAdded,
+			"status_changes":         enrichStats.StatusChanges,
+			"total_estimate":         enrichStats.TotalEstimate,
+			"status_updated":         statusUpdated,
+			"status_counts":          statusCounts,
+		}, nil
 	})
 }
 
@@ -812,18 +1367,39 @@ func (s *Server) handleGetSavedOpportunities(c echo.Context) error {
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Unauthorized"})
 	}
 
-	opps, err := s.AuthService.GetSavedOpportunities(ctx, userID)
+	tag := ""
+	if raw := c.QueryParam("tag"); raw != "" {
+		tags, err := normalizeSavedTags([]string{raw})
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		if len(tags) > 0 {
+			tag = tags[0]
+		}
+	}
+
+	opps, err := s.AuthService.GetSavedOpportunities(ctx, userID, tag)
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch saved opportunities"})
 	}
 
 	if opps == nil {
-		opps = []models.Opportunity{}
+		opps = []auth.SavedOpportunity{}
 	}
 
 	return c.JSON(http.StatusOK, opps)
 }
 
+// newPipeline builds an ingest pipeline wired to the server's AI client and
+// webhook dispatcher.
+func (s *Server) newPipeline(fetcher ingest.Fetcher, parser ingest.Parser) *ingest.Pipeline {
+	pipeline := ingest.NewPipeline(s.DB, fetcher, parser, s.AI)
+	if s.Webhooks != nil {
+		pipeline.Notifier = s.Webhooks
+	}
+	return pipeline
+}
+
 func (s *Server) Start(port string) error {
 	return s.Echo.Start(":" + port)
 }
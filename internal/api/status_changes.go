@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultStatusChangeWindow covers the last daily recompute when ?since=
+	// is omitted.
+	defaultStatusChangeWindow = 24 * time.Hour
+	defaultStatusChangeLimit  = 100
+	maxStatusChangeLimit      = 1000
+)
+
+// statusChangeParamsFromQuery reads ?since=&to=&from_status=&to_status=&limit=.
+// The window defaults to the last 24 hours ending now.
+func statusChangeParamsFromQuery(c echo.Context, now time.Time) (db.StatusChangeParams, error) {
+	params := db.StatusChangeParams{
+		To:         now,
+		FromStatus: strings.ToLower(strings.TrimSpace(c.QueryParam("from_status"))),
+		ToStatus:   strings.ToLower(strings.TrimSpace(c.QueryParam("to_status"))),
+		Limit:      defaultStatusChangeLimit,
+	}
+	if raw := c.QueryParam("to"); raw != "" {
+		to := parseDateParam(raw)
+		if to == nil {
+			return params, fmt.Errorf("to must be an RFC3339 timestamp or YYYY-MM-DD date")
+		}
+		params.To = *to
+	}
+	params.Since = params.To.Add(-defaultStatusChangeWindow)
+	if raw := c.QueryParam("since"); raw != "" {
+		since := parseDateParam(raw)
+		if since == nil {
+			return params, fmt.Errorf("since must be an RFC3339 timestamp or YYYY-MM-DD date")
+		}
+		params.Since = *since
+	}
+	if !params.Since.Before(params.To) {
+		return params, fmt.Errorf("since must be before to")
+	}
+	for name, status := range map[string]string{"from_status": params.FromStatus, "to_status": params.ToStatus} {
+		if status != "" && !db.IsNormalizedStatus(status) {
+			return params, fmt.Errorf("unknown %s %q", name, status)
+		}
+	}
+	if raw := c.QueryParam("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 || limit > maxStatusChangeLimit {
+			return params, fmt.Errorf("limit must be between 1 and %d", maxStatusChangeLimit)
+		}
+		params.Limit = limit
+	}
+	return params, nil
+}
+
+// handleStatusChanges lists opportunities whose normalized_status changed in
+// the requested window, most recent change first.
+func (s *Server) handleStatusChanges(c echo.Context) error {
+	params, err := statusChangeParamsFromQuery(c, time.Now().UTC())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	changes, err := s.Store.ListStatusChanges(c.Request().Context(), params)
+	if errors.Is(err, db.ErrQueryTimeout) {
+		return queryTimedOut(c)
+	}
+	if err != nil {
+		c.Logger().Errorf("Failed to list status changes: %v", err)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Internal Server Error"})
+	}
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"since":   params.Since,
+		"to":      params.To,
+		"count":   len(changes),
+		"changes": changes,
+	})
+}
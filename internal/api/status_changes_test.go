@@ -0,0 +1,42 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestStatusChangeParamsFromQuery(t *testing.T) {
+	e := echo.New()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	parse := func(query string) (time.Time, time.Time, string, string, error) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/opportunities/status-changed"+query, nil)
+		params, err := statusChangeParamsFromQuery(e.NewContext(req, httptest.NewRecorder()), now)
+		return params.Since, params.To, params.FromStatus, params.ToStatus, err
+	}
+
+	since, to, _, _, err := parse("")
+	if err != nil || !to.Equal(now) || !since.Equal(now.Add(-defaultStatusChangeWindow)) {
+		t.Fatalf("unexpected default window %v..%v (%v)", since, to, err)
+	}
+
+	since, to, from, toStatus, err := parse("?since=2026-10-01&to=2026-10-08T00:00:00Z&from_status=OPEN&to_status=closed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !since.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) || !to.Equal(time.Date(2026, 10, 8, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected window %v..%v", since, to)
+	}
+	if from != "open" || toStatus != "closed" {
+		t.Fatalf("unexpected statuses %q -> %q", from, toStatus)
+	}
+
+	for _, bad := range []string{"?since=yesterday", "?since=2026-10-19", "?to_status=gone", "?limit=0"} {
+		if _, _, _, _, err := parse(bad); err == nil {
+			t.Errorf("%s: expected an error", bad)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/labstack/echo/v4"
+)
+
+// statusPreviewRequest carries the fields ComputeStatusDecision reads, named
+// as in the opportunities table. Now defaults to the current time.
+type statusPreviewRequest struct {
+	Title            string                    `json:"title"`
+	Summary          string                    `json:"summary"`
+	Description      string                    `json:"description"`
+	ExternalURL      string                    `json:"external_url"`
+	SourceDomain     string                    `json:"source_domain"`
+	SourceStatusRaw  string                    `json:"source_status_raw"`
+	OppStatus        string                    `json:"opp_status"`
+	IsRolling        bool                      `json:"is_rolling"`
+	RollingEvidence  bool                      `json:"rolling_evidence"`
+	IsResultsPage    bool                      `json:"is_results_page"`
+	DeadlineAt       *time.Time                `json:"deadline_at"`
+	NextDeadlineAt   *time.Time                `json:"next_deadline_at"`
+	ExpirationAt     *time.Time                `json:"expiration_at"`
+	CloseAt          *time.Time                `json:"close_at"`
+	OpenAt           *time.Time                `json:"open_at"`
+	Deadlines        []string                  `json:"deadlines"`
+	DeadlineEvidence []ingest.DeadlineEvidence `json:"deadline_evidence"`
+	SourceEvidence   map[string]interface{}    `json:"source_evidence"`
+	Now              *time.Time                `json:"now"`
+}
+
+func (r statusPreviewRequest) opportunity() ingest.Opportunity {
+	return ingest.Opportunity{
+		Title:              r.Title,
+		Summary:            r.Summary,
+		Description:        r.Description,
+		ExternalURL:        r.ExternalURL,
+		SourceDomain:       r.SourceDomain,
+		SourceStatusRaw:    r.SourceStatusRaw,
+		OppStatus:          r.OppStatus,
+		IsRolling:          r.IsRolling,
+		RollingEvidence:    r.RollingEvidence,
+		IsResultsPage:      r.IsResultsPage,
+		DeadlineAt:         r.DeadlineAt,
+		NextDeadlineAt:     r.NextDeadlineAt,
+		ExpirationAt:       r.ExpirationAt,
+		CloseAt:            r.CloseAt,
+		OpenAt:             r.OpenAt,
+		Deadlines:          r.Deadlines,
+		DeadlineEvidence:   r.DeadlineEvidence,
+		SourceEvidenceJSON: r.SourceEvidence,
+	}
+}
+
+// handlePreviewStatus runs the status engine on a posted opportunity and
+// returns the decision with the signals behind it. Nothing is read from or
+// written to the database.
+func (s *Server) handlePreviewStatus(c echo.Context) error {
+	var req statusPreviewRequest
+	if err := c.Bind(&req); err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	now := time.Now().UTC()
+	if req.Now != nil {
+		now = req.Now.UTC()
+	}
+	opp := req.opportunity()
+	decision := ingest.ComputeStatusDecision(opp, now)
+
+	resp := map[string]interface{}{
+		"now": now,
+		"decision": map[string]interface{}{
+			"normalized_status": decision.NormalizedStatus,
+			"status_reason":     decision.StatusReason,
+			"status_confidence": decision.StatusConfidence,
+			"next_deadline_at":  decision.NextDeadlineAt,
+			"is_results_page":   decision.IsResultsPage,
+		},
+		"signals": ingest.ComputeStatusSignals(opp, now),
+	}
+	if decision.TimelineInversion != nil {
+		resp["timeline_inversion"] = decision.TimelineInversion
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestPreviewStatus_ReturnsDecisionAndSignals(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.POST("/status/preview", s.handlePreviewStatus)
+
+	body := `{
+		"title": "Fondo Semilla",
+		"source_status_raw": "Abierta",
+		"deadline_evidence": [
+			{"source": "html", "parsed_date_iso": "2026-05-30T23:59:00Z", "label": "cierre", "confidence": 0.9}
+		],
+		"now": "2026-03-01T00:00:00Z"
+	}`
+	req := httptest.NewRequest(http.MethodPost, "/status/preview", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	s.Echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp struct {
+		Decision struct {
+			NormalizedStatus string  `json:"normalized_status"`
+			StatusReason     string  `json:"status_reason"`
+			NextDeadlineAt   *string `json:"next_deadline_at"`
+		} `json:"decision"`
+		Signals struct {
+			MappedSource       string  `json:"mapped_source"`
+			HasRollingEvidence bool    `json:"has_rolling_evidence"`
+			PickedNextDeadline *string `json:"picked_next_deadline"`
+		} `json:"signals"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Decision.NormalizedStatus != "open" || resp.Decision.StatusReason != "future_deadline" {
+		t.Fatalf("unexpected decision %+v", resp.Decision)
+	}
+	if resp.Signals.MappedSource != "open" || resp.Signals.HasRollingEvidence {
+		t.Fatalf("unexpected signals %+v", resp.Signals)
+	}
+	if resp.Signals.PickedNextDeadline == nil || !strings.HasPrefix(*resp.Signals.PickedNextDeadline, "2026-05-30") {
+		t.Fatalf("expected picked deadline 2026-05-30, got %v", resp.Signals.PickedNextDeadline)
+	}
+}
+
+func TestPreviewStatus_RollingEvidenceAndSourceDomain(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.POST("/status/preview", s.handlePreviewStatus)
+
+	preview := func(body string) (decision struct {
+		NormalizedStatus string `json:"normalized_status"`
+		StatusReason     string `json:"status_reason"`
+	}, hasRolling bool) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/status/preview", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		s.Echo.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp struct {
+			Decision struct {
+				NormalizedStatus string `json:"normalized_status"`
+				StatusReason     string `json:"status_reason"`
+			} `json:"decision"`
+			Signals struct {
+				HasRollingEvidence bool `json:"has_rolling_evidence"`
+			} `json:"signals"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp.Decision, resp.Signals.HasRollingEvidence
+	}
+
+	// The stored flag alone is evidence; without it is_rolling is ignored.
+	withEvidence, rolling := preview(`{"title": "Fondo de apoyo a emprendedores", "is_rolling": true, "rolling_evidence": true, "now": "2026-03-01T00:00:00Z"}`)
+	if !rolling || withEvidence.NormalizedStatus != "open" {
+		t.Fatalf("expected rolling_evidence to keep the call open, got %+v (rolling %v)", withEvidence, rolling)
+	}
+	withoutEvidence, rolling := preview(`{"title": "Fondo de apoyo a emprendedores", "is_rolling": true, "now": "2026-03-01T00:00:00Z"}`)
+	if rolling || withoutEvidence == withEvidence {
+		t.Fatalf("expected a different decision without rolling_evidence, got %+v (rolling %v)", withoutEvidence, rolling)
+	}
+
+	// API-first sources skip the informational-page check.
+	page := `"title": "Program overview", "description": "About the program", "now": "2026-03-01T00:00:00Z"`
+	scraped, _ := preview(`{` + page + `, "source_domain": "example.org"}`)
+	api, _ := preview(`{` + page + `, "source_domain": "grants.gov"}`)
+	if scraped == api {
+		t.Fatalf("expected source_domain to change the decision, got %+v for both", api)
+	}
+}
+
+func TestPreviewStatus_RejectsMalformedJSON(t *testing.T) {
+	s := &Server{Echo: echo.New()}
+	s.Echo.POST("/status/preview", s.handlePreviewStatus)
+
+	req := httptest.NewRequest(http.MethodPost, "/status/preview", strings.NewReader(`{"open_at": "not a date"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	s.Echo.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
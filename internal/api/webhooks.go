@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/david/grant-finder/internal/webhook"
+	"github.com/labstack/echo/v4"
+)
+
+type createWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+var webhookEventTypes = map[string]bool{
+	ingest.EventOpportunityCreated:       true,
+	ingest.EventOpportunityStatusChanged: true,
+}
+
+func (s *Server) handleListWebhooks(c echo.Context) error {
+	subs, err := s.Webhooks.ListSubscriptions(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, subs)
+}
+
+func (s *Server) handleCreateWebhook(c echo.Context) error {
+	var req createWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		if isBodyTooLarge(err) {
+			return c.JSON(http.StatusRequestEntityTooLarge, map[string]string{"error": "Request body too large"})
+		}
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+
+	req.URL = strings.TrimSpace(req.URL)
+	if err := validatePublicURL(req.URL); err != nil {
+		return c.JSON(fetchURLErrorStatus(err), map[string]string{"error": err.Error()})
+	}
+	for _, event := range req.Events {
+		if !webhookEventTypes[event] {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Unknown event type: " + event})
+		}
+	}
+
+	sub, err := s.Webhooks.CreateSubscription(c.Request().Context(), req.URL, strings.TrimSpace(req.Secret), req.Events)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusCreated, sub)
+}
+
+func (s *Server) handleDeleteWebhook(c echo.Context) error {
+	err := s.Webhooks.DeleteSubscription(c.Request().Context(), c.Param("id"))
+	if errors.Is(err, webhook.ErrNotFound) {
+		return c.JSON(http.StatusNotFound, map[string]string{"error": err.Error()})
+	}
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleListWebhookDeliveries(c echo.Context) error {
+	limit := 50
+	if raw := strings.TrimSpace(c.QueryParam("limit")); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 && parsed <= 500 {
+			limit = parsed
+		}
+	}
+
+	deliveries, err := s.Webhooks.ListDeliveries(c.Request().Context(), c.Param("id"), limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+	return c.JSON(http.StatusOK, deliveries)
+}
@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/david/grant-finder/internal/models"
 )
 
 type User struct {
@@ -27,3 +29,11 @@ type AuthResponse struct {
 	Token string `json:"token"`
 	User  User   `json:"user"`
 }
+
+// SavedOpportunity is a saved opportunity with the user's own tags and note.
+// The opportunity's fields are inlined so the list keeps its original shape.
+type SavedOpportunity struct {
+	models.Opportunity
+	Tags []string `json:"tags"`
+	Note string   `json:"note"`
+}
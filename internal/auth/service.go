@@ -15,10 +15,9 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
-
-	"github.com/david/grant-finder/internal/models"
 )
 
 var (
@@ -163,25 +162,29 @@ func (s *Service) UnsaveOpportunity(ctx context.Context, userID uuid.UUID, oppID
 	return err
 }
 
-func (s *Service) GetSavedOpportunities(ctx context.Context, userID uuid.UUID) ([]models.Opportunity, error) {
+// GetSavedOpportunities lists the user's saved opportunities, newest first,
+// with their tags and note. A non-empty tag keeps only items carrying it.
+func (s *Service) GetSavedOpportunities(ctx context.Context, userID uuid.UUID, tag string) ([]SavedOpportunity, error) {
 	rows, err := s.db.Query(ctx, `
 		SELECT o.id, o.title, o.summary, o.source_domain, o.opportunity_number, 
 		       o.agency_name, o.agency_code, o.funder_type, o.amount_min, o.amount_max, 
 			   o.currency, o.deadline_at, o.open_date, o.is_rolling, o.doc_type, 
-			   o.opp_status, o.region, o.country, o.categories, o.eligibility
+			   o.opp_status, o.region, o.country, o.categories, o.eligibility,
+			   COALESCE(n.tags, '{}'), COALESCE(n.note, '')
 		FROM opportunities o
 		JOIN saved_opportunities so ON o.id = so.opportunity_id
-		WHERE so.user_id = $1
+		LEFT JOIN saved_opportunity_notes n ON n.user_id = so.user_id AND n.opportunity_id = so.opportunity_id
+		WHERE so.user_id = $1 AND ($2 = '' OR n.tags @> ARRAY[$2])
 		ORDER BY so.saved_at DESC
-	`, userID)
+	`, userID, tag)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var opps []models.Opportunity
+	var opps []SavedOpportunity
 	for rows.Next() {
-		var o models.Opportunity
+		var o SavedOpportunity
 		// We only scan fields we used in query. Make sure they match model struct field count/order?
 		// Or just scan into specific fields.
 		err := rows.Scan(
@@ -189,11 +192,51 @@ func (s *Service) GetSavedOpportunities(ctx context.Context, userID uuid.UUID) (
 			&o.AgencyName, &o.AgencyCode, &o.FunderType, &o.AmountMin, &o.AmountMax,
 			&o.Currency, &o.DeadlineAt, &o.OpenDate, &o.IsRolling, &o.DocType,
 			&o.OppStatus, &o.Region, &o.Country, &o.Categories, &o.Eligibility,
+			&o.Tags, &o.Note,
 		)
 		if err != nil {
 			return nil, err
 		}
 		opps = append(opps, o)
 	}
-	return opps, nil
+	return opps, rows.Err()
+}
+
+// ErrOpportunityNotFound is returned when a note targets an opportunity that
+// does not exist.
+var ErrOpportunityNotFound = errors.New("opportunity not found")
+
+// SetSavedNote replaces the user's tags and note on an opportunity, saving
+// the opportunity first if it isn't saved yet.
+func (s *Service) SetSavedNote(ctx context.Context, userID uuid.UUID, oppID uuid.UUID, tags []string, note string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO saved_opportunities (user_id, opportunity_id)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, opportunity_id) DO NOTHING
+	`, userID, oppID); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrOpportunityNotFound
+		}
+		return err
+	}
+
+	if tags == nil {
+		tags = []string{}
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO saved_opportunity_notes (user_id, opportunity_id, tags, note, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (user_id, opportunity_id) DO UPDATE
+		SET tags = EXCLUDED.tags, note = EXCLUDED.note, updated_at = NOW()
+	`, userID, oppID, tags, note); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestBuildAggregationWhere_AppliesSearchQuery(t *testing.T) {
+	for _, mode := range []string{"", SearchModeBoolean} {
+		agg := AggregationParams{Query: "climate", SearchMode: mode, Status: "all", Region: []string{"Europe"}}
+		list := ListParams{Query: "climate", SearchMode: mode, Status: "all", Region: []string{"Europe"}}
+
+		aggWhere, aggArgs := buildAggregationWhereExcluding(agg, "region")
+		listWhere, _ := buildOpportunityWhere(list, whereOptions{excludeDimension: "region"})
+		if aggWhere != listWhere {
+			t.Fatalf("mode %q: facet predicate should match the list:\nlist: %s\nagg:  %s", mode, listWhere, aggWhere)
+		}
+		if !strings.Contains(aggWhere, "search_vector @@ "+tsqueryFunc(mode)+"('english', $1)") {
+			t.Fatalf("mode %q: facet counts should follow the search: %s", mode, aggWhere)
+		}
+		if fmt.Sprint(aggArgs) != "[climate]" {
+			t.Fatalf("mode %q: unexpected args %v", mode, aggArgs)
+		}
+	}
+}
+
+// TestGetAggregations_QueryShrinksCounts runs against a migrated database;
+// set TEST_DATABASE_URL to enable it.
+func TestGetAggregations_QueryShrinksCounts(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain, agency = "facet-search-test.example.org", "Facet Search Test Agency"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	for i, title := range []string{"Glaciology field research grant", "Urban mobility pilots", "Rural broadband rollout"} {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, agency_name)
+			VALUES ($1, $2, $3, $4, $5)`,
+			title, fmt.Sprintf("https://%s/%d", domain, i), domain, fmt.Sprint(i), agency); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewStore(pool)
+	agencyCount := func(query string) int {
+		aggs, err := store.GetAggregations(ctx, AggregationParams{Status: "all", Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, ag := range aggs.Agencies {
+			if ag.Value == agency {
+				return ag.Count
+			}
+		}
+		return 0
+	}
+
+	if got := agencyCount(""); got != 3 {
+		t.Fatalf("without a query: agency count = %d, want 3", got)
+	}
+	if got := agencyCount("glaciology"); got != 1 {
+		t.Fatalf("with a restrictive query: agency count = %d, want 1", got)
+	}
+}
@@ -4,12 +4,19 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	pgxvector "github.com/pgvector/pgvector-go/pgx"
 )
 
+// defaultMaxConns replaces pgxpool's max(4, NumCPU) default, which a single
+// ingest run with a few workers plus API traffic exhausts on small hosts.
+const defaultMaxConns = 10
+
 func Connect(ctx context.Context) (*pgxpool.Pool, error) {
 	dbURL := os.Getenv("DATABASE_URL")
 	if dbURL == "" {
@@ -20,6 +27,9 @@ func Connect(ctx context.Context) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error parsing db config: %w", err)
 	}
+	if err := applyPoolEnv(config, os.Getenv); err != nil {
+		return nil, fmt.Errorf("error parsing db pool config: %w", err)
+	}
 
 	config.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
 		return pgxvector.RegisterTypes(ctx, conn)
@@ -36,3 +46,57 @@ func Connect(ctx context.Context) (*pgxpool.Pool, error) {
 
 	return pool, nil
 }
+
+// applyPoolEnv overrides pool sizing from DB_MAX_CONNS, DB_MIN_CONNS,
+// DB_MAX_CONN_LIFETIME, DB_MAX_CONN_IDLE_TIME and DB_HEALTH_CHECK_PERIOD.
+// Unset variables keep whatever DATABASE_URL (pool_* params) or pgxpool set,
+// except that MaxConns falls back to defaultMaxConns when neither names it.
+func applyPoolEnv(config *pgxpool.Config, getenv func(string) string) error {
+	if !strings.Contains(config.ConnString(), "pool_max_conns") {
+		config.MaxConns = defaultMaxConns
+	}
+
+	for _, v := range []struct {
+		name string
+		dst  *int32
+	}{
+		{"DB_MAX_CONNS", &config.MaxConns},
+		{"DB_MIN_CONNS", &config.MinConns},
+	} {
+		raw := strings.TrimSpace(getenv(v.name))
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || n < 0 {
+			return fmt.Errorf("%s must be a non-negative integer, got %q", v.name, raw)
+		}
+		*v.dst = int32(n)
+	}
+	if config.MaxConns < 1 {
+		return fmt.Errorf("DB_MAX_CONNS must be at least 1")
+	}
+	if config.MinConns > config.MaxConns {
+		return fmt.Errorf("DB_MIN_CONNS (%d) exceeds DB_MAX_CONNS (%d)", config.MinConns, config.MaxConns)
+	}
+
+	for _, v := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"DB_MAX_CONN_LIFETIME", &config.MaxConnLifetime},
+		{"DB_MAX_CONN_IDLE_TIME", &config.MaxConnIdleTime},
+		{"DB_HEALTH_CHECK_PERIOD", &config.HealthCheckPeriod},
+	} {
+		raw := strings.TrimSpace(getenv(v.name))
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("%s must be a positive duration such as 30m, got %q", v.name, raw)
+		}
+		*v.dst = d
+	}
+	return nil
+}
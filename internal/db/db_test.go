@@ -0,0 +1,65 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestApplyPoolEnv(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgres://u:p@localhost:5432/db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"DB_MIN_CONNS":           "2",
+		"DB_MAX_CONN_LIFETIME":   "45m",
+		"DB_HEALTH_CHECK_PERIOD": "30s",
+	}
+	if err := applyPoolEnv(config, func(k string) string { return env[k] }); err != nil {
+		t.Fatalf("applyPoolEnv: %v", err)
+	}
+	if config.MaxConns != defaultMaxConns {
+		t.Errorf("MaxConns = %d, want default %d", config.MaxConns, defaultMaxConns)
+	}
+	if config.MinConns != 2 {
+		t.Errorf("MinConns = %d, want 2", config.MinConns)
+	}
+	if config.MaxConnLifetime != 45*time.Minute {
+		t.Errorf("MaxConnLifetime = %v, want 45m", config.MaxConnLifetime)
+	}
+	if config.HealthCheckPeriod != 30*time.Second {
+		t.Errorf("HealthCheckPeriod = %v, want 30s", config.HealthCheckPeriod)
+	}
+}
+
+func TestApplyPoolEnv_URLMaxConnsWins(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgres://u:p@localhost:5432/db?pool_max_conns=25")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := applyPoolEnv(config, func(string) string { return "" }); err != nil {
+		t.Fatalf("applyPoolEnv: %v", err)
+	}
+	if config.MaxConns != 25 {
+		t.Errorf("MaxConns = %d, want 25 from DATABASE_URL", config.MaxConns)
+	}
+}
+
+func TestApplyPoolEnv_Invalid(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"DB_MAX_CONNS": "lots"},
+		{"DB_MAX_CONNS": "0"},
+		{"DB_MAX_CONNS": "4", "DB_MIN_CONNS": "8"},
+		{"DB_MAX_CONN_IDLE_TIME": "5"},
+	} {
+		config, err := pgxpool.ParseConfig("postgres://u:p@localhost:5432/db")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := applyPoolEnv(config, func(k string) string { return env[k] }); err == nil {
+			t.Errorf("applyPoolEnv(%v) = nil, want error", env)
+		}
+	}
+}
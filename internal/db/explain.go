@@ -0,0 +1,109 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/david/grant-finder/internal/models"
+	"github.com/pgvector/pgvector-go"
+)
+
+// explainSelect returns the extra select columns ListParams.Explain adds,
+// numbered from argIdx, and the args they bind: vector similarity, ts_rank,
+// and whether the row matched the keyword query through the full-text index
+// or the title fallback. Scores without a query are NULL.
+func explainSelect(params ListParams, argIdx int) (string, []interface{}) {
+	var args []interface{}
+	similarity := "NULL::float8"
+	if len(params.QueryEmbedding) > 0 {
+		similarity = fmt.Sprintf("(1 - (embedding <=> $%d))::float8", argIdx)
+		args = append(args, pgvector.NewVector(params.QueryEmbedding))
+		argIdx++
+	}
+	rank, ftsMatch, titleMatch := "NULL::float8", "false", "false"
+	if params.Query != "" {
+		tsquery := fmt.Sprintf("%s('english', $%d::text)", tsqueryFunc(params.SearchMode), argIdx)
+		rank = fmt.Sprintf("ts_rank(search_vector, %s)::float8", tsquery)
+		ftsMatch = fmt.Sprintf("COALESCE(search_vector @@ %s, false)", tsquery)
+		if params.SearchMode != SearchModeBoolean {
+			titleMatch = fmt.Sprintf("COALESCE(title ILIKE '%%' || $%d::text || '%%', false)", argIdx)
+		}
+		args = append(args, params.Query)
+	}
+	return fmt.Sprintf(",\n\t%s, %s, %s, %s", similarity, rank, ftsMatch, titleMatch), args
+}
+
+// explainRow receives the explainSelect columns of one row.
+type explainRow struct {
+	similarity *float64
+	rank       *float64
+	ftsMatch   bool
+	titleMatch bool
+}
+
+func (e *explainRow) dests() []interface{} {
+	return []interface{}{&e.similarity, &e.rank, &e.ftsMatch, &e.titleMatch}
+}
+
+// explanation combines the row's scores with the filters it passed. Every
+// returned row passed all active filters; the keyword query is the one with
+// per-row alternatives, so it names the branch that matched.
+func (e *explainRow) explanation(filters []string) *models.RankExplanation {
+	matched := make([]string, 0, len(filters)+2)
+	for _, f := range filters {
+		if f != "query" {
+			matched = append(matched, f)
+			continue
+		}
+		if e.ftsMatch {
+			matched = append(matched, "query:fulltext")
+		}
+		if e.titleMatch {
+			matched = append(matched, "query:title")
+		}
+	}
+	return &models.RankExplanation{
+		VectorSimilarity: e.similarity,
+		TextRank:         e.rank,
+		MatchedFilters:   matched,
+	}
+}
+
+// activeFilters names the buildOpportunityWhere filters params turns on, in
+// the order the WHERE clause applies them.
+func activeFilters(params ListParams) []string {
+	var filters []string
+	add := func(on bool, name string) {
+		if on {
+			filters = append(filters, name)
+		}
+	}
+	add(params.Query != "", "query")
+	add(params.Source != "", "source")
+	add(len(params.Region) > 0, "region")
+	add(len(params.FunderType) > 0, "funder_type")
+	add(len(params.Country) > 0, "country")
+	add(params.AgencyCode != "", "agency_code")
+	add(len(params.AgencyName) > 0, "agency_name")
+	add(len(params.Type) > 0, "type")
+	add(len(params.DocType) > 0, "doc_type")
+	add(len(params.CFDA) > 0, "cfda")
+	add(params.ExcludeTenders, "exclude_tenders")
+	add(params.MinAmount > 0, "min_amount")
+	add(params.MaxAmount > 0, "max_amount")
+	status := params.Status
+	if status == "" {
+		status = DefaultStatusFilter
+	}
+	add(status != "all", "status:"+status)
+	add(params.MinConfidence > 0, "min_confidence")
+	add(params.OpenAfter != nil, "open_after")
+	add(params.OpenBefore != nil, "open_before")
+	add(params.HideDeadLinks, "hide_dead_links")
+	add(params.CreatedAfter != nil, "created_after")
+	add(params.MaxAge > 0, "max_age")
+	add(params.DeadlineDays > 0, "deadline_days")
+	add(params.IsRolling != nil, "is_rolling")
+	add(len(sanitizeStringSlice(params.Categories)) > 0, "categories")
+	add(len(sanitizeStringSlice(params.Eligibility)) > 0, "eligibility")
+	return filters
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/david/grant-finder/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestExplainSelect_BindsAfterWhereArgs(t *testing.T) {
+	cols, args := explainSelect(ListParams{}, 4)
+	if len(args) != 0 || strings.Count(cols, "NULL::float8") != 2 {
+		t.Fatalf("expected null scores without a query, got %s %v", cols, args)
+	}
+
+	cols, args = explainSelect(ListParams{Query: "climate", QueryEmbedding: []float32{0.1, 0.2}}, 4)
+	for _, want := range []string{"embedding <=> $4", "ts_rank(search_vector, plainto_tsquery('english', $5::text))", "title ILIKE '%' || $5::text"} {
+		if !strings.Contains(cols, want) {
+			t.Errorf("explain columns missing %q:\n%s", want, cols)
+		}
+	}
+	if len(args) != 2 || args[1] != "climate" {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	cols, _ = explainSelect(ListParams{Query: `"soil health"`, SearchMode: SearchModeBoolean}, 1)
+	if strings.Contains(cols, "ILIKE") || !strings.Contains(cols, "websearch_to_tsquery") {
+		t.Fatalf("boolean search has no title fallback:\n%s", cols)
+	}
+}
+
+func TestExplainRow_MatchedFilters(t *testing.T) {
+	params := ListParams{Query: "climate", Region: []string{"Europe"}, Categories: []string{" "}}
+	filters := activeFilters(params)
+	if want := []string{"query", "region", "status:open"}; !reflect.DeepEqual(filters, want) {
+		t.Fatalf("active filters = %v, want %v", filters, want)
+	}
+
+	rank := 0.06
+	ex := explainRow{rank: &rank, titleMatch: true}
+	got := ex.explanation(filters)
+	if want := []string{"query:title", "region", "status:open"}; !reflect.DeepEqual(got.MatchedFilters, want) {
+		t.Fatalf("matched filters = %v, want %v", got.MatchedFilters, want)
+	}
+	if got.VectorSimilarity != nil || got.TextRank == nil || *got.TextRank != rank {
+		t.Fatalf("unexpected scores %+v", got)
+	}
+
+	if filters := activeFilters(ListParams{Status: "all"}); len(filters) != 0 {
+		t.Fatalf("status=all applies no filter, got %v", filters)
+	}
+}
+
+func TestOpportunity_ExplainOmittedByDefault(t *testing.T) {
+	body, err := json.Marshal(models.Opportunity{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), `"explain"`) {
+		t.Fatalf("explain should be omitted unless requested: %s", body)
+	}
+}
+
+// TestListOpportunities_Explain runs against a migrated database; set
+// TEST_DATABASE_URL to enable it.
+func TestListOpportunities_Explain(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "explain-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	for i, title := range []string{"Glaciology research grant", "Glaciology fellowship"} {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id)
+			VALUES ($1, $2, $3, $4)`,
+			title, fmt.Sprintf("https://%s/%d", domain, i), domain, fmt.Sprint(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewStore(pool)
+	params := ListParams{Query: "glaciology", Source: domain, Status: "all", Limit: 10}
+	plain, err := store.ListOpportunities(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range plain.Opportunities {
+		if o.Explain != nil {
+			t.Fatalf("explain attached without being asked for: %+v", o.Explain)
+		}
+	}
+
+	params.Explain = true
+	result, err := store.ListOpportunities(ctx, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Opportunities) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(result.Opportunities))
+	}
+	for _, o := range result.Opportunities {
+		ex := o.Explain
+		if ex == nil {
+			t.Fatalf("%q: missing explain", o.Title)
+		}
+		if ex.VectorSimilarity != nil {
+			t.Errorf("%q: similarity without a query embedding", o.Title)
+		}
+		if ex.TextRank == nil || *ex.TextRank <= 0 || *ex.TextRank > 1 {
+			t.Errorf("%q: text rank %v out of range", o.Title, ex.TextRank)
+		}
+		if !reflect.DeepEqual(ex.MatchedFilters, []string{"query:fulltext", "query:title", "source"}) {
+			t.Errorf("%q: matched filters %v", o.Title, ex.MatchedFilters)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"strings"
+	"time"
+)
+
+// Freshness labels exposed on opportunities.
+const (
+	FreshnessFresh  = "fresh"
+	FreshnessRecent = "recent"
+	FreshnessStale  = "stale"
+)
+
+// recentTTLMultiple is how many TTLs old data may be and still count as
+// recent rather than stale.
+const recentTTLMultiple = 3
+
+// EnrichmentTTL is how long data from a source domain is trusted before the
+// enrichment batch re-checks it. Peruvian government portals change often.
+func EnrichmentTTL(domain string) time.Duration {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	if strings.Contains(d, "gob.pe") || strings.Contains(d, "proinnovate") || strings.Contains(d, "prociencia") {
+		return 48 * time.Hour
+	}
+	if strings.Contains(d, "ukri") || strings.Contains(d, "neh") {
+		return 72 * time.Hour
+	}
+	return 168 * time.Hour
+}
+
+// Freshness labels how current a row is: fresh within its domain's TTL,
+// recent within a few TTLs, stale beyond that or when it was never checked.
+func Freshness(checkedAt *time.Time, domain string, now time.Time) string {
+	if checkedAt == nil || checkedAt.IsZero() {
+		return FreshnessStale
+	}
+	age := now.Sub(*checkedAt)
+	ttl := EnrichmentTTL(domain)
+	switch {
+	case age <= ttl:
+		return FreshnessFresh
+	case age <= recentTTLMultiple*ttl:
+		return FreshnessRecent
+	default:
+		return FreshnessStale
+	}
+}
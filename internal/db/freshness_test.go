@@ -0,0 +1,44 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFreshness_UsesDomainTTL(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	cases := []struct {
+		name      string
+		checkedAt *time.Time
+		domain    string
+		want      string
+	}{
+		{"hour old", ago(time.Hour), "grants.gov", FreshnessFresh},
+		{"within default ttl", ago(6 * 24 * time.Hour), "grants.gov", FreshnessFresh},
+		{"past short peru ttl", ago(3 * 24 * time.Hour), "www.gob.pe", FreshnessRecent},
+		{"three weeks default", ago(21 * 24 * time.Hour), "grants.gov", FreshnessRecent},
+		{"past three ttls", ago(22 * 24 * time.Hour), "grants.gov", FreshnessStale},
+		{"never checked", nil, "grants.gov", FreshnessStale},
+	}
+	for _, tc := range cases {
+		if got := Freshness(tc.checkedAt, tc.domain, now); got != tc.want {
+			t.Errorf("%s: Freshness = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBuildOpportunityWhere_MaxAge(t *testing.T) {
+	where, args := buildOpportunityWhere(ListParams{Status: "all", MaxAge: 36 * time.Hour}, whereOptions{})
+	if !strings.Contains(where, "COALESCE(last_enriched_at, updated_at) >= NOW() - ($1 * INTERVAL '1 second')") {
+		t.Fatalf("expected max_age predicate, got %s", where)
+	}
+	if len(args) != 1 || args[0] != int64(36*60*60) {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
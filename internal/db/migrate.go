@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -13,53 +16,136 @@ import (
 //go:embed migrations/*.sql
 var migrationsFS embed.FS
 
+// MigrationInfo describes one embedded migration and whether it has been applied.
+type MigrationInfo struct {
+	Filename  string     `json:"filename"`
+	Version   int        `json:"version"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+}
+
+// ApplyMigrations applies every pending embedded migration in filename order.
 func ApplyMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	return ApplyMigrationsTo(ctx, pool, 0)
+}
+
+// ApplyMigrationsTo applies pending migrations whose version is <= target.
+// A target of 0 applies everything.
+func ApplyMigrationsTo(ctx context.Context, pool *pgxpool.Pool, target int) error {
+	status, err := MigrationStatus(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pendingMigrations(status, target) {
+		content, err := migrationsFS.ReadFile("migrations/" + m.Filename)
+		if err != nil {
+			return fmt.Errorf("failed to read migration file %s: %w", m.Filename, err)
+		}
+
+		log.Printf("Applying migration: %s", m.Filename)
+		if _, err = pool.Exec(ctx, string(content)); err != nil {
+			return fmt.Errorf("failed to execute migration %s: %w", m.Filename, err)
+		}
+
+		if _, err = pool.Exec(ctx, "INSERT INTO schema_migrations (filename) VALUES ($1)", m.Filename); err != nil {
+			return fmt.Errorf("failed to mark migration %s as applied: %w", m.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+// MigrationStatus reports every embedded migration, in apply order, with its
+// applied state from schema_migrations.
+func MigrationStatus(ctx context.Context, pool *pgxpool.Pool) ([]MigrationInfo, error) {
 	if _, err := pool.Exec(ctx, `
 		CREATE TABLE IF NOT EXISTS schema_migrations (
 			filename TEXT PRIMARY KEY,
 			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
 		)
 	`); err != nil {
-		return fmt.Errorf("failed to ensure schema_migrations table: %w", err)
+		return nil, fmt.Errorf("failed to ensure schema_migrations table: %w", err)
 	}
 
+	files, err := embeddedMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pool.Query(ctx, "SELECT filename, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := map[string]time.Time{}
+	for rows.Next() {
+		var name string
+		var at time.Time
+		if err := rows.Scan(&name, &at); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[name] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	out := make([]MigrationInfo, 0, len(files))
+	for _, name := range files {
+		info := MigrationInfo{Filename: name, Version: migrationVersion(name)}
+		if at, ok := applied[name]; ok {
+			at := at
+			info.Applied = true
+			info.AppliedAt = &at
+		}
+		out = append(out, info)
+	}
+	return out, nil
+}
+
+func embeddedMigrationFiles() ([]string, error) {
 	entries, err := migrationsFS.ReadDir("migrations")
 	if err != nil {
-		return fmt.Errorf("failed to read embedded migrations: %w", err)
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
 	}
 
 	var migrationFiles []string
 	for _, entry := range entries {
-		if !entry.IsDir() && len(entry.Name()) > 4 && entry.Name()[len(entry.Name())-4:] == ".sql" {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
 			migrationFiles = append(migrationFiles, entry.Name())
 		}
 	}
 	sort.Strings(migrationFiles)
+	return migrationFiles, nil
+}
 
-	for _, fileName := range migrationFiles {
-		var alreadyApplied bool
-		err := pool.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM schema_migrations WHERE filename = $1)", fileName).Scan(&alreadyApplied)
-		if err != nil {
-			return fmt.Errorf("failed to check migration %s: %w", fileName, err)
-		}
-		if alreadyApplied {
-			continue
-		}
-
-		content, err := migrationsFS.ReadFile("migrations/" + fileName)
-		if err != nil {
-			return fmt.Errorf("failed to read migration file %s: %w", fileName, err)
-		}
+// migrationVersion parses the numeric prefix of a migration filename
+// ("014_semantic_status_engine.sql" -> 14). Files without one return 0.
+func migrationVersion(filename string) int {
+	end := 0
+	for end < len(filename) && filename[end] >= '0' && filename[end] <= '9' {
+		end++
+	}
+	version, err := strconv.Atoi(filename[:end])
+	if err != nil {
+		return 0
+	}
+	return version
+}
 
-		log.Printf("Applying migration: %s", fileName)
-		if _, err = pool.Exec(ctx, string(content)); err != nil {
-			return fmt.Errorf("failed to execute migration %s: %w", fileName, err)
+// pendingMigrations returns unapplied migrations up to target (0 = all).
+func pendingMigrations(status []MigrationInfo, target int) []MigrationInfo {
+	var out []MigrationInfo
+	for _, m := range status {
+		if m.Applied {
+			continue
 		}
-
-		if _, err = pool.Exec(ctx, "INSERT INTO schema_migrations (filename) VALUES ($1)", fileName); err != nil {
-			return fmt.Errorf("failed to mark migration %s as applied: %w", fileName, err)
+		if target > 0 && m.Version > target {
+			continue
 		}
+		out = append(out, m)
 	}
-
-	return nil
+	return out
 }
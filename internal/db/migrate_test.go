@@ -0,0 +1,44 @@
+package db
+
+import "testing"
+
+func TestMigrationVersion(t *testing.T) {
+	cases := map[string]int{
+		"002_add_embeddings.sql":         2,
+		"014_semantic_status_engine.sql": 14,
+		"readme.sql":                     0,
+	}
+	for name, want := range cases {
+		if got := migrationVersion(name); got != want {
+			t.Fatalf("migrationVersion(%q) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestPendingMigrations_RespectsTarget(t *testing.T) {
+	status := []MigrationInfo{
+		{Filename: "002_a.sql", Version: 2, Applied: true},
+		{Filename: "003_b.sql", Version: 3},
+		{Filename: "003_c.sql", Version: 3},
+		{Filename: "004_d.sql", Version: 4},
+	}
+
+	if got := pendingMigrations(status, 3); len(got) != 2 || got[0].Filename != "003_b.sql" || got[1].Filename != "003_c.sql" {
+		t.Fatalf("expected both 003 migrations pending up to 3, got %+v", got)
+	}
+	if got := pendingMigrations(status, 0); len(got) != 3 {
+		t.Fatalf("expected all 3 unapplied migrations with target 0, got %+v", got)
+	}
+}
+
+func TestEmbeddedMigrationFiles_Sorted(t *testing.T) {
+	files, err := embeddedMigrationFiles()
+	if err != nil {
+		t.Fatalf("embeddedMigrationFiles: %v", err)
+	}
+	for i := 1; i < len(files); i++ {
+		if files[i-1] > files[i] {
+			t.Fatalf("migrations not sorted: %q before %q", files[i-1], files[i])
+		}
+	}
+}
@@ -0,0 +1,7 @@
+-- Migration 018: persist opportunity type (grant, fellowship, prize, award) for filtering
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS opportunity_type TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_opp_opportunity_type ON opportunities (opportunity_type);
+CREATE INDEX IF NOT EXISTS idx_opp_doc_type ON opportunities (doc_type);
@@ -0,0 +1,5 @@
+-- Migration 020: case-insensitive lookup by official opportunity number
+
+CREATE INDEX IF NOT EXISTS idx_opp_opportunity_number_lower
+ON opportunities (LOWER(opportunity_number))
+WHERE opportunity_number IS NOT NULL;
@@ -0,0 +1,11 @@
+-- Migration 021: Distinct normalized status for awarded calls
+-- 'funded' (grants awarded) was previously collapsed into 'closed'. Existing
+-- rows pick up the new value on the next status recompute; the value cannot
+-- be used in the same transaction that adds it.
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'normalized_status_enum') THEN
+        ALTER TYPE normalized_status_enum ADD VALUE IF NOT EXISTS 'funded';
+    END IF;
+END $$;
@@ -0,0 +1,3 @@
+-- Migration 022: GIN index for CFDA/ALN filtering (cfda_list && $n)
+
+CREATE INDEX IF NOT EXISTS idx_opp_cfda_list_gin ON opportunities USING GIN (cfda_list);
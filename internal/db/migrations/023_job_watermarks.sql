@@ -0,0 +1,9 @@
+-- Migration 023: Watermarks for incremental background jobs
+-- 'recompute_status' holds the start time of the last successful status
+-- recompute; incremental runs only revisit rows that may have changed since.
+
+CREATE TABLE IF NOT EXISTS job_watermarks (
+    job TEXT PRIMARY KEY,
+    watermark TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
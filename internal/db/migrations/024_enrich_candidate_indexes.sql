@@ -0,0 +1,16 @@
+-- Migration 024: Indexes for the enrichment candidate query
+--
+-- EnrichOpportunities used to ORDER BY (status_reason = 'open_source_no_date_parsed')
+-- DESC, updated_at ASC over an OR-heavy filter, so Postgres read every row of
+-- the domain and sorted them before applying the LIMIT (Seq Scan/Bitmap Heap
+-- Scan -> Sort on ~50k rows for the largest domains). The query now splits the
+-- priority into two UNION ALL branches ordered by updated_at alone; each one is
+-- an Index Scan on the indexes below that filters rows as it walks and stops
+-- at the LIMIT, so cost tracks the batch size rather than the domain size.
+
+CREATE INDEX IF NOT EXISTS idx_opp_domain_updated_at
+ON opportunities (source_domain, updated_at);
+
+CREATE INDEX IF NOT EXISTS idx_opp_unparsed_domain_updated_at
+ON opportunities (source_domain, updated_at)
+WHERE status_reason = 'open_source_no_date_parsed';
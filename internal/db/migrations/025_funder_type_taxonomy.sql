@@ -0,0 +1,8 @@
+-- Migration 025: Canonical funder types
+-- funder_type now holds one of government, foundation, corporate,
+-- multilateral, academic, nonprofit. The source's own wording ("Gobierno",
+-- "Private Foundation") moves to funder_type_label for display. Existing rows
+-- are rewritten by POST /api/v1/admin/backfill-funder-types.
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS funder_type_label TEXT;
@@ -0,0 +1,17 @@
+-- Migration 026: Tags and notes on saved opportunities
+-- One row per saved item, written by PUT /api/v1/saved/:id/note. The row
+-- hangs off saved_opportunities, so unsaving an item drops its note too.
+
+CREATE TABLE IF NOT EXISTS saved_opportunity_notes (
+    user_id UUID NOT NULL,
+    opportunity_id UUID NOT NULL,
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    note TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, opportunity_id),
+    FOREIGN KEY (user_id, opportunity_id)
+        REFERENCES saved_opportunities(user_id, opportunity_id) ON DELETE CASCADE
+);
+
+-- Filtering a user's saved list by tag (tags @> ARRAY['submitted'])
+CREATE INDEX IF NOT EXISTS idx_saved_opportunity_notes_tags ON saved_opportunity_notes USING GIN (tags);
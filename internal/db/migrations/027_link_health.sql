@@ -0,0 +1,14 @@
+-- Migration 027: External link health
+-- POST /api/v1/admin/check-links re-checks external_url in batches, oldest
+-- check first. link_failures counts consecutive failed checks; link_dead is
+-- set once it reaches the threshold and cleared by the next good check, so a
+-- single outage doesn't hide a call. ?hide_dead_links=true filters on it.
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS link_status_code INTEGER,
+    ADD COLUMN IF NOT EXISTS link_checked_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS link_failures INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS link_dead BOOLEAN NOT NULL DEFAULT false;
+
+CREATE INDEX IF NOT EXISTS idx_opp_link_checked_at
+ON opportunities (link_checked_at ASC NULLS FIRST, id);
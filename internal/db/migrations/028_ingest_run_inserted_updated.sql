@@ -0,0 +1,8 @@
+-- Migration 028: Split saved items into new and updated rows
+-- items_saved counts every successful upsert; items_inserted and
+-- items_updated tell a crawl that discovers new calls apart from one that
+-- only refreshes known rows. Runs recorded before this stay at 0.
+
+ALTER TABLE ingest_runs
+    ADD COLUMN IF NOT EXISTS items_inserted INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS items_updated INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,37 @@
+-- Migration 029: normalized_status change history
+-- Every change of normalized_status, whether from ingestion, a recompute or
+-- archiving, is recorded by trigger so ops can review what flipped
+-- (GET /api/v1/admin/opportunities/status-changed) and catch status engine
+-- misclassifications. Inserts aren't changes and aren't recorded.
+
+CREATE TABLE IF NOT EXISTS opportunity_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    opportunity_id UUID NOT NULL REFERENCES opportunities(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    status_reason TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_status_history_changed_at
+    ON opportunity_status_history (changed_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_status_history_opportunity
+    ON opportunity_status_history (opportunity_id, changed_at DESC);
+
+CREATE OR REPLACE FUNCTION record_opportunity_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO opportunity_status_history (opportunity_id, from_status, to_status, status_reason)
+    VALUES (NEW.id, OLD.normalized_status::text, NEW.normalized_status::text, NEW.status_reason);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trigger_record_status_change ON opportunities;
+CREATE TRIGGER trigger_record_status_change
+    AFTER UPDATE OF normalized_status
+    ON opportunities
+    FOR EACH ROW
+    WHEN (OLD.normalized_status IS DISTINCT FROM NEW.normalized_status)
+    EXECUTE FUNCTION record_opportunity_status_change();
@@ -0,0 +1,8 @@
+-- Migration 030: Fingerprint crawled content
+-- content_hash is a SHA-256 of the title, summary and structured description
+-- text a crawl extracted. A re-crawl that yields the same hash skips the LLM
+-- and embedding calls. Rows saved before this stay NULL
+-- and are fingerprinted on their next crawl.
+
+ALTER TABLE opportunities
+    ADD COLUMN IF NOT EXISTS content_hash TEXT;
@@ -0,0 +1,11 @@
+-- Migration 031: Index for all-domains enrichment runs
+--
+-- Migration 024's (source_domain, updated_at) indexes serve enrichment runs
+-- scoped to one domain, which is how the enrich_batch and enrich_recompute
+-- tools call it. POST /admin/enrich-opportunities without ?domain= has no
+-- domain to lead with, so its candidate branch fell back to scanning and
+-- sorting every eligible row. This lets it walk updated_at and stop at the
+-- batch size like the per-domain runs do.
+
+CREATE INDEX IF NOT EXISTS idx_opp_updated_at
+ON opportunities (updated_at);
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// querier is the read surface shared by *pgxpool.Pool and pgx.Tx, so the
+// list and facet queries can run on either.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var (
+	_ querier = (*pgxpool.Pool)(nil)
+	_ querier = pgx.Tx(nil)
+)
+
+// SearchResult is one page of opportunities together with the sidebar facet
+// counts for the same filters.
+type SearchResult struct {
+	ListResult
+	Aggregations *AggregationResult `json:"aggregations"`
+}
+
+// Search runs ListOpportunities and GetAggregations for one filter set inside
+// a single read-only REPEATABLE READ transaction, so the page, its total and
+// the facet counts all see the same snapshot even while ingestion writes.
+func (s *Store) Search(ctx context.Context, params ListParams) (*SearchResult, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	params.Status = s.statusOrDefault(params.Status)
+	tx, err := s.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, readErr(ctx, fmt.Errorf("begin search snapshot: %w", err))
+	}
+	defer tx.Rollback(ctx)
+
+	list, err := listOpportunities(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+	aggs, err := aggregations(ctx, tx, params)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, readErr(ctx, fmt.Errorf("commit search snapshot: %w", err))
+	}
+	return &SearchResult{ListResult: *list, Aggregations: aggs}, nil
+}
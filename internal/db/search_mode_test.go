@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestBuildOpportunityWhere_SearchMode(t *testing.T) {
+	plain, _ := buildOpportunityWhere(ListParams{Status: "all", Query: "climate resilience"}, whereOptions{})
+	if !strings.Contains(plain, "plainto_tsquery('english', $1)") || !strings.Contains(plain, "title ILIKE") {
+		t.Fatalf("plain search should keep plainto_tsquery and the title fallback: %s", plain)
+	}
+
+	query := `"climate resilience" -drought`
+	boolean, args := buildOpportunityWhere(ListParams{Status: "all", Query: query, SearchMode: SearchModeBoolean}, whereOptions{})
+	if !strings.Contains(boolean, "search_vector @@ websearch_to_tsquery('english', $1)") {
+		t.Fatalf("boolean search should use websearch_to_tsquery: %s", boolean)
+	}
+	if strings.Contains(boolean, "ILIKE") {
+		t.Fatalf("boolean search must not fall back to a raw title match: %s", boolean)
+	}
+	if len(args) != 1 || args[0] != query {
+		t.Fatalf("unexpected args: %v", args)
+	}
+
+	orderBy, _ := buildOpportunityOrder(ListParams{Query: query, SearchMode: SearchModeBoolean}, 2)
+	if !strings.Contains(orderBy, "ts_rank(search_vector, websearch_to_tsquery('english', $2::text))") {
+		t.Fatalf("ranking should use the same tsquery as the match: %s", orderBy)
+	}
+	orderBy, _ = buildOpportunityOrder(ListParams{Query: query, QueryEmbedding: []float32{0.1}, SearchMode: SearchModeBoolean}, 2)
+	if !strings.Contains(orderBy, "websearch_to_tsquery('english', $3::text)") {
+		t.Fatalf("hybrid ranking should use the same tsquery as the match: %s", orderBy)
+	}
+}
+
+// TestWebsearchQuery_PhraseAndNegation checks the operators boolean mode
+// relies on against a real Postgres. Set TEST_DATABASE_URL to enable it.
+func TestWebsearchQuery_PhraseAndNegation(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	cases := []struct {
+		doc, query string
+		want       bool
+	}{
+		{"Funding for climate resilience in coastal cities", `"climate resilience"`, true},
+		{"Resilience of supply chains under a changing climate", `"climate resilience"`, false},
+		{"Climate adaptation grants", `resilience OR adaptation`, true},
+		{"Climate adaptation grants", `resilience or mitigation`, false},
+		{"Climate adaptation grants for drought regions", `climate -drought`, false},
+		{"Climate adaptation grants for coastal regions", `climate -drought`, true},
+	}
+	for _, tc := range cases {
+		var match bool
+		err := pool.QueryRow(context.Background(),
+			"SELECT to_tsvector('english', $1) @@ "+tsqueryFunc(SearchModeBoolean)+"('english', $2)", tc.doc, tc.query).Scan(&match)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if match != tc.want {
+			t.Errorf("%q @@ %q = %v, want %v", tc.doc, tc.query, match, tc.want)
+		}
+	}
+}
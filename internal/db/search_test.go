@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestFacetWhere_UsesWholeListFilter(t *testing.T) {
+	openAfter := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	list := ListParams{
+		Query:          "climate",
+		SearchMode:     SearchModeBoolean,
+		Status:         "closed",
+		Region:         []string{"Europe"},
+		FunderType:     []string{"Government"},
+		Country:        []string{"Peru"},
+		AgencyName:     []string{"NIH"},
+		Type:           []string{"grant"},
+		DocType:        []string{"Grant"},
+		CFDA:           []string{"93.242"},
+		ExcludeTenders: true,
+		MinConfidence:  0.7,
+		// Filters the facet endpoint itself doesn't take still narrow
+		// Search's counts.
+		Source:        "anid.cl",
+		MinAmount:     50000,
+		OpenAfter:     &openAfter,
+		HideDeadLinks: true,
+		Limit:         20,
+		Offset:        40,
+	}
+	listWhere, listArgs := buildOpportunityWhere(list, whereOptions{})
+	aggWhere, aggArgs := facetWhere(list, "")
+	if listWhere != aggWhere {
+		t.Fatalf("search facets and list predicates differ:\nlist: %s\nagg:  %s", listWhere, aggWhere)
+	}
+	if fmt.Sprint(listArgs) != fmt.Sprint(aggArgs) {
+		t.Fatalf("search facets and list args differ: %v vs %v", listArgs, aggArgs)
+	}
+
+	regionWhere, _ := facetWhere(list, "region")
+	for _, want := range []string{"source_domain =", "amount_max", "open_at", "link_dead"} {
+		if !strings.Contains(regionWhere, want) {
+			t.Errorf("region facet lost the %q filter: %s", want, regionWhere)
+		}
+	}
+	if strings.Contains(regionWhere, "region = ANY") {
+		t.Errorf("region facet should drop its own filter: %s", regionWhere)
+	}
+}
+
+func TestSearchResult_JSONShape(t *testing.T) {
+	body, err := json.Marshal(SearchResult{
+		ListResult:   ListResult{Total: 3, Limit: 20},
+		Aggregations: &AggregationResult{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"opportunities", "total", "limit", "offset", "has_more", "aggregations"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing top-level %q in %s", key, body)
+		}
+	}
+}
+
+// TestSearch_ListAndFacetsAgree runs against a migrated database; set
+// TEST_DATABASE_URL to enable it.
+func TestSearch_ListAndFacetsAgree(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain, agency = "search-snapshot-test.example.org", "Search Snapshot Test Agency"
+	const otherDomain = "search-snapshot-other.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain IN ($1, $2)`, domain, otherDomain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	for i := 0; i < 3; i++ {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, agency_name)
+			VALUES ($1, $2, $3, $4, $5)`,
+			fmt.Sprintf("Snapshot grant %d", i), fmt.Sprintf("https://%s/%d", domain, i), domain, fmt.Sprint(i), agency); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Same agency on another source: the Source filter, which the facet
+	// endpoint doesn't take, must keep it out of the facet count too.
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO opportunities (title, external_url, source_domain, source_id, agency_name)
+		VALUES ('Snapshot grant elsewhere', $1, $2, 'other', $3)`,
+		"https://"+otherDomain+"/other", otherDomain, agency); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewStore(pool).Search(ctx, ListParams{Status: "all", Source: domain, AgencyName: []string{agency}, Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 || len(result.Opportunities) != 2 {
+		t.Fatalf("expected page of 2 out of 3, got %d of %d", len(result.Opportunities), result.Total)
+	}
+	var facet int
+	for _, ag := range result.Aggregations.Agencies {
+		if ag.Value == agency {
+			facet = ag.Count
+		}
+	}
+	if facet != result.Total {
+		t.Fatalf("agency facet = %d, want the list total %d", facet, result.Total)
+	}
+}
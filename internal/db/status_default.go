@@ -0,0 +1,46 @@
+package db
+
+import (
+	"log"
+	"os"
+	"strings"
+)
+
+// DefaultStatusFilter is the status tab list, count and facet queries use
+// when the request names none and DEFAULT_STATUS_FILTER is unset.
+const DefaultStatusFilter = "open"
+
+// statusFilters are the values ?status= and DEFAULT_STATUS_FILTER accept.
+var statusFilters = map[string]bool{
+	"open": true, "active": true, "posted": true,
+	"upcoming": true, "forthcoming": true,
+	"closed": true, "archived": true, "funded": true, "needs_review": true,
+	"all": true,
+}
+
+// defaultStatusFromEnv reads DEFAULT_STATUS_FILTER ("open", "all",
+// "upcoming", ...). Unset or unknown values fall back to DefaultStatusFilter.
+func defaultStatusFromEnv() string {
+	raw := strings.ToLower(strings.TrimSpace(os.Getenv("DEFAULT_STATUS_FILTER")))
+	if raw == "" {
+		return DefaultStatusFilter
+	}
+	if !statusFilters[raw] {
+		log.Printf("Ignoring unknown DEFAULT_STATUS_FILTER %q; using %q", raw, DefaultStatusFilter)
+		return DefaultStatusFilter
+	}
+	return raw
+}
+
+// statusOrDefault resolves an empty request status to the deployment
+// default, so list, count and facets all filter the same rows. An explicit
+// status, including "all", is kept as is.
+func (s *Store) statusOrDefault(status string) string {
+	if status != "" {
+		return status
+	}
+	if s.defaultStatus == "" {
+		return DefaultStatusFilter
+	}
+	return s.defaultStatus
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDefaultStatusFilter_FromEnv(t *testing.T) {
+	t.Setenv("DEFAULT_STATUS_FILTER", "All")
+	s := NewStore(nil)
+
+	if got := s.statusOrDefault(""); got != "all" {
+		t.Fatalf("empty status should resolve to the configured default, got %q", got)
+	}
+	if got := s.statusOrDefault("open"); got != "open" {
+		t.Fatalf("an explicit status must win over the default, got %q", got)
+	}
+
+	// List and facets resolve the same default, so they filter the same rows.
+	list := ListParams{Status: s.statusOrDefault("")}
+	agg := AggregationParams{Status: s.statusOrDefault("")}
+	listWhere, _ := buildOpportunityWhere(list, whereOptions{})
+	aggWhere, _ := buildAggregationWhereExcluding(agg, "")
+	if listWhere != aggWhere {
+		t.Fatalf("list and facets disagree under the default:\nlist: %s\nagg:  %s", listWhere, aggWhere)
+	}
+	if strings.Contains(listWhere, "normalized_status") {
+		t.Fatalf(`default "all" should not filter by status: %s`, listWhere)
+	}
+}
+
+func TestDefaultStatusFilter_UnsetOrUnknownKeepsOpen(t *testing.T) {
+	for _, raw := range []string{"", "everything"} {
+		t.Setenv("DEFAULT_STATUS_FILTER", raw)
+		if got := NewStore(nil).statusOrDefault(""); got != DefaultStatusFilter {
+			t.Errorf("DEFAULT_STATUS_FILTER=%q: got %q, want %q", raw, got, DefaultStatusFilter)
+		}
+	}
+
+	explicitAll, _ := buildOpportunityWhere(ListParams{Status: "all"}, whereOptions{})
+	if strings.Contains(explicitAll, "normalized_status") {
+		t.Fatalf("status=all must stay an explicit escape from the default: %s", explicitAll)
+	}
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// normalizedStatuses are the values of normalized_status_enum, the only
+// statuses a history row can hold.
+var normalizedStatuses = map[string]bool{
+	"open": true, "upcoming": true, "closed": true,
+	"archived": true, "funded": true, "needs_review": true,
+}
+
+// IsNormalizedStatus reports whether status is a normalized_status value.
+func IsNormalizedStatus(status string) bool {
+	return normalizedStatuses[status]
+}
+
+// StatusChangeParams selects status changes made in [Since, To). Empty
+// FromStatus or ToStatus match any status.
+type StatusChangeParams struct {
+	Since      time.Time
+	To         time.Time
+	FromStatus string
+	ToStatus   string
+	Limit      int
+}
+
+// StatusChange is one recorded normalized_status change, with the
+// opportunity as it is now.
+type StatusChange struct {
+	OpportunityID  string     `json:"opportunity_id"`
+	Title          string     `json:"title"`
+	ExternalURL    string     `json:"external_url"`
+	SourceDomain   string     `json:"source_domain"`
+	FromStatus     string     `json:"from_status"`
+	ToStatus       string     `json:"to_status"`
+	StatusReason   string     `json:"status_reason,omitempty"`
+	CurrentStatus  string     `json:"current_status"`
+	NextDeadlineAt *time.Time `json:"next_deadline_at,omitempty"`
+	ChangedAt      time.Time  `json:"changed_at"`
+}
+
+// statusChangesQuery builds the history query for params, newest change
+// first.
+func statusChangesQuery(params StatusChangeParams) (string, []interface{}) {
+	var b strings.Builder
+	b.WriteString(`
+		SELECT h.opportunity_id, o.title, COALESCE(o.external_url, ''), COALESCE(o.source_domain, ''),
+		       h.from_status, h.to_status, COALESCE(h.status_reason, ''),
+		       o.normalized_status::text, o.next_deadline_at, h.changed_at
+		FROM opportunity_status_history h
+		JOIN opportunities o ON o.id = h.opportunity_id
+		WHERE h.changed_at >= $1 AND h.changed_at < $2`)
+	args := []interface{}{params.Since, params.To}
+	if params.FromStatus != "" {
+		args = append(args, params.FromStatus)
+		fmt.Fprintf(&b, " AND h.from_status = $%d", len(args))
+	}
+	if params.ToStatus != "" {
+		args = append(args, params.ToStatus)
+		fmt.Fprintf(&b, " AND h.to_status = $%d", len(args))
+	}
+	args = append(args, params.Limit)
+	fmt.Fprintf(&b, "\n\t\tORDER BY h.changed_at DESC, h.id DESC\n\t\tLIMIT $%d", len(args))
+	return b.String(), args
+}
+
+// ListStatusChanges returns the normalized_status changes recorded in the
+// params window, most recent first.
+func (s *Store) ListStatusChanges(ctx context.Context, params StatusChangeParams) ([]StatusChange, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	sql, args := statusChangesQuery(params)
+	rows, err := s.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, readErr(ctx, err)
+	}
+	defer rows.Close()
+
+	changes := []StatusChange{}
+	for rows.Next() {
+		var ch StatusChange
+		if err := rows.Scan(&ch.OpportunityID, &ch.Title, &ch.ExternalURL, &ch.SourceDomain,
+			&ch.FromStatus, &ch.ToStatus, &ch.StatusReason,
+			&ch.CurrentStatus, &ch.NextDeadlineAt, &ch.ChangedAt); err != nil {
+			return nil, readErr(ctx, err)
+		}
+		changes = append(changes, ch)
+	}
+	return changes, readErr(ctx, rows.Err())
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestStatusChangesQuery_OptionalStatusFilters(t *testing.T) {
+	since := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := since.Add(24 * time.Hour)
+
+	sql, args := statusChangesQuery(StatusChangeParams{Since: since, To: to, Limit: 50})
+	if strings.Contains(sql, "from_status =") || strings.Contains(sql, "to_status =") || len(args) != 3 {
+		t.Fatalf("unexpected status filters without params: %s %v", sql, args)
+	}
+
+	sql, args = statusChangesQuery(StatusChangeParams{Since: since, To: to, FromStatus: "open", ToStatus: "closed", Limit: 50})
+	for _, want := range []string{"h.from_status = $3", "h.to_status = $4", "LIMIT $5", "ORDER BY h.changed_at DESC"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query missing %q:\n%s", want, sql)
+		}
+	}
+	if len(args) != 5 || args[2] != "open" || args[3] != "closed" || args[4] != 50 {
+		t.Fatalf("unexpected args %v", args)
+	}
+}
+
+// TestListStatusChanges_RecordsFlips runs against a migrated database; set
+// TEST_DATABASE_URL to enable it.
+func TestListStatusChanges_RecordsFlips(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "status-history-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	var id string
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO opportunities (title, external_url, source_domain, source_id, normalized_status)
+		VALUES ('History grant', 'https://status-history-test.example.org/1', $1, '1', 'open')
+		RETURNING id`, domain).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Add(-time.Minute)
+	for _, status := range []string{"open", "closed", "needs_review"} {
+		if _, err := pool.Exec(ctx, `UPDATE opportunities SET normalized_status = $1::normalized_status_enum WHERE id = $2`, status, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewStore(pool)
+	window := StatusChangeParams{Since: before, To: time.Now().Add(time.Minute), Limit: 100}
+	changes, err := store.ListStatusChanges(ctx, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mine []StatusChange
+	for _, ch := range changes {
+		if ch.OpportunityID == id {
+			mine = append(mine, ch)
+		}
+	}
+	// The open -> open update is not a change.
+	if len(mine) != 2 {
+		t.Fatalf("expected 2 recorded changes, got %+v", mine)
+	}
+	if mine[0].FromStatus != "closed" || mine[0].ToStatus != "needs_review" || mine[0].CurrentStatus != "needs_review" {
+		t.Fatalf("expected the latest change first, got %+v", mine[0])
+	}
+
+	window.FromStatus, window.ToStatus = "open", "closed"
+	changes, err = store.ListStatusChanges(ctx, window)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, ch := range changes {
+		if ch.FromStatus != "open" || ch.ToStatus != "closed" {
+			t.Fatalf("status filters not applied: %+v", ch)
+		}
+	}
+}
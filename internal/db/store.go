@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -13,15 +14,48 @@ import (
 )
 
 type Store struct {
-	pool *pgxpool.Pool
+	pool          *pgxpool.Pool
+	readTimeout   time.Duration
+	defaultStatus string
 }
 
 func NewStore(pool *pgxpool.Pool) *Store {
-	return &Store{pool: pool}
+	return &Store{pool: pool, readTimeout: readTimeoutFromEnv(), defaultStatus: defaultStatusFromEnv()}
+}
+
+// PoolStats is a point-in-time snapshot of the connection pool. EmptyAcquireCount
+// counts acquires that had to wait for a free connection; if it climbs steadily,
+// raise DB_MAX_CONNS.
+type PoolStats struct {
+	MaxConns             int32   `json:"max_conns"`
+	TotalConns           int32   `json:"total_conns"`
+	AcquiredConns        int32   `json:"acquired_conns"`
+	IdleConns            int32   `json:"idle_conns"`
+	ConstructingConns    int32   `json:"constructing_conns"`
+	AcquireCount         int64   `json:"acquire_count"`
+	EmptyAcquireCount    int64   `json:"empty_acquire_count"`
+	CanceledAcquireCount int64   `json:"canceled_acquire_count"`
+	AcquireDurationSec   float64 `json:"acquire_duration_seconds"`
+}
+
+func (s *Store) PoolStats() PoolStats {
+	st := s.pool.Stat()
+	return PoolStats{
+		MaxConns:             st.MaxConns(),
+		TotalConns:           st.TotalConns(),
+		AcquiredConns:        st.AcquiredConns(),
+		IdleConns:            st.IdleConns(),
+		ConstructingConns:    st.ConstructingConns(),
+		AcquireCount:         st.AcquireCount(),
+		EmptyAcquireCount:    st.EmptyAcquireCount(),
+		CanceledAcquireCount: st.CanceledAcquireCount(),
+		AcquireDurationSec:   st.AcquireDuration().Seconds(),
+	}
 }
 
 type ListParams struct {
 	Query          string
+	SearchMode     string // "" (plain: all terms AND-ed) or "boolean" (quoted phrases, OR, -negation)
 	QueryEmbedding []float32
 	Source         string
 	MinAmount      float64
@@ -37,9 +71,20 @@ type ListParams struct {
 	Country        []string
 	AgencyCode     string
 	AgencyName     []string
+	Type           []string
+	DocType        []string
+	CFDA           []string // CFDA/ALN program numbers, e.g. "93.242"; matches any
 	SortBy         string
-	Status         string // "posted" (default), "closed", "archived", "forthcoming", "needs_review", or "all"
-	ExcludeExpired bool   // Deprecated: use Status filter instead
+	Status         string // "open" (default), "upcoming", "closed", "archived", "funded", "needs_review", or "all"
+	OpenAfter      *time.Time
+	OpenBefore     *time.Time
+	CreatedAfter   *time.Time    // Only rows first ingested at or after this time
+	MaxAge         time.Duration // Only rows enriched (or re-crawled) within this long
+	ExcludeTenders bool          // Drop procurement tenders/contracts from grant-focused results
+	MinConfidence  float64       // Only rows whose status_confidence is at least this (0 = no filter)
+	HideDeadLinks  bool          // Drop rows whose external_url kept failing link checks
+	ExcludeExpired bool          // Deprecated: use Status filter instead
+	Explain        bool          // Attach a RankExplanation (scores, matched filters) to each row
 }
 
 type ListResult struct {
@@ -47,30 +92,32 @@ type ListResult struct {
 	Total         int                  `json:"total"`
 	Limit         int                  `json:"limit"`
 	Offset        int                  `json:"offset"`
+	HasMore       bool                 `json:"has_more"`
 }
 
 // selectCols is the comprehensive column list for all queries.
 const selectCols = `id, title, summary, external_url, source_domain,
-	source_id, opportunity_number, agency_name, agency_code, funder_type,
+	source_id, opportunity_number, agency_name, agency_code, funder_type, funder_type_label,
 	amount_min, amount_max, currency, deadline_at, next_deadline_at, open_date, open_at, close_at, expiration_at,
-	is_rolling, rolling_evidence, doc_type, cfda_list, opp_status, source_status_raw, normalized_status, status_reason, deadlines, is_results_page,
+	is_rolling, rolling_evidence, opportunity_type, doc_type, cfda_list, opp_status, source_status_raw, normalized_status, status_reason, deadlines, is_results_page,
 	source_evidence_json, status_confidence,
-	region, country, categories, eligibility, created_at`
+	region, country, categories, eligibility, created_at, updated_at, last_enriched_at`
 
 func scanOpportunity(scan func(dest ...interface{}) error) (models.Opportunity, error) {
 	var o models.Opportunity
-	var summary, sourceID, oppNum, agencyName, agencyCode, funderType *string
-	var docType, oppStatus, sourceStatusRaw, normalizedStatus, statusReason, region, country *string
+	var summary, sourceID, oppNum, agencyName, agencyCode, funderType, funderTypeLabel *string
+	var oppType, docType, oppStatus, sourceStatusRaw, normalizedStatus, statusReason, region, country *string
 	var deadlinesRaw []byte
 	var evidenceRaw []byte
+	var updatedAt *time.Time
 
 	err := scan(
 		&o.ID, &o.Title, &summary, &o.ExternalURL, &o.SourceDomain,
-		&sourceID, &oppNum, &agencyName, &agencyCode, &funderType,
+		&sourceID, &oppNum, &agencyName, &agencyCode, &funderType, &funderTypeLabel,
 		&o.AmountMin, &o.AmountMax, &o.Currency, &o.DeadlineAt, &o.NextDeadlineAt, &o.OpenDate, &o.OpenAt, &o.CloseAt, &o.ExpirationAt,
-		&o.IsRolling, &o.RollingEvidence, &docType, &o.CfdaList, &oppStatus, &sourceStatusRaw, &normalizedStatus, &statusReason, &deadlinesRaw, &o.IsResultsPage,
+		&o.IsRolling, &o.RollingEvidence, &oppType, &docType, &o.CfdaList, &oppStatus, &sourceStatusRaw, &normalizedStatus, &statusReason, &deadlinesRaw, &o.IsResultsPage,
 		&evidenceRaw, &o.StatusConfidence,
-		&region, &country, &o.Categories, &o.Eligibility, &o.CreatedAt,
+		&region, &country, &o.Categories, &o.Eligibility, &o.CreatedAt, &updatedAt, &o.LastEnrichedAt,
 	)
 	if err != nil {
 		return o, err
@@ -95,6 +142,12 @@ func scanOpportunity(scan func(dest ...interface{}) error) (models.Opportunity,
 	if funderType != nil {
 		o.FunderType = *funderType
 	}
+	if funderTypeLabel != nil {
+		o.FunderTypeLabel = *funderTypeLabel
+	}
+	if oppType != nil {
+		o.Type = *oppType
+	}
 	if docType != nil {
 		o.DocType = *docType
 	}
@@ -122,19 +175,201 @@ func scanOpportunity(scan func(dest ...interface{}) error) (models.Opportunity,
 	if country != nil {
 		o.Country = *country
 	}
+	if updatedAt != nil {
+		o.UpdatedAt = *updatedAt
+	}
+
+	// Rows the enrichment batch never touched are as fresh as their last crawl.
+	checkedAt := o.LastEnrichedAt
+	if checkedAt == nil && updatedAt != nil {
+		checkedAt = updatedAt
+	}
+	o.Freshness = Freshness(checkedAt, o.SourceDomain, time.Now())
 
 	return o, nil
 }
 
 func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*ListResult, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+	params.Status = s.statusOrDefault(params.Status)
+	return listOpportunities(ctx, s.pool, params)
+}
+
+// listOpportunities runs the count and page queries on q, the pool or a
+// snapshot transaction.
+func listOpportunities(ctx context.Context, q querier, params ListParams) (*ListResult, error) {
 	// 1. Build WHERE clause and Args
+	where, args := buildOpportunityWhere(params, whereOptions{})
+	argIdx := len(args) + 1
+
+	// 2. Count Total
+	var total int
+	countSQL := "SELECT COUNT(*) FROM opportunities " + where
+	if err := q.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
+		return nil, readErr(ctx, fmt.Errorf("count failed: %w", err))
+	}
+
+	// 3. Select Data with Scoring/Sorting
+	cols := selectCols
+	if params.Explain {
+		explainCols, explainArgs := explainSelect(params, argIdx)
+		cols += explainCols
+		args = append(args, explainArgs...)
+		argIdx += len(explainArgs)
+	}
+	selectSQL := fmt.Sprintf("SELECT %s FROM opportunities %s", cols, where)
+
+	// Sorting
+	orderBy, orderArgs := buildOpportunityOrder(params, argIdx)
+	selectSQL += orderBy
+	args = append(args, orderArgs...)
+	argIdx += len(orderArgs)
+
+	// Pagination
+	selectSQL += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	args = append(args, params.Limit, params.Offset)
+
+	// Execute
+	rows, err := q.Query(ctx, selectSQL, args...)
+	if err != nil {
+		return nil, readErr(ctx, fmt.Errorf("query failed: %w", err))
+	}
+	defer rows.Close()
+
+	var filters []string
+	if params.Explain {
+		filters = activeFilters(params)
+	}
+	var opps []models.Opportunity
+	for rows.Next() {
+		scan := rows.Scan
+		var ex explainRow
+		if params.Explain {
+			scan = func(dest ...interface{}) error {
+				return rows.Scan(append(dest, ex.dests()...)...)
+			}
+		}
+		o, err := scanOpportunity(scan)
+		if err != nil {
+			return nil, readErr(ctx, fmt.Errorf("scan failed: %w", err))
+		}
+		if params.Explain {
+			o.Explain = ex.explanation(filters)
+		}
+		opps = append(opps, o)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, readErr(ctx, fmt.Errorf("rows iteration failed: %w", err))
+	}
+
+	if opps == nil {
+		opps = []models.Opportunity{}
+	}
+
+	return &ListResult{
+		Opportunities: opps,
+		Total:         total,
+		Limit:         params.Limit,
+		Offset:        params.Offset,
+	}, nil
+}
+
+// buildOpportunityOrder returns the ORDER BY clause for ListOpportunities and
+// any args it binds, numbered from argIdx. Every branch ends on id so rows with
+// equal sort keys keep the same order from one page request to the next.
+func buildOpportunityOrder(params ListParams, argIdx int) (string, []interface{}) {
+	switch params.SortBy {
+	case "deadline":
+		return " ORDER BY next_deadline_at ASC NULLS LAST, deadline_at ASC NULLS LAST, id", nil
+	case "amount_desc":
+		return " ORDER BY amount_max DESC NULLS LAST, id", nil
+	case "newest":
+		return " ORDER BY open_date DESC NULLS LAST, created_at DESC, id", nil
+	case "recent":
+		// Newest to the index, not newest call.
+		return " ORDER BY created_at DESC, id", nil
+	}
+
+	// "relevance"
+	if isUpcomingStatus(params.Status) && params.Query == "" {
+		// Upcoming calls are browsed by when they open.
+		return " ORDER BY open_at ASC NULLS LAST, created_at DESC, id", nil
+	}
+	if len(params.QueryEmbedding) > 0 {
+		vectorArg := argIdx
+		queryArg := argIdx + 1
+		return fmt.Sprintf(`
+				ORDER BY
+					CASE WHEN embedding IS NULL THEN 1 ELSE 0 END ASC,
+					COALESCE(1 - (embedding <=> $%d), -1) DESC,
+					CASE WHEN NULLIF($%d::text, '') IS NULL THEN 0 ELSE ts_rank(search_vector, %s('english', $%d::text)) END DESC,
+					updated_at DESC NULLS LAST,
+					created_at DESC,
+					id
+			`, vectorArg, queryArg, tsqueryFunc(params.SearchMode), queryArg), []interface{}{pgvector.NewVector(params.QueryEmbedding), params.Query}
+	}
+	if params.Query != "" {
+		return fmt.Sprintf(" ORDER BY ts_rank(search_vector, %s('english', $%d::text)) DESC, updated_at DESC NULLS LAST, created_at DESC, id", tsqueryFunc(params.SearchMode), argIdx), []interface{}{params.Query}
+	}
+	return " ORDER BY updated_at DESC NULLS LAST, created_at DESC, id", nil
+}
+
+// CountOpportunities runs only the count half of ListOpportunities.
+func (s *Store) CountOpportunities(ctx context.Context, params ListParams) (int, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	params.Status = s.statusOrDefault(params.Status)
+	where, args := buildOpportunityWhere(params, whereOptions{})
+
+	var total int
+	countSQL := "SELECT COUNT(*) FROM opportunities " + where
+	if err := s.pool.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
+		return 0, readErr(ctx, fmt.Errorf("count failed: %w", err))
+	}
+	return total, nil
+}
+
+// SearchModeBoolean makes ListParams.Query a web-search expression:
+// "quoted phrases", OR between alternatives and -term to exclude.
+const SearchModeBoolean = "boolean"
+
+// tsqueryFunc is the Postgres function that turns a query in the given
+// search mode into a tsquery.
+func tsqueryFunc(mode string) string {
+	if mode == SearchModeBoolean {
+		return "websearch_to_tsquery"
+	}
+	return "plainto_tsquery"
+}
+
+// whereOptions tweaks buildOpportunityWhere for callers that need a variation
+// of the shared filter.
+type whereOptions struct {
+	// excludeDimension names a facet dimension ("status", "region",
+	// "funder_type", "country", "agency_name", "type", "doc_type") whose filter is skipped,
+	// used for cross-faceted aggregation counts.
+	excludeDimension string
+}
+
+// buildOpportunityWhere builds the WHERE clause shared by list, count and
+// aggregation queries so their results can't drift apart.
+func buildOpportunityWhere(params ListParams, opts whereOptions) (string, []interface{}) {
 	where := "WHERE 1=1"
 	var args []interface{}
 	argIdx := 1
 
 	// Hybrid Search / Scoring
 	if params.Query != "" {
-		where += fmt.Sprintf(" AND (search_vector @@ plainto_tsquery('english', $%d) OR title ILIKE '%%' || $%d || '%%')", argIdx, argIdx)
+		if params.SearchMode == SearchModeBoolean {
+			// No title ILIKE fallback: it would match the raw operator text
+			// and let a negated term back in.
+			where += fmt.Sprintf(" AND search_vector @@ websearch_to_tsquery('english', $%d)", argIdx)
+		} else {
+			where += fmt.Sprintf(" AND (search_vector @@ plainto_tsquery('english', $%d) OR title ILIKE '%%' || $%d || '%%')", argIdx, argIdx)
+		}
 		args = append(args, params.Query)
 		argIdx++
 	}
@@ -144,17 +379,19 @@ func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*List
 		args = append(args, params.Source)
 		argIdx++
 	}
-	if len(params.Region) > 0 {
+	if len(params.Region) > 0 && opts.excludeDimension != "region" {
 		where += fmt.Sprintf(" AND region = ANY($%d)", argIdx)
 		args = append(args, params.Region)
 		argIdx++
 	}
-	if len(params.FunderType) > 0 {
-		where += fmt.Sprintf(" AND funder_type = ANY($%d)", argIdx)
-		args = append(args, params.FunderType)
+	if len(params.FunderType) > 0 && opts.excludeDimension != "funder_type" {
+		// Canonical funder types are lower-case; LOWER() also matches rows
+		// stored before the taxonomy backfill ran.
+		where += fmt.Sprintf(" AND LOWER(funder_type) = ANY($%d)", argIdx)
+		args = append(args, lowerAll(params.FunderType))
 		argIdx++
 	}
-	if len(params.Country) > 0 {
+	if len(params.Country) > 0 && opts.excludeDimension != "country" {
 		where += fmt.Sprintf(" AND country = ANY($%d)", argIdx)
 		args = append(args, params.Country)
 		argIdx++
@@ -164,11 +401,30 @@ func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*List
 		args = append(args, params.AgencyCode)
 		argIdx++
 	}
-	if len(params.AgencyName) > 0 {
+	if len(params.AgencyName) > 0 && opts.excludeDimension != "agency_name" {
 		where += fmt.Sprintf(" AND agency_name = ANY($%d)", argIdx)
 		args = append(args, params.AgencyName)
 		argIdx++
 	}
+	if len(params.Type) > 0 && opts.excludeDimension != "type" {
+		where += fmt.Sprintf(" AND opportunity_type = ANY($%d)", argIdx)
+		args = append(args, params.Type)
+		argIdx++
+	}
+	if len(params.DocType) > 0 && opts.excludeDimension != "doc_type" {
+		where += fmt.Sprintf(" AND doc_type = ANY($%d)", argIdx)
+		args = append(args, params.DocType)
+		argIdx++
+	}
+	if len(params.CFDA) > 0 && opts.excludeDimension != "cfda" {
+		// Overlap (&&) rather than ANY so idx_opp_cfda_list_gin is usable
+		where += fmt.Sprintf(" AND cfda_list && $%d", argIdx)
+		args = append(args, params.CFDA)
+		argIdx++
+	}
+	if params.ExcludeTenders {
+		where += buildExcludeTendersConstraint()
+	}
 	if params.MinAmount > 0 {
 		where += fmt.Sprintf(" AND amount_max >= $%d", argIdx)
 		args = append(args, params.MinAmount)
@@ -179,24 +435,34 @@ func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*List
 		args = append(args, params.MaxAmount)
 		argIdx++
 	}
-	// Status Filter logic on normalized_status.
+	// Status Filter logic on normalized_status. Store methods resolve an
+	// empty status to the configured default before they get here.
 	targetStatus := params.Status
 	if targetStatus == "" {
-		targetStatus = "open"
+		targetStatus = DefaultStatusFilter
 	}
 
 	if targetStatus == "active" {
 		targetStatus = "open"
 	}
 
-	if targetStatus == "open" {
+	if targetStatus == "forthcoming" {
+		targetStatus = "upcoming"
+	}
+
+	if opts.excludeDimension == "status" {
+		// Status facet counts span every status.
+	} else if targetStatus == "open" {
 		where += buildOpenTabConstraint()
+	} else if targetStatus == "upcoming" {
+		where += buildUpcomingTabConstraint()
 	} else if targetStatus == "all" {
 		// No filter.
 	} else if targetStatus == "closed" {
 		where += " AND normalized_status::text IN ('closed','archived')"
 	} else {
-		// Support product statuses: open, upcoming, closed, archived, needs_review.
+		// Support product statuses: open, upcoming, closed, archived, funded, needs_review.
+		// "funded" (grants awarded) is kept out of the closed tab on purpose.
 		if targetStatus == "posted" {
 			targetStatus = "open"
 		}
@@ -206,6 +472,40 @@ func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*List
 		argIdx++
 	}
 
+	// Applies on top of the status tab: "open, and at least this sure of it".
+	if params.MinConfidence > 0 {
+		where += fmt.Sprintf(" AND COALESCE(status_confidence, 0) >= $%d", argIdx)
+		args = append(args, params.MinConfidence)
+		argIdx++
+	}
+
+	if params.OpenAfter != nil {
+		where += fmt.Sprintf(" AND open_at >= $%d", argIdx)
+		args = append(args, *params.OpenAfter)
+		argIdx++
+	}
+	if params.OpenBefore != nil {
+		where += fmt.Sprintf(" AND open_at <= $%d", argIdx)
+		args = append(args, *params.OpenBefore)
+		argIdx++
+	}
+
+	if params.HideDeadLinks {
+		where += " AND link_dead = false"
+	}
+
+	if params.CreatedAfter != nil {
+		where += fmt.Sprintf(" AND created_at >= $%d", argIdx)
+		args = append(args, *params.CreatedAfter)
+		argIdx++
+	}
+
+	if params.MaxAge > 0 {
+		where += fmt.Sprintf(" AND COALESCE(last_enriched_at, updated_at) >= NOW() - ($%d * INTERVAL '1 second')", argIdx)
+		args = append(args, int64(params.MaxAge/time.Second))
+		argIdx++
+	}
+
 	// Deadline days filter (if specified, overrides default expired filter for deadline)
 	if params.DeadlineDays > 0 {
 		where += fmt.Sprintf(`
@@ -242,87 +542,28 @@ func (s *Store) ListOpportunities(ctx context.Context, params ListParams) (*List
 		argIdx++
 	}
 
-	// 2. Count Total
-	var total int
-	countSQL := "SELECT COUNT(*) FROM opportunities " + where
-	if err := s.pool.QueryRow(ctx, countSQL, args...).Scan(&total); err != nil {
-		return nil, fmt.Errorf("count failed: %w", err)
-	}
-
-	// 3. Select Data with Scoring/Sorting
-	selectSQL := fmt.Sprintf("SELECT %s FROM opportunities %s", selectCols, where)
-
-	// Sorting
-	switch params.SortBy {
-	case "deadline":
-		selectSQL += " ORDER BY next_deadline_at ASC NULLS LAST, deadline_at ASC NULLS LAST"
-	case "amount_desc":
-		selectSQL += " ORDER BY amount_max DESC NULLS LAST"
-	case "newest":
-		selectSQL += " ORDER BY open_date DESC NULLS LAST, created_at DESC"
-	default: // "relevance"
-		if len(params.QueryEmbedding) > 0 {
-			vectorArg := argIdx
-			queryArg := argIdx + 1
-			args = append(args, pgvector.NewVector(params.QueryEmbedding), params.Query)
-			argIdx += 2
-
-			selectSQL += fmt.Sprintf(`
-				ORDER BY
-					CASE WHEN embedding IS NULL THEN 1 ELSE 0 END ASC,
-					COALESCE(1 - (embedding <=> $%d), -1) DESC,
-					CASE WHEN NULLIF($%d::text, '') IS NULL THEN 0 ELSE ts_rank(search_vector, plainto_tsquery('english', $%d::text)) END DESC,
-					updated_at DESC NULLS LAST,
-					created_at DESC
-			`, vectorArg, queryArg, queryArg)
-		} else if params.Query != "" {
-			queryArg := argIdx
-			args = append(args, params.Query)
-			argIdx++
-			selectSQL += fmt.Sprintf(" ORDER BY ts_rank(search_vector, plainto_tsquery('english', $%d::text)) DESC, updated_at DESC NULLS LAST, created_at DESC", queryArg)
-		} else {
-			selectSQL += " ORDER BY updated_at DESC NULLS LAST, created_at DESC"
-		}
-	}
-
-	// Pagination
-	selectSQL += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
-	args = append(args, params.Limit, params.Offset)
-
-	// Execute
-	rows, err := s.pool.Query(ctx, selectSQL, args...)
-	if err != nil {
-		return nil, fmt.Errorf("query failed: %w", err)
-	}
-	defer rows.Close()
-
-	var opps []models.Opportunity
-	for rows.Next() {
-		o, err := scanOpportunity(rows.Scan)
-		if err != nil {
-			return nil, fmt.Errorf("scan failed: %w", err)
-		}
-		opps = append(opps, o)
-	}
+	return where, args
+}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration failed: %w", err)
-	}
+func buildOpenTabConstraint() string {
+	return " AND normalized_status = 'open' AND is_results_page = false AND (open_at IS NULL OR open_at <= NOW()) AND (rolling_evidence = true OR next_deadline_at >= NOW() OR close_at >= NOW())"
+}
 
-	if opps == nil {
-		opps = []models.Opportunity{}
-	}
+// buildUpcomingTabConstraint matches calls that have not opened yet. A future
+// open_at qualifies on its own, even without a deadline, so rows whose status
+// has not been recomputed since ingest still land here rather than in "open".
+func buildUpcomingTabConstraint() string {
+	return " AND is_results_page = false AND (normalized_status = 'upcoming' OR (open_at > NOW() AND normalized_status::text NOT IN ('closed','archived','funded')))"
+}
 
-	return &ListResult{
-		Opportunities: opps,
-		Total:         total,
-		Limit:         params.Limit,
-		Offset:        params.Offset,
-	}, nil
+func isUpcomingStatus(status string) bool {
+	return status == "upcoming" || status == "forthcoming"
 }
 
-func buildOpenTabConstraint() string {
-	return " AND normalized_status = 'open' AND is_results_page = false AND (rolling_evidence = true OR next_deadline_at >= NOW() OR close_at >= NOW())"
+// buildExcludeTendersConstraint filters out procurement notices (EU "Tender" doc types)
+// while keeping rows with no doc_type at all.
+func buildExcludeTendersConstraint() string {
+	return " AND (doc_type IS NULL OR doc_type NOT ILIKE 'tender%')"
 }
 
 func decodeDeadlineDates(raw []byte) []string {
@@ -389,6 +630,9 @@ func sanitizeStringSlice(values []string) []string {
 }
 
 func (s *Store) GetOpportunity(ctx context.Context, id string) (*models.Opportunity, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
 	sql := fmt.Sprintf(`
 		SELECT %s
 		FROM opportunities
@@ -398,12 +642,44 @@ func (s *Store) GetOpportunity(ctx context.Context, id string) (*models.Opportun
 
 	o, err := scanOpportunity(row.Scan)
 	if err != nil {
+		if err := readErr(ctx, err); errors.Is(err, ErrQueryTimeout) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("not found: %w", err)
 	}
 
 	return &o, nil
 }
 
+// GetSavedOpportunities returns a user's saved opportunities with the full
+// column set (including next_deadline_at and deadlines).
+func (s *Store) GetSavedOpportunities(ctx context.Context, userID string) ([]models.Opportunity, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM opportunities
+		WHERE id IN (SELECT opportunity_id FROM saved_opportunities WHERE user_id = $1)
+		ORDER BY next_deadline_at ASC NULLS LAST, id
+	`, selectCols)
+	rows, err := s.pool.Query(ctx, sql, userID)
+	if err != nil {
+		return nil, readErr(ctx, err)
+	}
+	defer rows.Close()
+
+	var opps []models.Opportunity
+	for rows.Next() {
+		o, err := scanOpportunity(rows.Scan)
+		if err != nil {
+			return nil, readErr(ctx, err)
+		}
+		opps = append(opps, o)
+	}
+	return opps, readErr(ctx, rows.Err())
+}
+
 func (s *Store) GetOpportunityBySourceID(ctx context.Context, sourceDomain, sourceID string) (*models.Opportunity, error) {
 	sql := fmt.Sprintf(`
 		SELECT %s
@@ -420,10 +696,43 @@ func (s *Store) GetOpportunityBySourceID(ctx context.Context, sourceDomain, sour
 	return &o, nil
 }
 
+// GetOpportunityByNumber returns the opportunities whose official number
+// ("RFA-NS-27-001", an EU call ID) matches number case-insensitively, most
+// recently updated first. The same number can be listed by several sources.
+func (s *Store) GetOpportunityByNumber(ctx context.Context, number string) ([]models.Opportunity, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	sql := fmt.Sprintf(`
+		SELECT %s
+		FROM opportunities
+		WHERE LOWER(opportunity_number) = LOWER($1)
+		ORDER BY updated_at DESC NULLS LAST, created_at DESC
+	`, selectCols)
+	rows, err := s.pool.Query(ctx, sql, strings.TrimSpace(number))
+	if err != nil {
+		return nil, readErr(ctx, err)
+	}
+	defer rows.Close()
+
+	var opps []models.Opportunity
+	for rows.Next() {
+		o, err := scanOpportunity(rows.Scan)
+		if err != nil {
+			return nil, readErr(ctx, err)
+		}
+		opps = append(opps, o)
+	}
+	return opps, readErr(ctx, rows.Err())
+}
+
 func (s *Store) GetSources(ctx context.Context) ([]string, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
 	rows, err := s.pool.Query(ctx, "SELECT DISTINCT source_domain FROM opportunities ORDER BY source_domain")
 	if err != nil {
-		return nil, err
+		return nil, readErr(ctx, err)
 	}
 	defer rows.Close()
 
@@ -438,6 +747,9 @@ func (s *Store) GetSources(ctx context.Context) ([]string, error) {
 }
 
 func (s *Store) GetStats(ctx context.Context) (map[string]interface{}, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
 	stats := make(map[string]interface{})
 
 	var total int
@@ -470,6 +782,11 @@ func (s *Store) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	}
 	stats["normalized_status_counts"] = statusCounts
 
+	// The counts above swallow their errors; don't report zeros for a run
+	// that hit the read deadline.
+	if err := readErr(ctx, ctx.Err()); err != nil {
+		return nil, err
+	}
 	return stats, nil
 }
 
@@ -481,32 +798,71 @@ type Aggregation struct {
 
 // AggregationResult contains all facet counts for the sidebar filters.
 type AggregationResult struct {
+	Statuses    []Aggregation `json:"statuses"`
 	Regions     []Aggregation `json:"regions"`
 	FunderTypes []Aggregation `json:"funder_types"`
 	Agencies    []Aggregation `json:"agencies"`
 	Countries   []Aggregation `json:"countries"`
+	Types       []Aggregation `json:"types"`
+	DocTypes    []Aggregation `json:"doc_types"`
 }
 
 // AggregationParams controls which subset of opportunities is used for facet counts.
 type AggregationParams struct {
+	// Query and SearchMode apply the list's text match, so facet counts
+	// follow an active search. The embedding only reorders results and
+	// doesn't take part.
+	Query      string
+	SearchMode string
 	Status     string // "open", "closed", "all", etc.
 	Region     []string
 	FunderType []string
 	Country    []string
 	AgencyName []string
+	Type       []string
+	DocType    []string
+	CFDA       []string
+	// ExcludeTenders mirrors ListParams.ExcludeTenders so facet counts match the list.
+	ExcludeTenders bool
+	MinConfidence  float64
 }
 
 func (s *Store) GetAggregations(ctx context.Context, params AggregationParams) (*AggregationResult, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+	params.Status = s.statusOrDefault(params.Status)
+	return aggregations(ctx, s.pool, params.listParams())
+}
+
+// aggregations runs the facet queries for the list filter on conn, the pool
+// or a snapshot transaction.
+func aggregations(ctx context.Context, conn querier, filter ListParams) (*AggregationResult, error) {
 	result := &AggregationResult{}
 
 	// Cross-faceted filtering: each dimension's query EXCLUDES its own filter
 	// so the sidebar always shows all options with correct counts.
 
+	// Statuses — exclude status filter
+	{
+		w, a := facetWhere(filter, "status")
+		q := fmt.Sprintf(`SELECT normalized_status::text, COUNT(*) FROM opportunities %s GROUP BY normalized_status ORDER BY COUNT(*) DESC`, w)
+		rows, err := conn.Query(ctx, q, a...)
+		if err == nil {
+			for rows.Next() {
+				var ag Aggregation
+				if err := rows.Scan(&ag.Value, &ag.Count); err == nil {
+					result.Statuses = append(result.Statuses, ag)
+				}
+			}
+			rows.Close()
+		}
+	}
+
 	// Regions — exclude region filter
 	{
-		w, a := buildAggregationWhereExcluding(params, "region")
+		w, a := facetWhere(filter, "region")
 		q := fmt.Sprintf(`SELECT COALESCE(region, 'Unknown'), COUNT(*) FROM opportunities %s GROUP BY region ORDER BY COUNT(*) DESC`, w)
-		rows, err := s.pool.Query(ctx, q, a...)
+		rows, err := conn.Query(ctx, q, a...)
 		if err == nil {
 			for rows.Next() {
 				var ag Aggregation
@@ -520,9 +876,9 @@ func (s *Store) GetAggregations(ctx context.Context, params AggregationParams) (
 
 	// Funder Types — exclude funder_type filter
 	{
-		w, a := buildAggregationWhereExcluding(params, "funder_type")
+		w, a := facetWhere(filter, "funder_type")
 		q := fmt.Sprintf(`SELECT COALESCE(funder_type, 'Unknown'), COUNT(*) FROM opportunities %s GROUP BY funder_type ORDER BY COUNT(*) DESC`, w)
-		rows, err := s.pool.Query(ctx, q, a...)
+		rows, err := conn.Query(ctx, q, a...)
 		if err == nil {
 			for rows.Next() {
 				var ag Aggregation
@@ -536,9 +892,9 @@ func (s *Store) GetAggregations(ctx context.Context, params AggregationParams) (
 
 	// Agencies — exclude agency_name filter
 	{
-		w, a := buildAggregationWhereExcluding(params, "agency_name")
+		w, a := facetWhere(filter, "agency_name")
 		q := fmt.Sprintf(`SELECT COALESCE(agency_name, 'Unknown'), COUNT(*) FROM opportunities %s AND agency_name IS NOT NULL AND agency_name != '' GROUP BY agency_name ORDER BY COUNT(*) DESC`, w)
-		rows, err := s.pool.Query(ctx, q, a...)
+		rows, err := conn.Query(ctx, q, a...)
 		if err == nil {
 			for rows.Next() {
 				var ag Aggregation
@@ -552,9 +908,9 @@ func (s *Store) GetAggregations(ctx context.Context, params AggregationParams) (
 
 	// Countries — exclude country filter
 	{
-		w, a := buildAggregationWhereExcluding(params, "country")
+		w, a := facetWhere(filter, "country")
 		q := fmt.Sprintf(`SELECT COALESCE(country, 'Unknown'), COUNT(*) FROM opportunities %s AND country IS NOT NULL AND country != '' GROUP BY country ORDER BY COUNT(*) DESC LIMIT 50`, w)
-		rows, err := s.pool.Query(ctx, q, a...)
+		rows, err := conn.Query(ctx, q, a...)
 		if err == nil {
 			for rows.Next() {
 				var ag Aggregation
@@ -566,57 +922,119 @@ func (s *Store) GetAggregations(ctx context.Context, params AggregationParams) (
 		}
 	}
 
+	// Opportunity Types — exclude type filter
+	{
+		w, a := facetWhere(filter, "type")
+		q := fmt.Sprintf(`SELECT opportunity_type, COUNT(*) FROM opportunities %s AND opportunity_type IS NOT NULL AND opportunity_type != '' GROUP BY opportunity_type ORDER BY COUNT(*) DESC`, w)
+		rows, err := conn.Query(ctx, q, a...)
+		if err == nil {
+			for rows.Next() {
+				var ag Aggregation
+				if err := rows.Scan(&ag.Value, &ag.Count); err == nil {
+					result.Types = append(result.Types, ag)
+				}
+			}
+			rows.Close()
+		}
+	}
+
+	// Doc Types — exclude doc_type filter
+	{
+		w, a := facetWhere(filter, "doc_type")
+		q := fmt.Sprintf(`SELECT doc_type, COUNT(*) FROM opportunities %s AND doc_type IS NOT NULL AND doc_type != '' GROUP BY doc_type ORDER BY COUNT(*) DESC`, w)
+		rows, err := conn.Query(ctx, q, a...)
+		if err == nil {
+			for rows.Next() {
+				var ag Aggregation
+				if err := rows.Scan(&ag.Value, &ag.Count); err == nil {
+					result.DocTypes = append(result.DocTypes, ag)
+				}
+			}
+			rows.Close()
+		}
+	}
+
+	// Facet queries skip failed dimensions; a deadline means they all did.
+	if err := readErr(ctx, ctx.Err()); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-// buildAggregationWhereExcluding constructs a WHERE clause that mirrors the status
-// filtering used by ListOpportunities. The `exclude` parameter names the dimension
+func lowerAll(values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = strings.ToLower(strings.TrimSpace(v))
+	}
+	return out
+}
+
+// buildAggregationWhereExcluding constructs the facet WHERE clause from the same
+// builder as ListOpportunities. The `exclude` parameter names the dimension
 // to omit, implementing cross-faceted filtering so each sidebar section always
 // shows all available options (not just the currently selected one).
 func buildAggregationWhereExcluding(params AggregationParams, exclude string) (string, []interface{}) {
-	where := "WHERE 1=1"
-	var args []interface{}
-	argIdx := 1
+	return facetWhere(params.listParams(), exclude)
+}
 
-	// Status is never excluded — it applies to all dimensions.
-	status := params.Status
-	if status == "" || status == "active" {
-		status = "open"
-	}
+// facetWhere is the WHERE for one facet dimension: the whole list filter
+// minus that dimension's own filter ("" keeps them all).
+func facetWhere(filter ListParams, dimension string) (string, []interface{}) {
+	return buildOpportunityWhere(filter, whereOptions{excludeDimension: dimension})
+}
 
-	if status == "open" {
-		where += " AND normalized_status = 'open' AND is_results_page = false AND (rolling_evidence = true OR next_deadline_at >= NOW() OR close_at >= NOW())"
-	} else if status == "closed" {
-		where += " AND normalized_status::text IN ('closed','archived')"
-	} else if status != "all" {
-		if status == "posted" {
-			status = "open"
-		}
-		where += fmt.Sprintf(" AND normalized_status::text = $%d", argIdx)
-		args = append(args, status)
-		argIdx++
+// listParams maps facet params onto the list filter so both share one builder.
+func (p AggregationParams) listParams() ListParams {
+	return ListParams{
+		Query:          p.Query,
+		SearchMode:     p.SearchMode,
+		Status:         p.Status,
+		Region:         p.Region,
+		FunderType:     p.FunderType,
+		Country:        p.Country,
+		AgencyName:     p.AgencyName,
+		Type:           p.Type,
+		DocType:        p.DocType,
+		CFDA:           p.CFDA,
+		ExcludeTenders: p.ExcludeTenders,
+		MinConfidence:  p.MinConfidence,
 	}
+}
 
-	if len(params.Region) > 0 && exclude != "region" {
-		where += fmt.Sprintf(" AND region = ANY($%d)", argIdx)
-		args = append(args, params.Region)
-		argIdx++
-	}
-	if len(params.FunderType) > 0 && exclude != "funder_type" {
-		where += fmt.Sprintf(" AND funder_type = ANY($%d)", argIdx)
-		args = append(args, params.FunderType)
-		argIdx++
-	}
-	if len(params.Country) > 0 && exclude != "country" {
-		where += fmt.Sprintf(" AND country = ANY($%d)", argIdx)
-		args = append(args, params.Country)
-		argIdx++
+// GetCFDAFacet lists distinct CFDA/ALN numbers with opportunity counts under
+// the same filters as the sidebar facets (minus the cfda filter itself).
+// prefix narrows the list, e.g. "93." for HHS programs.
+func (s *Store) GetCFDAFacet(ctx context.Context, params AggregationParams, prefix string, limit int) ([]Aggregation, error) {
+	ctx, cancel := s.readCtx(ctx)
+	defer cancel()
+
+	params.Status = s.statusOrDefault(params.Status)
+	q, args := buildCFDAFacetQuery(params, prefix, limit)
+	rows, err := s.pool.Query(ctx, q, args...)
+	if err != nil {
+		return nil, readErr(ctx, err)
 	}
-	if len(params.AgencyName) > 0 && exclude != "agency_name" {
-		where += fmt.Sprintf(" AND agency_name = ANY($%d)", argIdx)
-		args = append(args, params.AgencyName)
-		argIdx++
+	defer rows.Close()
+
+	facet := []Aggregation{}
+	for rows.Next() {
+		var ag Aggregation
+		if err := rows.Scan(&ag.Value, &ag.Count); err != nil {
+			return nil, readErr(ctx, err)
+		}
+		facet = append(facet, ag)
 	}
+	return facet, readErr(ctx, rows.Err())
+}
 
-	return where, args
+func buildCFDAFacetQuery(params AggregationParams, prefix string, limit int) (string, []interface{}) {
+	where, args := buildAggregationWhereExcluding(params, "cfda")
+	where += " AND cfda_list IS NOT NULL AND cfda_list <> '{}'::text[]"
+	if prefix = strings.TrimSpace(prefix); prefix != "" {
+		args = append(args, prefix)
+		where += fmt.Sprintf(" AND starts_with(code, $%d)", len(args))
+	}
+	args = append(args, limit)
+	q := fmt.Sprintf(`SELECT code, COUNT(*) FROM opportunities CROSS JOIN LATERAL unnest(cfda_list) AS code %s GROUP BY code ORDER BY COUNT(*) DESC, code LIMIT $%d`, where, len(args))
+	return q, args
 }
@@ -1,8 +1,10 @@
 package db
 
 import (
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestBuildOpenTabConstraint_IsStrict(t *testing.T) {
@@ -24,3 +26,286 @@ func TestBuildOpenTabConstraint_IsStrict(t *testing.T) {
 		t.Fatalf("open clause must not allow null deadlines by default: %s", clause)
 	}
 }
+
+func TestBuildAggregationWhere_MultiValueTypeAndDocType(t *testing.T) {
+	params := AggregationParams{
+		Status:  "all",
+		Type:    []string{"fellowship", "prize"},
+		DocType: []string{"synopsis", "forecasted"},
+	}
+
+	where, args := buildAggregationWhereExcluding(params, "")
+	if !strings.Contains(where, "opportunity_type = ANY($1)") {
+		t.Fatalf("expected type predicate, got: %s", where)
+	}
+	if !strings.Contains(where, "doc_type = ANY($2)") {
+		t.Fatalf("expected doc_type predicate, got: %s", where)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected 2 args, got %d", len(args))
+	}
+	if types, ok := args[0].([]string); !ok || len(types) != 2 || types[0] != "fellowship" || types[1] != "prize" {
+		t.Fatalf("expected both type values bound, got %#v", args[0])
+	}
+
+	// The type facet must not filter on its own dimension.
+	where, args = buildAggregationWhereExcluding(params, "type")
+	if strings.Contains(where, "opportunity_type") {
+		t.Fatalf("type facet should exclude its own filter: %s", where)
+	}
+	if !strings.Contains(where, "doc_type = ANY($1)") || len(args) != 1 {
+		t.Fatalf("expected only doc_type predicate, got: %s (%d args)", where, len(args))
+	}
+}
+
+func TestBuildAggregationWhere_ExcludeTenders(t *testing.T) {
+	params := AggregationParams{Status: "all"}
+
+	where, _ := buildAggregationWhereExcluding(params, "")
+	if strings.Contains(where, "tender") {
+		t.Fatalf("tenders must be included when the flag is off: %s", where)
+	}
+
+	params.ExcludeTenders = true
+	where, _ = buildAggregationWhereExcluding(params, "")
+	if !strings.Contains(where, "doc_type NOT ILIKE 'tender%'") {
+		t.Fatalf("expected tender exclusion when the flag is set: %s", where)
+	}
+	if !strings.Contains(where, "doc_type IS NULL") {
+		t.Fatalf("tender exclusion must keep rows without a doc_type: %s", where)
+	}
+}
+
+func TestBuildOpportunityWhere_PlaceholdersMatchArgs(t *testing.T) {
+	rolling := true
+	params := ListParams{
+		Query:        "climate",
+		Source:       "grants.gov",
+		Region:       []string{"Europe"},
+		Type:         []string{"grant"},
+		MinAmount:    1000,
+		Status:       "needs_review",
+		DeadlineDays: 30,
+		IsRolling:    &rolling,
+		Categories:   []string{"Health"},
+	}
+
+	where, args := buildOpportunityWhere(params, whereOptions{})
+	if len(args) != 9 {
+		t.Fatalf("expected 9 args, got %d: %v", len(args), args)
+	}
+	for i := 1; i <= len(args); i++ {
+		if !strings.Contains(where, fmt.Sprintf("$%d", i)) {
+			t.Fatalf("placeholder $%d missing from: %s", i, where)
+		}
+	}
+	if strings.Contains(where, fmt.Sprintf("$%d", len(args)+1)) {
+		t.Fatalf("unexpected extra placeholder in: %s", where)
+	}
+}
+
+func TestBuildOpportunityWhere_ListAndAggregationMatch(t *testing.T) {
+	for _, status := range []string{"", "open", "active", "posted", "closed", "all", "needs_review"} {
+		agg := AggregationParams{
+			Status:         status,
+			Region:         []string{"Europe"},
+			FunderType:     []string{"Government"},
+			Country:        []string{"Peru"},
+			AgencyName:     []string{"NIH"},
+			Type:           []string{"grant"},
+			DocType:        []string{"Grant"},
+			ExcludeTenders: true,
+		}
+		list := ListParams{
+			Status:         status,
+			Region:         agg.Region,
+			FunderType:     agg.FunderType,
+			Country:        agg.Country,
+			AgencyName:     agg.AgencyName,
+			Type:           agg.Type,
+			DocType:        agg.DocType,
+			ExcludeTenders: true,
+		}
+
+		listWhere, listArgs := buildOpportunityWhere(list, whereOptions{})
+		aggWhere, aggArgs := buildAggregationWhereExcluding(agg, "")
+		if listWhere != aggWhere {
+			t.Fatalf("status %q: list and aggregation predicates differ:\nlist: %s\nagg:  %s", status, listWhere, aggWhere)
+		}
+		if fmt.Sprint(listArgs) != fmt.Sprint(aggArgs) {
+			t.Fatalf("status %q: list and aggregation args differ: %v vs %v", status, listArgs, aggArgs)
+		}
+	}
+}
+
+func TestBuildOpportunityWhere_StatusMapping(t *testing.T) {
+	openTab := buildOpenTabConstraint()
+	tests := []struct {
+		status   string
+		contains string
+		arg      string
+	}{
+		{status: "", contains: openTab},
+		{status: "open", contains: openTab},
+		{status: "active", contains: openTab},
+		{status: "closed", contains: "normalized_status::text IN ('closed','archived')"},
+		{status: "posted", contains: "normalized_status::text = $1", arg: "open"},
+		{status: "needs_review", contains: "normalized_status::text = $1", arg: "needs_review"},
+		{status: "funded", contains: "normalized_status::text = $1", arg: "funded"},
+		{status: "upcoming", contains: buildUpcomingTabConstraint()},
+		{status: "forthcoming", contains: buildUpcomingTabConstraint()},
+	}
+
+	for _, tc := range tests {
+		where, args := buildOpportunityWhere(ListParams{Status: tc.status}, whereOptions{})
+		if !strings.Contains(where, tc.contains) {
+			t.Fatalf("status %q: expected %q in %s", tc.status, tc.contains, where)
+		}
+		if tc.arg != "" && (len(args) != 1 || args[0] != tc.arg) {
+			t.Fatalf("status %q: expected arg %q, got %v", tc.status, tc.arg, args)
+		}
+	}
+
+	where, args := buildOpportunityWhere(ListParams{Status: "all"}, whereOptions{})
+	if where != "WHERE 1=1" || len(args) != 0 {
+		t.Fatalf("status all must not filter, got %s %v", where, args)
+	}
+}
+
+func TestBuildOpportunityWhere_StatusFacetSkipsStatusFilter(t *testing.T) {
+	for _, status := range []string{"", "open", "closed", "funded"} {
+		where, args := buildAggregationWhereExcluding(AggregationParams{Status: status}, "status")
+		if where != "WHERE 1=1" || len(args) != 0 {
+			t.Fatalf("status %q: status facet must not filter by status, got %s %v", status, where, args)
+		}
+	}
+
+	closed, _ := buildOpportunityWhere(ListParams{Status: "closed"}, whereOptions{})
+	if strings.Contains(closed, "funded") {
+		t.Fatalf("closed tab must keep funded calls separate: %s", closed)
+	}
+}
+
+func TestBuildOpportunityWhere_UpcomingTabVsOpenTab(t *testing.T) {
+	// A row with a future open_at and no deadline must match upcoming, not open.
+	openTab := buildOpenTabConstraint()
+	if !strings.Contains(openTab, "(open_at IS NULL OR open_at <= NOW())") {
+		t.Fatalf("open tab must exclude calls that have not opened yet: %s", openTab)
+	}
+
+	upcoming, _ := buildOpportunityWhere(ListParams{Status: "upcoming"}, whereOptions{})
+	if !strings.Contains(upcoming, "open_at > NOW()") {
+		t.Fatalf("upcoming tab must include future open_at rows: %s", upcoming)
+	}
+	if strings.Contains(upcoming, "next_deadline_at") {
+		t.Fatalf("upcoming tab must not require a deadline: %s", upcoming)
+	}
+}
+
+func TestBuildOpportunityWhere_OpenDateRange(t *testing.T) {
+	after := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	where, args := buildOpportunityWhere(ListParams{Status: "upcoming", OpenAfter: &after, OpenBefore: &before}, whereOptions{})
+	if !strings.Contains(where, "open_at >= $1") || !strings.Contains(where, "open_at <= $2") {
+		t.Fatalf("expected open_at range predicates, got %s", where)
+	}
+	if len(args) != 2 || args[0] != after || args[1] != before {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildOpportunityWhere_HideDeadLinks(t *testing.T) {
+	where, _ := buildOpportunityWhere(ListParams{Status: "all"}, whereOptions{})
+	if strings.Contains(where, "link_dead") {
+		t.Fatalf("dead links are shown unless hidden: %s", where)
+	}
+	where, _ = buildOpportunityWhere(ListParams{Status: "all", HideDeadLinks: true}, whereOptions{})
+	if !strings.Contains(where, "link_dead = false") {
+		t.Fatalf("expected link_dead predicate, got %s", where)
+	}
+}
+
+func TestBuildOpportunityWhere_CreatedAfter(t *testing.T) {
+	after := time.Date(2026, 10, 11, 0, 0, 0, 0, time.UTC)
+
+	where, args := buildOpportunityWhere(ListParams{Status: "upcoming", CreatedAfter: &after}, whereOptions{})
+	if !strings.Contains(where, "created_at >= $1") {
+		t.Fatalf("expected created_at predicate, got %s", where)
+	}
+	if len(args) != 1 || args[0] != after {
+		t.Fatalf("unexpected args: %v", args)
+	}
+}
+
+func TestBuildOpportunityOrder_EndsOnIDTiebreak(t *testing.T) {
+	cases := []ListParams{
+		{SortBy: "deadline"},
+		{SortBy: "amount_desc"},
+		{SortBy: "newest"},
+		{SortBy: "recent"},
+		{Status: "upcoming"},
+		{Query: "water", QueryEmbedding: []float32{0.1, 0.2}},
+		{Query: "water"},
+		{},
+	}
+
+	for _, params := range cases {
+		orderBy, args := buildOpportunityOrder(params, 3)
+		if fields := strings.Fields(orderBy); fields[len(fields)-1] != "id" {
+			t.Fatalf("params %+v: ORDER BY must end on id, got %q", params, orderBy)
+		}
+		if strings.Contains(orderBy, "$") && !strings.Contains(orderBy, "$3") {
+			t.Fatalf("params %+v: placeholders must start at $3, got %q", params, orderBy)
+		}
+		if strings.Contains(orderBy, fmt.Sprintf("$%d", 3+len(args))) {
+			t.Fatalf("params %+v: placeholder beyond bound args in %q", params, orderBy)
+		}
+	}
+}
+
+func TestBuildOpportunityWhere_CFDAOverlap(t *testing.T) {
+	where, args := buildOpportunityWhere(ListParams{Status: "all", CFDA: []string{"93.242"}}, whereOptions{})
+	if !strings.Contains(where, "cfda_list && $1") || len(args) != 1 {
+		t.Fatalf("expected a single-code overlap predicate, got: %s (%d args)", where, len(args))
+	}
+
+	codes := []string{"93.242", "93.853", "47.041"}
+	where, args = buildOpportunityWhere(ListParams{Status: "all", Region: []string{"North America"}, CFDA: codes}, whereOptions{})
+	if !strings.Contains(where, "cfda_list && $2") {
+		t.Fatalf("expected the overlap predicate after region, got: %s", where)
+	}
+	if bound, ok := args[1].([]string); !ok || len(bound) != 3 || bound[2] != "47.041" {
+		t.Fatalf("expected all codes bound as one array, got %#v", args[1])
+	}
+}
+
+func TestBuildCFDAFacetQuery(t *testing.T) {
+	params := AggregationParams{Status: "all", CFDA: []string{"93.242"}, Region: []string{"North America"}}
+
+	q, args := buildCFDAFacetQuery(params, "93.", 50)
+	if strings.Contains(q, "cfda_list &&") {
+		t.Fatalf("cfda facet should exclude its own filter: %s", q)
+	}
+	if !strings.Contains(q, "unnest(cfda_list) AS code") || !strings.Contains(q, "starts_with(code, $2)") || !strings.HasSuffix(q, "LIMIT $3") {
+		t.Fatalf("unexpected facet query: %s", q)
+	}
+	if len(args) != 3 || args[1] != "93." || args[2] != 50 {
+		t.Fatalf("unexpected facet args: %v", args)
+	}
+}
+
+func TestBuildOpportunityWhere_MinConfidence(t *testing.T) {
+	where, _ := buildOpportunityWhere(ListParams{}, whereOptions{})
+	if strings.Contains(where, "status_confidence") {
+		t.Fatalf("no threshold should mean no confidence filter: %s", where)
+	}
+
+	where, args := buildOpportunityWhere(ListParams{MinConfidence: 0.8}, whereOptions{})
+	if !strings.Contains(where, buildOpenTabConstraint()) {
+		t.Fatalf("threshold must combine with the open tab, got: %s", where)
+	}
+	if !strings.Contains(where, "COALESCE(status_confidence, 0) >= $1") || len(args) != 1 || args[0] != 0.8 {
+		t.Fatalf("expected low-confidence rows excluded, got: %s %v", where, args)
+	}
+}
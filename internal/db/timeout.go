@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// DefaultReadTimeout bounds the API read queries (list, count, facets, stats,
+// lookups) so a pathological search can't hold pool connections indefinitely.
+// Ingest and admin queries don't go through readCtx and keep the caller's
+// budget.
+const DefaultReadTimeout = 5 * time.Second
+
+// ErrQueryTimeout is returned by read methods whose query ran past the read
+// timeout, or that Postgres canceled under its own statement_timeout.
+var ErrQueryTimeout = errors.New("query timed out")
+
+// readTimeoutFromEnv reads DB_READ_TIMEOUT ("5s", "750ms"). Unset or invalid
+// values fall back to DefaultReadTimeout; "0" disables the bound.
+func readTimeoutFromEnv() time.Duration {
+	raw := os.Getenv("DB_READ_TIMEOUT")
+	if raw == "" {
+		return DefaultReadTimeout
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return DefaultReadTimeout
+	}
+	return d
+}
+
+// SetReadTimeout overrides the read query budget; zero disables it.
+func (s *Store) SetReadTimeout(d time.Duration) {
+	s.readTimeout = d
+}
+
+// readCtx derives the context for an API read query. A deadline on the
+// context makes pgx send a cancel request, so Postgres stops the statement
+// rather than finishing it for nobody.
+func (s *Store) readCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.readTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.readTimeout)
+}
+
+// readErr maps a failure under a readCtx context onto ErrQueryTimeout when
+// the deadline (or the server's statement_timeout) caused it.
+func readErr(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pgErr *pgconn.PgError
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) || (errors.As(err, &pgErr) && pgErr.Code == "57014") {
+		return fmt.Errorf("%w: %v", ErrQueryTimeout, err)
+	}
+	return err
+}
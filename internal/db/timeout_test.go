@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestReadErr(t *testing.T) {
+	expired, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	if err := readErr(expired, errors.New("conn closed")); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("deadline exceeded: got %v, want ErrQueryTimeout", err)
+	}
+
+	live := context.Background()
+	if err := readErr(live, &pgconn.PgError{Code: "57014"}); !errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("statement_timeout: got %v, want ErrQueryTimeout", err)
+	}
+	if err := readErr(live, &pgconn.PgError{Code: "42P01"}); errors.Is(err, ErrQueryTimeout) {
+		t.Errorf("undefined table mapped to ErrQueryTimeout: %v", err)
+	}
+	if err := readErr(live, nil); err != nil {
+		t.Errorf("nil error mapped to %v", err)
+	}
+}
+
+// TestReadTimeout_BlockedRead holds a lock on opportunities so a Store read
+// blocks past its budget; set TEST_DATABASE_URL to enable it.
+func TestReadTimeout_BlockedRead(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	pool, err := pgxpool.New(context.Background(), dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	s := NewStore(pool)
+	s.SetReadTimeout(200 * time.Millisecond)
+
+	tx, err := pool.Begin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Rollback(context.Background())
+	if _, err := tx.Exec(context.Background(), "LOCK TABLE opportunities IN ACCESS EXCLUSIVE MODE"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	_, err = s.CountOpportunities(context.Background(), ListParams{})
+	if !errors.Is(err, ErrQueryTimeout) {
+		t.Fatalf("got %v, want ErrQueryTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("blocked read held the caller for %v", elapsed)
+	}
+
+	// Reads succeed again once the lock is released.
+	if err := tx.Rollback(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CountOpportunities(context.Background(), ListParams{}); err != nil {
+		t.Fatalf("store unusable after timeout: %v", err)
+	}
+}
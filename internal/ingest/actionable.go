@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// informationalPageReason marks rows whose page describes a program but
+// carries no call anyone can act on: no way to apply, no deadline.
+const informationalPageReason = "informational_page"
+
+// callToActionRegex matches the wording of a call someone can answer
+// ("how to apply", "submit your proposal", "postula aquí").
+var callToActionRegex = regexp.MustCompile(`(?i)\b(?:apply|applications? (?:are|is) (?:open|invited|welcome)|how to apply|submit|submissions?|call for (?:proposals|applications|projects)|request for (?:proposals|applications)|postul\w*|inscr[ií]b\w*|inscripci[oó]n(?:es)?|convocatoria|candidatur[ae]s?|soumettre|bewerb\w*)\b`)
+
+// applicationLinkRegex matches the text or URL of a link into an application
+// form or portal.
+var applicationLinkRegex = regexp.MustCompile(`(?i)apply|application|submit|postul|inscri|solicitud|formulario|candidat`)
+
+// informationalPageCheck says whether ComputeStatusDecision flags
+// non-actionable pages; INFORMATIONAL_PAGE_CHECK=false turns it off. Read
+// once at startup.
+var informationalPageCheck = !strings.EqualFold(strings.TrimSpace(os.Getenv("INFORMATIONAL_PAGE_CHECK")), "false")
+
+// exemptFromInformationalCheck reports whether something other than the page
+// text already says the call is live: an API record, a source status that
+// maps to open or upcoming, or an open date still ahead.
+func exemptFromInformationalCheck(opp Opportunity, mappedSource string, now time.Time) bool {
+	if isAPIFirstSource(opp.SourceDomain) || mappedSource == "open" || mappedSource == "upcoming" {
+		return true
+	}
+	return opp.OpenAt != nil && opp.OpenAt.After(now)
+}
+
+// isActionableOpportunity reports whether the page behind opp reads as a
+// call someone can answer: it links to an application, asks the reader to
+// apply, or names a deadline. Rolling evidence alone doesn't count; program
+// landing pages call themselves "ongoing" too. Rows without page text
+// (list-only items) can't be judged and count as actionable.
+func isActionableOpportunity(opp Opportunity) bool {
+	text := HTMLToText(opp.Description)
+	if strings.TrimSpace(text) == "" {
+		return true
+	}
+	if hasAnyDeadlineEvidence(opp) || opp.CloseAt != nil {
+		return true
+	}
+	if callToActionRegex.MatchString(opp.Title + "\n" + opp.Summary + "\n" + text) {
+		return true
+	}
+	return hasApplicationLink(opp.Description)
+}
+
+// hasApplicationLink looks for an anchor whose text or href points at an
+// application form.
+func hasApplicationLink(html string) bool {
+	if !strings.Contains(html, "<a") {
+		return false
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return false
+	}
+	found := false
+	doc.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		found = applicationLinkRegex.MatchString(a.Text() + " " + href)
+		return !found
+	})
+	return found
+}
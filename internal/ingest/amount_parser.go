@@ -6,63 +6,295 @@ import (
 	"strings"
 )
 
+// amountParse is the detailed result of parsing an amount string. When the
+// text also quotes an equivalent in another currency ("S/ 500,000 (approx.
+// USD 130,000)"), it is kept as the secondary pair.
+type amountParse struct {
+	Min               float64
+	Max               float64
+	Currency          string
+	SecondaryCurrency string
+	SecondaryAmount   float64
+}
+
+// Order matters: "US$" must win over the bare "$" at the same position.
+var currencyMarkerRegex = regexp.MustCompile(`(?i)US\$|U\$S|\bUSD\b|\bd[oó]lar(?:es)?\b|\bdollars?\b|\bS/\.?|\bPEN\b|\b(?:nuevos\s+)?soles\b|£|\bGBP\b|\bpounds?\b|€|\bEUR\b|\beuros?\b|\bMXN\b|\bpesos?\b|₹|\bINR\b|\bRs\.?|\brupees?\b|\$`)
+
+var amountNumberRegex = regexp.MustCompile(`[\d,\.]+(?:\.\d{2})?`)
+
+// spaceGroupedNumberRegex matches thousands grouped by a space, no-break
+// space or narrow no-break space ("1 000 000", "1 500 000,50"), as EU and
+// French texts write them. Only comma-decimal text is read this way, so a
+// run of separate figures in English prose stays separate.
+var spaceGroupedNumberRegex = regexp.MustCompile(`\d{1,3}(?:[ \x{00A0}\x{202F}]\d{3})+(?:,\d+)?\b|[\d,\.]+(?:\.\d{2})?`)
+
+// numberGroupSpaces strips the group separators spaceGroupedNumberRegex allows.
+var numberGroupSpaces = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "")
+
+// approxAmountRegex introduces an equivalent amount in a second currency.
+var approxAmountRegex = regexp.MustCompile(`(?i)\b(?:approx(?:imately)?|aprox(?:imadamente)?|equivalente?s?|equivalent(?: to)?|aproximado)\b\.?|≈`)
+
+func currencyForMarker(marker string) string {
+	m := strings.ToLower(marker)
+	switch {
+	case strings.HasPrefix(m, "s/"), m == "pen", strings.HasSuffix(m, "soles"):
+		return "PEN"
+	case m == "£", m == "gbp", strings.HasPrefix(m, "pound"):
+		return "GBP"
+	case m == "€", m == "eur", strings.HasPrefix(m, "euro"):
+		return "EUR"
+	case m == "mxn", strings.HasPrefix(m, "peso"):
+		return "MXN"
+	case m == "₹", m == "inr", strings.HasPrefix(m, "rs"), strings.HasPrefix(m, "rupee"):
+		return "INR"
+	default:
+		return "USD"
+	}
+}
+
+type amountToken struct {
+	value      float64
+	start, end int
+}
+
+// amountScaleRegex matches a magnitude word right after a number
+// ("1.5 million", "2 millones", "1.5 mil millones", "2,5 Mio.", "50 mil",
+// "250k", "50 lakh", "2 crore"). The word must end at whitespace, the end of
+// the text or punctuation: \b treats accented letters as non-word
+// characters, which read "150,000 más" as 150,000 million.
+var amountScaleRegex = regexp.MustCompile(`(?i)^\s*(mil\s+millones|millones|mill[oó]n|million|mio\.?|mn|billion|bn|thousand|mil|k|lakhs?|lacs?|crores?)(?:\s|$|[.,;:)])`)
+
+var bareYearRegex = regexp.MustCompile(`^(19|20)\d{2}$`)
+
+func amountScale(word string) float64 {
+	switch w := strings.ToLower(word); {
+	case w == "billion" || w == "bn" || len(strings.Fields(w)) == 2:
+		return 1e9
+	case w == "thousand" || w == "mil" || w == "k":
+		return 1e3
+	case strings.HasPrefix(w, "lakh") || strings.HasPrefix(w, "lac"):
+		return 1e5
+	case strings.HasPrefix(w, "cr"):
+		return 1e7
+	default:
+		return 1e6
+	}
+}
+
+// numberFormat says how to read a number whose only separator is ambiguous
+// ("1.500", "2,5").
+type numberFormat int
+
+const (
+	numberFormatAuto         numberFormat = iota // "," groups; a lone "." is a decimal point
+	numberFormatCommaDecimal                     // European: "." groups, "," is the decimal point
+)
+
+// commaDecimalLocales write "1.000.000,50". Peru and most of Latin America
+// use "1,000,000.50", so plain "es" is deliberately absent; use "es-ES".
+var commaDecimalLocales = map[string]bool{
+	"de": true, "fr": true, "it": true, "nl": true, "pt": true, "pl": true,
+	"es-es": true, "eu": true,
+}
+
+// numberFormatFor picks the number format from a locale hint
+// (detail.parse.number_locale) and the currency. EUR amounts default to
+// comma decimals; an explicit locale wins.
+func numberFormatFor(locale, currency string) numberFormat {
+	l := strings.ToLower(strings.TrimSpace(locale))
+	if l != "" {
+		if commaDecimalLocales[l] || commaDecimalLocales[strings.SplitN(l, "-", 2)[0]] {
+			return numberFormatCommaDecimal
+		}
+		return numberFormatAuto
+	}
+	if currency == "EUR" {
+		return numberFormatCommaDecimal
+	}
+	return numberFormatAuto
+}
+
+// parseLocalizedNumber reads a number written with "," and "." separators.
+// When both appear the last one is the decimal point, so "1.000.000,50" and
+// "1,000,000.50" both parse. Indian grouping ("1,00,000") is plain grouping.
+func parseLocalizedNumber(m string, format numberFormat) (float64, bool) {
+	m = strings.Trim(m, ".,")
+	if m == "" {
+		return 0, false
+	}
+	lastComma, lastDot := strings.LastIndex(m, ","), strings.LastIndex(m, ".")
+	var clean string
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		if lastComma > lastDot {
+			clean = strings.ReplaceAll(strings.ReplaceAll(m, ".", ""), ",", ".")
+		} else {
+			clean = strings.ReplaceAll(m, ",", "")
+		}
+	case lastComma >= 0:
+		// "2,5" is a decimal in comma-decimal text; "1,500" is still grouping.
+		if format == numberFormatCommaDecimal && strings.Count(m, ",") == 1 && len(m)-lastComma-1 != 3 {
+			clean = strings.Replace(m, ",", ".", 1)
+		} else {
+			clean = strings.ReplaceAll(m, ",", "")
+		}
+	case lastDot >= 0:
+		if strings.Count(m, ".") > 1 || (format == numberFormatCommaDecimal && len(m)-lastDot-1 == 3) {
+			clean = strings.ReplaceAll(m, ".", "")
+		} else {
+			clean = m
+		}
+	default:
+		clean = m
+	}
+	val, err := strconv.ParseFloat(clean, 64)
+	if err != nil || val <= 0 {
+		return 0, false
+	}
+	return val, true
+}
+
+// findAmounts returns every positive number in text with its byte offsets.
+// A scale word after the number is applied and included in the token. Bare
+// years ("Convocatoria 2026") are skipped unless a currency marker is glued to
+// them.
+func findAmounts(text string, format numberFormat) []amountToken {
+	numberRegex := amountNumberRegex
+	if format == numberFormatCommaDecimal {
+		numberRegex = spaceGroupedNumberRegex
+	}
+	var out []amountToken
+	for _, loc := range numberRegex.FindAllStringIndex(text, -1) {
+		m := text[loc[0]:loc[1]]
+		val, ok := parseLocalizedNumber(numberGroupSpaces.Replace(m), format)
+		if !ok {
+			continue
+		}
+
+		end := loc[1]
+		if scale := amountScaleRegex.FindStringSubmatchIndex(text[end:]); scale != nil {
+			val *= amountScale(text[end+scale[2] : end+scale[3]])
+			end += scale[3]
+		} else if bareYearRegex.MatchString(m) && !currencyGlued(text, loc[0]) {
+			continue
+		}
+		out = append(out, amountToken{value: val, start: loc[0], end: end})
+	}
+	return out
+}
+
+// currencyGlued reports whether a currency marker ends right before pos
+// (allowing one space), as in "$2026" or "S/ 2026".
+func currencyGlued(text string, pos int) bool {
+	before := strings.TrimSuffix(text[:pos], " ")
+	for _, loc := range currencyMarkerRegex.FindAllStringIndex(before, -1) {
+		if loc[1] == len(before) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstCurrency returns the currency of the first marker in text, or
+// fallback when there is none.
+func firstCurrency(text, fallback string) string {
+	if m := currencyMarkerRegex.FindString(text); m != "" {
+		return currencyForMarker(m)
+	}
+	return fallback
+}
+
+// nearestCurrency returns the currency whose marker sits closest to the
+// amount, or "" when the text has no marker.
+func nearestCurrency(text string, amount amountToken) string {
+	best, bestDist := "", -1
+	for _, loc := range currencyMarkerRegex.FindAllStringIndex(text, -1) {
+		dist := amount.start - loc[1]
+		if loc[0] >= amount.end {
+			dist = loc[0] - amount.end
+		}
+		if dist < 0 {
+			dist = 0
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = currencyForMarker(text[loc[0]:loc[1]]), dist
+		}
+	}
+	return best
+}
+
 // parseAmountRobust extracts min/max amounts and currency from text with improved detection
 func parseAmountRobust(text string, defaultCurrency string) (float64, float64, string) {
-	textLower := strings.ToLower(text)
+	p := parseAmountDetailed(text, defaultCurrency)
+	return p.Min, p.Max, p.Currency
+}
+
+// parseAmountDetailed is parseAmountRobust plus the secondary currency pair.
+// The currency is the one written nearest the primary amount, so "S/ 500,000"
+// is soles even if a "$" appears elsewhere in the text.
+func parseAmountDetailed(text string, defaultCurrency string) amountParse {
+	return parseAmountLocalized(text, defaultCurrency, "")
+}
 
-	// Currency detection (enhanced)
+// parseAmountLocalized is parseAmountDetailed with a number locale hint
+// ("de", "es-ES", "en-IN") for reading ambiguous separators.
+func parseAmountLocalized(text, defaultCurrency, locale string) amountParse {
 	currency := defaultCurrency
 	if currency == "" {
 		currency = "USD" // Default
 	}
 
-	// Check for currency symbols and codes
-	if strings.Contains(textLower, "£") || strings.Contains(textLower, "gbp") || strings.Contains(textLower, "pound") {
-		currency = "GBP"
-	} else if strings.Contains(textLower, "€") || strings.Contains(textLower, "eur") || strings.Contains(textLower, "euro") {
-		currency = "EUR"
-	} else if strings.Contains(textLower, "$") || strings.Contains(textLower, "usd") || strings.Contains(textLower, "dollar") {
-		currency = "USD"
-	} else if strings.Contains(textLower, "peso") || strings.Contains(textLower, "mxn") {
-		currency = "MXN"
-	} else if strings.Contains(textLower, "sol") || strings.Contains(textLower, "pen") {
-		currency = "PEN"
-	}
-
-	// Enhanced regex to find numbers with various separators
-	// Handles: 1,000,000 / 1.000.000 / 1000000 / 1,000.50
-	numberRegex := regexp.MustCompile(`[\d,\.]+(?:\.\d{2})?`)
-	matches := numberRegex.FindAllString(text, -1)
-
-	var amounts []float64
-	for _, m := range matches {
-		// Try parsing with comma as thousands separator
-		clean := strings.ReplaceAll(m, ",", "")
-		if val, err := strconv.ParseFloat(clean, 64); err == nil && val > 0 {
-			amounts = append(amounts, val)
-		} else {
-			// Try with dot as thousands separator (European format)
-			clean = strings.ReplaceAll(m, ".", "")
-			if val, err := strconv.ParseFloat(clean, 64); err == nil && val > 0 {
-				amounts = append(amounts, val)
-			}
+	primary, secondary := text, ""
+	if loc := approxAmountRegex.FindStringIndex(text); loc != nil {
+		cut := loc[0]
+		if paren := strings.LastIndex(text[:cut], "("); paren >= 0 {
+			cut = paren
 		}
+		primary, secondary = text[:cut], text[loc[1]:]
 	}
 
+	format := numberFormatFor(locale, firstCurrency(primary, currency))
+
+	result := amountParse{}
+	amounts := findAmounts(primary, format)
 	if len(amounts) == 0 {
-		return 0, 0, ""
+		return result
+	}
+	if c := nearestCurrency(primary, amounts[0]); c != "" {
+		currency = c
+	}
+	result.Currency = currency
+
+	if secondary != "" {
+		if extra := findAmounts(secondary, numberFormatFor(locale, firstCurrency(secondary, ""))); len(extra) > 0 {
+			if c := nearestCurrency(secondary, extra[0]); c != "" && c != currency {
+				result.SecondaryCurrency = c
+				result.SecondaryAmount = extra[0].value
+			}
+		}
+	}
+
+	result.Min, result.Max = amountRange(strings.ToLower(primary), amounts)
+	return result
+}
+
+// amountRange turns the amounts found in text into a min/max pair.
+func amountRange(textLower string, tokens []amountToken) (float64, float64) {
+	amounts := make([]float64, len(tokens))
+	for i, t := range tokens {
+		amounts[i] = t.value
 	}
 
 	if len(amounts) == 1 {
 		// Single amount - check if it's "up to" or "minimum"
 		if strings.Contains(textLower, "up to") || strings.Contains(textLower, "hasta") || strings.Contains(textLower, "maximum") {
-			return 0, amounts[0], currency
+			return 0, amounts[0]
 		}
 		if strings.Contains(textLower, "minimum") || strings.Contains(textLower, "at least") {
-			return amounts[0], 0, currency
+			return amounts[0], 0
 		}
 		// Default: treat as maximum
-		return 0, amounts[0], currency
+		return 0, amounts[0]
 	}
 
 	// Multiple amounts - assume range
@@ -92,12 +324,65 @@ func parseAmountRobust(text string, defaultCurrency string) (float64, float64, s
 		}
 		if len(distinct) >= 2 {
 			if distinct[0] < distinct[1] {
-				return distinct[0], distinct[1], currency
+				return distinct[0], distinct[1]
 			}
-			return distinct[1], distinct[0], currency
+			return distinct[1], distinct[0]
 		}
-		return 0, max, currency
+		return 0, max
 	}
 
-	return min, max, currency
+	return min, max
+}
+
+// amountKeywordRegex finds prose that usually states a call's budget.
+var amountKeywordRegex = regexp.MustCompile(`(?i)\b(?:budget|awards?|funding|grants?\s+of|financiamiento|cofinanciamiento|monto|presupuesto|subvenci[oó]n)\b`)
+
+// amountKeywordWindow is how far after a keyword an amount may start.
+const amountKeywordWindow = 120
+
+// currencyMarkerReach is how close a currency marker must sit to an amount
+// for prose extraction ("£1.5 million", "1.5 millones de soles").
+const currencyMarkerReach = 12
+
+// findAmountSnippet returns the clause of text that states an amount near a
+// budget keyword, for pages without an amount selector. The amount must carry
+// a currency marker so stray numbers (years, counts) are not picked up.
+func findAmountSnippet(text string) (string, bool) {
+	for _, kw := range amountKeywordRegex.FindAllStringIndex(text, -1) {
+		windowEnd := kw[1] + amountKeywordWindow
+		if windowEnd > len(text) {
+			windowEnd = len(text)
+		}
+		window := text[kw[0]:windowEnd]
+		if nl := strings.IndexByte(window, '\n'); nl >= 0 {
+			window = window[:nl]
+		}
+
+		for _, amt := range findAmounts(window, numberFormatFor("", firstCurrency(window, ""))) {
+			if amt.value < 100 || !currencyNear(window, amt) {
+				continue
+			}
+			clauseEnd := len(text)
+			rest := text[kw[0]+amt.end:]
+			for _, sep := range []string{". ", "\n", "; "} {
+				if i := strings.Index(rest, sep); i >= 0 && kw[0]+amt.end+i < clauseEnd {
+					clauseEnd = kw[0] + amt.end + i
+				}
+			}
+			return strings.TrimSpace(text[kw[0]:clauseEnd]), true
+		}
+	}
+	return "", false
+}
+
+func currencyNear(text string, amount amountToken) bool {
+	for _, loc := range currencyMarkerRegex.FindAllStringIndex(text, -1) {
+		if loc[1] <= amount.start && amount.start-loc[1] <= currencyMarkerReach {
+			return true
+		}
+		if loc[0] >= amount.end && loc[0]-amount.end <= currencyMarkerReach {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,154 @@
+package ingest
+
+import "testing"
+
+func TestParseAmountDetailed_SolesWithUSDParenthetical(t *testing.T) {
+	p := parseAmountDetailed("S/ 500,000 (approx. USD 130,000)", "USD")
+	if p.Currency != "PEN" || p.Max != 500000 || p.Min != 0 {
+		t.Fatalf("expected PEN 0-500000, got %s %v-%v", p.Currency, p.Min, p.Max)
+	}
+	if p.SecondaryCurrency != "USD" || p.SecondaryAmount != 130000 {
+		t.Fatalf("expected secondary USD 130000, got %s %v", p.SecondaryCurrency, p.SecondaryAmount)
+	}
+}
+
+func TestParseAmountDetailed_SpanishEquivalent(t *testing.T) {
+	p := parseAmountDetailed("Hasta S/. 300,000, equivalente a US$ 80,000", "PEN")
+	if p.Currency != "PEN" || p.Max != 300000 {
+		t.Fatalf("expected PEN max 300000, got %s %v", p.Currency, p.Max)
+	}
+	if p.SecondaryCurrency != "USD" || p.SecondaryAmount != 80000 {
+		t.Fatalf("expected secondary USD 80000, got %s %v", p.SecondaryCurrency, p.SecondaryAmount)
+	}
+}
+
+func TestParseAmountRobust_SolesBeatsDollarElsewhere(t *testing.T) {
+	// "$" appears in the text, but the amount is written next to "S/".
+	_, max, currency := parseAmountRobust("Monto: S/ 150,000 por proyecto ($ no aplica)", "USD")
+	if currency != "PEN" || max != 150000 {
+		t.Fatalf("expected PEN 150000, got %s %v", currency, max)
+	}
+
+	_, max, currency = parseAmountRobust("Up to $50,000", "PEN")
+	if currency != "USD" || max != 50000 {
+		t.Fatalf("expected USD 50000, got %s %v", currency, max)
+	}
+}
+
+func TestParseAmountRobust_NoMarkerKeepsDefault(t *testing.T) {
+	min, max, currency := parseAmountRobust("10,000 - 25,000", "EUR")
+	if currency != "EUR" || min != 10000 || max != 25000 {
+		t.Fatalf("expected EUR 10000-25000, got %s %v-%v", currency, min, max)
+	}
+}
+
+func TestFromRaw_RecordsSecondaryAmountEvidence(t *testing.T) {
+	opp := FromRaw(RawOpportunity{
+		Title:       "Fondo",
+		ExternalURL: "https://example.org/fondo",
+		RawAmount:   "S/ 500,000 (approx. USD 130,000)",
+	})
+	if opp.Currency != "PEN" || opp.AmountMax != 500000 {
+		t.Fatalf("expected PEN 500000, got %s %v", opp.Currency, opp.AmountMax)
+	}
+	secondary, ok := opp.SourceEvidenceJSON["amount_secondary"].(map[string]interface{})
+	if !ok || secondary["currency"] != "USD" || secondary["amount"] != 130000.0 {
+		t.Fatalf("expected amount_secondary evidence, got %v", opp.SourceEvidenceJSON["amount_secondary"])
+	}
+}
+
+func TestFindAmountSnippet_ProseBudgets(t *testing.T) {
+	tests := []struct {
+		text     string
+		currency string
+		max      float64
+	}{
+		{"The programme offers awards of up to £1.5 million per project. Applications open in May 2026.", "GBP", 1500000},
+		{"Funding call 2026: the total budget is €2,000,000 for three years.", "EUR", 2000000},
+		{"El monto máximo de financiamiento es de S/ 150,000 por proyecto. Postula hasta el 30 de junio de 2026.", "PEN", 150000},
+		{"Convocatoria 2026. Financiamiento no reembolsable de hasta 1.2 millones de soles.", "PEN", 1200000},
+		{"Presupuesto total de hasta 1.5 mil millones de soles.", "PEN", 1500000000},
+		// An accented word after the number is not a scale suffix.
+		{"Financiamiento de S/ 150,000 más IGV.", "PEN", 150000},
+		{"Monto de hasta S/ 50,000 máximo por proyecto.", "PEN", 50000},
+		{"Monto de US$ 20,000 mínimo.", "USD", 20000},
+		{"Financiamiento de € 100.000 más IVA.", "EUR", 100000},
+		{"Monto: hasta S/ 50,000 créditos.", "PEN", 50000},
+	}
+
+	for _, tt := range tests {
+		snippet, ok := findAmountSnippet(tt.text)
+		if !ok {
+			t.Errorf("%q: expected an amount snippet", tt.text)
+			continue
+		}
+		_, max, currency := parseAmountRobust(snippet, "USD")
+		if currency != tt.currency || max != tt.max {
+			t.Errorf("%q: snippet %q parsed as %s %v, want %s %v", tt.text, snippet, currency, max, tt.currency, tt.max)
+		}
+	}
+}
+
+func TestFindAmountSnippet_IgnoresNumbersWithoutCurrency(t *testing.T) {
+	if snippet, ok := findAmountSnippet("Funding for 2026 supports 350 researchers across 12 countries."); ok {
+		t.Fatalf("expected no amount, got %q", snippet)
+	}
+}
+
+func TestParseAmountRobust_SolesMarkerNeedsWordStart(t *testing.T) {
+	_, max, currency := parseAmountRobust("Ver bases en /fondos/ 2026: hasta USD 40,000", "EUR")
+	if max != 40000 || currency != "USD" {
+		t.Fatalf("expected USD 40000, got %s %v", currency, max)
+	}
+}
+
+func TestParseAmountRobust_SkipsBareYear(t *testing.T) {
+	min, max, currency := parseAmountRobust("Convocatoria 2026: hasta S/ 50,000", "USD")
+	if min != 0 || max != 50000 || currency != "PEN" {
+		t.Fatalf("expected PEN 0-50000, got %s %v-%v", currency, min, max)
+	}
+}
+
+func TestParseAmountLocalized_EuropeanAndIndian(t *testing.T) {
+	cases := []struct {
+		text, defaultCurrency, locale string
+		wantMax                       float64
+		wantCurrency                  string
+	}{
+		{"1.000.000,50 €", "USD", "", 1000000.50, "EUR"},
+		{"€ 1.000,50", "USD", "", 1000.50, "EUR"},
+		{"Bis zu 250.000 EUR", "USD", "", 250000, "EUR"},
+		{"EUR 1 000 000", "USD", "", 1000000, "EUR"},
+		{"jusqu'à 1\u202f500\u00a0000,50 €", "USD", "", 1500000.50, "EUR"},
+		{"2,5 Mio. EUR", "USD", "", 2500000, "EUR"},
+		{"Hasta 2,5 millones de euros", "EUR", "es-ES", 2500000, "EUR"},
+		{"₹1.5 crore", "USD", "", 15000000, "INR"},
+		{"Grants of up to ₹50 lakh", "USD", "", 5000000, "INR"},
+		{"INR 1,00,000", "USD", "en-IN", 100000, "INR"},
+		// Peruvian amounts keep comma grouping even though they're Spanish.
+		{"S/ 1,500,000.00", "PEN", "", 1500000, "PEN"},
+		{"Up to $1.5 million", "USD", "", 1500000, "USD"},
+		// Accented words after the number ("más", "máximo") are no scale.
+		{"Hasta 100.000 € más IVA", "EUR", "es-ES", 100000, "EUR"},
+		{"2,5 Mio. EUR máximo", "USD", "", 2500000, "EUR"},
+		{"₹50 lakh máximo", "USD", "", 5000000, "INR"},
+	}
+	for _, tc := range cases {
+		p := parseAmountLocalized(tc.text, tc.defaultCurrency, tc.locale)
+		if p.Max != tc.wantMax || p.Currency != tc.wantCurrency {
+			t.Errorf("%q (locale %q): got %s %v, want %s %v", tc.text, tc.locale, p.Currency, p.Max, tc.wantCurrency, tc.wantMax)
+		}
+	}
+}
+
+func TestFromRaw_NumberLocaleHint(t *testing.T) {
+	opp := FromRaw(RawOpportunity{
+		Title:       "Förderung",
+		ExternalURL: "https://example.de/foerderung",
+		RawAmount:   "bis 1.500 Euro",
+		Extra:       map[string]string{"number_locale": "de"},
+	})
+	if opp.AmountMax != 1500 || opp.Currency != "EUR" {
+		t.Fatalf("expected EUR 1500, got %s %v", opp.Currency, opp.AmountMax)
+	}
+}
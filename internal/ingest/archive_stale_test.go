@@ -0,0 +1,92 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestArchiveStale_SeededSet runs against a migrated database; set
+// TEST_DATABASE_URL to enable it. ArchiveStale is table-wide, so the seeded
+// rows sit ten years back and the cutoff at nine, clear of any other data.
+func TestArchiveStale_SeededSet(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "archive-stale-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	const year = 365 * 24 * time.Hour
+	now := time.Now().UTC()
+	old := now.Add(-10 * year)
+	recent := now.Add(-8 * year)
+	future := now.Add(30 * 24 * time.Hour)
+
+	seed := []struct {
+		id              string
+		status          string
+		updatedAt       time.Time
+		nextDeadline    *time.Time
+		isRolling       bool
+		rollingEvidence bool
+		want            string
+	}{
+		{"old-open", "open", old, nil, false, false, "archived"},
+		{"old-needs-review", "needs_review", old, nil, false, false, "archived"},
+		{"recently-updated", "open", recent, nil, false, false, "open"},
+		{"has-next-deadline", "open", old, &future, false, false, "open"},
+		{"rolling-flag", "open", old, nil, true, false, "open"},
+		{"rolling-evidence", "needs_review", old, nil, false, true, "needs_review"},
+		{"already-closed", "closed", old, nil, false, false, "closed"},
+		{"upcoming", "upcoming", old, nil, false, false, "upcoming"},
+	}
+	for _, s := range seed {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, normalized_status,
+			                           updated_at, next_deadline_at, is_rolling, rolling_evidence)
+			VALUES ($1, $2, $3, $1, $4::normalized_status_enum, $5, $6, $7, $8)`,
+			s.id, "https://"+domain+"/"+s.id, domain, s.status, s.updatedAt, s.nextDeadline, s.isRolling, s.rollingEvidence); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archived, err := NewPipeline(pool, nil, nil, nil).ArchiveStale(ctx, 9*year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived < 2 {
+		t.Errorf("archived = %d, want at least the 2 stale seeded rows", archived)
+	}
+
+	for _, s := range seed {
+		var status, reason string
+		if err := pool.QueryRow(ctx, `
+			SELECT normalized_status::text, COALESCE(status_reason, '') FROM opportunities
+			WHERE source_domain = $1 AND source_id = $2`, domain, s.id).Scan(&status, &reason); err != nil {
+			t.Fatal(err)
+		}
+		if status != s.want {
+			t.Errorf("%s: status = %q, want %q", s.id, status, s.want)
+		}
+		if s.want == "archived" && reason != staleArchivedReason {
+			t.Errorf("%s: status_reason = %q, want %q", s.id, reason, staleArchivedReason)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// bulkUpsertTable is the per-transaction staging table BulkUpsertOpportunities
+// copies into before merging.
+const bulkUpsertTable = "opportunities_bulk"
+
+// txStarter is the part of *pgxpool.Pool the bulk path needs.
+type txStarter interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// BulkUpsertOpportunities prepares opps like SaveOpportunity, then writes
+// them in one transaction: COPY into a temp table and a single
+// INSERT ... SELECT with the same ON CONFLICT merge. The page's stored rows
+// are read in one query and its embeddings generated in one call, so the
+// database and embedding costs no longer grow with the page. LLM extraction
+// of a missing deadline still runs per record.
+//
+// It returns how many rows were written. Rows that fail preparation (no
+// source_id) are logged and skipped. API-first sources use it; HTML sources
+// that enrich page by page keep calling SaveOpportunity.
+func (p *Pipeline) BulkUpsertOpportunities(ctx context.Context, opps []Opportunity) (int, error) {
+	written, _, err := p.bulkUpsert(ctx, p.DB, p.prepareBatch(ctx, opps, &IngestionStats{}))
+	return written, err
+}
+
+// storedRow is what preparing a record reads from the row already stored
+// under its (source_domain, source_id).
+type storedRow struct {
+	DeadlineAt   *time.Time
+	IsRolling    bool
+	OppStatus    string
+	ContentHash  string
+	HasEmbedding bool
+}
+
+// batchPage is the state prepareBatch shares with prepareOpportunity through
+// the context.
+type batchPage struct {
+	// rows holds the page's stored rows by sourceKey; a key with no row is
+	// absent. nil when the page could not be loaded and lookups fall back
+	// to one query per record.
+	rows map[string]storedRow
+	// deferredEmbedding is set by prepareOpportunity when the record it just
+	// prepared wants an embedding.
+	deferredEmbedding bool
+}
+
+// prepareBatch prepares each of opps for the write, dropping the ones that
+// fail and recording them in stats.
+func (p *Pipeline) prepareBatch(ctx context.Context, opps []Opportunity, stats *IngestionStats) []Opportunity {
+	page := &batchPage{rows: p.loadPageRows(ctx, opps)}
+	ctx = context.WithValue(ctx, pageRowsKey, page)
+
+	prepared := make([]Opportunity, 0, len(opps))
+	var embed []int
+	for _, opp := range opps {
+		page.deferredEmbedding = false
+		if err := p.prepareOpportunity(ctx, &opp); err != nil {
+			log.Printf("[Bulk] Skipping %q: %v", opp.Title, err)
+			stats.Record("", err)
+			continue
+		}
+		if page.deferredEmbedding {
+			embed = append(embed, len(prepared))
+		}
+		prepared = append(prepared, opp)
+	}
+	p.embedBatch(ctx, prepared, embed)
+	if numberDedupEnabled(ctx) {
+		collapsed := collapseBatchNumbers(prepared)
+		for i := len(collapsed); i < len(prepared); i++ {
+			stats.Skip(SkipSupersededNumber)
+		}
+		prepared = collapsed
+	}
+	return prepared
+}
+
+// loadPageRows reads the stored rows for all of opps in one query. It returns
+// nil, leaving lookups to prepareOpportunity, when the query fails.
+func (p *Pipeline) loadPageRows(ctx context.Context, opps []Opportunity) map[string]storedRow {
+	rows := make(map[string]storedRow, len(opps))
+	if p.DB == nil || len(opps) == 0 {
+		return rows
+	}
+	domains := make([]string, len(opps))
+	ids := make([]string, len(opps))
+	for i, opp := range opps {
+		domains[i] = opp.SourceDomain
+		ids[i] = opp.SourceID
+	}
+	result, err := p.DB.Query(ctx, `
+		SELECT o.source_domain, o.source_id, o.deadline_at, o.is_rolling,
+		       COALESCE(o.opp_status, ''), COALESCE(o.content_hash, ''), o.embedding IS NOT NULL
+		FROM opportunities o
+		JOIN unnest($1::text[], $2::text[]) AS page(source_domain, source_id)
+		  ON o.source_domain = page.source_domain AND o.source_id = page.source_id
+	`, domains, ids)
+	if err != nil {
+		log.Printf("[Bulk] Loading stored rows failed, looking them up one by one: %v", err)
+		return nil
+	}
+	defer result.Close()
+	for result.Next() {
+		var domain, sourceID string
+		var row storedRow
+		if err := result.Scan(&domain, &sourceID, &row.DeadlineAt, &row.IsRolling, &row.OppStatus, &row.ContentHash, &row.HasEmbedding); err != nil {
+			log.Printf("[Bulk] Loading stored rows failed, looking them up one by one: %v", err)
+			return nil
+		}
+		rows[sourceKey(domain, sourceID)] = row
+	}
+	if err := result.Err(); err != nil {
+		log.Printf("[Bulk] Loading stored rows failed, looking them up one by one: %v", err)
+		return nil
+	}
+	return rows
+}
+
+// existingRow returns the stored row for (domain, sourceID), or nil. Inside
+// prepareBatch it reads the page loaded up front instead of the database.
+func (p *Pipeline) existingRow(ctx context.Context, domain, sourceID string) *storedRow {
+	if page, ok := ctx.Value(pageRowsKey).(*batchPage); ok && page.rows != nil {
+		row, found := page.rows[sourceKey(domain, sourceID)]
+		if !found {
+			return nil
+		}
+		return &row
+	}
+	existing, err := p.Store.GetOpportunityBySourceID(ctx, domain, sourceID)
+	if err != nil || existing == nil {
+		return nil
+	}
+	return &storedRow{DeadlineAt: existing.DeadlineAt, IsRolling: existing.IsRolling, OppStatus: existing.OppStatus}
+}
+
+// embedBatch fills in the embeddings prepareOpportunity deferred for the rows
+// of opps at the given indexes, in one call. When the call fails they stay
+// empty for BackfillEmbeddings to catch up on.
+func (p *Pipeline) embedBatch(ctx context.Context, opps []Opportunity, indexes []int) {
+	if len(indexes) == 0 || !p.AI.Available() {
+		return
+	}
+	texts := make([]string, len(indexes))
+	for i, idx := range indexes {
+		texts[i] = embeddingText(opps[idx].Title, opps[idx].Summary)
+	}
+	vectors, err := p.AI.GenerateEmbeddings(ctx, texts)
+	if err != nil {
+		log.Printf("⚠️ Failed to generate %d embeddings: %v", len(texts), err)
+		return
+	}
+	for i, idx := range indexes {
+		opps[idx].Embedding = vectors[i]
+		p.dropMismatchedEmbedding(&opps[idx])
+	}
+}
+
+func (p *Pipeline) bulkUpsert(ctx context.Context, db txStarter, opps []Opportunity) (written, inserted int, err error) {
+	// Duplicates within a page collapse onto one row and still count as
+	// saved; only rows the merge reports as new count as inserted.
+	written = len(opps)
+	opps = dedupeBySourceKey(opps)
+	if len(opps) == 0 {
+		return 0, 0, nil
+	}
+	columns := opportunityUpsertColumnNames()
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, bulkStagingSQL(columns)); err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: create staging table: %w", err)
+	}
+
+	rows := make([][]interface{}, len(opps))
+	for i, opp := range opps {
+		rows[i] = opportunityUpsertArgs(opp)
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{bulkUpsertTable}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: copy: %w", err)
+	}
+
+	type mergedRow struct {
+		id, domain, sourceID, previousStatus string
+		inserted                             bool
+	}
+	var merged []mergedRow
+	result, err := tx.Query(ctx, bulkMergeSQL(columns))
+	if err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: merge: %w", err)
+	}
+	for result.Next() {
+		var m mergedRow
+		if err := result.Scan(&m.id, &m.domain, &m.sourceID, &m.inserted, &m.previousStatus); err != nil {
+			result.Close()
+			return 0, 0, fmt.Errorf("bulk upsert: scan: %w", err)
+		}
+		if m.inserted {
+			inserted++
+		}
+		merged = append(merged, m)
+	}
+	result.Close()
+	if err := result.Err(); err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: merge: %w", err)
+	}
+	if numberDedupEnabled(ctx) {
+		if err := archiveNumberSiblings(ctx, tx, opps); err != nil {
+			return 0, 0, fmt.Errorf("bulk upsert: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("bulk upsert: commit: %w", err)
+	}
+
+	if p.Notifier != nil {
+		byKey := make(map[string]Opportunity, len(opps))
+		for _, opp := range opps {
+			byKey[sourceKey(opp.SourceDomain, opp.SourceID)] = opp
+		}
+		now := time.Now().UTC()
+		for _, m := range merged {
+			opp, ok := byKey[sourceKey(m.domain, m.sourceID)]
+			if !ok {
+				continue
+			}
+			if event, ok := opportunityEventFor(opp, m.id, m.inserted, m.previousStatus, now); ok {
+				p.Notifier.Notify(event)
+			}
+		}
+	}
+	return written, inserted, nil
+}
+
+// dedupeBySourceKey keeps the last record per (source_domain, source_id);
+// ON CONFLICT DO UPDATE can't touch the same row twice in one statement.
+func dedupeBySourceKey(opps []Opportunity) []Opportunity {
+	last := make(map[string]int, len(opps))
+	for i, opp := range opps {
+		last[sourceKey(opp.SourceDomain, opp.SourceID)] = i
+	}
+	if len(last) == len(opps) {
+		return opps
+	}
+	out := make([]Opportunity, 0, len(last))
+	for i, opp := range opps {
+		if last[sourceKey(opp.SourceDomain, opp.SourceID)] == i {
+			out = append(out, opp)
+		}
+	}
+	return out
+}
+
+func sourceKey(domain, sourceID string) string {
+	return domain + "\x00" + sourceID
+}
+
+func opportunityUpsertColumnNames() []string {
+	parts := strings.Split(opportunityUpsertColumns, ",")
+	columns := make([]string, len(parts))
+	for i, part := range parts {
+		columns[i] = strings.TrimSpace(part)
+	}
+	return columns
+}
+
+// bulkStagingSQL creates the staging table with the target's column types.
+// normalized_status is staged as text because COPY can't encode an
+// unregistered enum; bulkMergeSQL casts it back.
+func bulkStagingSQL(columns []string) string {
+	selectList := make([]string, len(columns))
+	for i, col := range columns {
+		if col == "normalized_status" {
+			selectList[i] = "normalized_status::text AS normalized_status"
+			continue
+		}
+		selectList[i] = col
+	}
+	return fmt.Sprintf("CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s FROM opportunities WITH NO DATA",
+		bulkUpsertTable, strings.Join(selectList, ", "))
+}
+
+// bulkMergeSQL upserts the staged rows and returns, per row, its id, whether
+// it was inserted, and the status it had before (for webhook events).
+func bulkMergeSQL(columns []string) string {
+	selectList := make([]string, len(columns))
+	for i, col := range columns {
+		switch col {
+		case "normalized_status":
+			selectList[i] = "normalized_status::normalized_status_enum"
+		case "fetch_blocked_detected":
+			selectList[i] = "COALESCE(fetch_blocked_detected, false)"
+		default:
+			selectList[i] = col
+		}
+	}
+	return fmt.Sprintf(`
+		WITH prev AS (
+			SELECT o.source_domain, o.source_id, o.normalized_status::text AS status
+			FROM opportunities o
+			JOIN %[1]s b ON b.source_domain = o.source_domain AND b.source_id = o.source_id
+		), up AS (
+			INSERT INTO opportunities (%[2]s)
+			SELECT %[3]s FROM %[1]s
+			%[4]s
+			RETURNING id::text, source_domain, source_id, (xmax = 0) AS inserted
+		)
+		SELECT up.id, COALESCE(up.source_domain, ''), up.source_id, up.inserted, COALESCE(prev.status, '')
+		FROM up
+		LEFT JOIN prev ON prev.source_domain = up.source_domain AND prev.source_id = up.source_id
+	`, bulkUpsertTable, strings.Join(columns, ", "), strings.Join(selectList, ", "), opportunityConflictSQL)
+}
+
+// saveBatch bulk-writes one fetched page and folds the outcome into stats.
+// Rows that fail preparation are counted as skips or errors on their own.
+func (p *Pipeline) saveBatch(ctx context.Context, tag string, opps []Opportunity, stats *IngestionStats) {
+	if len(opps) == 0 {
+		return
+	}
+	p.writeBatch(ctx, p.DB, tag, p.prepareBatch(ctx, opps, stats), stats)
+}
+
+// writeBatch writes prepared rows in one bulk transaction. If that fails, for
+// instance on one row the database rejects, it writes them one by one so only
+// the bad rows count as errors.
+func (p *Pipeline) writeBatch(ctx context.Context, db txStarter, tag string, prepared []Opportunity, stats *IngestionStats) {
+	saved, inserted, err := p.bulkUpsert(ctx, db, prepared)
+	if err == nil {
+		stats.TotalSaved += saved
+		stats.Inserted += inserted
+		stats.Updated += saved - inserted
+		return
+	}
+	log.Printf("[%s] Bulk save of %d failed, saving rows one by one: %v", tag, len(prepared), err)
+	for _, opp := range prepared {
+		outcome, err := p.upsertPrepared(ctx, db, opp)
+		if err != nil {
+			log.Printf("[%s] Failed to save %q: %v", tag, opp.Title, err)
+		}
+		stats.Record(outcome, err)
+	}
+}
@@ -0,0 +1,263 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeBulkDB records every statement the bulk path sends; each call is one
+// round trip to Postgres.
+type fakeBulkDB struct {
+	pgx.Tx // unused methods panic
+
+	roundTrips int
+	execSQL    []string
+	copied     [][]interface{}
+	copyCols   []string
+	mergeSQL   string
+	returned   [][]interface{}
+	committed  bool
+
+	// copyErr fails the bulk COPY; failTitle fails the single-row upsert of
+	// the record with that title.
+	copyErr   error
+	failTitle string
+	rowWrites int
+}
+
+func (f *fakeBulkDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	f.roundTrips++
+	return f, nil
+}
+
+func (f *fakeBulkDB) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.roundTrips++
+	f.execSQL = append(f.execSQL, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (f *fakeBulkDB) CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error) {
+	f.roundTrips++
+	if f.copyErr != nil {
+		return 0, f.copyErr
+	}
+	f.copyCols = columns
+	for src.Next() {
+		values, err := src.Values()
+		if err != nil {
+			return 0, err
+		}
+		f.copied = append(f.copied, values)
+	}
+	return int64(len(f.copied)), nil
+}
+
+func (f *fakeBulkDB) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.roundTrips++
+	f.mergeSQL = sql
+	return &fakeRows{rows: f.returned, idx: -1}, nil
+}
+
+func (f *fakeBulkDB) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.roundTrips++
+	f.rowWrites++
+	if args[0] == f.failTitle {
+		return fakeRow{err: fmt.Errorf("invalid input for %q", f.failTitle)}
+	}
+	return fakeRow{values: []interface{}{fmt.Sprintf("id-%d", f.rowWrites), true, ""}}
+}
+
+func (f *fakeBulkDB) Commit(ctx context.Context) error {
+	f.roundTrips++
+	f.committed = true
+	return nil
+}
+
+func (f *fakeBulkDB) Rollback(ctx context.Context) error { return nil }
+
+type fakeRows struct {
+	pgx.Rows
+	rows [][]interface{}
+	idx  int
+}
+
+func (r *fakeRows) Next() bool { r.idx++; return r.idx < len(r.rows) }
+func (r *fakeRows) Close()     {}
+func (r *fakeRows) Err() error { return nil }
+func (r *fakeRows) Scan(dest ...any) error {
+	for i, v := range r.rows[r.idx] {
+		switch d := dest[i].(type) {
+		case *string:
+			*d = v.(string)
+		case *bool:
+			*d = v.(bool)
+		}
+	}
+	return nil
+}
+
+type fakeRow struct {
+	values []interface{}
+	err    error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	rows := &fakeRows{rows: [][]interface{}{r.values}}
+	return rows.Scan(dest...)
+}
+
+type recordingNotifier struct{ events []OpportunityEvent }
+
+func (n *recordingNotifier) Notify(event OpportunityEvent) { n.events = append(n.events, event) }
+
+func TestBulkUpsert_ConstantRoundTrips(t *testing.T) {
+	deadline := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+	opps := make([]Opportunity, 0, 200)
+	for i := 0; i < 200; i++ {
+		opps = append(opps, Opportunity{
+			Title:            fmt.Sprintf("Grant %d", i),
+			SourceDomain:     "grants.gov",
+			SourceID:         fmt.Sprintf("GG-%d", i),
+			DeadlineAt:       &deadline,
+			NormalizedStatus: "open",
+		})
+	}
+	// A repeated key within the page collapses onto the later record.
+	opps = append(opps, Opportunity{Title: "Grant 7 (amended)", SourceDomain: "grants.gov", SourceID: "GG-7", DeadlineAt: &deadline, NormalizedStatus: "open"})
+
+	fake := &fakeBulkDB{returned: [][]interface{}{
+		{"id-0", "grants.gov", "GG-0", true, ""},
+		{"id-7", "grants.gov", "GG-7", false, "upcoming"},
+	}}
+	notifier := &recordingNotifier{}
+	p := &Pipeline{Notifier: notifier}
+
+	saved, inserted, err := p.bulkUpsert(context.Background(), fake, opps)
+	if err != nil {
+		t.Fatalf("bulkUpsert: %v", err)
+	}
+	if saved != len(opps) {
+		t.Errorf("saved = %d, want %d", saved, len(opps))
+	}
+	// GG-0 came back as a new row, GG-7 as an update of an existing one.
+	if inserted != 1 {
+		t.Errorf("inserted = %d, want 1", inserted)
+	}
+
+	// begin, create staging table, COPY, merge, commit — versus one
+	// statement per row through SaveOpportunity.
+	if fake.roundTrips != 5 {
+		t.Errorf("round trips = %d, want 5 for %d rows", fake.roundTrips, len(opps))
+	}
+	if !fake.committed {
+		t.Error("transaction was not committed")
+	}
+	if len(fake.copied) != 200 {
+		t.Errorf("copied %d rows, want 200 after dedupe", len(fake.copied))
+	}
+	if len(fake.copyCols) != len(fake.copied[0]) {
+		t.Errorf("COPY has %d columns but %d values per row", len(fake.copyCols), len(fake.copied[0]))
+	}
+	for _, row := range fake.copied {
+		if row[5] == "GG-7" && row[0] != "Grant 7 (amended)" {
+			t.Errorf("duplicate key kept %q, want the later record", row[0])
+		}
+	}
+	if !strings.Contains(fake.execSQL[0], "normalized_status::text AS normalized_status") {
+		t.Errorf("staging table should stage the enum as text: %s", fake.execSQL[0])
+	}
+	for _, want := range []string{"normalized_status::normalized_status_enum", "ON CONFLICT (source_domain, source_id) DO UPDATE", "RETURNING id::text"} {
+		if !strings.Contains(fake.mergeSQL, want) {
+			t.Errorf("merge SQL missing %q", want)
+		}
+	}
+
+	if len(notifier.events) != 2 {
+		t.Fatalf("got %d events, want created + status_changed", len(notifier.events))
+	}
+	if notifier.events[0].Type != EventOpportunityCreated || notifier.events[1].Type != EventOpportunityStatusChanged {
+		t.Errorf("unexpected events: %+v", notifier.events)
+	}
+}
+
+func TestBulkUpsert_EmptyBatchSkipsDB(t *testing.T) {
+	fake := &fakeBulkDB{}
+	if _, _, err := (&Pipeline{}).bulkUpsert(context.Background(), fake, nil); err != nil {
+		t.Fatal(err)
+	}
+	if fake.roundTrips != 0 {
+		t.Errorf("round trips = %d, want 0 for an empty batch", fake.roundTrips)
+	}
+}
+
+func TestPrepareBatch_DropsBadRowsAndReadsStoredRowsOnce(t *testing.T) {
+	deadline := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+	opps := []Opportunity{
+		{Title: "Grant with deadline", SourceDomain: "grants.gov", SourceID: "GG-1", DeadlineAt: &deadline},
+		{Title: "Grant without source id", SourceDomain: "grants.gov"},
+		// No deadline: preparation looks for a stored one. Outside a page
+		// that is a Store query, which would panic on this Store-less
+		// pipeline; inside prepareBatch it reads the page loaded up front.
+		{Title: "Grant without deadline", SourceDomain: "grants.gov", SourceID: "GG-3"},
+	}
+
+	var stats IngestionStats
+	prepared := (&Pipeline{}).prepareBatch(context.Background(), opps, &stats)
+
+	if len(prepared) != 2 || prepared[0].SourceID != "GG-1" || prepared[1].SourceID != "GG-3" {
+		t.Fatalf("prepared = %+v, want GG-1 and GG-3", prepared)
+	}
+	if stats.Skipped != 1 || stats.SkipReasons[SkipMissingSourceID] != 1 || stats.Errors != 0 {
+		t.Errorf("stats = %+v, want the row without source_id skipped alone", stats)
+	}
+	if prepared[0].NormalizedStatus == "" {
+		t.Error("prepared rows should carry a status decision")
+	}
+}
+
+func TestExistingRow_ReadsThePage(t *testing.T) {
+	deadline := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+	page := &batchPage{rows: map[string]storedRow{
+		sourceKey("grants.gov", "GG-1"): {DeadlineAt: &deadline, OppStatus: "posted"},
+	}}
+	ctx := context.WithValue(context.Background(), pageRowsKey, page)
+	p := &Pipeline{}
+
+	row := p.existingRow(ctx, "grants.gov", "GG-1")
+	if row == nil || row.DeadlineAt == nil || !row.DeadlineAt.Equal(deadline) || row.OppStatus != "posted" {
+		t.Fatalf("existingRow = %+v, want the page's row", row)
+	}
+	if row := p.existingRow(ctx, "grants.gov", "GG-2"); row != nil {
+		t.Errorf("a key missing from the page has no stored row, got %+v", row)
+	}
+}
+
+func TestWriteBatch_FailedBulkWriteDropsOnlyBadRows(t *testing.T) {
+	deadline := time.Date(2027, 1, 15, 0, 0, 0, 0, time.UTC)
+	var stats IngestionStats
+	p := &Pipeline{}
+	prepared := p.prepareBatch(context.Background(), []Opportunity{
+		{Title: "Good grant A", SourceDomain: "grants.gov", SourceID: "GG-1", DeadlineAt: &deadline},
+		{Title: "Bad grant", SourceDomain: "grants.gov", SourceID: "GG-2", DeadlineAt: &deadline},
+		{Title: "Good grant B", SourceDomain: "grants.gov", SourceID: "GG-3", DeadlineAt: &deadline},
+	}, &stats)
+
+	fake := &fakeBulkDB{copyErr: fmt.Errorf("invalid input syntax"), failTitle: "Bad grant"}
+	p.writeBatch(context.Background(), fake, "Test", prepared, &stats)
+
+	if fake.rowWrites != 3 {
+		t.Errorf("row writes = %d, want one per prepared row after the bulk failure", fake.rowWrites)
+	}
+	if stats.TotalSaved != 2 || stats.Inserted != 2 || stats.Errors != 1 {
+		t.Errorf("stats = %+v, want 2 saved and only the bad row as an error", stats)
+	}
+}
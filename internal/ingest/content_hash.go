@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// contentHash fingerprints what a crawl extracted for an opportunity: its
+// title, summary and the structured text of its description. Markup and
+// whitespace changes leave it as is.
+func contentHash(opp Opportunity) string {
+	text := strings.Join([]string{opp.Title, opp.Summary, buildStructuredExtractionText(opp.Description)}, "\n")
+	sum := sha256.Sum256([]byte(strings.Join(strings.Fields(text), " ")))
+	return hex.EncodeToString(sum[:])
+}
+
+// storedContent is what a re-crawled page with an unchanged content hash
+// keeps from its existing row.
+type storedContent struct {
+	HasEmbedding bool
+}
+
+// unchangedContent returns the stored row for opp when a crawl finds its
+// content hash unchanged, and nil otherwise. Only crawls are checked:
+// refine and recompute re-derive everything on purpose.
+func (p *Pipeline) unchangedContent(ctx context.Context, opp *Opportunity) *storedContent {
+	if _, crawling := ctx.Value(runIDKey).(string); !crawling || p.DB == nil || opp.ContentHash == "" {
+		return nil
+	}
+	if page, ok := ctx.Value(pageRowsKey).(*batchPage); ok && page.rows != nil {
+		row, found := page.rows[sourceKey(opp.SourceDomain, opp.SourceID)]
+		if !found || row.ContentHash != opp.ContentHash {
+			return nil
+		}
+		return &storedContent{HasEmbedding: row.HasEmbedding}
+	}
+	var s storedContent
+	err := p.DB.QueryRow(ctx, `
+		SELECT embedding IS NOT NULL
+		FROM opportunities
+		WHERE source_domain = $1 AND source_id = $2 AND content_hash = $3`,
+		opp.SourceDomain, opp.SourceID, opp.ContentHash,
+	).Scan(&s.HasEmbedding)
+	if err != nil {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,145 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/ai"
+	"github.com/david/grant-finder/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestContentHash_IgnoresMarkupAndWhitespace(t *testing.T) {
+	a := Opportunity{Title: "Seed Fund", Summary: "Grants for startups.", Description: "<p>Apply by <b>30 March</b>.</p>"}
+	b := Opportunity{Title: "Seed  Fund ", Summary: "Grants for startups.", Description: "<div>\n  <p>Apply by 30 March.</p>\n</div>"}
+	if contentHash(a) != contentHash(b) {
+		t.Fatal("expected markup and whitespace changes to keep the hash")
+	}
+	b.Description = "<p>Apply by 15 April.</p>"
+	if contentHash(a) == contentHash(b) {
+		t.Fatal("expected a text change to change the hash")
+	}
+}
+
+// TestUpsertOpportunity_UnchangedContentSkipsAI runs against a migrated
+// database; set TEST_DATABASE_URL to enable it. Re-crawling an unchanged page
+// makes no LLM or embedding call, and a changed page does.
+func TestUpsertOpportunity_UnchangedContentSkipsAI(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	var aiCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/embeddings":
+			aiCalls.Add(1)
+			vec := make([]float32, ai.DefaultEmbeddingDim)
+			for i := range vec {
+				vec[i] = 0.1
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"embedding": vec})
+		case "/api/generate":
+			aiCalls.Add(1)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"response": "{}", "done": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	const domain = "content-hash-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	client := ai.NewOllamaClient(server.URL, ai.ModelConfig{})
+	p := &Pipeline{DB: pool, Store: db.NewStore(pool), Fetcher: NewHTTPFetcher(), AI: client}
+	crawlCtx := context.WithValue(ctx, runIDKey, "content-hash-test")
+	opp := Opportunity{
+		Title:        "Seed Fund",
+		Summary:      "Grants for early-stage startups.",
+		Description:  "<p>Applications are reviewed on a rolling basis.</p>",
+		ExternalURL:  server.URL + "/calls/seed",
+		SourceDomain: domain,
+		SourceID:     "seed",
+	}
+
+	if _, err := p.UpsertOpportunity(crawlCtx, opp); err != nil {
+		t.Fatal(err)
+	}
+	if aiCalls.Load() == 0 {
+		t.Fatal("expected the first crawl to call the AI")
+	}
+
+	aiCalls.Store(0)
+	if _, err := p.UpsertOpportunity(crawlCtx, opp); err != nil {
+		t.Fatal(err)
+	}
+	if n := aiCalls.Load(); n != 0 {
+		t.Fatalf("expected no AI calls for unchanged content, got %d", n)
+	}
+	var hash string
+	var enriched *time.Time
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(content_hash, ''), last_enriched_at FROM opportunities
+		WHERE source_domain = $1 AND source_id = 'seed'`, domain).Scan(&hash, &enriched); err != nil {
+		t.Fatal(err)
+	}
+	if hash == "" || enriched == nil {
+		t.Fatalf("expected content_hash and last_enriched_at stored, got %q %v", hash, enriched)
+	}
+
+	// Only the source status changes: still no AI call, but the status follows.
+	opp.OppStatus = "closed"
+	opp.SourceStatusRaw = "Closed"
+	if _, err := p.UpsertOpportunity(crawlCtx, opp); err != nil {
+		t.Fatal(err)
+	}
+	if n := aiCalls.Load(); n != 0 {
+		t.Fatalf("expected no AI calls for a status-only change, got %d", n)
+	}
+	var status string
+	if err := pool.QueryRow(ctx, `
+		SELECT normalized_status::text FROM opportunities
+		WHERE source_domain = $1 AND source_id = 'seed'`, domain).Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status != "closed" {
+		t.Fatalf("expected the source's closed status to be applied, got %s", status)
+	}
+
+	opp.Description = "<p>Applications are reviewed on a rolling basis. New: budget raised.</p>"
+	if _, err := p.UpsertOpportunity(crawlCtx, opp); err != nil {
+		t.Fatal(err)
+	}
+	if aiCalls.Load() == 0 {
+		t.Fatal("expected changed content to call the AI again")
+	}
+	var updated string
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(content_hash, '') FROM opportunities
+		WHERE source_domain = $1 AND source_id = 'seed'`, domain).Scan(&updated); err != nil {
+		t.Fatal(err)
+	}
+	if updated == hash || updated != contentHash(opp) {
+		t.Fatalf("expected the stored hash to follow the changed content, got %q", updated)
+	}
+}
@@ -3,6 +3,7 @@ package ingest
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -11,6 +12,17 @@ import (
 func parseDateRobust(text string, locales []string) (time.Time, error) {
 	// Clean the text first
 	text = cleanDateString(text)
+
+	// "5:00 PM EST" / "17:00 CET": pull the zoned time out, parse the rest as a
+	// date, then rebuild the instant in that zone.
+	if rest, clock, ok := extractZonedTime(text); ok {
+		day, err := parseDateRobust(rest, locales)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return clock.on(day), nil
+	}
+
 	text = strings.ReplaceAll(text, "a.m.", "AM")
 	text = strings.ReplaceAll(text, "p.m.", "PM")
 	text = strings.ReplaceAll(text, "a.m", "AM")
@@ -41,11 +53,10 @@ func parseDateRobust(text string, locales []string) (time.Time, error) {
 		"Jan 2, 2006",
 		"2 Jan 2006",
 		"02 Jan 2006",
-		"01/02/2006",
-		"01/02/2006 3 PM",
-		"02/01/2006", // UK format
 		"2006-01-02 15:04:05",
 	}
+	// Slash dates (03/04/2026) are left to parseDateWithRegex, which picks
+	// day- or month-first from the locales.
 
 	for _, format := range englishFormats {
 		if t, err := time.Parse(format, text); err == nil {
@@ -64,7 +75,6 @@ func parseDateRobust(text string, locales []string) (time.Time, error) {
 				"2 de enero de 2006",
 				"02 de enero de 2006",
 				"2 de ene de 2006",
-				"02/01/2006",
 				"2-01-2006",
 			}
 			for _, format := range spanishFormats {
@@ -80,7 +90,7 @@ func parseDateRobust(text string, locales []string) (time.Time, error) {
 	}
 
 	// Try regex-based parsing for common patterns
-	if t := parseDateWithRegex(text); !t.IsZero() {
+	if t := parseDateWithRegex(text, prefersDayFirst(locales)); !t.IsZero() {
 		return toEndOfDay(t), nil
 	}
 
@@ -92,6 +102,121 @@ func toEndOfDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 59, 999999999, time.UTC)
 }
 
+// tzTokenPattern matches the timezone tokens we see after deadline times,
+// as whole words. ASCII abbreviations need a trailing word boundary; the
+// Spanish phrases end in accented letters, which \b does not treat as word
+// characters.
+const tzTokenPattern = `\b(?:(?:est|edt|eastern(?:\s+time)?|pst|pdt|pacific(?:\s+time)?|gmt|utc|bst|cet|cest|pet)\b|hora\s+(?:de\s+lima|peruana|del\s+per[uú]|de\s+per[uú]))`
+
+// shortTZTokenPattern is ET and PT. They are also ordinary words ("17h et
+// 18h" in French), so they only count right after an am/pm marker.
+const shortTZTokenPattern = `\b(?:et|pt)\b`
+
+const meridiemPattern = `a\.?\s?m\.?|p\.?\s?m\.?`
+
+// zonedTimeTail follows the hour and optional minutes of a zoned time:
+// either am/pm and ET/PT, or an optional am/pm or "hrs" and a full zone
+// token. Groups: meridiem and zone of the first form, then of the second.
+const zonedTimeTail = `\s*(?:(` + meridiemPattern + `)\s*[,(]?\s*(` + shortTZTokenPattern + `)|(` + meridiemPattern + `)?\s*(?:h(?:rs?|oras)?\.?)?\s*[,(]?\s*(` + tzTokenPattern + `))\)?`
+
+// zonedTimeRegex matches a time of day followed by a timezone token, e.g.
+// "5 PM ET", "5:00 p.m. EST", "17:00 hrs CET", "17:00 horas, hora de Lima".
+var zonedTimeRegex = regexp.MustCompile(`(?i)(?:,?\s*(?:at|a\s+las)\s+)?\b(\d{1,2})(?::(\d{2}))?` + zonedTimeTail)
+
+// zonedClock is a wall-clock time in a known location.
+type zonedClock struct {
+	hour, minute int
+	loc          *time.Location
+}
+
+// on returns the UTC instant of c on the calendar day of d.
+func (c zonedClock) on(d time.Time) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), c.hour, c.minute, 0, 0, c.loc).UTC()
+}
+
+// extractZonedTime finds a time with an explicit timezone in text and returns
+// the text with it removed. A bare number before a zone ("15 CET") is not
+// treated as a time: it needs minutes or an am/pm marker.
+func extractZonedTime(text string) (string, zonedClock, bool) {
+	m := zonedTimeRegex.FindStringSubmatchIndex(text)
+	if m == nil {
+		return text, zonedClock{}, false
+	}
+	group := func(i int) string {
+		if m[2*i] < 0 {
+			return ""
+		}
+		return text[m[2*i]:m[2*i+1]]
+	}
+
+	minuteStr, meridiem := group(2), strings.ToLower(strings.NewReplacer(".", "", " ", "").Replace(group(3)+group(5)))
+	if minuteStr == "" && meridiem == "" {
+		return text, zonedClock{}, false
+	}
+
+	hour, _ := strconv.Atoi(group(1))
+	minute := 0
+	if minuteStr != "" {
+		minute, _ = strconv.Atoi(minuteStr)
+	}
+	if meridiem != "" && (hour < 1 || hour > 12) {
+		return text, zonedClock{}, false
+	}
+	switch meridiem {
+	case "pm":
+		if hour < 12 {
+			hour += 12
+		}
+	case "am":
+		if hour == 12 {
+			hour = 0
+		}
+	}
+	if hour > 23 || minute > 59 {
+		return text, zonedClock{}, false
+	}
+
+	loc := timezoneLocation(group(4) + group(6))
+	if loc == nil {
+		return text, zonedClock{}, false
+	}
+
+	rest := strings.TrimSpace(text[:m[0]] + " " + text[m[1]:])
+	return rest, zonedClock{hour: hour, minute: minute, loc: loc}, true
+}
+
+// timezoneLocation maps a timezone token to a location. Generic names (ET, PT)
+// follow DST; explicit standard/daylight abbreviations (EST, EDT) are fixed.
+func timezoneLocation(token string) *time.Location {
+	t := strings.Join(strings.Fields(strings.ToLower(token)), " ")
+	switch {
+	case t == "et" || strings.HasPrefix(t, "eastern"):
+		return loadLocationOr("America/New_York", -5)
+	case t == "pt" || strings.HasPrefix(t, "pacific"):
+		return loadLocationOr("America/Los_Angeles", -8)
+	case t == "pet" || strings.HasPrefix(t, "hora "):
+		return loadLocationOr("America/Lima", -5)
+	}
+	offsets := map[string]int{
+		"est": -5, "edt": -4, "pst": -8, "pdt": -7,
+		"gmt": 0, "utc": 0, "bst": 1, "cet": 1, "cest": 2,
+	}
+	if h, ok := offsets[t]; ok {
+		if h == 0 {
+			return time.UTC
+		}
+		return time.FixedZone(strings.ToUpper(t), h*3600)
+	}
+	return nil
+}
+
+func loadLocationOr(name string, fallbackHours int) *time.Location {
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	return time.FixedZone(name, fallbackHours*3600)
+}
+
 // parseSpanishDate handles Spanish date formats with month names
 func parseSpanishDate(text, format string) (time.Time, error) {
 	// Map Spanish months
@@ -138,8 +263,41 @@ func parseSpanishDate(text, format string) (time.Time, error) {
 	return time.Parse(englishFormat, textLower)
 }
 
+var slashDateRegex = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(20\d{2})\b`)
+
+// prefersDayFirst reports whether ambiguous slash dates should be read
+// day-first. The first locale with a known convention wins, so ["en", "es"]
+// stays month-first and ["es", "en"] is day-first.
+func prefersDayFirst(locales []string) bool {
+	for _, locale := range locales {
+		l := strings.ToLower(strings.TrimSpace(locale))
+		switch {
+		case l == "en-gb" || l == "en-uk" || l == "uk" || l == "gb":
+			return true
+		case strings.HasPrefix(l, "es") || strings.HasPrefix(l, "pt"):
+			return true
+		case strings.HasPrefix(l, "en"):
+			return false
+		}
+	}
+	return false
+}
+
+// validDate builds a date, rejecting values time.Date would normalize
+// (month 13, 31 April).
+func validDate(year, month, day int) (time.Time, bool) {
+	if month < 1 || month > 12 || day < 1 {
+		return time.Time{}, false
+	}
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 // parseDateWithRegex uses regex to extract dates from text
-func parseDateWithRegex(text string) time.Time {
+func parseDateWithRegex(text string, dayFirst bool) time.Time {
 	// ISO date: 2026-03-15
 	isoRegex := regexp.MustCompile(`\b(20\d{2})-(\d{2})-(\d{2})\b`)
 	if matches := isoRegex.FindStringSubmatch(text); len(matches) == 4 {
@@ -148,23 +306,17 @@ func parseDateWithRegex(text string) time.Time {
 		}
 	}
 
-	// US format: 03/15/2026 or 3/15/2026
-	usRegex := regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(20\d{2})\b`)
-	if matches := usRegex.FindStringSubmatch(text); len(matches) == 4 {
-		dateStr := fmt.Sprintf("%s/%s/%s", matches[1], matches[2], matches[3])
-		if t, err := time.Parse("1/2/2006", dateStr); err == nil {
-			return t
-		}
-		if t, err := time.Parse("01/02/2006", dateStr); err == nil {
-			return t
+	// Slash format: 03/15/2026 (US) or 15/03/2026 (UK/EU/LatAm). A part
+	// above 12 can only be the day; otherwise the locale decides.
+	if matches := slashDateRegex.FindStringSubmatch(text); len(matches) == 4 {
+		first, _ := strconv.Atoi(matches[1])
+		second, _ := strconv.Atoi(matches[2])
+		year, _ := strconv.Atoi(matches[3])
+		day, month := second, first
+		if first > 12 || (dayFirst && second <= 12) {
+			day, month = first, second
 		}
-	}
-
-	// UK/EU format: 15/03/2026
-	ukRegex := regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})/(20\d{2})\b`)
-	if matches := ukRegex.FindStringSubmatch(text); len(matches) == 4 {
-		dateStr := fmt.Sprintf("%s/%s/%s", matches[2], matches[1], matches[3]) // Swap day/month
-		if t, err := time.Parse("01/02/2006", dateStr); err == nil {
+		if t, ok := validDate(year, month, day); ok {
 			return t
 		}
 	}
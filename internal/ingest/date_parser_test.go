@@ -0,0 +1,182 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateRobust_SourceStrings(t *testing.T) {
+	en := []string{"en"}
+	es := []string{"es"}
+	tests := []struct {
+		name    string
+		in      string
+		locales []string
+		want    string // RFC3339Nano, UTC
+	}{
+		{"iso date is end of day", "2026-03-15", en, "2026-03-15T23:59:59.999999999Z"},
+		{"iso datetime kept", "2026-03-15T17:00:00Z", en, "2026-03-15T17:00:00Z"},
+		{"iso datetime with offset", "2026-03-15T17:00:00-05:00", en, "2026-03-15T22:00:00Z"},
+		{"english month day year", "January 2, 2006", en, "2006-01-02T23:59:59.999999999Z"},
+		{"english short month", "Jan 2, 2026", en, "2026-01-02T23:59:59.999999999Z"},
+		{"english day month year", "2 January 2026", en, "2026-01-02T23:59:59.999999999Z"},
+		{"spanish del", "2 de junio del 2025", es, "2025-06-02T23:59:59.999999999Z"},
+		{"spanish de", "2 de junio de 2025", es, "2025-06-02T23:59:59.999999999Z"},
+		{"pm with dots", "2 January 2026 3:04 p.m.", en, "2026-01-02T15:04:00Z"},
+		{"lowercase pm", "2 January 2026 3:04 pm", en, "2026-01-02T15:04:00Z"},
+		{"am with dots", "January 2, 2026 11:30 a.m.", en, "2026-01-02T11:30:00Z"},
+		{"fecha limite prefix", "Fecha límite: 2 de junio del 2025", es, "2025-06-02T23:59:59.999999999Z"},
+		{"deadline prefix", "Deadline: March 15, 2026", en, "2026-03-15T23:59:59.999999999Z"},
+		{"closing date prefix", "Closing date: 2026-03-15", en, "2026-03-15T23:59:59.999999999Z"},
+
+		// Slash dates: when both readings are valid the locale decides
+		// (en month-first, es/pt/en-GB day-first); a part above 12 can only
+		// be the day.
+		{"ambiguous slash en is month first", "03/04/2026", en, "2026-03-04T23:59:59.999999999Z"},
+		{"ambiguous slash es is day first", "03/04/2026", es, "2026-04-03T23:59:59.999999999Z"},
+		{"ambiguous slash en-GB is day first", "03/04/2026", []string{"en-GB"}, "2026-04-03T23:59:59.999999999Z"},
+		{"first listed locale wins", "03/04/2026", []string{"en", "es"}, "2026-03-04T23:59:59.999999999Z"},
+		{"us slash", "03/15/2026", en, "2026-03-15T23:59:59.999999999Z"},
+		{"us slash under es", "03/15/2026", es, "2026-03-15T23:59:59.999999999Z"},
+		{"uk slash under es", "15/03/2026", es, "2026-03-15T23:59:59.999999999Z"},
+		{"day over twelve is day first", "15/03/2026", en, "2026-03-15T23:59:59.999999999Z"},
+		{"13/01 always day first en", "13/01/2026", en, "2026-01-13T23:59:59.999999999Z"},
+		{"13/01 always day first es", "13/01/2026", es, "2026-01-13T23:59:59.999999999Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDateRobust(tt.in, tt.locales)
+			if err != nil {
+				t.Fatalf("parseDateRobust(%q): %v", tt.in, err)
+			}
+			if g := got.UTC().Format(time.RFC3339Nano); g != tt.want {
+				t.Fatalf("parseDateRobust(%q) = %s, want %s", tt.in, g, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDateRobust_Unparseable(t *testing.T) {
+	for _, in := range []string{"", "garbage", "Rolling basis", "31/04/2026", "13/13/2026"} {
+		if got, err := parseDateRobust(in, []string{"en", "es"}); err == nil {
+			t.Errorf("parseDateRobust(%q) = %s, want error", in, got)
+		}
+	}
+	// Spanish month names need the es locale.
+	if _, err := parseDateRobust("17 de junio del 2025", []string{"en"}); err == nil {
+		t.Error("expected Spanish date to need the es locale")
+	}
+}
+
+func TestCleanDateString(t *testing.T) {
+	tests := map[string]string{
+		"Fecha límite: 30 de abril de 2026": "30 de abril de 2026",
+		"Fecha de cierre:  15/03/2026 ":     "15/03/2026",
+		"Closing date: 2 June 2025":         "2 June 2025",
+		"DEADLINE: March 1, 2026":           "March 1, 2026",
+		"March 1, 2026":                     "March 1, 2026",
+	}
+	for in, want := range tests {
+		if got := cleanDateString(in); got != want {
+			t.Errorf("cleanDateString(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseDateRobust_ExplicitTimezones(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"March 15, 2026 5:00 PM EST", "2026-03-15T22:00:00Z"},
+		{"March 15, 2026 at 5 p.m. EDT", "2026-03-15T21:00:00Z"},
+		{"January 20, 2026 5 PM ET", "2026-01-20T22:00:00Z"}, // ET follows DST: EST in January
+		{"July 1, 2026 5 PM ET", "2026-07-01T21:00:00Z"},     // and EDT in July
+		{"15 June 2026 11:59 PM PT", "2026-06-16T06:59:00Z"},
+		{"30 April 2026 17:00 CET", "2026-04-30T16:00:00Z"},
+		{"30 April 2026 17:00 CEST", "2026-04-30T15:00:00Z"},
+		{"30 April 2026 12:00 GMT", "2026-04-30T12:00:00Z"},
+		{"30 April 2026 12:00 BST", "2026-04-30T11:00:00Z"},
+		{"17 de junio de 2026 a las 17:00 horas, hora de Lima", "2026-06-17T22:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDateRobust(tt.in, []string{"en", "es"})
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.in, err)
+			continue
+		}
+		if g := got.UTC().Format(time.RFC3339); g != tt.want {
+			t.Errorf("%q: got %s, want %s", tt.in, g, tt.want)
+		}
+	}
+}
+
+func TestExtractZonedTime_RequiresMinutesOrMeridiem(t *testing.T) {
+	if _, _, ok := extractZonedTime("15 CET"); ok {
+		t.Error("bare number before a zone should not be read as a time")
+	}
+	if _, _, ok := extractZonedTime("5 pm etc"); ok {
+		t.Error("\"etc\" should not be read as ET")
+	}
+}
+
+func TestExtractZonedTime_ShortZonesNeedMeridiem(t *testing.T) {
+	for _, text := range []string{
+		"le 15 mars 2026 de 9:00 et 17:00",
+		"entre 14:30 et 18h",
+		"sessão às 10:00 pt",
+		"17:00 hrs ET",
+	} {
+		if _, clock, ok := extractZonedTime(text); ok {
+			t.Errorf("%q: read as a zoned time %02d:%02d %s", text, clock.hour, clock.minute, clock.loc)
+		}
+	}
+
+	if _, clock, ok := extractZonedTime("due 5:30 p.m. (ET)"); !ok || clock.hour != 17 || clock.minute != 30 {
+		t.Errorf("expected 17:30 ET, got %+v (ok=%v)", clock, ok)
+	}
+	if _, clock, ok := extractZonedTime("closes 17:00 CET"); !ok || clock.hour != 17 {
+		t.Errorf("expected 17:00 CET, got %+v (ok=%v)", clock, ok)
+	}
+}
+
+func TestParseDeadlineEvidenceFromText_ZonedAndSourceFallback(t *testing.T) {
+	evidence := parseDeadlineEvidenceFromText("applications close march 15, 2026 5:00 pm est.", "html", "https://example.org/call", nil, 0.8, deadlineCutoff{})
+	if len(evidence) != 1 || evidence[0].ParsedDateISO != "2026-03-15T22:00:00Z" {
+		t.Fatalf("expected 5 PM EST as 22:00 UTC, got %+v", evidence)
+	}
+
+	// No zone on a Peruvian source: the time is Lima wall-clock.
+	evidence = parseDeadlineEvidenceFromText("cierre: 15 march 2026 5:00 pm", "html", "https://www.gob.pe/institucion/proinnovate/x", nil, 0.8, deadlineCutoff{})
+	if len(evidence) != 1 || evidence[0].ParsedDateISO != "2026-03-15T22:00:00Z" {
+		t.Fatalf("expected Lima wall-clock fallback, got %+v", evidence)
+	}
+}
+
+func TestParseDeadlineEvidenceFromText_SlashOrderFromConfiguredLocales(t *testing.T) {
+	const limaPage = "https://prociencia.gob.pe/convocatoria"
+	cases := []struct {
+		url     string
+		locales []string
+		want    string
+	}{
+		{limaPage, []string{"es"}, "2026-03-05"},
+		{limaPage, nil, "2026-05-03"}, // the host alone no longer implies day-first
+		{"https://example.org/call", []string{"es", "en"}, "2026-03-05"},
+		{"https://example.org/call", []string{"en"}, "2026-05-03"},
+	}
+	for _, tc := range cases {
+		evidence := parseDeadlineEvidenceFromText("cierre: 05/03/2026", "html", tc.url, tc.locales, 0.8, deadlineCutoff{})
+		if len(evidence) != 1 {
+			t.Errorf("%s %v: expected one date, got %+v", tc.url, tc.locales, evidence)
+			continue
+		}
+		// Date-only deadlines close at the end of the day in the source's zone.
+		parsed, err := time.Parse(time.RFC3339, evidence[0].ParsedDateISO)
+		if got := parsed.In(SourceLocation(tc.url)).Format("2006-01-02"); err != nil || got != tc.want {
+			t.Errorf("%s %v: expected %s, got %s (%v)", tc.url, tc.locales, tc.want, got, err)
+		}
+	}
+}
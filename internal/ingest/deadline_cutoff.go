@@ -0,0 +1,57 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// deadlineCutoff is the time of day a deadline given as a bare date closes
+// at. The zero value is the end of the day.
+type deadlineCutoff struct {
+	set          bool
+	hour, minute int
+}
+
+var deadlineCutoffLayouts = []string{"15:04", "3:04 PM", "3:04PM", "3 PM", "3PM"}
+
+// parseDeadlineCutoff reads a detail.parse.default_deadline_time value
+// ("17:00", "5:00 PM"). An empty value means the end of the day.
+func parseDeadlineCutoff(value string) (deadlineCutoff, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	if value == "" {
+		return deadlineCutoff{}, nil
+	}
+	for _, layout := range deadlineCutoffLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return deadlineCutoff{set: true, hour: t.Hour(), minute: t.Minute()}, nil
+		}
+	}
+	return deadlineCutoff{}, fmt.Errorf("unrecognized time %q (want HH:MM)", value)
+}
+
+// deadlineCutoff returns the configured cutoff. Invalid values, which
+// ValidateSource reports, fall back to the end of the day.
+func (c DetailParseConfig) deadlineCutoff() deadlineCutoff {
+	cutoff, _ := parseDeadlineCutoff(c.DefaultDeadlineTime)
+	return cutoff
+}
+
+// on returns day's date at the cutoff, as wall-clock time in loc.
+func (c deadlineCutoff) on(day time.Time, loc *time.Location) time.Time {
+	if !c.set {
+		return time.Date(day.Year(), day.Month(), day.Day(), 23, 59, 59, 0, loc)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), c.hour, c.minute, 0, 0, loc)
+}
+
+// deadlineCutoffFor returns the cutoff of the registry source serving url,
+// for rows whose deadline is parsed outside a crawl of that source.
+func (p *Pipeline) deadlineCutoffFor(url string) deadlineCutoff {
+	if registry, err := p.registry(); err == nil {
+		if src := registry.SourceForURL(url); src != nil {
+			return src.Detail.Parse.deadlineCutoff()
+		}
+	}
+	return deadlineCutoff{}
+}
@@ -0,0 +1,66 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDeadlineCutoff(t *testing.T) {
+	for _, value := range []string{"17:00", "5:00 pm", "5 PM"} {
+		cutoff, err := parseDeadlineCutoff(value)
+		if err != nil || !cutoff.set || cutoff.hour != 17 || cutoff.minute != 0 {
+			t.Errorf("%q: got %+v, %v", value, cutoff, err)
+		}
+	}
+	if cutoff, err := parseDeadlineCutoff(""); err != nil || cutoff.set {
+		t.Errorf("empty value should mean end of day, got %+v, %v", cutoff, err)
+	}
+	if _, err := parseDeadlineCutoff("5pm-ish"); err == nil {
+		t.Error("expected an error for an unreadable time")
+	}
+}
+
+func TestParseDeadlineEvidenceFromText_ConfiguredCutoff(t *testing.T) {
+	cutoff, _ := parseDeadlineCutoff("17:00")
+	const limaSource = "https://www.gob.pe/institucion/proinnovate/x"
+
+	// 17:00 America/Lima (UTC-5) is 22:00 UTC.
+	evidence := parseDeadlineEvidenceFromText("cierre: 30 de marzo de 2026", "html", limaSource, nil, 0.8, cutoff)
+	if len(evidence) != 1 || evidence[0].ParsedDateISO != "2026-03-30T22:00:00Z" {
+		t.Fatalf("expected the Lima cutoff, got %+v", evidence)
+	}
+
+	evidence = parseDeadlineEvidenceFromText("cierre: 30 de marzo de 2026", "html", limaSource, nil, 0.8, deadlineCutoff{})
+	if len(evidence) != 1 || evidence[0].ParsedDateISO != "2026-03-31T04:59:59Z" {
+		t.Fatalf("expected end of day in Lima without a cutoff, got %+v", evidence)
+	}
+
+	// An explicit time on the page wins over the cutoff.
+	evidence = parseDeadlineEvidenceFromText("cierre: 30 march 2026 11:00 am", "html", limaSource, nil, 0.8, cutoff)
+	if len(evidence) != 1 || evidence[0].ParsedDateISO != "2026-03-30T16:00:00Z" {
+		t.Fatalf("expected the page's own time, got %+v", evidence)
+	}
+}
+
+func TestFromRaw_DefaultDeadlineTime(t *testing.T) {
+	opp := FromRaw(RawOpportunity{
+		Title:       "Fondo concursable",
+		ExternalURL: "https://www.gob.pe/institucion/prociencia/convocatorias/x",
+		RawDeadline: "2026-04-15",
+		Extra:       map[string]string{"default_deadline_time": "17:00"},
+	})
+	want := time.Date(2026, 4, 15, 22, 0, 0, 0, time.UTC)
+	if opp.DeadlineAt == nil || !opp.DeadlineAt.Equal(want) {
+		t.Fatalf("expected %s, got %v", want, opp.DeadlineAt)
+	}
+
+	opp = FromRaw(RawOpportunity{
+		Title:       "Open call",
+		ExternalURL: "https://example.org/call",
+		RawDeadline: "2026-04-15",
+	})
+	want = time.Date(2026, 4, 15, 23, 59, 59, 999999999, time.UTC)
+	if opp.DeadlineAt == nil || !opp.DeadlineAt.Equal(want) {
+		t.Fatalf("expected end of day UTC without a cutoff, got %v", opp.DeadlineAt)
+	}
+}
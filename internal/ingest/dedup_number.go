@@ -0,0 +1,159 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// mergedDuplicateReason is the status_reason of a row archived because its
+// record now updates the canonical row for the same opportunity_number.
+// Recompute leaves these rows alone.
+const mergedDuplicateReason = "merged_duplicate"
+
+// execer is the write surface shared by *pgxpool.Pool and pgx.Tx.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// numberDedupEnabled reports whether ctx belongs to a run of a source with
+// dedup_by_number set.
+func numberDedupEnabled(ctx context.Context) bool {
+	on, _ := ctx.Value(numberDedupKey).(bool)
+	return on
+}
+
+// opportunityNumberKey is the case-insensitive form numbers are matched on;
+// "" means the record has none and is never merged.
+func opportunityNumberKey(opp Opportunity) string {
+	return strings.ToLower(strings.TrimSpace(opp.OpportunityNumber))
+}
+
+// mergeIntoNumberCanonical points opp at the canonical row for its
+// opportunity_number within its source, the oldest live row holding it, so
+// the upsert refreshes that row instead of adding a near-duplicate. A record
+// posted before the one the canonical row holds is older news: it is skipped,
+// and only its own row, if any, is archived. Rows the record replaces are
+// archived by archiveNumberSiblings in the upsert's transaction.
+func (p *Pipeline) mergeIntoNumberCanonical(ctx context.Context, opp *Opportunity) error {
+	key := opportunityNumberKey(*opp)
+	if !numberDedupEnabled(ctx) || key == "" {
+		return nil
+	}
+
+	var canonical string
+	var canonicalPosted *time.Time
+	err := p.DB.QueryRow(ctx, `
+		SELECT source_id, COALESCE(open_at, open_date) FROM opportunities
+		WHERE source_domain = $1
+		  AND LOWER(opportunity_number) = $2
+		  AND COALESCE(status_reason, '') <> $3
+		ORDER BY created_at, id
+		LIMIT 1`, opp.SourceDomain, key, mergedDuplicateReason).Scan(&canonical, &canonicalPosted)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("opportunity number lookup: %w", err)
+	}
+	if canonical == opp.SourceID {
+		return nil
+	}
+
+	if posted := postedAt(*opp); posted != nil && canonicalPosted != nil && posted.Before(*canonicalPosted) {
+		if err := archiveNumberSiblings(ctx, p.DB, []Opportunity{{SourceDomain: opp.SourceDomain, SourceID: canonical, OpportunityNumber: opp.OpportunityNumber}}); err != nil {
+			return err
+		}
+		return &SkipError{
+			Reason: SkipSupersededNumber,
+			Err:    fmt.Errorf("%s (%s) was posted before the record in %s", opp.SourceID, opp.OpportunityNumber, canonical),
+		}
+	}
+
+	log.Printf("[dedup] %s: %s (%s) merged into %s", opp.SourceDomain, opp.SourceID, opp.OpportunityNumber, canonical)
+	if opp.SourceEvidenceJSON == nil {
+		opp.SourceEvidenceJSON = map[string]interface{}{}
+	}
+	opp.SourceEvidenceJSON["merged_source_id"] = opp.SourceID
+	opp.SourceID = canonical
+	return nil
+}
+
+// archiveNumberSiblings archives, as merged_duplicate, every other row of
+// each opp's source holding the same opportunity_number. The upsert paths
+// run it in the write's transaction so the canonical row and its duplicates
+// never disagree on which one is live.
+func archiveNumberSiblings(ctx context.Context, q execer, opps []Opportunity) error {
+	var domains, numbers, sourceIDs []string
+	for _, opp := range opps {
+		if key := opportunityNumberKey(opp); key != "" {
+			domains = append(domains, opp.SourceDomain)
+			numbers = append(numbers, key)
+			sourceIDs = append(sourceIDs, opp.SourceID)
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+	if _, err := q.Exec(ctx, `
+		UPDATE opportunities o
+		SET normalized_status = 'archived'::normalized_status_enum,
+		    status_reason = $4
+		FROM unnest($1::text[], $2::text[], $3::text[]) AS k(source_domain, number, source_id)
+		WHERE o.source_domain = k.source_domain
+		  AND LOWER(o.opportunity_number) = k.number
+		  AND o.source_id <> k.source_id
+		  AND o.status_reason IS DISTINCT FROM $4`,
+		domains, numbers, sourceIDs, mergedDuplicateReason); err != nil {
+		return fmt.Errorf("archive merged duplicates: %w", err)
+	}
+	return nil
+}
+
+// postedAt is when the source says opp was posted, nil if unknown.
+func postedAt(opp Opportunity) *time.Time {
+	if opp.OpenAt != nil {
+		return opp.OpenAt
+	}
+	return opp.OpenDate
+}
+
+// collapseBatchNumbers keeps one record per opportunity_number in a bulk
+// batch, so records new to the database merge with each other too: the one
+// posted last, or the later one in the batch when posted dates don't settle
+// it.
+func collapseBatchNumbers(opps []Opportunity) []Opportunity {
+	winner := make(map[string]int, len(opps))
+	for i := range opps {
+		key := opportunityNumberKey(opps[i])
+		if key == "" {
+			continue
+		}
+		domainKey := sourceKey(opps[i].SourceDomain, key)
+		if best, ok := winner[domainKey]; !ok || !postedBefore(opps[i], opps[best]) {
+			winner[domainKey] = i
+		}
+	}
+
+	kept := opps[:0:0]
+	for i := range opps {
+		key := opportunityNumberKey(opps[i])
+		if key != "" && winner[sourceKey(opps[i].SourceDomain, key)] != i {
+			continue
+		}
+		kept = append(kept, opps[i])
+	}
+	return kept
+}
+
+// postedBefore reports whether a was posted strictly before b.
+func postedBefore(a, b Opportunity) bool {
+	pa, pb := postedAt(a), postedAt(b)
+	return pa != nil && pb != nil && pa.Before(*pb)
+}
@@ -0,0 +1,145 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestCollapseBatchNumbers_LatestPostedWins(t *testing.T) {
+	march := time.Date(2027, 3, 1, 0, 0, 0, 0, time.UTC)
+	april := time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC)
+	batch := []Opportunity{
+		{SourceDomain: "grants.example.org", SourceID: "amendment-1", OpportunityNumber: " rfa-27-001 ", Title: "Amendment", OpenAt: &april},
+		{SourceDomain: "grants.example.org", SourceID: "other", OpportunityNumber: "RFA-27-002", Title: "Other"},
+		{SourceDomain: "grants.example.org", SourceID: "synopsis-1", OpportunityNumber: "RFA-27-001", Title: "Synopsis", OpenAt: &march},
+		{SourceDomain: "grants.example.org", SourceID: "no-number", Title: "No number"},
+	}
+
+	// The amendment wins on its posted date whichever order the crawl saw.
+	for _, order := range [][]int{{0, 1, 2, 3}, {3, 2, 1, 0}} {
+		opps := make([]Opportunity, 0, len(batch))
+		for _, i := range order {
+			opps = append(opps, batch[i])
+		}
+		kept := collapseBatchNumbers(opps)
+		if len(kept) != 3 {
+			t.Fatalf("expected 3 rows after collapsing, got %d", len(kept))
+		}
+		for _, opp := range kept {
+			if opp.SourceID == "synopsis-1" {
+				t.Fatalf("order %v: expected the earlier-posted synopsis dropped", order)
+			}
+		}
+	}
+
+	// Without posted dates the later record in the batch wins.
+	undated := []Opportunity{
+		{SourceDomain: "grants.example.org", SourceID: "a", OpportunityNumber: "RFA-27-003"},
+		{SourceDomain: "grants.example.org", SourceID: "b", OpportunityNumber: "RFA-27-003"},
+	}
+	if kept := collapseBatchNumbers(undated); len(kept) != 1 || kept[0].SourceID != "b" {
+		t.Fatalf("expected the later undated record kept, got %+v", kept)
+	}
+}
+
+// TestSaveOpportunity_DedupByNumber runs against a migrated database; set
+// TEST_DATABASE_URL to enable it. Two records sharing an opportunity number
+// under different URLs end up as one row carrying the later-posted record.
+func TestSaveOpportunity_DedupByNumber(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "dedup-number-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	p := &Pipeline{DB: pool, Store: db.NewStore(pool)}
+	deadline := time.Now().Add(60 * 24 * time.Hour)
+	record := func(sourceID, title string, postedDaysAgo int) Opportunity {
+		posted := time.Now().UTC().AddDate(0, 0, -postedDaysAgo).Truncate(time.Second)
+		return Opportunity{
+			Title:             title,
+			ExternalURL:       "https://" + domain + "/" + sourceID,
+			SourceDomain:      domain,
+			SourceID:          sourceID,
+			OpportunityNumber: "DEDUP-27-001",
+			DeadlineAt:        &deadline,
+			OpenAt:            &posted,
+		}
+	}
+
+	// Without the flag, the amendment URL is a row of its own.
+	if err := p.SaveOpportunity(ctx, record("synopsis", "Soil grant", 30)); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SaveOpportunity(ctx, record("amendment", "Soil grant (amended)", 20)); err != nil {
+		t.Fatal(err)
+	}
+
+	dedupCtx := context.WithValue(ctx, numberDedupKey, true)
+	if err := p.SaveOpportunity(dedupCtx, record("amendment", "Soil grant (amendment 2)", 10)); err != nil {
+		t.Fatal(err)
+	}
+	// A stale copy of the synopsis crawled afterwards must not overwrite it.
+	err = p.SaveOpportunity(dedupCtx, record("synopsis-mirror", "Soil grant", 30))
+	var skip *SkipError
+	if !errors.As(err, &skip) || skip.Reason != SkipSupersededNumber {
+		t.Fatalf("expected the earlier-posted record skipped, got %v", err)
+	}
+
+	var live int
+	var title, url string
+	if err := pool.QueryRow(ctx, `
+		SELECT COUNT(*) OVER (), title, external_url FROM opportunities
+		WHERE source_domain = $1 AND COALESCE(status_reason, '') <> $2`,
+		domain, mergedDuplicateReason).Scan(&live, &title, &url); err != nil {
+		t.Fatal(err)
+	}
+	if live != 1 || title != "Soil grant (amendment 2)" || url != "https://"+domain+"/amendment" {
+		t.Fatalf("expected one live row with the latest record, got %d %q %q", live, title, url)
+	}
+
+	var archived string
+	if err := pool.QueryRow(ctx, `
+		SELECT normalized_status::text FROM opportunities
+		WHERE source_domain = $1 AND source_id = 'amendment'`, domain).Scan(&archived); err != nil {
+		t.Fatal(err)
+	}
+	if archived != "archived" {
+		t.Fatalf("expected the amendment's own row archived, got %s", archived)
+	}
+
+	// Already merged rows are left alone on later runs.
+	var before, after time.Time
+	if err := pool.QueryRow(ctx, `SELECT updated_at FROM opportunities WHERE source_domain = $1 AND source_id = 'amendment'`, domain).Scan(&before); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SaveOpportunity(dedupCtx, record("amendment", "Soil grant (amendment 2)", 10)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `SELECT updated_at FROM opportunities WHERE source_domain = $1 AND source_id = 'amendment'`, domain).Scan(&after); err != nil {
+		t.Fatal(err)
+	}
+	if !after.Equal(before) {
+		t.Fatalf("expected the merged row untouched, updated_at moved from %v to %v", before, after)
+	}
+}
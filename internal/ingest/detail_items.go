@@ -0,0 +1,132 @@
+package ingest
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// defaultMultiItemTitle picks a block's title when MultiItemTitle is unset.
+const defaultMultiItemTitle = "h1 | h2 | h3 | h4 | h5 | h6"
+
+// detailItems returns the opportunities to save for an enriched list item:
+// one per sub-call when the detail page splits, otherwise the item itself.
+func (s *HtmlGenericStrategy) detailItems(sourceID string, base, enriched RawOpportunity, config DetailConfig) []RawOpportunity {
+	items := s.splitDetailItems(base, enriched.DetailPage, config)
+	if items == nil {
+		return []RawOpportunity{enriched}
+	}
+	log.Printf("[%s] Detail page %s split into %d opportunities", sourceID, enriched.ExternalURL, len(items))
+	return items
+}
+
+// cloneRawOpportunity copies raw with its own Extra map, so enriching the
+// copy leaves the original untouched.
+func cloneRawOpportunity(raw RawOpportunity) RawOpportunity {
+	out := raw
+	out.Extra = make(map[string]string, len(raw.Extra))
+	for k, v := range raw.Extra {
+		out.Extra[k] = v
+	}
+	return out
+}
+
+// splitDetailItems returns one RawOpportunity per MultiItemSelector block of
+// an already fetched detail page, or nil when the page holds a single call.
+// Each block is extracted on its own from base (the item as read from the
+// list page), so one sub-call's deadline or budget never leaks into another.
+func (s *HtmlGenericStrategy) splitDetailItems(base RawOpportunity, page *SourceAdapterRaw, config DetailConfig) []RawOpportunity {
+	if strings.TrimSpace(config.MultiItemSelector) == "" || page == nil {
+		return nil
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page.BodyHTML))
+	if err != nil {
+		return nil
+	}
+	_, blocks := firstMatchingSelector(doc.Selection, config.MultiItemSelector)
+	if blocks.Length() < 2 {
+		return nil
+	}
+
+	// Blocks are extracted as standalone documents, where the page-level
+	// container no longer exists.
+	blockConfig := config
+	blockConfig.Selectors.Container = ""
+	titleSelector := config.MultiItemTitle
+	if titleSelector == "" {
+		titleSelector = defaultMultiItemTitle
+	}
+
+	items := make([]RawOpportunity, 0, blocks.Length())
+	suffixes := make(map[string]bool, blocks.Length())
+	blocks.Each(func(i int, block *goquery.Selection) {
+		blockHTML, err := goquery.OuterHtml(block)
+		if err != nil {
+			return
+		}
+		blockDoc, err := goquery.NewDocumentFromReader(strings.NewReader(blockHTML))
+		if err != nil {
+			return
+		}
+
+		item := cloneRawOpportunity(base)
+		item.Description = ""
+		heading := firstHeading(block, titleSelector)
+		if heading != "" {
+			item.Title = heading
+		}
+		suffix := multiItemSuffix(heading, i)
+		if suffixes[suffix] {
+			suffix = fmt.Sprintf("%d", i+1)
+		}
+		suffixes[suffix] = true
+		item.SourceID = base.SourceID + "#" + suffix
+
+		s.extractDetailContent(&item, blockConfig, blockDoc)
+		item.DetailPage = blockPage(page, blockHTML, config.Attachments)
+		items = append(items, item)
+	})
+	if len(items) < 2 {
+		return nil
+	}
+	return items
+}
+
+// firstHeading returns the text of the first element matching the first
+// alternative of selector that yields any.
+func firstHeading(block *goquery.Selection, selector string) string {
+	for _, alt := range selectorAlternatives(selector) {
+		if text := cleanText(block.Find(alt).First().Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// multiItemSuffix keys a block by its heading so its source_id survives
+// sub-calls being added or reordered; untitled blocks fall back to position.
+func multiItemSuffix(heading string, index int) string {
+	key := strings.ToLower(strings.Join(strings.Fields(heading), " "))
+	if key == "" {
+		return fmt.Sprintf("%d", index+1)
+	}
+	hash := sha1.Sum([]byte(key))
+	return hex.EncodeToString(hash[:4])
+}
+
+// blockPage narrows a fetched detail page to one block, so evidence
+// enrichment reads only that sub-call's dates and attachments.
+func blockPage(page *SourceAdapterRaw, blockHTML string, attachments AttachmentConfig) *SourceAdapterRaw {
+	out := *page
+	out.BodyHTML = blockHTML
+	out.AttachmentURLs = collectAttachmentPDFLinks(page.URL, blockHTML, attachments)
+	out.FetchMeta = make(map[string]interface{}, len(page.FetchMeta))
+	for k, v := range page.FetchMeta {
+		out.FetchMeta[k] = v
+	}
+	return &out
+}
@@ -0,0 +1,37 @@
+package ingest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/david/grant-finder/internal/ai"
+)
+
+func TestDropMismatchedEmbedding(t *testing.T) {
+	p := &Pipeline{AI: &ai.OllamaClient{EmbedModel: "mxbai-embed-large", EmbeddingDim: 768}}
+
+	opp := Opportunity{Title: "Wrong model", Embedding: make([]float32, 1024)}
+	p.dropMismatchedEmbedding(&opp)
+	if opp.Embedding != nil {
+		t.Errorf("expected 1024-dim embedding to be dropped")
+	}
+
+	opp = Opportunity{Title: "Right model", Embedding: make([]float32, 768)}
+	p.dropMismatchedEmbedding(&opp)
+	if len(opp.Embedding) != 768 {
+		t.Errorf("expected 768-dim embedding to be kept, got %d", len(opp.Embedding))
+	}
+}
+
+func TestCheckEmbedding_ReportsExpectedAndActual(t *testing.T) {
+	client := &ai.OllamaClient{EmbedModel: "mxbai-embed-large", EmbeddingDim: 768}
+
+	err := client.CheckEmbedding(make([]float32, 1024))
+	var dimErr *ai.EmbeddingDimensionError
+	if !errors.As(err, &dimErr) {
+		t.Fatalf("expected EmbeddingDimensionError, got %v", err)
+	}
+	if dimErr.Expected != 768 || dimErr.Actual != 1024 {
+		t.Errorf("unexpected dimensions: %+v", dimErr)
+	}
+}
@@ -0,0 +1,123 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func TestEnrichCandidateWhere_DomainFilter(t *testing.T) {
+	all := enrichCandidateWhere("", true)
+	if !strings.Contains(all, "$1 = ''") || strings.Contains(all, "source_domain = $1") {
+		t.Errorf("an all-domains run should not filter on source_domain:\n%s", all)
+	}
+	one := enrichCandidateWhere("grants.gov", true)
+	if !strings.Contains(one, "source_domain = $1") {
+		t.Errorf("a per-domain run should lead with source_domain = $1 to use the domain index:\n%s", one)
+	}
+
+	if !strings.Contains(one, "next_deadline_at IS NULL") {
+		t.Error("only_missing_deadlines should restrict open rows to those without a deadline")
+	}
+	if strings.Contains(enrichCandidateWhere("grants.gov", false), "next_deadline_at IS NULL") {
+		t.Error("without only_missing_deadlines every open row is a candidate")
+	}
+}
+
+func TestEnrichCandidateQuery_EachBranchStopsAtTheBatch(t *testing.T) {
+	where := enrichCandidateWhere("grants.gov", true)
+	query := enrichCandidateQuery(where)
+
+	if got := strings.Count(query, where); got != 2 {
+		t.Errorf("the filter appears %d times, want once per branch", got)
+	}
+	// Each branch is ordered by updated_at alone so it can walk an index
+	// and stop at the LIMIT; the outer query only sorts 2*$4 ids.
+	if got := strings.Count(query, "ORDER BY updated_at ASC"); got != 2 {
+		t.Errorf("ORDER BY updated_at appears %d times, want 2", got)
+	}
+	if got := strings.Count(query, "LIMIT $4"); got != 3 {
+		t.Errorf("LIMIT $4 appears %d times, want 3", got)
+	}
+	if !strings.Contains(query, "ORDER BY candidates.priority, candidates.candidate_updated_at ASC") {
+		t.Errorf("unparsed rows should come first:\n%s", query)
+	}
+}
+
+// TestEnrichCandidateQuery_UnparsedFirstThenOldest runs against a migrated
+// database; set TEST_DATABASE_URL to enable it.
+func TestEnrichCandidateQuery_UnparsedFirstThenOldest(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "enrich-candidates-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	base := time.Now().UTC().Add(-48 * time.Hour)
+	seed := []struct {
+		id     string
+		reason string
+		age    time.Duration
+	}{
+		{"old", "missing_deadline", 0},
+		{"unparsed-new", "open_source_no_date_parsed", 3 * time.Hour},
+		{"newer", "missing_deadline", 2 * time.Hour},
+		{"unparsed-old", "open_source_no_date_parsed", time.Hour},
+		{"newest", "missing_deadline", 4 * time.Hour},
+	}
+	for _, s := range seed {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, status_reason, updated_at)
+			VALUES ($1, $2, $3, $1, $4, $5)`, s.id, "https://"+domain+"/"+s.id, domain, s.reason, base.Add(s.age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	query := enrichCandidateQuery(enrichCandidateWhere(domain, true))
+	for _, tc := range []struct {
+		limit int
+		want  []string
+	}{
+		{4, []string{"unparsed-old", "unparsed-new", "old", "newer"}},
+		{1, []string{"unparsed-old"}},
+	} {
+		rows, err := pool.Query(ctx, query, domain, 0.6, domainTTLIntervalLiteral(domain), tc.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for rows.Next() {
+			values, err := rows.Values()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, values[6].(string))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("limit %d: candidates = %v, want %v", tc.limit, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package ingest
+
+import "time"
+
+// Event types emitted by SaveOpportunity.
+const (
+	EventOpportunityCreated       = "opportunity.created"
+	EventOpportunityStatusChanged = "opportunity.status_changed"
+)
+
+// OpportunityEvent describes an insert or normalized_status change.
+type OpportunityEvent struct {
+	Type           string     `json:"type"`
+	OpportunityID  string     `json:"opportunity_id"`
+	Title          string     `json:"title"`
+	ExternalURL    string     `json:"external_url"`
+	SourceDomain   string     `json:"source_domain"`
+	SourceID       string     `json:"source_id"`
+	Status         string     `json:"status"`
+	PreviousStatus string     `json:"previous_status,omitempty"`
+	NextDeadlineAt *time.Time `json:"next_deadline_at,omitempty"`
+	OccurredAt     time.Time  `json:"occurred_at"`
+}
+
+// OpportunityNotifier receives events from SaveOpportunity. Notify must not
+// block; implementations queue the event and deliver it elsewhere.
+type OpportunityNotifier interface {
+	Notify(event OpportunityEvent)
+}
+
+// opportunityEventFor builds the event for a saved row, if any. Updates that
+// leave normalized_status unchanged emit nothing.
+func opportunityEventFor(opp Opportunity, id string, inserted bool, previousStatus string, now time.Time) (OpportunityEvent, bool) {
+	event := OpportunityEvent{
+		OpportunityID:  id,
+		Title:          opp.Title,
+		ExternalURL:    opp.ExternalURL,
+		SourceDomain:   opp.SourceDomain,
+		SourceID:       opp.SourceID,
+		Status:         opp.NormalizedStatus,
+		NextDeadlineAt: opp.NextDeadlineAt,
+		OccurredAt:     now,
+	}
+	switch {
+	case inserted:
+		event.Type = EventOpportunityCreated
+	case previousStatus != opp.NormalizedStatus:
+		event.Type = EventOpportunityStatusChanged
+		event.PreviousStatus = previousStatus
+	default:
+		return OpportunityEvent{}, false
+	}
+	return event, true
+}
@@ -0,0 +1,24 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOpportunityEventFor(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	opp := Opportunity{Title: "Fondo", SourceDomain: "example.org", SourceID: "abc", NormalizedStatus: "open"}
+
+	if ev, ok := opportunityEventFor(opp, "id-1", true, "", now); !ok || ev.Type != EventOpportunityCreated || ev.OpportunityID != "id-1" {
+		t.Fatalf("expected created event, got %+v (ok=%v)", ev, ok)
+	}
+
+	ev, ok := opportunityEventFor(opp, "id-1", false, "upcoming", now)
+	if !ok || ev.Type != EventOpportunityStatusChanged || ev.PreviousStatus != "upcoming" || ev.Status != "open" {
+		t.Fatalf("expected status_changed upcoming->open, got %+v (ok=%v)", ev, ok)
+	}
+
+	if _, ok := opportunityEventFor(opp, "id-1", false, "open", now); ok {
+		t.Fatal("expected no event when status is unchanged")
+	}
+}
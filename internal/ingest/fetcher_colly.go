@@ -89,6 +89,7 @@ func (f *CollyFetcher) buildCollector(allowedDomains []string) *colly.Collector
 	})
 
 	c.SetRequestTimeout(f.RequestTimeout)
+	c.WithTransport(newSafeTransport())
 
 	// Retry on errors
 	c.OnError(func(r *colly.Response, err error) {
@@ -108,7 +109,7 @@ func (f *CollyFetcher) buildCollector(allowedDomains []string) *colly.Collector
 }
 
 // Fetch implements the Fetcher interface, returning a FetchedDocument.
-func (f *CollyFetcher) Fetch(ctx context.Context, targetURL string) (*FetchedDocument, error) {
+func (f *CollyFetcher) Fetch(ctx context.Context, targetURL string, opts ...FetchOption) (*FetchedDocument, error) {
 	parsedURL, err := url.Parse(targetURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
@@ -146,6 +147,14 @@ func (f *CollyFetcher) Fetch(ctx context.Context, targetURL string) (*FetchedDoc
 		}
 	})
 
+	if len(opts) > 0 {
+		c.OnRequest(func(r *colly.Request) {
+			for _, opt := range opts {
+				opt(*r.Headers)
+			}
+		})
+	}
+
 	// Handle context cancellation
 	done := make(chan struct{})
 	go func() {
@@ -250,6 +259,7 @@ func NewCollyScraper(config CollyScraperConfig) *CollyScraper {
 	})
 
 	c.SetRequestTimeout(config.RequestTimeout)
+	c.WithTransport(newSafeTransport())
 
 	// Custom headers
 	if len(config.Headers) > 0 {
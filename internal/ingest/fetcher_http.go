@@ -1,18 +1,27 @@
 package ingest
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"math/rand"
 	"net"
 	"net/netip"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
+
+	"golang.org/x/net/publicsuffix"
+	"golang.org/x/time/rate"
 )
 
+// defaultMaxRedirects is the redirect limit when FetchConfig.MaxRedirects is unset.
+const defaultMaxRedirects = 10
+
 var blockedPrefixStrings = []string{
 	"127.0.0.0/8",
 	"10.0.0.0/8",
@@ -38,27 +47,45 @@ type HTTPFetcher struct {
 	Client *http.Client
 }
 
-func NewHTTPFetcher() *HTTPFetcher {
-	transport := &http.Transport{
+// newSafeTransport returns a transport whose dialer refuses private and
+// loopback addresses, except for hosts listed in allowHosts.
+func newSafeTransport(allowHosts ...string) *http.Transport {
+	return &http.Transport{
 		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           safeDialContext,
+		DialContext:           safeDialContextAllowing(allowHosts),
 		ForceAttemptHTTP2:     true,
 		MaxIdleConns:          100,
 		IdleConnTimeout:       90 * time.Second,
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
+}
+
+// newSafeHTTPClient is the constructor every outbound client in this package
+// goes through: private targets are refused both when dialing and when
+// following redirects. allowHosts exempts specific hosts, such as the local
+// Ollama daemon.
+func newSafeHTTPClient(timeout time.Duration, allowHosts ...string) *http.Client {
+	return &http.Client{
+		Timeout:       timeout,
+		Transport:     newSafeTransport(allowHosts...),
+		CheckRedirect: safeCheckRedirectAllowing(allowHosts),
+	}
+}
+
+// NewSafeHTTPClient exposes newSafeHTTPClient (without an allowlist) for
+// outbound calls made outside this package, such as webhook deliveries.
+func NewSafeHTTPClient(timeout time.Duration) *http.Client {
+	return newSafeHTTPClient(timeout)
+}
 
+func NewHTTPFetcher() *HTTPFetcher {
 	return &HTTPFetcher{
-		Client: &http.Client{
-			Timeout:       30 * time.Second,
-			Transport:     transport,
-			CheckRedirect: safeCheckRedirect,
-		},
+		Client: newSafeHTTPClient(30 * time.Second),
 	}
 }
 
-func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (*FetchedDocument, error) {
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string, opts ...FetchOption) (*FetchedDocument, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -69,6 +96,9 @@ func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (*FetchedDocument,
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Upgrade-Insecure-Requests", "1")
+	for _, opt := range opts {
+		opt(req.Header)
+	}
 
 	resp, err := f.Client.Do(req)
 	if err != nil {
@@ -93,10 +123,19 @@ func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (*FetchedDocument,
 // RateLimitedFetcher provides rate limiting, retries, and configurable timeouts per domain
 type RateLimitedFetcher struct {
 	clients       map[string]*http.Client // per domain
-	limiters      map[string]*time.Ticker // per domain (simple ticker-based rate limiting)
-	configs       map[string]FetchConfig  // per domain config
+	limiters      map[string]*rate.Limiter // per domain; retuned in place, never replaced
+	configs       map[string]FetchConfig  // per domain config, set via SetDomainConfig
 	defaultConfig FetchConfig
 	mu            sync.RWMutex
+
+	// AllowHosts exempts hosts from the private-address guard, such as an
+	// httptest server on loopback. Set it before the first Fetch; clients
+	// the pipeline builds outside the fetcher inherit it.
+	AllowHosts []string
+
+	// RetryBackoff, when set, replaces the exponential backoff before retry
+	// attempt n (1-based).
+	RetryBackoff func(attempt int) time.Duration
 }
 
 // NewRateLimitedFetcher creates a new rate-limited fetcher with default config
@@ -113,15 +152,117 @@ func NewRateLimitedFetcher(defaultConfig FetchConfig) *RateLimitedFetcher {
 	if defaultConfig.AcceptLanguage == "" {
 		defaultConfig.AcceptLanguage = "en-US,en;q=0.5"
 	}
+	if defaultConfig.BlockedMaxBytes == 0 {
+		defaultConfig.BlockedMaxBytes = DefaultBlockedMaxBytes
+	}
+	if defaultConfig.MaxRedirects == 0 {
+		defaultConfig.MaxRedirects = defaultMaxRedirects
+	}
 
 	return &RateLimitedFetcher{
 		clients:       make(map[string]*http.Client),
-		limiters:      make(map[string]*time.Ticker),
+		limiters:      make(map[string]*rate.Limiter),
 		configs:       make(map[string]FetchConfig),
 		defaultConfig: defaultConfig,
 	}
 }
 
+// SetDomainConfig registers config for rawURL's host, with zero fields taken
+// from the fetcher default. A client already built for the host is replaced
+// only when a setting baked into it (timeout, proxy, redirects) changed; the
+// rate limit is retuned in place, so a Fetch waiting on it is not stranded.
+func (f *RateLimitedFetcher) SetDomainConfig(rawURL string, config FetchConfig) {
+	domain, err := getDomain(rawURL)
+	if err != nil || domain == "" {
+		return
+	}
+	config = f.withDefaults(config)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current, exists := f.configs[domain]
+	if exists && reflect.DeepEqual(current, config) {
+		return
+	}
+	f.configs[domain] = config
+	if !exists || clientSettingsChanged(current, config) {
+		delete(f.clients, domain)
+	}
+	if limiter, ok := f.limiters[domain]; ok {
+		limiter.SetLimit(rateLimit(config.RateLimitRPS))
+	}
+}
+
+// clientSettingsChanged reports whether the settings an *http.Client is
+// built with differ between a and b. Headers are applied per request.
+func clientSettingsChanged(a, b FetchConfig) bool {
+	return a.TimeoutSeconds != b.TimeoutSeconds || a.ProxyURL != b.ProxyURL ||
+		a.MaxRedirects != b.MaxRedirects || a.RestrictRedirectsToOrigin != b.RestrictRedirectsToOrigin
+}
+
+// rateLimit converts requests per second to a limiter rate, one per second
+// when unset.
+func rateLimit(rps float64) rate.Limit {
+	if rps <= 0 {
+		return rate.Limit(1)
+	}
+	return rate.Limit(rps)
+}
+
+// hasDomainConfig reports whether SetDomainConfig was called for rawURL's host.
+func (f *RateLimitedFetcher) hasDomainConfig(rawURL string) bool {
+	domain, err := getDomain(rawURL)
+	if err != nil {
+		return false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, exists := f.configs[domain]
+	return exists
+}
+
+// withDefaults fills the zero fields of config from the fetcher default.
+// Default headers are kept unless config sets the same header.
+func (f *RateLimitedFetcher) withDefaults(config FetchConfig) FetchConfig {
+	def := f.defaultConfig
+	if config.TimeoutSeconds == 0 {
+		config.TimeoutSeconds = def.TimeoutSeconds
+	}
+	if config.MaxRetries == 0 {
+		config.MaxRetries = def.MaxRetries
+	}
+	if config.RateLimitRPS == 0 {
+		config.RateLimitRPS = def.RateLimitRPS
+	}
+	if config.ProxyURL == "" {
+		config.ProxyURL = def.ProxyURL
+	}
+	if config.AcceptLanguage == "" {
+		config.AcceptLanguage = def.AcceptLanguage
+	}
+	if config.BlockedMaxBytes == 0 {
+		config.BlockedMaxBytes = def.BlockedMaxBytes
+	}
+	if config.Accept == "" {
+		config.Accept = def.Accept
+	}
+	if config.MaxRedirects == 0 {
+		config.MaxRedirects = def.MaxRedirects
+	}
+	config.RestrictRedirectsToOrigin = config.RestrictRedirectsToOrigin || def.RestrictRedirectsToOrigin
+	if len(def.Headers) > 0 {
+		headers := make(map[string]string, len(def.Headers)+len(config.Headers))
+		for name, value := range def.Headers {
+			headers[name] = value
+		}
+		for name, value := range config.Headers {
+			headers[name] = value
+		}
+		config.Headers = headers
+	}
+	return config
+}
+
 // getDomain extracts the domain from a URL
 func getDomain(rawURL string) (string, error) {
 	u, err := url.Parse(rawURL)
@@ -131,6 +272,55 @@ func getDomain(rawURL string) (string, error) {
 	return u.Host, nil
 }
 
+// ConfigFor returns the fetch config used for rawURL's domain.
+func (f *RateLimitedFetcher) ConfigFor(rawURL string) FetchConfig {
+	domain, err := getDomain(rawURL)
+	if err != nil {
+		return f.defaultConfig
+	}
+	return f.configForDomain(domain)
+}
+
+// configForDomain returns the config for a domain or the default
+func (f *RateLimitedFetcher) configForDomain(domain string) FetchConfig {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	if domainConfig, exists := f.configs[domain]; exists {
+		return domainConfig
+	}
+	return f.defaultConfig
+}
+
+// limiterFor returns the domain's rate limiter, creating it from config on
+// first use. Everything that requests the domain shares it.
+func (f *RateLimitedFetcher) limiterFor(domain string, config FetchConfig) *rate.Limiter {
+	f.mu.RLock()
+	limiter, exists := f.limiters[domain]
+	f.mu.RUnlock()
+	if exists {
+		return limiter
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if limiter, exists := f.limiters[domain]; exists {
+		return limiter
+	}
+	limiter = rate.NewLimiter(rateLimit(config.RateLimitRPS), 1)
+	f.limiters[domain] = limiter
+	return limiter
+}
+
+// wait blocks until rawURL's host may be requested again, for callers that
+// make their own requests but share the host's rate limit with Fetch.
+func (f *RateLimitedFetcher) wait(ctx context.Context, rawURL string) error {
+	domain, err := getDomain(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	return f.limiterFor(domain, f.configForDomain(domain)).Wait(ctx)
+}
+
 // getClient returns or creates an HTTP client for a domain
 func (f *RateLimitedFetcher) getClient(domain string, config FetchConfig) *http.Client {
 	f.mu.RLock()
@@ -155,55 +345,56 @@ func (f *RateLimitedFetcher) getClient(domain string, config FetchConfig) *http.
 		timeout = 30 * time.Second
 	}
 
-	transport := &http.Transport{
-		Proxy:                 http.ProxyFromEnvironment,
-		DialContext:           safeDialContext,
-		ForceAttemptHTTP2:     true,
-		MaxIdleConns:          100,
-		IdleConnTimeout:       90 * time.Second,
-		TLSHandshakeTimeout:   10 * time.Second,
-		ExpectContinueTimeout: 1 * time.Second,
-	}
-
+	client = newSafeHTTPClient(timeout, f.AllowHosts...)
+	client.CheckRedirect = safeCheckRedirectWith(f.AllowHosts, config.MaxRedirects, config.RestrictRedirectsToOrigin)
 	if config.ProxyURL != "" {
 		proxyURL, err := url.Parse(config.ProxyURL)
 		if err == nil {
-			transport.Proxy = http.ProxyURL(proxyURL)
+			client.Transport.(*http.Transport).Proxy = http.ProxyURL(proxyURL)
 		}
 	}
 
-	client = &http.Client{
-		Timeout:       timeout,
-		Transport:     transport,
-		CheckRedirect: safeCheckRedirect,
-	}
-
 	f.clients[domain] = client
 
-	// Create rate limiter (ticker-based, simple approach)
-	interval := time.Duration(float64(time.Second) / config.RateLimitRPS)
-	if interval == 0 {
-		interval = time.Second
-	}
-	f.limiters[domain] = time.NewTicker(interval)
-	f.configs[domain] = config
-
 	return client
 }
 
+// hostAllowed reports whether host is in allowHosts.
+func hostAllowed(host string, allowHosts []string) bool {
+	for _, allowed := range allowHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
 // safeDialContext wraps the default dialer to block private IPs
 func safeDialContext(ctx context.Context, network, addr string) (net.Conn, error) {
-	d := &net.Dialer{
-		Timeout:   30 * time.Second,
-		KeepAlive: 30 * time.Second,
-	}
+	return safeDialContextAllowing(nil)(ctx, network, addr)
+}
 
-	// Split host and port
-	host, _, err := net.SplitHostPort(addr)
-	if err != nil {
-		return nil, err
+// safeDialContextAllowing is safeDialContext with an allowlist of hosts that
+// may resolve to private addresses.
+func safeDialContextAllowing(allowHosts []string) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		d := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}
+
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if hostAllowed(host, allowHosts) {
+			return d.DialContext(ctx, network, addr)
+		}
+		return dialPublic(ctx, d, network, addr, host)
 	}
+}
 
+func dialPublic(ctx context.Context, d *net.Dialer, network, addr, host string) (net.Conn, error) {
 	// Resolve IPs
 	ips, err := net.LookupIP(host)
 	if err != nil {
@@ -260,9 +451,49 @@ func isPrivateIP(ip net.IP) bool {
 
 // safeCheckRedirect limits redirects and validates destinations
 func safeCheckRedirect(req *http.Request, via []*http.Request) error {
-	if len(via) >= 10 {
-		return fmt.Errorf("stopped after 10 redirects")
+	return safeCheckRedirectAllowing(nil)(req, via)
+}
+
+// safeCheckRedirectAllowing is safeCheckRedirect with a host allowlist.
+func safeCheckRedirectAllowing(allowHosts []string) func(req *http.Request, via []*http.Request) error {
+	return safeCheckRedirectWith(allowHosts, defaultMaxRedirects, false)
+}
+
+// safeCheckRedirectWith is safeCheckRedirectAllowing with a redirect limit
+// (defaultMaxRedirects when not positive) and, when sameOrigin is set, a ban
+// on leaving the registrable domain of the first request's host.
+func safeCheckRedirectWith(allowHosts []string, maxRedirects int, sameOrigin bool) func(req *http.Request, via []*http.Request) error {
+	if maxRedirects <= 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		if sameOrigin && req.URL != nil && len(via) > 0 {
+			origin := via[0].URL.Hostname()
+			if registrableDomain(req.URL.Hostname()) != registrableDomain(origin) {
+				return fmt.Errorf("redirect from %s to %s blocked: off origin", origin, req.URL.Hostname())
+			}
+		}
+		if req.URL != nil && hostAllowed(req.URL.Hostname(), allowHosts) {
+			return nil
+		}
+		return checkRedirectTarget(req, via)
 	}
+}
+
+// registrableDomain returns host's eTLD+1 ("example.gov.pe" for
+// "www.example.gov.pe"), or the host itself for IPs and bare names.
+func registrableDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if domain, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return domain
+	}
+	return host
+}
+
+func checkRedirectTarget(req *http.Request, via []*http.Request) error {
 	if req.URL == nil {
 		return fmt.Errorf("invalid redirect URL")
 	}
@@ -316,29 +547,40 @@ func shouldRetry(err error, statusCode int) bool {
 }
 
 // Fetch implements the Fetcher interface with rate limiting and retries
-func (f *RateLimitedFetcher) Fetch(ctx context.Context, rawURL string) (*FetchedDocument, error) {
+func (f *RateLimitedFetcher) Fetch(ctx context.Context, rawURL string, opts ...FetchOption) (*FetchedDocument, error) {
+	return f.do(ctx, "GET", rawURL, "", nil, opts)
+}
+
+// Post sends body to rawURL with the same rate limit, headers and retries as
+// Fetch.
+func (f *RateLimitedFetcher) Post(ctx context.Context, rawURL, contentType string, body []byte, opts ...FetchOption) (*FetchedDocument, error) {
+	return f.do(ctx, "POST", rawURL, contentType, body, opts)
+}
+
+// retryBackoff is the wait before retry attempt n (1-based): 0.5s, 1s, 2s
+// plus jitter unless RetryBackoff overrides it.
+func (f *RateLimitedFetcher) retryBackoff(attempt int) time.Duration {
+	if f.RetryBackoff != nil {
+		return f.RetryBackoff(attempt)
+	}
+	backoff := time.Duration(500*(1<<uint(attempt-1))) * time.Millisecond
+	jitter := time.Duration(rand.Intn(100)) * time.Millisecond
+	return backoff + jitter
+}
+
+func (f *RateLimitedFetcher) do(ctx context.Context, method, rawURL, contentType string, body []byte, opts []FetchOption) (*FetchedDocument, error) {
 	domain, err := getDomain(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	// Get config for this domain or use default
-	config := f.defaultConfig
-	f.mu.RLock()
-	if domainConfig, exists := f.configs[domain]; exists {
-		config = domainConfig
-	}
-	f.mu.RUnlock()
+	config := f.configForDomain(domain)
 
 	// Get client for this domain
 	client := f.getClient(domain, config)
 
-	// Wait for rate limiter
-	f.mu.RLock()
-	limiter, exists := f.limiters[domain]
-	f.mu.RUnlock()
-	if exists {
-		<-limiter.C
+	if err := f.limiterFor(domain, config).Wait(ctx); err != nil {
+		return nil, err
 	}
 
 	// Retry logic with exponential backoff
@@ -347,27 +589,41 @@ func (f *RateLimitedFetcher) Fetch(ctx context.Context, rawURL string) (*Fetched
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff: 0.5s, 1s, 2s + jitter
-			backoff := time.Duration(500*(1<<uint(attempt-1))) * time.Millisecond
-			jitter := time.Duration(rand.Intn(100)) * time.Millisecond
 			select {
 			case <-ctx.Done():
 				return nil, ctx.Err()
-			case <-time.After(backoff + jitter):
+			case <-time.After(f.retryBackoff(attempt)):
 			}
 		}
 
-		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, rawURL, reqBody)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
 
 		// Set headers
+		accept := "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8"
+		if config.Accept != "" {
+			accept = config.Accept
+		}
 		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
+		req.Header.Set("Accept", accept)
 		req.Header.Set("Accept-Language", config.AcceptLanguage)
 		req.Header.Set("Cache-Control", "no-cache")
 		req.Header.Set("Upgrade-Insecure-Requests", "1")
+		for name, value := range config.Headers {
+			req.Header.Set(name, value)
+		}
+		for _, opt := range opts {
+			opt(req.Header)
+		}
 
 		resp, err := client.Do(req)
 		if err != nil {
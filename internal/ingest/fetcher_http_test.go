@@ -0,0 +1,241 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testServerHost returns the host of an httptest server, for the allowlist of
+// a safe client that has to reach it on loopback.
+func testServerHost(t *testing.T, serverURL string) string {
+	t.Helper()
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		t.Fatalf("bad server URL %q: %v", serverURL, err)
+	}
+	return u.Hostname()
+}
+
+// testFetcher returns a RateLimitedFetcher without a meaningful rate limit
+// that may reach the httptest server at serverURL.
+func testFetcher(t *testing.T, serverURL string) *RateLimitedFetcher {
+	t.Helper()
+	fetcher := NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 1000})
+	fetcher.AllowHosts = []string{testServerHost(t, serverURL)}
+	return fetcher
+}
+
+func TestSafeHTTPClient_RefusesPrivateTarget(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private target should never be reached")
+	}))
+	defer server.Close()
+
+	_, err := newSafeHTTPClient(5 * time.Second).Get(server.URL)
+	if err == nil || !strings.Contains(err.Error(), "blocked private IP") {
+		t.Fatalf("expected private IP to be refused, got %v", err)
+	}
+}
+
+func TestSafeHTTPClient_RefusesRedirectToPrivateTarget(t *testing.T) {
+	req, _ := http.NewRequest("GET", "http://127.0.0.1:8080/admin", nil)
+	via := []*http.Request{{URL: &url.URL{Scheme: "https", Host: "example.org"}}}
+	if err := safeCheckRedirectAllowing(nil)(req, via); err == nil {
+		t.Fatal("expected redirect to loopback to be refused")
+	}
+}
+
+func TestSafeHTTPClient_AllowlistedHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	resp, err := newSafeHTTPClient(5*time.Second, "127.0.0.1").Get(server.URL)
+	if err != nil {
+		t.Fatalf("allowlisted host should be reachable: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestFetchers_RefusePrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("private target should never be reached")
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, err := NewHTTPFetcher().Fetch(ctx, server.URL); err == nil {
+		t.Error("HTTPFetcher reached a private target")
+	}
+	if _, err := NewRateLimitedFetcher(FetchConfig{MaxRetries: 1, RateLimitRPS: 100}).Fetch(ctx, server.URL); err == nil {
+		t.Error("RateLimitedFetcher reached a private target")
+	}
+	if _, err := NewCollyFetcher().Fetch(ctx, server.URL); err == nil {
+		t.Error("CollyFetcher reached a private target")
+	}
+
+	grants := NewGrantsGovFetcher()
+	grants.BaseURL = server.URL
+	if _, _, err := grants.FetchOpportunities(ctx, "", 1, 0); err == nil {
+		t.Error("GrantsGovFetcher reached a private target")
+	}
+
+	eu := SourceConfig{ID: "eu_test", BaseURL: server.URL}
+	if _, err := (&EuFundingTendersStrategy{}).Run(ctx, eu, &Pipeline{}); err == nil {
+		t.Error("EU strategy reached a private target")
+	}
+}
+
+func TestRateLimitedFetcher_PerDomainAcceptAndHeaders(t *testing.T) {
+	echoHeaders := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Accept", r.Header.Get("Accept"))
+		w.Header().Set("X-Seen-Key", r.Header.Get("X-Api-Key"))
+	})
+	apiServer := httptest.NewServer(echoHeaders)
+	defer apiServer.Close()
+	htmlServer := httptest.NewServer(echoHeaders)
+	defer htmlServer.Close()
+
+	fetcher := testFetcher(t, apiServer.URL)
+	fetcher.SetDomainConfig(apiServer.URL+"/wp-json/wp/v2/posts", FetchConfig{
+		Accept:  "application/json",
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	})
+
+	doc, err := fetcher.Fetch(context.Background(), apiServer.URL+"/wp-json/wp/v2/posts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Body.Close()
+	seen := http.Header(doc.Headers)
+	if seen.Get("X-Seen-Accept") != "application/json" || seen.Get("X-Seen-Key") != "secret" {
+		t.Fatalf("expected the domain's Accept and headers, got %q %q", seen.Get("X-Seen-Accept"), seen.Get("X-Seen-Key"))
+	}
+	if cfg := fetcher.ConfigFor(apiServer.URL); cfg.RateLimitRPS != 1000 || cfg.MaxRetries != 3 {
+		t.Fatalf("expected unset fields from the default config, got %+v", cfg)
+	}
+
+	doc, err = fetcher.Fetch(context.Background(), htmlServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Body.Close()
+	seen = http.Header(doc.Headers)
+	if !strings.HasPrefix(seen.Get("X-Seen-Accept"), "text/html") || seen.Get("X-Seen-Key") != "" {
+		t.Fatalf("expected the default HTML Accept elsewhere, got %q %q", seen.Get("X-Seen-Accept"), seen.Get("X-Seen-Key"))
+	}
+}
+
+func TestRateLimitedFetcher_FetchOptionStaysOnTheRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Seen-Accept", r.Header.Get("Accept"))
+	}))
+	defer server.Close()
+
+	fetcher := testFetcher(t, server.URL)
+	doc, err := fetcher.Fetch(context.Background(), server.URL+"/wp-json/wp/v2/posts", WithHeader("Accept", "application/json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Body.Close()
+	if got := http.Header(doc.Headers).Get("X-Seen-Accept"); got != "application/json" {
+		t.Fatalf("expected the request's Accept, got %q", got)
+	}
+
+	doc, err = fetcher.Fetch(context.Background(), server.URL+"/convocatoria")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Body.Close()
+	if got := http.Header(doc.Headers).Get("X-Seen-Accept"); !strings.HasPrefix(got, "text/html") {
+		t.Fatalf("expected the host's HTML Accept on other pages, got %q", got)
+	}
+}
+
+func TestRateLimitedFetcher_WaitHonoursContextAndReconfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// One request every 100s: the second Fetch has to wait on the limiter.
+	fetcher := NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 0.01, MaxRetries: 1})
+	fetcher.AllowHosts = []string{testServerHost(t, server.URL)}
+	doc, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Body.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Fetch(ctx, server.URL)
+		done <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+	// Reconfiguring the host while a Fetch waits must not strand it.
+	fetcher.SetDomainConfig(server.URL, FetchConfig{RateLimitRPS: 0.02, TimeoutSeconds: 5})
+	cancel()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the cancelled Fetch to fail")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Fetch kept waiting after its context was cancelled")
+	}
+}
+
+func TestRateLimitedFetcher_MaxRedirects(t *testing.T) {
+	// /hop/N redirects to /hop/N-1; /hop/0 answers.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/hop/"))
+		if n > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/hop/%d", n-1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fetcher := NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 1000, MaxRetries: 1})
+	fetcher.AllowHosts = []string{testServerHost(t, server.URL)}
+	if doc, err := fetcher.Fetch(context.Background(), server.URL+"/hop/3"); err != nil {
+		t.Fatalf("expected 3 redirects within the default limit: %v", err)
+	} else {
+		doc.Body.Close()
+	}
+
+	fetcher.SetDomainConfig(server.URL, FetchConfig{MaxRedirects: 2})
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/hop/3"); err == nil || !strings.Contains(err.Error(), "stopped after 2 redirects") {
+		t.Fatalf("expected the configured limit to stop the chain, got %v", err)
+	}
+}
+
+func TestSafeCheckRedirect_RestrictToOrigin(t *testing.T) {
+	via := []*http.Request{{URL: &url.URL{Scheme: "https", Host: "www.conicyt.gob.cl", Path: "/fondos"}}}
+	sibling, _ := http.NewRequest("GET", "https://convocatorias.conicyt.gob.cl/fondo-1", nil)
+	offsite, _ := http.NewRequest("GET", "https://mirror.example.com/fondo-1", nil)
+	// Allowlisting skips the DNS check so only the origin rule is exercised.
+	allow := []string{"convocatorias.conicyt.gob.cl", "mirror.example.com"}
+
+	restricted := safeCheckRedirectWith(allow, 0, true)
+	if err := restricted(sibling, via); err != nil {
+		t.Fatalf("expected a redirect within the registrable domain to pass: %v", err)
+	}
+	if err := restricted(offsite, via); err == nil || !strings.Contains(err.Error(), "off origin") {
+		t.Fatalf("expected the off-origin redirect refused, got %v", err)
+	}
+
+	if err := safeCheckRedirectWith(allow, 0, false)(offsite, via); err != nil {
+		t.Fatalf("expected cross-origin redirects allowed by default: %v", err)
+	}
+}
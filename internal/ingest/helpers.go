@@ -2,6 +2,10 @@ package ingest
 
 import (
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // normalizeSpace collapses multiple spaces into one and trims the string.
@@ -25,48 +29,77 @@ func appendUnique(list []string, v string) []string {
 	return append(list, vClean)
 }
 
-// cleanText normalizes whitespace (alias for normalizeSpace)
+// cleanText is the text cleaner used across normalization. It composes
+// accents to NFC (so "n" + combining tilde is stored as "ñ"), drops control
+// characters, zero-width spaces, byte-order marks and soft hyphens, and
+// collapses whitespace (including no-break spaces) to single spaces. Letters,
+// combining marks and punctuation such as "—" are kept as they are; the
+// zero-width joiners some scripts need are kept too. Invalid UTF-8 goes
+// through sanitizeUTF8 first, so Windows-1252 bytes are repaired rather than
+// stored as U+FFFD.
 func cleanText(s string) string {
-	return normalizeSpace(s)
+	s = sanitizeUTF8(s)
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u200b', r == '\ufeff', r == '\u00ad':
+			return -1
+		case unicode.IsSpace(r):
+			return r
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+	return normalizeSpace(norm.NFC.String(s))
 }
 
-func normalizeRegion(s string) string {
-	return cleanText(s)
+// truncateAtRune returns the longest prefix of s that is at most maxBytes
+// long and doesn't split a multibyte character.
+func truncateAtRune(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	if maxBytes <= 0 {
+		return ""
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
 }
 
 func normalizeCountry(s string) string {
 	return cleanText(s)
 }
 
-func normalizeFunderType(s string) string {
-	return cleanText(s)
-}
-
 func splitAndCleanList(block string) []string {
 	block = strings.ReplaceAll(block, "\r\n", "\n")
 	block = strings.ReplaceAll(block, "\r", "\n")
 
 	var out []string
 	for _, raw := range strings.Split(block, "\n") {
-		s := strings.TrimSpace(raw)
-		if s == "" {
-			continue
+		if s := cleanListItem(raw); s != "" {
+			out = append(out, s)
 		}
-
-		s = strings.TrimLeft(s, " \t-*•–—")
-		s = strings.TrimSpace(s)
-		s = stripLeadingNumbering(s)
-		s = cleanText(s)
-		if s == "" {
-			continue
-		}
-
-		out = append(out, s)
 	}
 
 	return mergeUniqueFold(nil, out)
 }
 
+// cleanListItem trims leading bullets and numbering ("•", "-", "1.", "2)") from a
+// single list entry and normalizes its whitespace.
+func cleanListItem(s string) string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return s
+	}
+	s = strings.TrimLeft(s, " \t-*•–—")
+	s = strings.TrimSpace(s)
+	s = stripLeadingNumbering(s)
+	return cleanText(s)
+}
+
 func stripLeadingNumbering(s string) string {
 	s = strings.TrimSpace(s)
 	if s == "" {
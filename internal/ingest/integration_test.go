@@ -17,7 +17,7 @@ type MockFetcher struct {
 	Data map[string][]byte
 }
 
-func (m *MockFetcher) Fetch(ctx context.Context, url string) (*FetchedDocument, error) {
+func (m *MockFetcher) Fetch(ctx context.Context, url string, _ ...FetchOption) (*FetchedDocument, error) {
 	content, ok := m.Data[url]
 	if !ok {
 		return nil, fmt.Errorf("mock 404: %s", url)
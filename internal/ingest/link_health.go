@@ -0,0 +1,223 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	// linkDeadAfterFailures is how many consecutive failed checks mark a
+	// link dead.
+	linkDeadAfterFailures = 3
+	// linkRecheckAfter is how long a checked link is left alone.
+	linkRecheckAfter = 7 * 24 * time.Hour
+	linkCheckTimeout = 15 * time.Second
+	linkCheckWorkers = 4
+)
+
+// Link check verdicts. Only broken and error count towards a dead link;
+// blocked (401/403/429, usually bot protection) says nothing either way.
+const (
+	linkOK      = "ok"
+	linkBroken  = "broken"
+	linkBlocked = "blocked"
+	linkError   = "error"
+)
+
+// LinkHealthStats reports one CheckLinkHealth batch.
+type LinkHealthStats struct {
+	Checked int `json:"checked"`
+	OK      int `json:"ok"`
+	Broken  int `json:"broken"`
+	Blocked int `json:"blocked"`
+	Errors  int `json:"errors"`
+	// Dead counts checked rows that are flagged dead after this batch.
+	Dead int `json:"dead"`
+}
+
+type linkTarget struct {
+	ID  string
+	URL string
+}
+
+type linkResult struct {
+	linkTarget
+	StatusCode int // 0 when no response arrived
+	Verdict    string
+}
+
+// CheckLinkHealth re-checks the external_url of up to batchSize non-archived
+// opportunities, least recently checked first, and records the status code.
+// Requests go through the safe client and share the pipeline fetcher's
+// per-host rate limit. Each result is written as soon as it arrives, so
+// cancelling ctx stops the batch but keeps every check already made.
+func (p *Pipeline) CheckLinkHealth(ctx context.Context, batchSize int) (LinkHealthStats, error) {
+	var stats LinkHealthStats
+	if batchSize <= 0 {
+		batchSize = 200
+	}
+
+	rows, err := p.DB.Query(ctx, `
+		SELECT id::text, external_url
+		FROM opportunities
+		WHERE COALESCE(external_url, '') <> ''
+		  AND normalized_status::text IS DISTINCT FROM 'archived'
+		  AND (link_checked_at IS NULL OR link_checked_at < NOW() - $2 * INTERVAL '1 second')
+		ORDER BY link_checked_at ASC NULLS FIRST, id
+		LIMIT $1`, batchSize, int64(linkRecheckAfter/time.Second))
+	if err != nil {
+		return stats, fmt.Errorf("link health: select batch: %w", err)
+	}
+	var targets []linkTarget
+	for rows.Next() {
+		var t linkTarget
+		if err := rows.Scan(&t.ID, &t.URL); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("link health: scan: %w", err)
+		}
+		targets = append(targets, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("link health: select batch: %w", err)
+	}
+
+	fetcher, ok := p.Fetcher.(*RateLimitedFetcher)
+	if !ok {
+		fetcher = NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 1})
+	}
+	// A check that finished is recorded even if ctx was cancelled meanwhile.
+	recordCtx := context.WithoutCancel(ctx)
+	var recordErr error
+	checkLinks(ctx, newSafeHTTPClient(linkCheckTimeout, fetcher.AllowHosts...), fetcher.wait, targets, func(r linkResult) {
+		if recordErr != nil {
+			return
+		}
+		ok, failed := r.Verdict == linkOK, r.Verdict == linkBroken || r.Verdict == linkError
+		var dead bool
+		err := p.DB.QueryRow(recordCtx, `
+			UPDATE opportunities
+			SET link_status_code = NULLIF($2, 0),
+			    link_checked_at = NOW(),
+			    link_failures = CASE WHEN $3 THEN 0 WHEN $4 THEN link_failures + 1 ELSE link_failures END,
+			    link_dead = CASE WHEN $3 THEN false WHEN $4 THEN link_failures + 1 >= $5 ELSE link_dead END
+			WHERE id = $1::uuid
+			RETURNING link_dead`, r.ID, r.StatusCode, ok, failed, linkDeadAfterFailures).Scan(&dead)
+		if err != nil {
+			recordErr = fmt.Errorf("link health: record %s: %w", r.ID, err)
+			return
+		}
+		stats.Checked++
+		switch r.Verdict {
+		case linkOK:
+			stats.OK++
+		case linkBroken:
+			stats.Broken++
+		case linkBlocked:
+			stats.Blocked++
+		default:
+			stats.Errors++
+		}
+		if dead {
+			stats.Dead++
+		}
+	})
+	if recordErr != nil {
+		return stats, recordErr
+	}
+	return stats, ctx.Err()
+}
+
+// checkLinks checks targets with a few workers, calling record for each
+// result as it completes; record calls never overlap. wait paces requests per
+// host. Targets not reached before ctx is cancelled are skipped.
+func checkLinks(ctx context.Context, client *http.Client, wait func(ctx context.Context, rawURL string) error, targets []linkTarget, record func(linkResult)) {
+	jobs := make(chan linkTarget)
+	var mu sync.Mutex
+
+	var wg sync.WaitGroup
+	for i := 0; i < linkCheckWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				u, err := url.Parse(t.URL)
+				if err != nil || u.Host == "" {
+					mu.Lock()
+					record(linkResult{linkTarget: t, Verdict: linkBroken})
+					mu.Unlock()
+					continue
+				}
+				if err := wait(ctx, t.URL); err != nil {
+					continue
+				}
+				status, err := checkLink(ctx, client, t.URL)
+				if ctx.Err() != nil {
+					continue
+				}
+				mu.Lock()
+				record(linkResult{linkTarget: t, StatusCode: status, Verdict: linkVerdict(status, err)})
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, t := range targets {
+		select {
+		case jobs <- t:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// checkLink returns the final status code for rawURL after redirects. It
+// tries HEAD first and falls back to GET for servers that refuse HEAD.
+func checkLink(ctx context.Context, client *http.Client, rawURL string) (int, error) {
+	status, err := doLinkRequest(ctx, client, http.MethodHead, rawURL)
+	if err == nil && (status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden || status == http.StatusBadRequest) {
+		status, err = doLinkRequest(ctx, client, http.MethodGet, rawURL)
+	}
+	return status, err
+}
+
+func doLinkRequest(ctx context.Context, client *http.Client, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// The body isn't needed; closing unread just drops the connection.
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// linkVerdict classifies a check. 404 and 410 (and other client errors) are
+// broken; 5xx, timeouts and network failures are errors; 401, 403 and 429
+// usually mean a bot wall rather than a missing page.
+func linkVerdict(status int, err error) string {
+	switch {
+	case err != nil:
+		return linkError
+	case status < 400:
+		return linkOK
+	case status == http.StatusUnauthorized || status == http.StatusForbidden || status == http.StatusTooManyRequests:
+		return linkBlocked
+	case status < 500:
+		return linkBroken
+	default:
+		return linkError
+	}
+}
@@ -0,0 +1,154 @@
+package ingest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCheckLinks_OKNotFoundAndTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open-call":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/open-call", http.StatusMovedPermanently)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/bot-wall":
+			w.WriteHeader(http.StatusForbidden)
+		case "/slow":
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	targets := []linkTarget{
+		{ID: "ok", URL: server.URL + "/open-call"},
+		{ID: "redirect", URL: server.URL + "/moved"},
+		{ID: "head-refused", URL: server.URL + "/no-head"},
+		{ID: "gone", URL: server.URL + "/retired-call"},
+		{ID: "blocked", URL: server.URL + "/bot-wall"},
+		{ID: "timeout", URL: server.URL + "/slow"},
+		{ID: "bad-url", URL: "not a url"},
+	}
+	results := collectLinks(context.Background(), newSafeHTTPClient(200*time.Millisecond, testServerHost(t, server.URL)), targets)
+
+	got := map[string]linkResult{}
+	for _, r := range results {
+		got[r.ID] = r
+	}
+	want := map[string]struct {
+		verdict string
+		status  int
+	}{
+		"ok":           {linkOK, 200},
+		"redirect":     {linkOK, 200},
+		"head-refused": {linkOK, 200},
+		"gone":         {linkBroken, 404},
+		"blocked":      {linkBlocked, 403},
+		"timeout":      {linkError, 0},
+		"bad-url":      {linkBroken, 0},
+	}
+	if len(got) != len(want) {
+		ids := make([]string, 0, len(got))
+		for id := range got {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		t.Fatalf("got results for %v, want all %d targets", ids, len(want))
+	}
+	for id, w := range want {
+		if got[id].Verdict != w.verdict || got[id].StatusCode != w.status {
+			t.Errorf("%s: verdict %q status %d, want %q %d", id, got[id].Verdict, got[id].StatusCode, w.verdict, w.status)
+		}
+	}
+}
+
+func TestCheckLinks_RefusesPrivateTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// No allowlist: the safe client must refuse loopback.
+	results := collectLinks(context.Background(), newSafeHTTPClient(time.Second), []linkTarget{{ID: "local", URL: server.URL}})
+	if len(results) != 1 || results[0].Verdict != linkError {
+		t.Fatalf("expected the loopback link to fail, got %+v", results)
+	}
+}
+
+func TestCheckLinks_RecordsEachResultBeforeCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var recorded []string
+	targets := []linkTarget{{ID: "quick", URL: server.URL + "/open-call"}, {ID: "slow", URL: server.URL + "/slow"}}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fetcher := testFetcher(t, server.URL)
+		checkLinks(ctx, newSafeHTTPClient(5*time.Second, fetcher.AllowHosts...), fetcher.wait, targets, func(r linkResult) {
+			recorded = append(recorded, r.ID)
+			if r.ID == "quick" {
+				cancel()
+			}
+		})
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("checkLinks kept running after cancel")
+	}
+	if len(recorded) != 1 || recorded[0] != "quick" {
+		t.Fatalf("expected only the finished check to be recorded, got %v", recorded)
+	}
+}
+
+func TestCheckLinks_SharesFetcherRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 20 requests per second: three checks of one host need at least 100ms.
+	fetcher := NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 20})
+	targets := []linkTarget{{ID: "a", URL: server.URL + "/a"}, {ID: "b", URL: server.URL + "/b"}, {ID: "c", URL: server.URL + "/c"}}
+	start := time.Now()
+	checkLinks(context.Background(), newSafeHTTPClient(time.Second, testServerHost(t, server.URL)), fetcher.wait, targets, func(linkResult) {})
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three checks of one host took %v, want at least 100ms", elapsed)
+	}
+}
+
+// collectLinks runs checkLinks without a rate limit and returns its results.
+func collectLinks(ctx context.Context, client *http.Client, targets []linkTarget) []linkResult {
+	var results []linkResult
+	fetcher := NewRateLimitedFetcher(FetchConfig{RateLimitRPS: 1000})
+	checkLinks(ctx, client, fetcher.wait, targets, func(r linkResult) {
+		results = append(results, r)
+	})
+	return results
+}
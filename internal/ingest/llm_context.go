@@ -0,0 +1,150 @@
+package ingest
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// deadlineLabelKeywords mark schedule labels ("Fecha de cierre", "Closing
+// date") in English and Spanish pages.
+var deadlineLabelKeywords = []string{"cierre", "postul", "deadline", "closing", "submission", "fecha límite", "fecha maxima", "cronograma", "calendario", "opening", "apertura"}
+
+// eligibilityKeywords mark who-can-apply sections.
+var eligibilityKeywords = []string{"eligib", "elegib", "who can apply", "quién puede", "quienes pueden", "requisitos", "requirements", "beneficiari", "applicants"}
+
+const (
+	// llmContextSegmentMax is the longest segment selectLLMContext keeps
+	// whole; longer lines are split at sentence ends.
+	llmContextSegmentMax = 400
+	// llmContextGap replaces skipped text so the model knows it is missing.
+	llmContextGap = "[...]"
+)
+
+// llmContextBudget is the character budget for text sent to the LLM:
+// LLM_CONTEXT_CHARS when set to at least 1000, otherwise defaultChars.
+func llmContextBudget(defaultChars int) int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("LLM_CONTEXT_CHARS"))); err == nil && v >= 1000 {
+		return v
+	}
+	return defaultChars
+}
+
+// llmContextFromHTML is the LLM input for an HTML description: the plain
+// text when it fits, otherwise a selection from the structured extraction
+// text, whose label lines ("Cierre | 15 de marzo") rank ahead of prose.
+func llmContextFromHTML(html string, budget int) string {
+	if plain := HTMLToText(html); len(plain) <= budget {
+		return plain
+	}
+	return selectLLMContext(buildStructuredExtractionText(html), budget)
+}
+
+// selectLLMContext fits text into budget bytes without losing the parts the
+// LLM is asked for. Deadlines often sit at the end of long pages, past a
+// plain head cut, so it keeps, in this order: the opening quarter of the
+// budget (title and intro), every segment with a deadline, amount or
+// eligibility keyword plus the segment after a short label, and then the
+// rest in page order while room remains. Segments stay in page order and
+// skipped runs are marked with llmContextGap.
+func selectLLMContext(text string, budget int) string {
+	if len(text) <= budget {
+		return text
+	}
+	segments := splitLLMSegments(text)
+
+	keep := make([]bool, len(segments))
+	seen := make(map[string]bool, len(segments))
+	used := 0
+	take := func(i int) {
+		if keep[i] || seen[segments[i]] {
+			return
+		}
+		// Room for the newline and a possible gap marker.
+		cost := len(segments[i]) + len(llmContextGap) + 2
+		if used+cost > budget {
+			return
+		}
+		keep[i] = true
+		seen[segments[i]] = true
+		used += cost
+	}
+
+	for i := range segments {
+		if used+len(segments[i]) > budget/4 {
+			break
+		}
+		take(i)
+	}
+	for i, seg := range segments {
+		if !isLLMPrioritySegment(seg) {
+			continue
+		}
+		take(i)
+		// A short label is usually followed by its value.
+		if len(seg) < 80 && i+1 < len(segments) {
+			take(i + 1)
+		}
+	}
+	for i := range segments {
+		take(i)
+	}
+
+	var b strings.Builder
+	gap := false
+	for i, seg := range segments {
+		if !keep[i] {
+			gap = true
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		if gap && b.Len() > 0 {
+			b.WriteString(llmContextGap + "\n")
+		}
+		gap = false
+		b.WriteString(seg)
+	}
+	if gap {
+		b.WriteString("\n" + llmContextGap)
+	}
+	return truncateAtRune(b.String(), budget)
+}
+
+// isLLMPrioritySegment reports whether seg mentions a deadline, an amount
+// or eligibility.
+func isLLMPrioritySegment(seg string) bool {
+	lower := strings.ToLower(seg)
+	for _, list := range [][]string{deadlineLabelKeywords, eligibilityKeywords} {
+		for _, keyword := range list {
+			if strings.Contains(lower, keyword) {
+				return true
+			}
+		}
+	}
+	return amountKeywordRegex.MatchString(seg)
+}
+
+// splitLLMSegments splits text into non-empty lines, breaking lines longer
+// than llmContextSegmentMax at sentence ends and, failing that, at the limit.
+func splitLLMSegments(text string) []string {
+	var out []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		for len(line) > llmContextSegmentMax {
+			cut := strings.LastIndex(line[:llmContextSegmentMax], ". ")
+			if cut > 0 {
+				cut++ // keep the period
+			} else {
+				cut = len(truncateAtRune(line, llmContextSegmentMax))
+			}
+			out = append(out, strings.TrimSpace(line[:cut]))
+			line = strings.TrimSpace(line[cut:])
+		}
+		if line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
@@ -0,0 +1,78 @@
+package ingest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// longCallPage is a call page whose schedule sits well past 12000 bytes.
+func longCallPage() string {
+	var b strings.Builder
+	b.WriteString("<html><body><h1>Fondo de Innovación Regional 2027</h1>\n")
+	b.WriteString("<p>El fondo apoya proyectos de innovación en regiones.</p>\n")
+	for i := 0; i < 120; i++ {
+		fmt.Fprintf(&b, "<p>Antecedente %d: el programa ha financiado iniciativas de desarrollo productivo en distintas zonas del país durante la última década.</p>\n", i)
+	}
+	b.WriteString("<h3>Fecha de cierre</h3><p>15 de marzo de 2027, 17:00 horas</p>\n")
+	b.WriteString("<p>Monto máximo: S/ 500,000 por proyecto.</p>\n")
+	b.WriteString("</body></html>")
+	return b.String()
+}
+
+func TestSelectLLMContext_KeepsDeadlinePastOldCutoff(t *testing.T) {
+	page := longCallPage()
+	deadlineAt := strings.Index(page, "15 de marzo de 2027")
+	if deadlineAt < 12000 {
+		t.Fatalf("fixture deadline at byte %d, want it past the old 12000 cut", deadlineAt)
+	}
+	if strings.Contains(truncateAtRune(page, 12000), "15 de marzo") {
+		t.Fatal("fixture no longer exercises the head cut")
+	}
+
+	got := selectLLMContext(page, 12000)
+	if len(got) > 12000 || !utf8.ValidString(got) {
+		t.Fatalf("context is %d bytes (valid UTF-8: %v), want at most 12000", len(got), utf8.ValidString(got))
+	}
+	for _, want := range []string{"Fondo de Innovación Regional 2027", "Fecha de cierre", "15 de marzo de 2027", "S/ 500,000", llmContextGap} {
+		if !strings.Contains(got, want) {
+			t.Errorf("context lost %q", want)
+		}
+	}
+	if strings.Index(got, "Fondo de Innovación") > strings.Index(got, "Fecha de cierre") {
+		t.Error("segments should stay in page order")
+	}
+}
+
+func TestLLMContextFromHTML_KeepsLabelledDeadline(t *testing.T) {
+	got := llmContextFromHTML(longCallPage(), 8000)
+	if len(got) > 8000 {
+		t.Fatalf("context is %d bytes, want at most 8000", len(got))
+	}
+	if !strings.Contains(got, "Fecha de cierre") || !strings.Contains(got, "15 de marzo de 2027") {
+		t.Errorf("deadline dropped from extraction context:\n%s", got)
+	}
+}
+
+func TestSelectLLMContext_ShortTextUnchanged(t *testing.T) {
+	text := "Deadline: 1 June 2027\nBudget: EUR 2 000 000"
+	if got := selectLLMContext(text, 8000); got != text {
+		t.Errorf("short text changed: %q", got)
+	}
+}
+
+func TestLLMContextBudget(t *testing.T) {
+	t.Setenv("LLM_CONTEXT_CHARS", "")
+	if got := llmContextBudget(8000); got != 8000 {
+		t.Errorf("default budget = %d, want 8000", got)
+	}
+	t.Setenv("LLM_CONTEXT_CHARS", "20000")
+	if got := llmContextBudget(8000); got != 20000 {
+		t.Errorf("LLM_CONTEXT_CHARS budget = %d, want 20000", got)
+	}
+	t.Setenv("LLM_CONTEXT_CHARS", "50")
+	if got := llmContextBudget(8000); got != 8000 {
+		t.Errorf("too small LLM_CONTEXT_CHARS should be ignored, got %d", got)
+	}
+}
@@ -7,15 +7,16 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-// TruncateText cuts a string to max length, appending ellipsis if truncated.
+// TruncateText cuts a string to max length in bytes, appending ellipsis if
+// truncated. It never splits a multibyte character.
 func TruncateText(text string, maxLen int) string {
 	if len(text) <= maxLen {
 		return text
 	}
 	if maxLen > 3 {
-		return text[:maxLen-3] + "..."
+		return truncateAtRune(text, maxLen-3) + "..."
 	}
-	return text[:maxLen]
+	return truncateAtRune(text, maxLen)
 }
 
 // HTMLToText converts HTML to plain text, collapsing whitespace.
@@ -50,6 +51,8 @@ func FromRaw(raw RawOpportunity) Opportunity {
 		IsResultsPage: raw.IsResultsPage,
 		RollingEvidence: raw.RollingEvidence,
 		SourceEvidenceJSON: raw.SourceEvidenceJSON,
+		FollowURLs: raw.FollowURLs,
+		DetailPage: raw.DetailPage,
 		// CreatedAt/UpdatedAt handled by DB or Pipeline defaults
 	}
 
@@ -60,6 +63,10 @@ func FromRaw(raw RawOpportunity) Opportunity {
 	}
 	if raw.RawDeadline != "" {
 		if dt, err := parseDateRobust(raw.RawDeadline, locales); err == nil {
+			// A configured cutoff replaces end of day for bare dates.
+			if cutoff, _ := parseDeadlineCutoff(raw.Extra["default_deadline_time"]); cutoff.set && !hasExplicitTimeToken(raw.RawDeadline) {
+				dt = normalizeDateOnlyBySource(dt, raw.ExternalURL, cutoff)
+			}
 			opp.DeadlineAt = &dt
 		}
 	}
@@ -70,18 +77,31 @@ func FromRaw(raw RawOpportunity) Opportunity {
 		if raw.RawCurrency != "" {
 			defaultCurrency = raw.RawCurrency
 		}
-		// parseAmountRobust is in amount_parser.go (same package)
-		min, max, currency := parseAmountRobust(raw.RawAmount, defaultCurrency)
-		if min > 0 || max > 0 {
-			opp.AmountMin = min
-			opp.AmountMax = max
-			if currency != "" {
-				opp.Currency = currency
+		// parseAmountLocalized is in amount_parser.go (same package)
+		parsed := parseAmountLocalized(raw.RawAmount, defaultCurrency, raw.Extra["number_locale"])
+		if parsed.Min > 0 || parsed.Max > 0 {
+			opp.AmountMin = parsed.Min
+			opp.AmountMax = parsed.Max
+			if parsed.Currency != "" {
+				opp.Currency = parsed.Currency
+			}
+		}
+		if parsed.SecondaryCurrency != "" {
+			if opp.SourceEvidenceJSON == nil {
+				opp.SourceEvidenceJSON = map[string]interface{}{}
+			}
+			opp.SourceEvidenceJSON["amount_secondary"] = map[string]interface{}{
+				"currency": parsed.SecondaryCurrency,
+				"amount":   parsed.SecondaryAmount,
+				"raw":      raw.RawAmount,
 			}
 		}
 	}
 
 	// 3. Extra Fields Handling
+	if val, ok := raw.Extra["canonical_url"]; ok && val != "" {
+		opp.CanonicalURL = val
+	}
 	if val, ok := raw.Extra["is_rolling"]; ok && val == "true" {
 		opp.IsRolling = true
 		opp.RollingEvidence = true
@@ -98,6 +118,9 @@ func FromRaw(raw RawOpportunity) Opportunity {
 	if val, ok := raw.Extra["is_results_page"]; ok && strings.EqualFold(val, "true") {
 		opp.IsResultsPage = true
 	}
+	if len(raw.Eligibility) > 0 {
+		opp.Eligibility = mergeUniqueFold(opp.Eligibility, raw.Eligibility)
+	}
 	if val, ok := raw.Extra["eligibility"]; ok && val != "" {
 		opp.Eligibility = mergeUniqueFold(opp.Eligibility, splitAndCleanList(val))
 	}
@@ -133,6 +156,12 @@ func NormalizeOpportunity(opp *Opportunity) {
 	opp.Summary = cleanText(opp.Summary)
 	opp.Region = normalizeRegion(opp.Region)
 	opp.Country = normalizeCountry(opp.Country)
+	if opp.Region == "" {
+		opp.Region = regionForCountry(opp.Country)
+	}
+	if opp.FunderTypeLabel == "" {
+		opp.FunderTypeLabel = cleanText(opp.FunderType)
+	}
 	opp.FunderType = normalizeFunderType(opp.FunderType)
 	opp.AgencyName = cleanText(opp.AgencyName)
 
@@ -141,7 +170,7 @@ func NormalizeOpportunity(opp *Opportunity) {
 
 // UpdateStatus applies the single source of truth for opportunity status.
 // Rules:
-// - If explicitly 'closed' or 'archived', stay closed.
+// - If explicitly 'closed', 'archived' or 'funded', stay closed.
 // - If IsRolling -> posted.
 // - If Deadline parsing failed -> posted (default, do not force unknown).
 // - If Deadline < Now -> closed.
@@ -154,7 +183,7 @@ func UpdateStatus(opp *Opportunity) {
 	opp.NextDeadlineAt = decision.NextDeadlineAt
 	opp.IsResultsPage = decision.IsResultsPage
 
-	if decision.NormalizedStatus == "closed" || decision.NormalizedStatus == "archived" {
+	if decision.NormalizedStatus == "closed" || decision.NormalizedStatus == "archived" || decision.NormalizedStatus == "funded" {
 		opp.OppStatus = decision.NormalizedStatus
 		return
 	}
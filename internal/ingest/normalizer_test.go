@@ -1,8 +1,11 @@
 package ingest
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 func TestUpdateStatus(t *testing.T) {
@@ -76,3 +79,83 @@ func TestUpdateStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizeEUDocType(t *testing.T) {
+	tests := map[string]string{
+		"Tenders":           "Tender",
+		"TENDER":            "Tender",
+		" call for tenders": "Tender",
+		"Grants":            "Grant",
+		"grant":             "Grant",
+		"":                  "Grant",
+	}
+	for raw, want := range tests {
+		if got := normalizeEUDocType(raw); got != want {
+			t.Fatalf("normalizeEUDocType(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestCleanText_PreservesAccents(t *testing.T) {
+	cases := map[string]string{
+		"Convocatoria   de Innovación\n\tEmpresarial":     "Convocatoria de Innovación Empresarial",
+		"Fondo para la Niñez — 2026":                      "Fondo para la Niñez — 2026",
+		"Programa de Certificação\u00a0Orgânica":          "Programa de Certificação Orgânica",
+		"Ayuda a la Inves\u00adtigación\u200b Científica": "Ayuda a la Investigación Científica",
+		"\ufeffSubvención\x00 para\x07 Pymes":             "Subvención para Pymes",
+		// Decomposed accents ("n" + U+0303) are composed.
+		"Espan\u0303a y Portugal": "España y Portugal",
+	}
+	for in, want := range cases {
+		if got := cleanText(in); got != want {
+			t.Errorf("cleanText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSanitizeUTF8_RepairsLatin1Bytes(t *testing.T) {
+	cases := map[string]string{
+		"Fondo para la Niñez — Perú":           "Fondo para la Niñez — Perú",
+		"Espa\xf1a: ayudas a la innovaci\xf3n": "España: ayudas a la innovación",
+		"Certifica\xe7\xe3o \x97 2026":         "Certificação — 2026",
+		"Becas\x00 2026":                       "Becas 2026",
+	}
+	for in, want := range cases {
+		got := sanitizeUTF8(in)
+		if got != want {
+			t.Errorf("sanitizeUTF8(%q) = %q, want %q", in, got, want)
+		}
+		if !utf8.ValidString(got) {
+			t.Errorf("sanitizeUTF8(%q) is still invalid UTF-8", in)
+		}
+	}
+}
+
+func TestTruncateText_DoesNotSplitRunes(t *testing.T) {
+	text := strings.Repeat("ñ", 200) // 400 bytes
+	for _, max := range []int{280, 281, 5, 4, 1} {
+		got := TruncateText(text, max)
+		if !utf8.ValidString(got) || len(got) > max {
+			t.Errorf("TruncateText(_, %d) = %q (%d bytes)", max, got, len(got))
+		}
+	}
+}
+
+func TestPrepareTitle_KeepsAccentsThroughHTMLCleaning(t *testing.T) {
+	titles := map[string]string{
+		"<b>Año Internacional</b> de la Ciencia — Convocatoria": "Año Internacional de la Ciencia — Convocatoria",
+		"Inova\xe7\xe3o &amp; Tecnolog\xeda":                    "Inovação & Tecnología",
+		"Programa  Científico\r\n de Açores":                    "Programa Científico de Açores",
+	}
+	titles["Espa\xf1a: ayudas a la innovaci\xf3n"] = "España: ayudas a la innovación"
+	p := &Pipeline{}
+	for in, want := range titles {
+		opp := FromRaw(RawOpportunity{Title: in, SourceID: "accents", Extra: map[string]string{"is_rolling": "true"}})
+		if err := p.prepareOpportunity(context.Background(), &opp); err != nil {
+			t.Fatalf("title %q: %v", in, err)
+		}
+		if opp.Title != want {
+			t.Errorf("title %q cleaned to %q, want %q", in, opp.Title, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultQueryMaxPages caps query-param pagination when neither
+// pagination.max_pages nor max_pages is set; an empty page usually ends the
+// crawl well before that.
+const defaultQueryMaxPages = 20
+
+// usesQueryParam reports whether list pages are addressed by a query param
+// rather than followed through a next link.
+func (p PaginationConfig) usesQueryParam() bool {
+	return strings.TrimSpace(p.Param) != ""
+}
+
+// pageURL returns baseURL with the pagination param set for the page at
+// index (0 for the first page).
+func (p PaginationConfig) pageURL(baseURL string, index int) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	step := p.Step
+	if step == 0 {
+		step = 1
+	}
+	q := u.Query()
+	q.Set(strings.TrimSpace(p.Param), strconv.Itoa(p.Start+index*step))
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// listPageLimit returns how many list pages a run may fetch.
+func listPageLimit(config SourceConfig) int {
+	if config.Pagination.usesQueryParam() && config.Pagination.MaxPages > 0 {
+		return config.Pagination.MaxPages
+	}
+	if config.MaxPages > 0 {
+		return config.MaxPages
+	}
+	if config.Pagination.usesQueryParam() {
+		return defaultQueryMaxPages
+	}
+	return 1
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/david/grant-finder/internal/ai"
 )
 
 // OllamaParser uses a local Ollama LLM to extract grant data from HTML/text.
@@ -19,13 +21,17 @@ type OllamaParser struct {
 	Client  *http.Client
 }
 
+// NewOllamaParser returns a parser that extracts with model, or with
+// ai.DefaultExtractionModel when model is empty.
 func NewOllamaParser(model string) *OllamaParser {
+	if model == "" {
+		model = ai.DefaultExtractionModel
+	}
 	return &OllamaParser{
 		BaseURL: "http://localhost:11434",
 		Model:   model,
-		Client: &http.Client{
-			Timeout: 120 * time.Second, // LLM can be slow
-		},
+		// The local Ollama daemon is the only private host this client may reach.
+		Client: newSafeHTTPClient(120*time.Second, "localhost", "127.0.0.1", "::1"), // LLM can be slow
 	}
 }
 
@@ -85,10 +91,8 @@ func (p *OllamaParser) Parse(ctx context.Context, r io.Reader) ([]Opportunity, e
 	}
 
 	text := string(bodyBytes)
-	// Truncate if too long (LLM context limit)
-	if len(text) > 12000 {
-		text = text[:12000]
-	}
+	// Fit the LLM context limit, keeping deadline/amount sections of long pages
+	text = selectLLMContext(text, llmContextBudget(12000))
 
 	prompt := fmt.Sprintf(extractionPrompt, text)
 
@@ -17,12 +17,17 @@ var deadlineLabelHints = []string{
 	"inicio de postulaciones", "cierre de postulaciones", "fecha máxima", "deadline", "closes", "fecha límite", "cronograma", "calendario", "postulación",
 }
 
+// zonedTimeSuffix extends a date match with a time and timezone ("... 5:00 PM
+// EST", "... a las 17:00 horas, hora de Lima") so the zone reaches
+// parseDateRobust.
+const zonedTimeSuffix = `,?\s+(?:at\s+|a\s+las\s+)?\d{1,2}(?::\d{2})?` + zonedTimeTail
+
 var dateSnippetRegexes = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)\b\d{1,2}/\d{1,2}/20\d{2}\b`),
+	regexp.MustCompile(`(?i)\b\d{1,2}/\d{1,2}/20\d{2}\b(?:` + zonedTimeSuffix + `)?`),
 	regexp.MustCompile(`(?i)\b20\d{2}-\d{2}-\d{2}\b`),
-	regexp.MustCompile(`(?i)\b\d{1,2}\s+de\s+(enero|febrero|marzo|abril|mayo|junio|julio|agosto|septiembre|octubre|noviembre|diciembre)\s+(de|del)\s+20\d{2}\b`),
-	regexp.MustCompile(`(?i)\b\d{1,2}\s+(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+20\d{2}(\s+\d{1,2}(:\d{2})?\s*(a\.?m\.?|p\.?m\.?))?\b`),
-	regexp.MustCompile(`(?i)\b(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+\d{1,2},?\s+20\d{2}(\s+\d{1,2}(:\d{2})?\s*(a\.?m\.?|p\.?m\.?))?\b`),
+	regexp.MustCompile(`(?i)\b\d{1,2}\s+de\s+(enero|febrero|marzo|abril|mayo|junio|julio|agosto|septiembre|octubre|noviembre|diciembre)\s+(de|del)\s+20\d{2}\b(?:` + zonedTimeSuffix + `)?`),
+	regexp.MustCompile(`(?i)\b\d{1,2}\s+(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+20\d{2}(?:` + zonedTimeSuffix + `|(\s+\d{1,2}(:\d{2})?\s*(a\.?m\.?|p\.?m\.?))?\b)`),
+	regexp.MustCompile(`(?i)\b(January|February|March|April|May|June|July|August|September|October|November|December|Jan|Feb|Mar|Apr|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+\d{1,2},?\s+20\d{2}(?:` + zonedTimeSuffix + `|(\s+\d{1,2}(:\d{2})?\s*(a\.?m\.?|p\.?m\.?))?\b)`),
 }
 
 func extractPDFText(content []byte) (text string, err error) {
@@ -56,7 +61,7 @@ func extractPDFText(content []byte) (text string, err error) {
 }
 
 func parseDateCandidatesFromText(text string) []string {
-	evidence := parseDeadlineEvidenceFromText(text, "text", "", 0.7)
+	evidence := parseDeadlineEvidenceFromText(text, "text", "", nil, 0.7, deadlineCutoff{})
 	if len(evidence) == 0 {
 		return nil
 	}
@@ -74,9 +79,15 @@ func parseDateCandidatesFromText(text string) []string {
 	return result
 }
 
-func parseDeadlineEvidenceFromText(text, source, sourceURL string, defaultConfidence float64) []DeadlineEvidence {
+// parseDeadlineEvidenceFromText finds dated snippets in text. Dates without
+// a time close at cutoff in the source's timezone. locales is the source's
+// detail.parse.date_locales, which also decides whether slash dates are
+// day-first; empty means ["en", "es"].
+func parseDeadlineEvidenceFromText(text, source, sourceURL string, locales []string, defaultConfidence float64, cutoff deadlineCutoff) []DeadlineEvidence {
 	matches := make(map[string]DeadlineEvidence)
-	locales := []string{"en", "es"}
+	if len(locales) == 0 {
+		locales = []string{"en", "es"}
+	}
 
 	for _, expr := range dateSnippetRegexes {
 		for _, loc := range expr.FindAllStringIndex(text, -1) {
@@ -85,8 +96,13 @@ func parseDeadlineEvidenceFromText(text, source, sourceURL string, defaultConfid
 			if err != nil {
 				continue
 			}
-			if !hasExplicitTimeToken(token) {
-				parsed = normalizeDateOnlyBySource(parsed, sourceURL)
+			if _, _, zoned := extractZonedTime(token); !zoned {
+				if hasExplicitTimeToken(token) {
+					// A time without a zone is wall-clock in the source's timezone.
+					parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), parsed.Hour(), parsed.Minute(), parsed.Second(), 0, SourceLocation(sourceURL)).UTC()
+				} else {
+					parsed = normalizeDateOnlyBySource(parsed, sourceURL, cutoff)
+				}
 			}
 			iso := parsed.UTC().Format(time.RFC3339)
 			start := loc[0] - 80
@@ -98,12 +114,17 @@ func parseDeadlineEvidenceFromText(text, source, sourceURL string, defaultConfid
 				end = len(text)
 			}
 			snippet := strings.TrimSpace(strings.ReplaceAll(text[start:end], "\n", " "))
-			label := "deadline"
-			snippetLower := strings.ToLower(snippet)
-			for _, hint := range deadlineLabelHints {
-				if strings.Contains(snippetLower, hint) {
-					label = hint
-					break
+			// Prefer the milestone word right before the date: in "inicio 1 de
+			// marzo / cierre 30 de marzo" both dates share one snippet.
+			label := nearestLabelBefore(strings.ToLower(text[start:loc[0]]))
+			if label == "" {
+				label = "deadline"
+				snippetLower := strings.ToLower(snippet)
+				for _, hint := range deadlineLabelHints {
+					if strings.Contains(snippetLower, hint) {
+						label = hint
+						break
+					}
 				}
 			}
 			matches[iso] = DeadlineEvidence{
@@ -134,6 +155,25 @@ func parseDeadlineEvidenceFromText(text, source, sourceURL string, defaultConfid
 	return ordered
 }
 
+// nearestLabelBefore returns the open/close hint closest to the end of
+// before, preferring the longer phrase when two end at the same place.
+func nearestLabelBefore(before string) string {
+	best, bestEnd := "", -1
+	for _, group := range [][]string{deadlineLabelHints, scheduleOpenHints, scheduleCloseHints} {
+		for _, hint := range group {
+			i := strings.LastIndex(before, hint)
+			if i < 0 {
+				continue
+			}
+			end := i + len(hint)
+			if end > bestEnd || (end == bestEnd && len(hint) > len(best)) {
+				best, bestEnd = hint, end
+			}
+		}
+	}
+	return best
+}
+
 func hasExplicitTimeToken(token string) bool {
 	lower := strings.ToLower(token)
 	if strings.Contains(lower, ":") {
@@ -148,16 +188,20 @@ func hasExplicitTimeToken(token string) bool {
 	return false
 }
 
-func normalizeDateOnlyBySource(parsed time.Time, sourceURL string) time.Time {
-	loc := time.UTC
+func normalizeDateOnlyBySource(parsed time.Time, sourceURL string, cutoff deadlineCutoff) time.Time {
+	return cutoff.on(parsed, SourceLocation(sourceURL)).UTC()
+}
+
+// SourceLocation is the timezone deadlines without an explicit zone are
+// assumed to be in: America/Lima for Peruvian sources, UTC otherwise.
+func SourceLocation(sourceURL string) *time.Location {
 	lowerURL := strings.ToLower(sourceURL)
 	if strings.Contains(lowerURL, "gob.pe") || strings.Contains(lowerURL, "proinnovate") || strings.Contains(lowerURL, "prociencia") {
 		if lima, err := time.LoadLocation("America/Lima"); err == nil {
-			loc = lima
+			return lima
 		}
 	}
-	localized := time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 23, 59, 59, 0, loc)
-	return localized.UTC()
+	return time.UTC
 }
 
 func extractDeadlinesFromPDF(ctx context.Context, fetcher Fetcher, pdfURL string) ([]string, string, error) {
@@ -167,16 +211,25 @@ func extractDeadlinesFromPDF(ctx context.Context, fetcher Fetcher, pdfURL string
 	}
 	defer doc.Body.Close()
 
-	pdfContent, err := io.ReadAll(doc.Body)
+	text, err := readPDFText(doc.Body)
 	if err != nil {
-		return nil, "", fmt.Errorf("pdf read failed: %w", err)
+		return nil, "", err
 	}
 
-	text, err := extractPDFText(pdfContent)
+	deadlines := parseDateCandidatesFromText(text)
+	return deadlines, text, nil
+}
+
+// readPDFText reads an already-fetched PDF body and extracts its text.
+func readPDFText(body io.Reader) (string, error) {
+	pdfContent, err := io.ReadAll(body)
 	if err != nil {
-		return nil, "", fmt.Errorf("pdf text extraction failed: %w", err)
+		return "", fmt.Errorf("pdf read failed: %w", err)
 	}
 
-	deadlines := parseDateCandidatesFromText(text)
-	return deadlines, text, nil
+	text, err := extractPDFText(pdfContent)
+	if err != nil {
+		return "", fmt.Errorf("pdf text extraction failed: %w", err)
+	}
+	return text, nil
 }
@@ -29,7 +29,7 @@ func TestParseDateCandidatesFromText_Multilingual(t *testing.T) {
 
 func TestNormalizeDateOnlyBySource_GobPeUsesEndOfDayUTC(t *testing.T) {
 	parsed := time.Date(2026, 2, 18, 0, 0, 0, 0, time.UTC)
-	normalized := normalizeDateOnlyBySource(parsed, "https://www.gob.pe/institucion/proinnovate/campanas/x")
+	normalized := normalizeDateOnlyBySource(parsed, "https://www.gob.pe/institucion/proinnovate/campanas/x", deadlineCutoff{})
 
 	if normalized.Hour() < 4 || normalized.Hour() > 6 {
 		t.Fatalf("expected America/Lima end-of-day normalized near 04-06 UTC, got %s", normalized.Format(time.RFC3339))
@@ -3,17 +3,33 @@ package ingest
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/david/grant-finder/internal/ai"
 	"github.com/david/grant-finder/internal/db"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/pgvector/pgvector-go"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// ctxKey namespaces context values set by this package.
+type ctxKey int
+
+const (
+	// runIDKey carries the ingest_runs id from IngestSource to SaveOpportunity.
+	runIDKey ctxKey = iota
+	// numberDedupKey marks a run of a source with dedup_by_number set.
+	numberDedupKey
+	// pageRowsKey carries prepareBatch's *batchPage to prepareOpportunity.
+	pageRowsKey
 )
 
 type Pipeline struct {
@@ -22,6 +38,20 @@ type Pipeline struct {
 	Fetcher Fetcher
 	Parser  Parser
 	AI      *ai.OllamaClient
+
+	// Notifier, when set, is told about inserts and status changes made by
+	// SaveOpportunity.
+	Notifier OpportunityNotifier
+
+	// Saver, when set, replaces SaveRaw's normalize-and-write step and
+	// reports the outcome itself, such as a skip. Strategies save through
+	// SaveRaw, so it sees every item a run would write; the admin preview
+	// uses it to collect items without touching the database.
+	Saver func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error)
+
+	// Registry is the source registry IngestSource and IngestAll read. nil
+	// means the shared cached registry, which the admin reload endpoint swaps.
+	Registry *Registry
 }
 
 func NewPipeline(pool *pgxpool.Pool, fetcher Fetcher, parser Parser, aiClient *ai.OllamaClient) *Pipeline {
@@ -44,6 +74,15 @@ func NewPipeline(pool *pgxpool.Pool, fetcher Fetcher, parser Parser, aiClient *a
 	}
 }
 
+// allowedHosts returns the hosts the pipeline's fetcher exempts from the
+// private-address guard, for the clients strategies build for themselves.
+func (p *Pipeline) allowedHosts() []string {
+	if f, ok := p.Fetcher.(*RateLimitedFetcher); ok {
+		return f.AllowHosts
+	}
+	return nil
+}
+
 // Run fetches a URL, parses it with the LLM, and saves results.
 func (p *Pipeline) Run(ctx context.Context, url string) error {
 	log.Printf("Starting ingestion for: %s", url)
@@ -84,6 +123,47 @@ func (p *Pipeline) Run(ctx context.Context, url string) error {
 
 // IngestSource triggers ingestion for a specific source ID defined in registry.
 func (p *Pipeline) IngestSource(ctx context.Context, sourceID string) (IngestionStats, error) {
+	registry, err := p.registry()
+	if err != nil {
+		return IngestionStats{}, fmt.Errorf("failed to load registry: %w", err)
+	}
+
+	config := registry.Source(sourceID)
+	if config == nil {
+		return IngestionStats{}, fmt.Errorf("source id %q not found in registry", sourceID)
+	}
+	return p.ingestConfig(ctx, *config)
+}
+
+// configureFetch applies a source's fetch settings (timeouts, rate limit,
+// Accept and extra headers) to rawURL's host when the pipeline fetcher
+// supports per-domain config.
+func (p *Pipeline) configureFetch(rawURL string, config FetchConfig) {
+	if f, ok := p.Fetcher.(*RateLimitedFetcher); ok {
+		f.SetDomainConfig(rawURL, config)
+	}
+}
+
+// blockedMaxBytes is the block-page size threshold for rawURL's host, or 0
+// (the default) when the fetcher has no per-host config.
+func (p *Pipeline) blockedMaxBytes(rawURL string) int {
+	if f, ok := p.Fetcher.(*RateLimitedFetcher); ok {
+		return f.ConfigFor(rawURL).BlockedMaxBytes
+	}
+	return 0
+}
+
+func (p *Pipeline) registry() (*Registry, error) {
+	if p.Registry != nil {
+		return p.Registry, nil
+	}
+	return currentRegistry()
+}
+
+// ingestConfig runs one registry source and records an ingest_runs row.
+func (p *Pipeline) ingestConfig(ctx context.Context, config SourceConfig) (IngestionStats, error) {
+	sourceID := config.ID
+
 	// 1. Create Run Record
 	var runID string
 	err := p.DB.QueryRow(ctx,
@@ -93,7 +173,7 @@ func (p *Pipeline) IngestSource(ctx context.Context, sourceID string) (Ingestion
 		log.Printf("[Warn] Failed to create ingest run: %v", err)
 	} else {
 		// Attach runID to context for SaveOpportunity to pick up
-		ctx = context.WithValue(ctx, "source_run_id", runID)
+		ctx = context.WithValue(ctx, runIDKey, runID)
 	}
 
 	start := time.Now()
@@ -121,10 +201,13 @@ func (p *Pipeline) IngestSource(ctx context.Context, sourceID string) (Ingestion
 					items_saved = $3, 
 					errors = $4, 
 					completed_at = NOW(),
-					details = $5
-				WHERE run_id = $6`,
+					details = $5,
+					items_inserted = $6,
+					items_updated = $7
+				WHERE run_id = $8`,
 				status, stats.TotalFound, stats.TotalSaved, stats.Errors,
-				fmt.Sprintf(`{"duration_ms": %d}`, duration.Milliseconds()),
+				runDetailsJSON(stats, duration),
+				stats.Inserted, stats.Updated,
 				runID,
 			)
 			if execErr != nil {
@@ -133,47 +216,61 @@ func (p *Pipeline) IngestSource(ctx context.Context, sourceID string) (Ingestion
 		}
 	}()
 
-	// Load registry (in production, this might be loaded once at startup)
-	registry, err := LoadRegistry("internal/config/sources.yaml")
-	if err != nil {
-		return IngestionStats{}, fmt.Errorf("failed to load registry: %w", err)
+	if errs := ValidateSource(config); len(errs) > 0 {
+		return IngestionStats{}, fmt.Errorf("source %q is invalid: %s", sourceID, strings.Join(errs, "; "))
 	}
 
-	var config *SourceConfig
-	for _, src := range registry.Sources {
-		if src.ID == sourceID {
-			config = &src
-			break
-		}
+	strategy, err := GlobalStrategyFactory.Get(config.Strategy)
+	if err != nil {
+		return IngestionStats{}, fmt.Errorf("strategy %q not found for source %q", config.Strategy, sourceID)
 	}
 
-	if config == nil {
-		return IngestionStats{}, fmt.Errorf("source id %q not found in registry", sourceID)
+	if config.BaseURL != "" {
+		p.configureFetch(config.BaseURL, config.Fetch)
 	}
 
-	strategy, err := GlobalStrategyFactory.Get(config.Strategy)
-	if err != nil {
-		return IngestionStats{}, fmt.Errorf("strategy %q not found for source %q", config.Strategy, sourceID)
+	if config.DedupByNumber {
+		ctx = context.WithValue(ctx, numberDedupKey, true)
 	}
 
 	log.Printf("Starting ingestion for source: %s (%s)", config.Name, config.ID)
 	// Update stats variable with result
-	s, err := strategy.Run(ctx, *config, p)
+	s, err := strategy.Run(ctx, config, p)
 	stats = s // capture stats for defer
+	log.Printf("[%s] Run outcome: found %d, saved %d (%d new, %d updated), skipped %d %v, errors %d",
+		sourceID, stats.TotalFound, stats.TotalSaved, stats.Inserted, stats.Updated, stats.Skipped, stats.SkipReasons, stats.Errors)
 	return stats, err
 }
 
+// runDetailsJSON is the ingest_runs.details payload: run duration plus the
+// skip breakdown, so "found 50, saved 12" can be explained after the fact.
+func runDetailsJSON(stats IngestionStats, duration time.Duration) string {
+	skipReasons := stats.SkipReasons
+	if skipReasons == nil {
+		skipReasons = map[string]int{}
+	}
+	details, _ := json.Marshal(map[string]interface{}{
+		"duration_ms":  duration.Milliseconds(),
+		"inserted":     stats.Inserted,
+		"updated":      stats.Updated,
+		"skipped":      stats.Skipped,
+		"skip_reasons": skipReasons,
+	})
+	return string(details)
+}
+
 // IngestAll triggers ingestion for ALL sources in the registry.
 func (p *Pipeline) IngestAll(ctx context.Context) (map[string]IngestionStats, error) {
-	registry, err := LoadRegistry("internal/config/sources.yaml")
+	registry, err := p.registry()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load registry: %w", err)
 	}
 
 	results := make(map[string]IngestionStats)
 
+	// Run from one snapshot so a reload mid-run doesn't mix registries.
 	for _, src := range registry.Sources {
-		stats, err := p.IngestSource(ctx, src.ID)
+		stats, err := p.ingestConfig(ctx, src)
 		if err != nil {
 			log.Printf("Error ingesting source %q: %v", src.ID, err)
 			// We continue with other sources
@@ -186,19 +283,112 @@ func (p *Pipeline) IngestAll(ctx context.Context) (map[string]IngestionStats, er
 	return results, nil
 }
 
-// SaveRaw normalizes a raw opportunity and saves it to the database.
-func (p *Pipeline) SaveRaw(ctx context.Context, raw RawOpportunity) error {
-	opp := FromRaw(raw)
-	return p.SaveOpportunity(ctx, opp)
+// SaveRaw normalizes a raw opportunity and saves it to the database,
+// reporting whether it created a row or updated an existing one. Dry runs
+// report OutcomeSaved.
+func (p *Pipeline) SaveRaw(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+	if p.Saver != nil {
+		return p.Saver(ctx, raw)
+	}
+	return p.UpsertOpportunity(ctx, FromRaw(raw))
 }
 
 func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
+	_, err := p.UpsertOpportunity(ctx, opp)
+	return err
+}
+
+// UpsertOpportunity is SaveOpportunity that also reports whether the write
+// inserted a new row (OutcomeInserted) or refreshed an existing one
+// (OutcomeUpdated).
+func (p *Pipeline) UpsertOpportunity(ctx context.Context, opp Opportunity) (SaveOutcome, error) {
+	if err := p.prepareOpportunity(ctx, &opp); err != nil {
+		return "", err
+	}
+	return p.upsertPrepared(ctx, p.DB, opp)
+}
+
+// upsertPrepared writes one prepared opportunity in its own transaction. It is
+// UpsertOpportunity's write, and saveBatch's fallback when a page's bulk
+// write fails.
+func (p *Pipeline) upsertPrepared(ctx context.Context, db txStarter, opp Opportunity) (SaveOutcome, error) {
+	query := `
+		INSERT INTO opportunities (` + opportunityUpsertColumns + `
+		) VALUES (
+			$1, $2, $3, $4, $5,
+			$6, $7, $8, $9, $10,
+			$11, $12, $13, $14, $15,
+			$16, $17, $18, $19, $20,
+			$21, $22, $23, $24, $25,
+			$26, $27, $28, $29, $30,
+			$31, $32, $33, $34,
+			$35, $36, $37, $38::jsonb, $39,
+			$40::jsonb, $41, $42, $43,
+			$44, $45, $46, $47, COALESCE($48, false),
+			$49, $50
+		)
+		` + opportunityConflictSQL
+	args := opportunityUpsertArgs(opp)
+
+	// xmax is 0 only on a freshly inserted row version. The prev CTE reads
+	// the row as it was before this statement, which tells a status change
+	// apart from a plain refresh.
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var id, previousStatus string
+	var inserted bool
+	err = tx.QueryRow(ctx, `
+		WITH prev AS (
+			SELECT normalized_status::text AS status
+			FROM opportunities
+			WHERE source_domain = $5 AND source_id = $6
+		), up AS (`+query+`
+			RETURNING id::text, (xmax = 0) AS inserted
+		)
+		SELECT up.id, up.inserted, COALESCE((SELECT status FROM prev), '') FROM up
+	`, args...).Scan(&id, &inserted, &previousStatus)
+	if err != nil {
+		return "", err
+	}
+	if numberDedupEnabled(ctx) {
+		if err := archiveNumberSiblings(ctx, tx, []Opportunity{opp}); err != nil {
+			return "", err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
+	if p.Notifier != nil {
+		if event, ok := opportunityEventFor(opp, id, inserted, previousStatus, time.Now().UTC()); ok {
+			p.Notifier.Notify(event)
+		}
+	}
+	return upsertOutcome(inserted), nil
+}
+
+func upsertOutcome(inserted bool) SaveOutcome {
+	if inserted {
+		return OutcomeInserted
+	}
+	return OutcomeUpdated
+}
+
+// prepareOpportunity runs everything SaveOpportunity does before the write:
+// cleanup, LLM and DB backfill of missing dates, embedding, evidence
+// enrichment and the status decision.
+func (p *Pipeline) prepareOpportunity(ctx context.Context, opp *Opportunity) error {
 	// 1. Normalize Data (Clean countries, funder types, text)
-	NormalizeOpportunity(&opp)
+	NormalizeOpportunity(opp)
 
-	// Sanitize all text fields to remove invalid UTF-8 sequences and strip HTML from summary/title
-	opp.Title = sanitizeUTF8(HTMLToText(opp.Title))
-	opp.Summary = sanitizeUTF8(HTMLToText(opp.Summary))
+	// Repair invalid UTF-8 before parsing, so a Latin-1 "ñ" survives as
+	// itself, then strip HTML from summary/title
+	opp.Title = HTMLToText(sanitizeUTF8(opp.Title))
+	opp.Summary = HTMLToText(sanitizeUTF8(opp.Summary))
+	opp.Description = sanitizeUTF8(opp.Description)
 
 	// Fallback: If summary is empty (or was just HTML tags), derive from description
 	if strings.TrimSpace(opp.Summary) == "" && strings.TrimSpace(opp.Description) != "" {
@@ -208,7 +398,13 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 	}
 
 	// Sanitize HTML description (remove scripts, unsafe tags)
-	opp.Description = sanitizeHTML(sanitizeUTF8(opp.Description))
+	opp.Description = sanitizeHTML(opp.Description)
+
+	// A crawl that finds the page as it was last time skips the LLM and
+	// embedding calls below. The status is still decided: it also reads the
+	// source's status and dates, which change without the prose changing.
+	opp.ContentHash = contentHash(*opp)
+	stored := p.unchangedContent(ctx, opp)
 
 	// 2. Conditional LLM Extraction (Augmentation)
 	// Logic: If critical fields are missing (Deadline), check DB first. If still missing, use LLM.
@@ -219,8 +415,7 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 
 	if needsExtraction {
 		// Optimization: Check DB first to avoid expensive LLM calls if we already have the data
-		existing, err := p.Store.GetOpportunityBySourceID(ctx, opp.SourceDomain, opp.SourceID)
-		if err == nil && existing != nil {
+		if existing := p.existingRow(ctx, opp.SourceDomain, opp.SourceID); existing != nil {
 			// Copy hard-won data from existing record
 			if existing.DeadlineAt != nil {
 				opp.DeadlineAt = existing.DeadlineAt
@@ -230,21 +425,20 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 				opp.IsRolling = true
 				needsExtraction = false
 			}
-			// If existing status is effectively closed/archived, stop trying to extract
-			if existing.OppStatus == "closed" || existing.OppStatus == "archived" {
+			// If existing status is effectively closed/archived/funded, stop trying to extract
+			if existing.OppStatus == "closed" || existing.OppStatus == "archived" || existing.OppStatus == "funded" {
 				needsExtraction = false
 			}
 		}
 
 		// If still needs extraction and AI is available
-		if needsExtraction && p.AI != nil {
+		if needsExtraction && stored == nil && p.AI.Available() {
 			log.Printf("🤖 Triggering LLM extraction for %q (Source: %s)", opp.Title, opp.SourceID)
 
-			// Prepare text context (limited length)
-			textCtx := fmt.Sprintf("%s\n%s", opp.Summary, HTMLToText(opp.Description))
-			if len(textCtx) > 8000 {
-				textCtx = textCtx[:8000]
-			}
+			// Prepare text context (limited length, keeping deadline-bearing text)
+			budget := llmContextBudget(8000)
+			textCtx := fmt.Sprintf("%s\n%s", opp.Summary, llmContextFromHTML(opp.Description, budget-len(opp.Summary)-1))
+			textCtx = truncateAtRune(textCtx, budget)
 
 			extracted, err := p.AI.ExtractOpportunityData(ctx, opp.Title, opp.ExternalURL, textCtx)
 			if err != nil {
@@ -262,8 +456,12 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 				}
 				if extracted.DeadlineISO != "" {
 					if dt, err := time.Parse("2006-01-02", extracted.DeadlineISO); err == nil {
-						// Set properly to end of day in UTC
-						dt = time.Date(dt.Year(), dt.Month(), dt.Day(), 23, 59, 59, 999000000, time.UTC)
+						// Close at the source's configured time, else end of day in UTC
+						if cutoff := p.deadlineCutoffFor(opp.ExternalURL); cutoff.set {
+							dt = normalizeDateOnlyBySource(dt, opp.ExternalURL, cutoff)
+						} else {
+							dt = time.Date(dt.Year(), dt.Month(), dt.Day(), 23, 59, 59, 999000000, time.UTC)
+						}
 						opp.DeadlineAt = &dt
 						opp.Deadlines = mergeUniqueFold(opp.Deadlines, []string{dt.Format(time.RFC3339)})
 					}
@@ -328,7 +526,7 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 
 	// Set defaults for production fields
 	if opp.SourceRunID == "" {
-		if runID, ok := ctx.Value("source_run_id").(string); ok {
+		if runID, ok := ctx.Value(runIDKey).(string); ok {
 			opp.SourceRunID = runID
 		}
 	}
@@ -342,35 +540,51 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 		opp.RawURL = opp.ExternalURL
 	}
 
-	// Generate embedding if missing
-	if len(opp.Embedding) == 0 && p.AI != nil {
-		text := fmt.Sprintf("%s\n%s", opp.Title, opp.Summary)
-		if len(text) > 8000 {
-			text = text[:8000]
-		}
-		vec, err := p.AI.GenerateEmbedding(ctx, text)
-		if err != nil {
-			log.Printf("⚠️ Failed to generate embedding for %q: %v", opp.Title, err)
+	// Generate embedding if missing; BackfillEmbeddings catches rows saved
+	// while the AI circuit was open.
+	// An unchanged page keeps its stored embedding through the upsert.
+	if len(opp.Embedding) == 0 && !(stored != nil && stored.HasEmbedding) && p.AI.Available() {
+		if page, ok := ctx.Value(pageRowsKey).(*batchPage); ok {
+			// prepareBatch embeds its whole page in one call afterwards.
+			page.deferredEmbedding = true
 		} else {
-			opp.Embedding = vec
+			vec, err := p.AI.GenerateEmbedding(ctx, embeddingText(opp.Title, opp.Summary))
+			if err != nil {
+				log.Printf("⚠️ Failed to generate embedding for %q: %v", opp.Title, err)
+			} else {
+				opp.Embedding = vec
+			}
 		}
 	}
+	p.dropMismatchedEmbedding(opp)
 
 	if strings.TrimSpace(opp.SourceID) == "" {
-		return fmt.Errorf("missing source_id (url=%s, source=%s)", opp.ExternalURL, opp.SourceDomain)
+		return &SkipError{
+			Reason: SkipMissingSourceID,
+			Err:    fmt.Errorf("missing source_id (url=%s, source=%s)", opp.ExternalURL, opp.SourceDomain),
+		}
+	}
+	if err := p.mergeIntoNumberCanonical(ctx, opp); err != nil {
+		return err
 	}
 
-	if shouldEnrichEvidence(opp) && opp.ExternalURL != "" {
-		_ = p.applyEvidenceEnrichment(ctx, &opp)
+	// A detail page the crawl already fetched is always turned into evidence:
+	// it costs no extra request and spares the enrichment batch a refetch.
+	if (shouldEnrichEvidence(*opp) || len(opp.FollowURLs) > 0 || opp.DetailPage != nil) && opp.ExternalURL != "" {
+		_ = p.applyEvidenceEnrichment(ctx, opp)
 	}
-	opp.RollingEvidence = detectRollingEvidence(opp)
+	opp.RollingEvidence = detectRollingEvidence(*opp)
 
-	statusDecision := ComputeStatusDecision(opp, time.Now().UTC())
+	now := time.Now().UTC()
+	statusDecision := ComputeStatusDecision(*opp, now)
 	opp.NormalizedStatus = statusDecision.NormalizedStatus
 	opp.StatusReason = statusDecision.StatusReason
 	opp.StatusConfidence = statusDecision.StatusConfidence
 	opp.NextDeadlineAt = statusDecision.NextDeadlineAt
 	opp.IsResultsPage = statusDecision.IsResultsPage
+	if stored != nil && opp.LastEnrichedAt == nil {
+		opp.LastEnrichedAt = &now
+	}
 
 	if opp.SourceStatusRaw == "" {
 		opp.SourceStatusRaw = opp.OppStatus
@@ -381,6 +595,7 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 	if opp.SourceEvidenceJSON == nil {
 		opp.SourceEvidenceJSON = map[string]interface{}{}
 	}
+	recordTimelineInversion(opp.SourceEvidenceJSON, statusDecision)
 	if isAPIFirstSource(opp.SourceDomain) && (opp.OpenAt != nil || opp.CloseAt != nil || opp.ExpirationAt != nil || len(opp.Deadlines) > 0) {
 		opp.SourceEvidenceJSON["authority"] = "api"
 		if opp.StatusConfidence < 0.95 {
@@ -390,12 +605,12 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 	if !opp.RollingEvidence {
 		opp.IsRolling = false
 	}
+	return nil
+}
 
-	deadlinesJSON := buildDeadlinesJSON(opp.Deadlines, opp.DeadlineEvidence, opp.ExternalURL)
-	evidenceJSON := buildEvidenceJSON(opp.SourceEvidenceJSON)
-
-	query := `
-		INSERT INTO opportunities (
+// opportunityUpsertColumns lists the columns written by SaveOpportunity and
+// BulkUpsertOpportunities, in opportunityUpsertArgs order.
+const opportunityUpsertColumns = `
 			title, summary, description_html, external_url, source_domain,
 			source_id, opportunity_number, agency_name, agency_code, funder_type,
 			amount_min, amount_max, currency, deadline_at, open_date,
@@ -404,18 +619,13 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 			source_run_id, canonical_url, raw_url, content_type, data_quality_score,
 			source_status_raw, normalized_status, status_reason, next_deadline_at,
 			expiration_at, close_at, open_at, deadlines, is_results_page,
-			source_evidence_json, status_confidence, rolling_evidence
-		) VALUES (
-			$1, $2, $3, $4, $5,
-			$6, $7, $8, $9, $10,
-			$11, $12, $13, $14, $15,
-			$16, $17, $18, $19, $20,
-			$21, $22, $23, $24, $25,
-			$26, $27, $28, $29, $30,
-			$31, $32, $33, $34,
-			$35, $36, $37, $38::jsonb, $39,
-			$40::jsonb, $41, $42
-		)
+			source_evidence_json, status_confidence, rolling_evidence, opportunity_type,
+			last_enriched_at, fetch_last_status_code, fetch_last_bytes, fetch_last_duration_ms, fetch_blocked_detected,
+			funder_type_label, content_hash`
+
+// opportunityConflictSQL merges a re-ingested row into the existing one,
+// keeping hard-won fields the new record lacks.
+const opportunityConflictSQL = `
 		ON CONFLICT (source_domain, source_id) DO UPDATE SET
 			updated_at = NOW(),
 			title = EXCLUDED.title,
@@ -428,6 +638,7 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 			open_date = COALESCE(EXCLUDED.open_date, opportunities.open_date),
 			close_date_raw = COALESCE(NULLIF(EXCLUDED.close_date_raw, ''), opportunities.close_date_raw),
 			doc_type = COALESCE(NULLIF(EXCLUDED.doc_type, ''), opportunities.doc_type),
+			opportunity_type = COALESCE(NULLIF(EXCLUDED.opportunity_type, ''), opportunities.opportunity_type),
 			opp_status = CASE 
 				-- Prevent re-opening if currently closed/archived/funded and new status is weak (posted or empty)
 				WHEN opportunities.opp_status IN ('closed', 'archived', 'funded') AND COALESCE(EXCLUDED.opp_status, 'posted') IN ('posted', '') THEN opportunities.opp_status 
@@ -435,6 +646,9 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 			END,
 			is_rolling = COALESCE(opportunities.is_rolling, false) OR COALESCE(EXCLUDED.is_rolling, false),
 			opportunity_number = COALESCE(NULLIF(EXCLUDED.opportunity_number, ''), opportunities.opportunity_number),
+			funder_type = COALESCE(EXCLUDED.funder_type, opportunities.funder_type),
+			region = COALESCE(EXCLUDED.region, opportunities.region),
+			funder_type_label = COALESCE(EXCLUDED.funder_type_label, opportunities.funder_type_label),
 			categories = COALESCE(NULLIF(EXCLUDED.categories, '{}'::text[]), opportunities.categories),
 			eligibility = COALESCE(NULLIF(EXCLUDED.eligibility, '{}'::text[]), opportunities.eligibility),
 			cfda_list = COALESCE(NULLIF(EXCLUDED.cfda_list, '{}'::text[]), opportunities.cfda_list),
@@ -455,15 +669,31 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 			is_results_page = EXCLUDED.is_results_page,
 			source_evidence_json = COALESCE(EXCLUDED.source_evidence_json, opportunities.source_evidence_json),
 			status_confidence = GREATEST(COALESCE(EXCLUDED.status_confidence, 0), COALESCE(opportunities.status_confidence, 0)),
-			rolling_evidence = COALESCE(EXCLUDED.rolling_evidence, opportunities.rolling_evidence)
-	`
+			rolling_evidence = COALESCE(EXCLUDED.rolling_evidence, opportunities.rolling_evidence),
+			last_enriched_at = COALESCE(EXCLUDED.last_enriched_at, opportunities.last_enriched_at),
+			content_hash = COALESCE(EXCLUDED.content_hash, opportunities.content_hash),
+			fetch_last_status_code = COALESCE(EXCLUDED.fetch_last_status_code, opportunities.fetch_last_status_code),
+			fetch_last_bytes = COALESCE(EXCLUDED.fetch_last_bytes, opportunities.fetch_last_bytes),
+			fetch_last_duration_ms = COALESCE(EXCLUDED.fetch_last_duration_ms, opportunities.fetch_last_duration_ms),
+			fetch_blocked_detected = CASE WHEN EXCLUDED.last_enriched_at IS NULL THEN opportunities.fetch_blocked_detected ELSE EXCLUDED.fetch_blocked_detected END
+`
+
+// opportunityUpsertArgs returns the values for opportunityUpsertColumns.
+func opportunityUpsertArgs(opp Opportunity) []interface{} {
+	deadlinesJSON := buildDeadlinesJSON(opp.Deadlines, opp.DeadlineEvidence, opp.ExternalURL)
+	evidenceJSON := buildEvidenceJSON(opp.SourceEvidenceJSON)
+	var fetchStatusCode, fetchBytes, fetchDurationMs *int
+	var fetchBlocked *bool
+	if opp.LastEnrichedAt != nil {
+		fetchStatusCode, fetchBytes, fetchDurationMs, fetchBlocked = extractFetchMeta(opp.SourceEvidenceJSON)
+	}
 
 	var embedding interface{}
 	if len(opp.Embedding) > 0 {
 		embedding = pgvector.NewVector(opp.Embedding)
 	}
 
-	_, err := p.DB.Exec(ctx, query,
+	return []interface{}{
 		opp.Title,                         // $1
 		opp.Summary,                       // $2
 		opp.Description,                   // $3
@@ -506,8 +736,15 @@ func (p *Pipeline) SaveOpportunity(ctx context.Context, opp Opportunity) error {
 		evidenceJSON,                      // $40
 		opp.StatusConfidence,              // $41
 		opp.RollingEvidence,               // $42
-	)
-	return err
+		nilIfEmpty(opp.Type),              // $43
+		opp.LastEnrichedAt,                // $44
+		fetchStatusCode,                   // $45
+		fetchBytes,                        // $46
+		fetchDurationMs,                   // $47
+		fetchBlocked,                      // $48
+		nilIfEmpty(opp.FunderTypeLabel),   // $49
+		nilIfEmpty(opp.ContentHash),       // $50
+	}
 }
 
 func buildDeadlinesJSON(deadlines []string, evidence []DeadlineEvidence, fallbackURL string) interface{} {
@@ -612,13 +849,35 @@ func nilIfEmpty(s string) interface{} {
 	return s
 }
 
-// sanitizeUTF8 removes invalid UTF-8 byte sequences that cause PostgreSQL errors.
+// derefString returns the value of a nullable text column, "" for NULL.
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// sanitizeUTF8 repairs invalid UTF-8, which PostgreSQL rejects. Stray bytes
+// almost always come from a Latin-1/Windows-1252 page mislabelled as UTF-8,
+// so each one is decoded as Windows-1252 ("Espa\xf1a" becomes "España")
+// rather than dropped. NUL, which PostgreSQL text can't hold, is removed.
 func sanitizeUTF8(s string) string {
-	if utf8.ValidString(s) {
+	if utf8.ValidString(s) && !strings.ContainsRune(s, 0) {
 		return s
 	}
-	v := strings.ToValidUTF8(s, "")
-	return v
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(charmap.Windows1252.DecodeByte(s[i]))
+		case r != 0:
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
 }
 
 // sanitizeHTML uses bluemonday to strip unsafe tags and attributes from HTML.
@@ -648,97 +907,141 @@ func findSubstring(s, substr string) int {
 	return -1
 }
 
-// RefineAllData iterates over all existing opportunities, applies normalization, and re-saves them.
-func (p *Pipeline) RefineAllData(ctx context.Context) (int, error) {
-	// 1. Fetch all IDs
-	rows, err := p.DB.Query(ctx, "SELECT id FROM opportunities")
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch IDs: %w", err)
+// defaultRefineBatchSize is how many rows RefineAllData loads per query.
+const defaultRefineBatchSize = 200
+
+// RefineStats summarizes a RefineAllData run.
+type RefineStats struct {
+	Processed        int    `json:"processed"`
+	Updated          int    `json:"updated"`
+	Failed           int    `json:"failed"`
+	EmbeddingsReused int    `json:"embeddings_reused"`
+	Batches          int    `json:"batches"`
+	LastID           string `json:"last_id,omitempty"`
+}
+
+// refineBatchSQL loads one keyset page of rows for RefineAllData. The stored
+// embedding comes along so re-saving doesn't embed the row again.
+const refineBatchSQL = `
+	SELECT id::text, title, COALESCE(summary, ''), COALESCE(description_html, ''), external_url, source_domain,
+	       source_id, opportunity_number, agency_name, agency_code, funder_type,
+	       amount_min, amount_max, currency, deadline_at, open_date, is_rolling, doc_type, cfda_list, opp_status,
+	       region, country, categories, eligibility, close_date_raw, embedding
+	FROM opportunities
+	WHERE ($1 = '' OR id::text > $1)
+	ORDER BY id::text
+	LIMIT $2`
+
+// RefineAllData re-normalizes and re-saves every opportunity, walking the
+// table in id keyset order batchSize rows at a time so memory stays bounded
+// by one batch. Rows that already have an embedding keep it. Cancelling ctx
+// stops the run after the current row; the returned stats say how far it got.
+func (p *Pipeline) RefineAllData(ctx context.Context, batchSize int, progress ProgressFunc) (RefineStats, error) {
+	stats := RefineStats{}
+	if batchSize <= 0 {
+		batchSize = defaultRefineBatchSize
 	}
-	defer rows.Close()
 
-	var ids []string
-	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err == nil {
-			ids = append(ids, id)
+	// The total is only an estimate for progress reporting.
+	total := 0
+	if progress != nil {
+		if err := p.DB.QueryRow(ctx, `SELECT COUNT(*) FROM opportunities`).Scan(&total); err != nil {
+			log.Printf("[refine] count for progress failed: %v", err)
 		}
 	}
-	rows.Close()
 
-	log.Printf("Refining %d records...", len(ids))
-	updated := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		batch, ids, err := p.loadRefineBatch(ctx, stats.LastID, batchSize)
+		if err != nil {
+			return stats, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		stats.Batches++
+
+		for i := range batch {
+			if err := ctx.Err(); err != nil {
+				return stats, err
+			}
+			opp := batch[i]
+			if len(opp.Embedding) > 0 {
+				stats.EmbeddingsReused++
+			}
+
+			// AI Status Refinement
+			p.refineGrantStatus(ctx, &opp)
+
+			// SaveOpportunity will call NormalizeOpportunity internally
+			if err := p.SaveOpportunity(ctx, opp); err != nil {
+				log.Printf("Failed to update %s: %v", ids[i], err)
+				stats.Failed++
+			} else {
+				stats.Updated++
+			}
+			stats.Processed++
+			stats.LastID = ids[i]
+		}
+
+		log.Printf("[refine] Refined %d/%d", stats.Processed, total)
+		progress.report(stats.Processed, total, stats.Updated, nil)
+	}
+
+	return stats, nil
+}
 
-	// 2. Query all necessary fields directly
-	// We scan directly into variables to populate ingest.Opportunity
-	// Note: We need description and embedding which are not in models.Opportunity
-	query := "SELECT title, summary, COALESCE(description_html, ''), external_url, source_domain, source_id, opportunity_number, agency_name, agency_code, funder_type, amount_min, amount_max, currency, deadline_at, open_date, is_rolling, doc_type, cfda_list, opp_status, region, country, categories, eligibility, close_date_raw FROM opportunities WHERE id = $1"
+// loadRefineBatch reads up to limit rows after afterID, returning them with
+// their ids in the same order.
+func (p *Pipeline) loadRefineBatch(ctx context.Context, afterID string, limit int) ([]Opportunity, []string, error) {
+	rows, err := p.DB.Query(ctx, refineBatchSQL, afterID, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refine query failed: %w", err)
+	}
+	defer rows.Close()
 
-	for _, id := range ids {
+	var batch []Opportunity
+	var ids []string
+	for rows.Next() {
+		var id string
 		var opp Opportunity
 		var sourceID, oppNum, agencyName, agencyCode, funderType, docType, oppStatus, region, country, closeDateRaw *string
+		var embedding *pgvector.Vector
 
-		err := p.DB.QueryRow(ctx, query, id).Scan(
-			&opp.Title, &opp.Summary, &opp.Description, &opp.ExternalURL, &opp.SourceDomain,
+		if err := rows.Scan(
+			&id, &opp.Title, &opp.Summary, &opp.Description, &opp.ExternalURL, &opp.SourceDomain,
 			&sourceID, &oppNum, &agencyName, &agencyCode, &funderType,
 			&opp.AmountMin, &opp.AmountMax, &opp.Currency, &opp.DeadlineAt, &opp.OpenDate,
 			&opp.IsRolling, &docType, &opp.CfdaList, &oppStatus,
-			&region, &country, &opp.Categories, &opp.Eligibility, &closeDateRaw,
-		)
-
-		if err != nil {
-			log.Printf("Skipping %s (scan error): %v", id, err)
-			continue
+			&region, &country, &opp.Categories, &opp.Eligibility, &closeDateRaw, &embedding,
+		); err != nil {
+			return nil, nil, fmt.Errorf("refine scan failed for %s: %w", id, err)
 		}
 
 		// Handle nullable strings
-		if sourceID != nil {
-			opp.SourceID = *sourceID
-		}
-		if oppNum != nil {
-			opp.OpportunityNumber = *oppNum
-		}
-		if agencyName != nil {
-			opp.AgencyName = *agencyName
+		opp.SourceID = derefString(sourceID)
+		opp.OpportunityNumber = derefString(oppNum)
+		opp.AgencyName = derefString(agencyName)
+		opp.AgencyCode = derefString(agencyCode)
+		opp.FunderType = derefString(funderType)
+		opp.DocType = derefString(docType)
+		opp.OppStatus = derefString(oppStatus)
+		opp.Region = derefString(region)
+		opp.Country = derefString(country)
+		opp.CloseDateRaw = derefString(closeDateRaw)
+		if embedding != nil {
+			opp.Embedding = embedding.Slice()
 		}
-		if agencyCode != nil {
-			opp.AgencyCode = *agencyCode
-		}
-		if funderType != nil {
-			opp.FunderType = *funderType
-		}
-		if docType != nil {
-			opp.DocType = *docType
-		}
-		if oppStatus != nil {
-			opp.OppStatus = *oppStatus
-		}
-		if region != nil {
-			opp.Region = *region
-		}
-		if country != nil {
-			opp.Country = *country
-		}
-		if closeDateRaw != nil {
-			opp.CloseDateRaw = *closeDateRaw
-		}
-
-		// AI Status Refinement
-		p.refineGrantStatus(ctx, &opp)
 
-		// saveOpportunity will call NormalizeOpportunity internally
-		if err := p.SaveOpportunity(ctx, opp); err != nil {
-			log.Printf("Failed to update %s: %v", id, err)
-		} else {
-			updated++
-		}
-
-		if updated%100 == 0 {
-			log.Printf("Refined %d/%d", updated, len(ids))
-		}
+		batch = append(batch, opp)
+		ids = append(ids, id)
 	}
-
-	return updated, nil
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("refine query failed: %w", err)
+	}
+	return batch, ids, nil
 }
 
 // RefineGrantStatus uses LLM to check status if ambiguous
@@ -753,11 +1056,11 @@ func (p *Pipeline) refineGrantStatus(ctx context.Context, opp *Opportunity) {
 	// 1. Status is "posted" (default)
 	// 2. No future deadline
 	// 3. Not rolling (rolling usually implies open)
-	// 4. We have an AI client
+	// 4. The AI client is available (breaker closed)
 	if opp.OppStatus == "posted" &&
 		(opp.DeadlineAt == nil || opp.DeadlineAt.Before(time.Now())) &&
 		!opp.IsRolling &&
-		p.AI != nil {
+		p.AI.Available() {
 
 		log.Printf("Analyzing status for ambiguous grant: %s", opp.Title)
 		// Use Description if available, otherwise Summary
@@ -773,15 +1076,127 @@ func (p *Pipeline) refineGrantStatus(ctx context.Context, opp *Opportunity) {
 	}
 }
 
-func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[string]int, int, error) {
+// Progress is a snapshot of a long-running loop, reported as it goes so admin
+// jobs can show live counts.
+type Progress struct {
+	Processed int `json:"processed"`
+	// TotalEstimate is the row count taken when the loop started. Rows can
+	// change mid-run, so Processed may overshoot it.
+	TotalEstimate int            `json:"total_estimate"`
+	Percent       float64        `json:"percent"`
+	Updated       int            `json:"updated"`
+	StatusCounts  map[string]int `json:"status_counts,omitempty"`
+}
+
+// progressPercent returns processed/total as a percentage clamped to
+// [0, 100], or 0 when the total is unknown.
+func progressPercent(processed, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	pct := float64(processed) * 100 / float64(total)
+	if pct > 100 {
+		return 100
+	}
+	return math.Round(pct*10) / 10
+}
+
+// ProgressFunc receives Progress snapshots. It runs on the loop's goroutine,
+// so it must not block. A nil ProgressFunc is allowed.
+type ProgressFunc func(Progress)
+
+func (f ProgressFunc) report(processed, total, updated int, counts map[string]int) {
+	if f == nil {
+		return
+	}
+	snapshot := make(map[string]int, len(counts))
+	for k, v := range counts {
+		snapshot[k] = v
+	}
+	f(Progress{
+		Processed:     processed,
+		TotalEstimate: total,
+		Percent:       progressPercent(processed, total),
+		Updated:       updated,
+		StatusCounts:  snapshot,
+	})
+}
+
+// recomputeWatermarkJob names the job_watermarks row for status recompute.
+const recomputeWatermarkJob = "recompute_status"
+
+// RecomputeStatuses re-runs the status engine over every row in batches of
+// batchSize, reporting progress after each batch.
+func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int, progress ProgressFunc) (map[string]int, int, error) {
+	return p.recomputeStatuses(ctx, nil, batchSize, progress)
+}
+
+// RecomputeStatusesSince is the incremental RecomputeStatuses: it only
+// revisits rows that may have changed status since since (see
+// recomputeScope).
+func (p *Pipeline) RecomputeStatusesSince(ctx context.Context, since time.Time, batchSize int, progress ProgressFunc) (map[string]int, int, error) {
+	return p.recomputeStatuses(ctx, &since, batchSize, progress)
+}
+
+// LastRecomputeAt returns the start time of the last successful recompute,
+// or nil when none has been recorded.
+func (p *Pipeline) LastRecomputeAt(ctx context.Context) (*time.Time, error) {
+	var watermark time.Time
+	err := p.DB.QueryRow(ctx, `SELECT watermark FROM job_watermarks WHERE job = $1`, recomputeWatermarkJob).Scan(&watermark)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &watermark, nil
+}
+
+// recomputeScope narrows a recompute to rows whose status may have flipped
+// since the watermark: a date column crossed into [since, now], or the row
+// was re-ingested or enriched after it. since binds to $argIdx; a nil since
+// means a full scan.
+func recomputeScope(since *time.Time, argIdx int) (string, []interface{}) {
+	if since == nil {
+		return "", nil
+	}
+	clause := fmt.Sprintf(`
+			  AND (updated_at > $%[1]d
+			       OR last_enriched_at > $%[1]d
+			       OR next_deadline_at BETWEEN $%[1]d AND NOW()
+			       OR deadline_at BETWEEN $%[1]d AND NOW()
+			       OR close_at BETWEEN $%[1]d AND NOW()
+			       OR expiration_at BETWEEN $%[1]d AND NOW()
+			       OR open_at BETWEEN $%[1]d AND NOW())`, argIdx)
+	return clause, []interface{}{*since}
+}
+
+func (p *Pipeline) recomputeStatuses(ctx context.Context, since *time.Time, batchSize int, progress ProgressFunc) (map[string]int, int, error) {
 	if batchSize <= 0 {
 		batchSize = 500
 	}
+	// Rows changed while this run is in flight are picked up next time.
+	startedAt := time.Now().UTC()
 
 	updated := 0
+	processed := 0
 	counts := map[string]int{}
 	lastID := ""
 
+	// The total is only an estimate for progress reporting, so a failed count
+	// isn't fatal.
+	total := 0
+	if progress != nil {
+		scope, scopeArgs := recomputeScope(since, 1)
+		if err := p.DB.QueryRow(ctx, `
+			SELECT COUNT(*) FROM opportunities
+			WHERE `+recomputeSkipsReasonSQL+scope,
+			scopeArgs...).Scan(&total); err != nil {
+			log.Printf("[recompute] count for progress failed: %v", err)
+		}
+	}
+
+	scope, scopeArgs := recomputeScope(since, 3)
 	for {
 		rows, err := p.DB.Query(ctx, `
 			SELECT id::text, title, COALESCE(summary,''), COALESCE(description_html,''), external_url,
@@ -791,9 +1206,10 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 			       COALESCE(source_evidence_json, '{}'::jsonb)
 			FROM opportunities
 			WHERE ($1 = '' OR id::text > $1)
+			  AND `+recomputeSkipsReasonSQL+scope+`
 			ORDER BY id::text
 			LIMIT $2
-		`, lastID, batchSize)
+		`, append([]interface{}{lastID, batchSize}, scopeArgs...)...)
 		if err != nil {
 			return counts, updated, fmt.Errorf("recompute status query failed: %w", err)
 		}
@@ -828,8 +1244,9 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 			decision := ComputeStatusDecision(opp, time.Now().UTC())
 
 			// LLM fallback: if the rule engine can't decide (needs_review),
-			// use the LLM to classify the grant status.
-			if decision.NormalizedStatus == "needs_review" && p.AI != nil {
+			// use the LLM to classify the grant status. A swapped open/close
+			// pair is a parse problem the title can't settle, so it stays.
+			if decision.NormalizedStatus == "needs_review" && decision.TimelineInversion == nil && p.AI.Available() {
 				llmCtx, llmCancel := context.WithTimeout(ctx, 60*time.Second)
 				llmStatus, llmErr := ai.AnalyzeStatus(llmCtx, p.AI, opp.Title, opp.Summary)
 				llmCancel()
@@ -847,12 +1264,26 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 						decision.NormalizedStatus = "upcoming"
 						decision.StatusReason = "llm_classified_upcoming"
 						decision.StatusConfidence = 0.6
+					case "funded":
+						decision.NormalizedStatus = "funded"
+						decision.StatusReason = "llm_classified_funded"
+						decision.StatusConfidence = 0.6
 					}
 				} else if llmErr != nil {
 					log.Printf("[recompute] LLM classify failed for %s: %v", id, llmErr)
 				}
 			}
 
+			if opp.SourceEvidenceJSON == nil {
+				opp.SourceEvidenceJSON = map[string]interface{}{}
+			}
+			recordTimelineInversion(opp.SourceEvidenceJSON, decision)
+			evidenceJSON, err := json.Marshal(opp.SourceEvidenceJSON)
+			if err != nil {
+				rows.Close()
+				return counts, updated, fmt.Errorf("recompute evidence encode failed: %w", err)
+			}
+
 			rollingEvidence := detectRollingEvidence(opp)
 			normalizedCloseAt := opp.CloseAt
 			if opp.CloseAt != nil && !opp.CloseAt.After(time.Now().UTC()) && decision.NextDeadlineAt != nil && decision.NextDeadlineAt.After(time.Now().UTC()) {
@@ -867,7 +1298,8 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 				    is_results_page = $4,
 				    status_confidence = $5,
 				    rolling_evidence = $6,
-				    close_at = $7
+				    close_at = $7,
+				    source_evidence_json = $9::jsonb
 				WHERE id = $8
 				  AND (
 				      normalized_status::text IS DISTINCT FROM $1
@@ -877,8 +1309,9 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 				      OR status_confidence IS DISTINCT FROM $5
 				      OR rolling_evidence IS DISTINCT FROM $6
 				      OR close_at IS DISTINCT FROM $7
+				      OR COALESCE(source_evidence_json, '{}'::jsonb) IS DISTINCT FROM $9::jsonb
 				  )
-			`, decision.NormalizedStatus, nilIfEmpty(decision.StatusReason), decision.NextDeadlineAt, decision.IsResultsPage, decision.StatusConfidence, rollingEvidence, normalizedCloseAt, id)
+			`, decision.NormalizedStatus, nilIfEmpty(decision.StatusReason), decision.NextDeadlineAt, decision.IsResultsPage, decision.StatusConfidence, rollingEvidence, normalizedCloseAt, id, string(evidenceJSON))
 			if err != nil {
 				rows.Close()
 				return counts, updated, fmt.Errorf("recompute status update failed: %w", err)
@@ -895,6 +1328,15 @@ func (p *Pipeline) RecomputeStatuses(ctx context.Context, batchSize int) (map[st
 		if batchRows == 0 {
 			break
 		}
+		processed += batchRows
+		progress.report(processed, total, updated, counts)
+	}
+
+	if _, err := p.DB.Exec(ctx, `
+		INSERT INTO job_watermarks (job, watermark) VALUES ($1, $2)
+		ON CONFLICT (job) DO UPDATE SET watermark = EXCLUDED.watermark, updated_at = NOW()
+	`, recomputeWatermarkJob, startedAt); err != nil {
+		log.Printf("[recompute] failed to store watermark: %v", err)
 	}
 
 	return counts, updated, nil
@@ -953,6 +1395,163 @@ func (p *Pipeline) BackfillCleanArrays(ctx context.Context) (int, error) {
 	return updated, nil
 }
 
+// staleArchivedReason is the status_reason ArchiveStale writes. Recompute
+// leaves these rows alone; a later re-ingest revives them.
+const staleArchivedReason = "stale_auto_archived"
+
+// recomputeSkipsReasonSQL keeps recompute away from rows archived by an admin
+// action (ArchiveStale, PurgeSource) or a number merge rather than by the
+// status engine.
+const recomputeSkipsReasonSQL = `COALESCE(status_reason, '') NOT IN ('` + staleArchivedReason + `', '` + sourceRetiredReason + `', '` + mergedDuplicateReason + `')`
+
+// ArchiveStale archives open/needs_review rows that have no next deadline,
+// are not rolling, and were last updated more than olderThan ago.
+func (p *Pipeline) ArchiveStale(ctx context.Context, olderThan time.Duration) (int, error) {
+	if olderThan <= 0 {
+		return 0, fmt.Errorf("archive stale: olderThan must be positive")
+	}
+	cutoff := time.Now().UTC().Add(-olderThan)
+
+	tag, err := p.DB.Exec(ctx, `
+		UPDATE opportunities
+		SET normalized_status = 'archived'::normalized_status_enum,
+		    status_reason = $1
+		WHERE normalized_status::text IN ('open', 'needs_review')
+		  AND next_deadline_at IS NULL
+		  AND COALESCE(is_rolling, false) = false
+		  AND COALESCE(rolling_evidence, false) = false
+		  AND updated_at < $2
+	`, staleArchivedReason, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("archive stale failed: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// embeddingText is the text embedded for semantic search: title and summary,
+// capped so long summaries stay within the embedding model's context.
+func embeddingText(title, summary string) string {
+	text := fmt.Sprintf("%s\n%s", title, summary)
+	text = truncateAtRune(text, 8000)
+	return text
+}
+
+type EmbeddingBackfillStats struct {
+	ItemsScanned int    `json:"items_scanned"`
+	ItemsUpdated int    `json:"items_updated"`
+	ItemsFailed  int    `json:"items_failed"`
+	Batches      int    `json:"batches"`
+	LastID       string `json:"last_id,omitempty"`
+}
+
+// BackfillEmbeddings fills in missing embeddings for rows saved while the AI
+// client was unavailable. Rows are walked in id keyset order starting after
+// afterID, so an interrupted run can resume from the returned LastID. LastID
+// stops advancing at the first failed batch so a resume retries its rows. A
+// failed batch is counted and skipped; once the AI circuit breaker opens, the
+// run stops with an error instead of walking the rest of the table.
+func (p *Pipeline) BackfillEmbeddings(ctx context.Context, batchSize int, afterID string) (EmbeddingBackfillStats, error) {
+	stats := EmbeddingBackfillStats{LastID: afterID}
+	if !p.AI.Available() {
+		return stats, fmt.Errorf("embedding backfill requires an available AI client")
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	lastID := afterID
+	batchFailed := false
+	for {
+		rows, err := p.DB.Query(ctx, `
+			SELECT id::text, title, COALESCE(summary,'')
+			FROM opportunities
+			WHERE embedding IS NULL
+			  AND ($1 = '' OR id::text > $1)
+			ORDER BY id::text
+			LIMIT $2
+		`, lastID, batchSize)
+		if err != nil {
+			return stats, fmt.Errorf("embedding backfill query failed: %w", err)
+		}
+
+		var ids, texts []string
+		for rows.Next() {
+			var id, title, summary string
+			if err := rows.Scan(&id, &title, &summary); err != nil {
+				rows.Close()
+				return stats, fmt.Errorf("embedding backfill scan failed: %w", err)
+			}
+			ids = append(ids, id)
+			texts = append(texts, embeddingText(title, summary))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return stats, fmt.Errorf("embedding backfill query failed: %w", err)
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		stats.Batches++
+		stats.ItemsScanned += len(ids)
+		lastID = ids[len(ids)-1]
+
+		vectors, err := p.AI.GenerateEmbeddings(ctx, texts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return stats, ctx.Err()
+			}
+			stats.ItemsFailed += len(ids)
+			if !p.AI.Available() {
+				return stats, fmt.Errorf("embedding backfill batch starting at %s failed: %w", ids[0], err)
+			}
+			log.Printf("[embedding-backfill] batch starting at %s failed: %v", ids[0], err)
+			batchFailed = true
+			continue
+		}
+
+		for i, id := range ids {
+			if len(vectors[i]) == 0 {
+				stats.ItemsFailed++
+				continue
+			}
+			if err := p.AI.CheckEmbedding(vectors[i]); err != nil {
+				log.Printf("[embedding-backfill] skipping %s: %v", id, err)
+				stats.ItemsFailed++
+				continue
+			}
+			tag, err := p.DB.Exec(ctx, `
+				UPDATE opportunities
+				SET embedding = $1
+				WHERE id = $2 AND embedding IS NULL
+			`, pgvector.NewVector(vectors[i]), id)
+			if err != nil {
+				return stats, fmt.Errorf("embedding backfill update failed: %w", err)
+			}
+			if tag.RowsAffected() > 0 {
+				stats.ItemsUpdated++
+			}
+		}
+		if !batchFailed {
+			stats.LastID = lastID
+		}
+	}
+
+	return stats, nil
+}
+
+// dropMismatchedEmbedding clears an embedding whose length does not fit the
+// vector column so the row still saves instead of failing the insert.
+func (p *Pipeline) dropMismatchedEmbedding(opp *Opportunity) {
+	if len(opp.Embedding) == 0 || p.AI == nil {
+		return
+	}
+	if err := p.AI.CheckEmbedding(opp.Embedding); err != nil {
+		log.Printf("⚠️ Dropping embedding for %q: %v", opp.Title, err)
+		opp.Embedding = nil
+	}
+}
+
 func shouldEnrichEvidence(opp Opportunity) bool {
 	return !opp.RollingEvidence && opp.NextDeadlineAt == nil && opp.CloseAt == nil && opp.DeadlineAt == nil
 }
@@ -970,9 +1569,32 @@ func isAPIFirstSource(domain string) bool {
 
 func (p *Pipeline) applyEvidenceEnrichment(ctx context.Context, opp *Opportunity) error {
 	adapter := NewGenericSourceAdapter(p.Fetcher)
-	raw, err := adapter.FetchOpportunityRaw(ctx, opp.ExternalURL)
-	if err != nil {
-		return err
+	// A page crawled this run already carries its source's attachment rules;
+	// rows enriched later from the database find them by host, along with
+	// the source's fetch settings when no run has registered them.
+	if registry, err := p.registry(); err == nil {
+		if src := registry.SourceForURL(opp.ExternalURL); src != nil {
+			adapter.Attachments = src.Detail.Attachments
+			adapter.DeadlineCutoff = src.Detail.Parse.deadlineCutoff()
+			adapter.DateLocales = src.Detail.Parse.DateLocales
+			if f, ok := p.Fetcher.(*RateLimitedFetcher); ok && !f.hasDomainConfig(opp.ExternalURL) {
+				f.SetDomainConfig(opp.ExternalURL, src.Fetch)
+			}
+		}
+	}
+	adapter.BlockedMaxBytes = p.blockedMaxBytes(opp.ExternalURL)
+	raw := opp.DetailPage
+	if raw == nil {
+		var err error
+		raw, err = adapter.FetchOpportunityRaw(ctx, opp.ExternalURL)
+		if err != nil {
+			return err
+		}
+	} else {
+		adapter.FetchAttachments(ctx, raw)
+	}
+	if len(opp.FollowURLs) > 0 {
+		adapter.FetchFollowLinks(ctx, raw, opp.FollowURLs)
 	}
 
 	candidates, err := adapter.ExtractCandidates(raw)
@@ -995,18 +1617,22 @@ func (p *Pipeline) applyEvidenceEnrichment(ctx context.Context, opp *Opportunity
 			if !ok {
 				continue
 			}
-			label := strings.ToLower(ev.Label + " " + ev.Snippet)
-			if (strings.Contains(label, "inicio") || strings.Contains(label, "opening") || strings.Contains(label, "open")) && opp.OpenAt == nil {
-				t := parsed.UTC()
-				opp.OpenAt = &t
-			}
-			if strings.Contains(label, "cierre") || strings.Contains(label, "deadline") || strings.Contains(label, "closes") || strings.Contains(label, "fecha máxima") {
+			switch evidenceRole(ev) {
+			case "open":
+				if opp.OpenAt == nil {
+					t := parsed.UTC()
+					opp.OpenAt = &t
+				}
+			case "close":
 				if opp.CloseAt == nil || parsed.UTC().Before(*opp.CloseAt) {
 					t := parsed.UTC()
 					opp.CloseAt = &t
 				}
 			}
 		}
+		if openAt, _, ok := openClosePair(candidates.DeadlineEvidence); ok && opp.OpenAt == nil {
+			opp.OpenAt = &openAt
+		}
 		if opp.DeadlineAt == nil {
 			if parsed, ok := parseDeadlineCandidate(candidates.DeadlineCandidates[0]); ok {
 				opp.DeadlineAt = &parsed
@@ -1029,6 +1655,8 @@ func (p *Pipeline) applyEvidenceEnrichment(ctx context.Context, opp *Opportunity
 	if candidates.StatusConfidence > opp.StatusConfidence {
 		opp.StatusConfidence = candidates.StatusConfidence
 	}
+	enrichedAt := time.Now().UTC()
+	opp.LastEnrichedAt = &enrichedAt
 
 	return nil
 }
@@ -1039,9 +1667,79 @@ type EnrichmentStats struct {
 	PDFsParsed     int `json:"pdfs_parsed"`
 	DeadlinesAdded int `json:"deadlines_added"`
 	StatusChanges  int `json:"status_changes"`
+	// TotalEstimate is only counted when a progress func is passed.
+	TotalEstimate int `json:"total_estimate"`
+}
+
+// enrichProgressEvery is how many enriched rows pass between progress reports;
+// each row is a network fetch, so reports are more frequent than recompute's.
+const enrichProgressEvery = 10
+
+// enrichCandidateQuery selects enrichment candidates: rows whose date could
+// not be parsed first, then the least recently updated. Ordering on the
+// priority expression directly forced a scan and sort of every matching
+// row; each UNION ALL branch instead walks an index in updated_at order and
+// stops after $4 rows, leaving only 2*$4 ids to sort. A per-domain run (the
+// enrich_batch and enrich_recompute tools go domain by domain) walks
+// idx_opp_domain_updated_at, or idx_opp_unparsed_domain_updated_at for the
+// first branch; an all-domains run walks idx_opp_updated_at, and its first
+// branch sorts the few unparsed rows from the partial index.
+func enrichCandidateQuery(where string) string {
+	return `
+		SELECT id::text, title, COALESCE(summary,''), COALESCE(description_html,''), external_url,
+		       source_domain, source_id, is_rolling, rolling_evidence, COALESCE(opp_status,''), COALESCE(source_status_raw,''),
+		       normalized_status::text, COALESCE(status_reason,''),
+		       deadline_at, next_deadline_at, close_at, expiration_at, COALESCE(deadlines, '[]'::jsonb),
+		       COALESCE(source_evidence_json, '{}'::jsonb), COALESCE(status_confidence, 0)
+		FROM opportunities
+		JOIN (
+			(SELECT id AS candidate_id, 0 AS priority, updated_at AS candidate_updated_at
+			 FROM opportunities ` + where + `
+			   AND status_reason = 'open_source_no_date_parsed'
+			 ORDER BY updated_at ASC
+			 LIMIT $4)
+			UNION ALL
+			(SELECT id, 1, updated_at
+			 FROM opportunities ` + where + `
+			   AND status_reason IS DISTINCT FROM 'open_source_no_date_parsed'
+			 ORDER BY updated_at ASC
+			 LIMIT $4)
+		) candidates ON candidates.candidate_id = opportunities.id
+		ORDER BY candidates.priority, candidates.candidate_updated_at ASC
+		LIMIT $4
+	`
 }
 
-func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyMissingDeadlines bool, batchSize int, maxItems int, confidenceThreshold float64) (EnrichmentStats, error) {
+// enrichCandidateWhere is the WHERE clause selecting enrichment candidates,
+// with $1 the domain ("" for all), $2 the confidence threshold and $3 the
+// re-enrichment interval. The domain test is spelled out per call rather than
+// as one OR covering both cases, which a generic plan cannot match to an
+// index.
+func enrichCandidateWhere(domain string, onlyMissingDeadlines bool) string {
+	domainFilter := "source_domain = $1"
+	if domain == "" {
+		domainFilter = "$1 = ''" // always true; keeps $1 bound
+	}
+	eligible := `
+				(normalized_status IN ('open', 'needs_review') AND next_deadline_at IS NULL AND rolling_evidence = false)
+				OR COALESCE(status_reason,'') IN ('rolling_without_evidence', 'missing_deadline', 'open_source_no_date_parsed', 'inconsistent_dates', 'inconsistent_open_close')
+				OR COALESCE(status_confidence, 0) < $2
+				OR COALESCE(last_enriched_at, 'epoch'::timestamptz) < NOW() - $3::interval`
+	if !onlyMissingDeadlines {
+		eligible = `
+				normalized_status IN ('open', 'needs_review')
+				OR COALESCE(status_reason,'') IN ('rolling_without_evidence', 'missing_deadline', 'open_source_no_date_parsed', 'inconsistent_dates', 'inconsistent_open_close')
+				OR COALESCE(status_confidence, 0) < $2
+				OR COALESCE(last_enriched_at, 'epoch'::timestamptz) < NOW() - $3::interval`
+	}
+	return `
+		WHERE ` + domainFilter + `
+		  AND (` + eligible + `
+		  )
+	`
+}
+
+func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyMissingDeadlines bool, batchSize int, maxItems int, confidenceThreshold float64, progress ProgressFunc) (EnrichmentStats, error) {
 	stats := EnrichmentStats{}
 	if batchSize <= 0 {
 		batchSize = 200
@@ -1054,45 +1752,22 @@ func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyM
 	}
 	ttlInterval := domainTTLIntervalLiteral(domain)
 
-	query := `
-		SELECT id::text, title, COALESCE(summary,''), COALESCE(description_html,''), external_url,
-		       source_domain, source_id, is_rolling, rolling_evidence, COALESCE(opp_status,''), COALESCE(source_status_raw,''),
-		       normalized_status::text, COALESCE(status_reason,''),
-		       deadline_at, next_deadline_at, close_at, expiration_at, COALESCE(deadlines, '[]'::jsonb),
-		       COALESCE(source_evidence_json, '{}'::jsonb), COALESCE(status_confidence, 0)
-		FROM opportunities
-		WHERE ($1 = '' OR source_domain = $1)
-		  AND (
-				(normalized_status IN ('open', 'needs_review') AND next_deadline_at IS NULL AND rolling_evidence = false)
-				OR COALESCE(status_reason,'') IN ('rolling_without_evidence', 'missing_deadline', 'inconsistent_dates')
-				OR COALESCE(status_confidence, 0) < $2
-				OR COALESCE(last_enriched_at, 'epoch'::timestamptz) < NOW() - $4::interval
-			  )
-		ORDER BY updated_at ASC
-		LIMIT $3
-	`
+	// Candidate filter shared by the row query and the progress count.
+	where := enrichCandidateWhere(domain, onlyMissingDeadlines)
 
-	if !onlyMissingDeadlines {
-		query = `
-			SELECT id::text, title, COALESCE(summary,''), COALESCE(description_html,''), external_url,
-			       source_domain, source_id, is_rolling, rolling_evidence, COALESCE(opp_status,''), COALESCE(source_status_raw,''),
-			       normalized_status::text, COALESCE(status_reason,''),
-			       deadline_at, next_deadline_at, close_at, expiration_at, COALESCE(deadlines, '[]'::jsonb),
-			       COALESCE(source_evidence_json, '{}'::jsonb), COALESCE(status_confidence, 0)
-			FROM opportunities
-			WHERE ($1 = '' OR source_domain = $1)
-			  AND (
-					normalized_status IN ('open', 'needs_review')
-					OR COALESCE(status_reason,'') IN ('rolling_without_evidence', 'missing_deadline', 'inconsistent_dates')
-					OR COALESCE(status_confidence, 0) < $2
-					OR COALESCE(last_enriched_at, 'epoch'::timestamptz) < NOW() - $4::interval
-				  )
-			ORDER BY updated_at ASC
-			LIMIT $3
-		`
+	// The count only feeds progress reports, so callers without one skip it.
+	// The run visits at most min(batchSize, maxItems) rows, so the estimate
+	// is capped there. It is only an estimate: rows can change mid-run.
+	if progress != nil {
+		var candidates int
+		if err := p.DB.QueryRow(ctx, "SELECT COUNT(*) FROM opportunities "+where, domain, confidenceThreshold, ttlInterval).Scan(&candidates); err != nil {
+			log.Printf("[enrich] count for progress failed: %v", err)
+		}
+		stats.TotalEstimate = min(candidates, batchSize, maxItems)
 	}
 
-	rows, err := p.DB.Query(ctx, query, domain, confidenceThreshold, batchSize, ttlInterval)
+	query := enrichCandidateQuery(where)
+	rows, err := p.DB.Query(ctx, query, domain, confidenceThreshold, ttlInterval, batchSize)
 	if err != nil {
 		return stats, fmt.Errorf("enrichment query failed: %w", err)
 	}
@@ -1100,6 +1775,7 @@ func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyM
 
 	processed := 0
 	updated := 0
+	counts := map[string]int{}
 	for rows.Next() {
 		if processed >= maxItems {
 			break
@@ -1141,6 +1817,10 @@ func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyM
 			stats.PDFsParsed += int(pdfCountFloat)
 		}
 		decision := ComputeStatusDecision(opp, time.Now().UTC())
+		if opp.SourceEvidenceJSON == nil {
+			opp.SourceEvidenceJSON = map[string]interface{}{}
+		}
+		recordTimelineInversion(opp.SourceEvidenceJSON, decision)
 		fetchStatusCode, fetchBytes, fetchDurationMs, fetchBlocked := extractFetchMeta(opp.SourceEvidenceJSON)
 		if previousStatus != decision.NormalizedStatus || previousReason != decision.StatusReason {
 			stats.StatusChanges++
@@ -1174,11 +1854,18 @@ func (p *Pipeline) EnrichOpportunities(ctx context.Context, domain string, onlyM
 		if tag.RowsAffected() > 0 {
 			updated++
 		}
+		counts[decision.NormalizedStatus]++
+		if processed%enrichProgressEvery == 0 {
+			progress.report(processed, stats.TotalEstimate, updated, counts)
+		}
 	}
 
 	if err := rows.Err(); err != nil {
 		return stats, fmt.Errorf("enrichment iteration failed: %w", err)
 	}
+	if processed%enrichProgressEvery != 0 {
+		progress.report(processed, stats.TotalEstimate, updated, counts)
+	}
 
 	stats.ItemsScanned = processed
 	stats.ItemsUpdated = updated
@@ -1193,14 +1880,7 @@ func max(a, b int) int {
 }
 
 func domainTTLIntervalLiteral(domain string) string {
-	d := strings.ToLower(strings.TrimSpace(domain))
-	if strings.Contains(d, "gob.pe") || strings.Contains(d, "proinnovate") || strings.Contains(d, "prociencia") {
-		return "48 hours"
-	}
-	if strings.Contains(d, "ukri") || strings.Contains(d, "neh") {
-		return "72 hours"
-	}
-	return "168 hours"
+	return fmt.Sprintf("%d hours", int(db.EnrichmentTTL(domain).Hours()))
 }
 
 func extractFetchMeta(evidence map[string]interface{}) (*int, *int, *int, *bool) {
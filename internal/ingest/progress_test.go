@@ -0,0 +1,37 @@
+package ingest
+
+import "testing"
+
+func TestProgressPercent_ClampsOvershoot(t *testing.T) {
+	tests := []struct {
+		processed, total int
+		want             float64
+	}{
+		{processed: 3000, total: 42000, want: 7.1},
+		{processed: 42000, total: 42000, want: 100},
+		{processed: 43500, total: 42000, want: 100}, // rows added mid-run
+		{processed: 10, total: 0, want: 0},
+	}
+	for _, tc := range tests {
+		if got := progressPercent(tc.processed, tc.total); got != tc.want {
+			t.Fatalf("progressPercent(%d, %d) = %v, want %v", tc.processed, tc.total, got, tc.want)
+		}
+	}
+}
+
+func TestProgressFunc_ReportSnapshotsCounts(t *testing.T) {
+	counts := map[string]int{"open": 1}
+	var got Progress
+	ProgressFunc(func(p Progress) { got = p }).report(5, 10, 2, counts)
+	counts["open"] = 99
+
+	if got.Processed != 5 || got.TotalEstimate != 10 || got.Percent != 50 || got.Updated != 2 {
+		t.Fatalf("unexpected progress %+v", got)
+	}
+	if got.StatusCounts["open"] != 1 {
+		t.Fatalf("reported counts must not alias the loop's map, got %v", got.StatusCounts)
+	}
+
+	var nilFunc ProgressFunc
+	nilFunc.report(1, 1, 1, counts) // must not panic
+}
@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// sourceRetiredReason is the status_reason PurgeSource writes when it
+// archives a retired source's rows. Recompute leaves these rows alone.
+const sourceRetiredReason = "source_retired"
+
+// SourcePurgeStats reports a PurgeSource run. ByStatus counts the source's
+// rows per normalized_status before anything changed.
+type SourcePurgeStats struct {
+	Domain   string         `json:"domain"`
+	Mode     string         `json:"mode"` // "archive" or "delete"
+	DryRun   bool           `json:"dry_run"`
+	Matched  int            `json:"matched"`
+	Affected int            `json:"affected"`
+	ByStatus map[string]int `json:"by_status"`
+}
+
+// PurgeSource removes a retired source's opportunities: by default it
+// archives them (reversible, and saved lists keep working), with hard it
+// deletes them, cascading to users' saved entries. domain must equal
+// source_domain exactly; no pattern matching is done. A dry run only counts.
+func (p *Pipeline) PurgeSource(ctx context.Context, domain string, hard, dryRun bool) (SourcePurgeStats, error) {
+	stats := SourcePurgeStats{Domain: domain, Mode: "archive", DryRun: dryRun, ByStatus: map[string]int{}}
+	if hard {
+		stats.Mode = "delete"
+	}
+	if strings.TrimSpace(domain) == "" {
+		return stats, fmt.Errorf("purge source: domain is required")
+	}
+
+	rows, err := p.DB.Query(ctx, `
+		SELECT COALESCE(normalized_status::text, ''), COUNT(*)
+		FROM opportunities
+		WHERE source_domain = $1
+		GROUP BY 1`, domain)
+	if err != nil {
+		return stats, fmt.Errorf("purge source: count: %w", err)
+	}
+	for rows.Next() {
+		var status string
+		var n int
+		if err := rows.Scan(&status, &n); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("purge source: scan: %w", err)
+		}
+		stats.ByStatus[status] = n
+		stats.Matched += n
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("purge source: count: %w", err)
+	}
+
+	if stats.Matched == 0 {
+		return stats, nil
+	}
+	if dryRun {
+		// Archiving skips rows that are already archived.
+		stats.Affected = stats.Matched
+		if !hard {
+			stats.Affected -= stats.ByStatus["archived"]
+		}
+		return stats, nil
+	}
+
+	query := `
+		UPDATE opportunities
+		SET normalized_status = 'archived'::normalized_status_enum,
+		    status_reason = $2
+		WHERE source_domain = $1
+		  AND normalized_status::text IS DISTINCT FROM 'archived'`
+	args := []interface{}{domain, sourceRetiredReason}
+	if hard {
+		query = `DELETE FROM opportunities WHERE source_domain = $1`
+		args = args[:1]
+	}
+	tag, err := p.DB.Exec(ctx, query, args...)
+	if err != nil {
+		return stats, fmt.Errorf("purge source: %s: %w", stats.Mode, err)
+	}
+	stats.Affected = int(tag.RowsAffected())
+	return stats, nil
+}
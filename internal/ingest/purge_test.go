@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestPurgeSource_SeededDomains runs against a migrated database; set
+// TEST_DATABASE_URL to enable it. It seeds three rows for the retired source
+// and two for a neighbour whose domain shares a prefix.
+func TestPurgeSource_SeededDomains(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const retired, neighbour = "purge-test.example.org", "purge-test.example.org.uk"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain IN ($1, $2)`, retired, neighbour); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	seed := []struct{ domain, id, status string }{
+		{retired, "r1", "open"},
+		{retired, "r2", "closed"},
+		{retired, "r3", "archived"},
+		{neighbour, "n1", "open"},
+		{neighbour, "n2", "open"},
+	}
+	for _, row := range seed {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, normalized_status)
+			VALUES ($1, $2, $3, $4, $5::normalized_status_enum)`,
+			"Purge "+row.id, "https://"+row.domain+"/"+row.id, row.domain, row.id, row.status); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p := &Pipeline{DB: pool}
+	countDomain := func(domain, where string) int {
+		var n int
+		if err := pool.QueryRow(ctx, `SELECT COUNT(*) FROM opportunities WHERE source_domain = $1 `+where, domain).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	dry, err := p.PurgeSource(ctx, retired, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Matched != 3 || dry.Affected != 2 || dry.ByStatus["archived"] != 1 {
+		t.Errorf("dry run = %+v, want 3 matched, 2 to archive", dry)
+	}
+	if countDomain(retired, "AND normalized_status::text = 'archived'") != 1 {
+		t.Fatal("dry run changed rows")
+	}
+
+	soft, err := p.PurgeSource(ctx, retired, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if soft.Affected != 2 {
+		t.Errorf("archived %d rows, want 2", soft.Affected)
+	}
+	if n := countDomain(retired, "AND status_reason = '"+sourceRetiredReason+"'"); n != 2 {
+		t.Errorf("%d rows carry %s, want 2", n, sourceRetiredReason)
+	}
+
+	hard, err := p.PurgeSource(ctx, retired, true, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hard.Affected != 3 || countDomain(retired, "") != 0 {
+		t.Errorf("hard delete removed %d rows, want all 3", hard.Affected)
+	}
+
+	// Exact match only: the neighbour is untouched throughout.
+	if n := countDomain(neighbour, "AND normalized_status::text = 'open'"); n != 2 {
+		t.Errorf("neighbour has %d open rows, want 2", n)
+	}
+}
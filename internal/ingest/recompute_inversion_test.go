@@ -0,0 +1,65 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestRecomputeStatuses_RecordsTimelineInversion runs against a migrated
+// database; set TEST_DATABASE_URL to enable it.
+func TestRecomputeStatuses_RecordsTimelineInversion(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "recompute-inversion-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	now := time.Now().UTC()
+	closeAt := now.Add(30 * 24 * time.Hour)
+	openAt := now.Add(60 * 24 * time.Hour)
+	since := now.Add(-time.Second)
+	if _, err := pool.Exec(ctx, `
+		INSERT INTO opportunities (title, external_url, source_domain, source_id, normalized_status,
+		                           open_at, close_at, source_evidence_json)
+		VALUES ('Swapped dates', $1, $2, 'swapped', 'open'::normalized_status_enum, $3, $4, '{"authority":"inference"}')`,
+		"https://"+domain+"/swapped", domain, openAt, closeAt); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := NewPipeline(pool, nil, nil, nil).RecomputeStatusesSince(ctx, since, 100, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var reason, check, authority string
+	if err := pool.QueryRow(ctx, `
+		SELECT COALESCE(status_reason, ''),
+		       COALESCE(source_evidence_json->'timeline_inversion'->>'check', ''),
+		       COALESCE(source_evidence_json->>'authority', '')
+		FROM opportunities WHERE source_domain = $1`, domain).Scan(&reason, &check, &authority); err != nil {
+		t.Fatal(err)
+	}
+	if reason != "inconsistent_open_close" || check != "open_after_close" {
+		t.Errorf("expected the inversion recorded, got reason %q check %q", reason, check)
+	}
+	if authority != "inference" {
+		t.Errorf("expected the rest of the evidence kept, got authority %q", authority)
+	}
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecomputeScope_FullVsIncremental(t *testing.T) {
+	clause, args := recomputeScope(nil, 3)
+	if clause != "" || len(args) != 0 {
+		t.Fatalf("a full recompute must not narrow the scan, got %q %v", clause, args)
+	}
+
+	since := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	clause, args = recomputeScope(&since, 3)
+	if len(args) != 1 || args[0] != since {
+		t.Fatalf("expected since bound once, got %v", args)
+	}
+	for _, want := range []string{
+		"updated_at > $3",
+		"last_enriched_at > $3",
+		"next_deadline_at BETWEEN $3 AND NOW()",
+		"close_at BETWEEN $3 AND NOW()",
+		"expiration_at BETWEEN $3 AND NOW()",
+	} {
+		if !strings.Contains(clause, want) {
+			t.Fatalf("incremental scope missing %q:\n%s", want, clause)
+		}
+	}
+	if strings.Contains(clause, "$4") || !strings.HasPrefix(strings.TrimSpace(clause), "AND (") {
+		t.Fatalf("expected one ANDed group on $3, got:\n%s", clause)
+	}
+
+	// The progress count query binds since as $1.
+	if clause, _ := recomputeScope(&since, 1); !strings.Contains(clause, "updated_at > $1") || strings.Contains(clause, "$3") {
+		t.Fatalf("expected the placeholder index honored, got:\n%s", clause)
+	}
+}
@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestRefineAllData_SeededBatch runs against a migrated database; set
+// TEST_DATABASE_URL to enable it. It seeds five rows, one with an
+// embedding, and refines the table two rows at a time.
+func TestRefineAllData_SeededBatch(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "refine-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	var dims int
+	if err := pool.QueryRow(ctx, `
+		SELECT atttypmod FROM pg_attribute
+		WHERE attrelid = 'opportunities'::regclass AND attname = 'embedding'`).Scan(&dims); err != nil {
+		t.Fatal(err)
+	}
+	vector := "[" + strings.TrimSuffix(strings.Repeat("0.5,", dims), ",") + "]"
+
+	// A future deadline keeps SaveOpportunity away from the LLM and the network.
+	deadline := time.Now().Add(30 * 24 * time.Hour)
+	for i := 0; i < 5; i++ {
+		var embedding interface{}
+		if i == 0 {
+			embedding = vector
+		}
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, summary, external_url, source_domain, source_id, deadline_at, embedding)
+			VALUES ($1, '<p>Refined summary</p>', $2, $3, $4, $5, $6::vector)`,
+			fmt.Sprintf("Refine grant %d", i), fmt.Sprintf("https://%s/%d", domain, i), domain, fmt.Sprint(i), deadline, embedding); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p := &Pipeline{DB: pool, Store: db.NewStore(pool)}
+	var reports []Progress
+	stats, err := p.RefineAllData(ctx, 2, func(pr Progress) { reports = append(reports, pr) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Processed < 5 || stats.Batches < 3 || stats.EmbeddingsReused < 1 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if len(reports) != stats.Batches || reports[len(reports)-1].Processed != stats.Processed {
+		t.Fatalf("expected one progress report per batch, got %+v", reports)
+	}
+
+	var summaries, embedded int
+	if err := pool.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE summary = 'Refined summary'), COUNT(embedding)
+		FROM opportunities WHERE source_domain = $1`, domain).Scan(&summaries, &embedded); err != nil {
+		t.Fatal(err)
+	}
+	if summaries != 5 || embedded != 1 {
+		t.Fatalf("expected 5 cleaned summaries and the stored embedding kept, got %d and %d", summaries, embedded)
+	}
+
+	// Cancelling after the first batch stops the run there.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stats, err = p.RefineAllData(cancelCtx, 2, func(Progress) { cancel() })
+	if !errors.Is(err, context.Canceled) || stats.Processed != 2 {
+		t.Fatalf("expected a cancelled run after one batch, got %+v, %v", stats, err)
+	}
+}
@@ -2,7 +2,11 @@ package ingest
 
 import (
 	"embed"
+	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,6 +26,22 @@ type FetchConfig struct {
 	RateLimitRPS   float64 `yaml:"rate_limit_rps,omitempty"`  // Requests per second, default: 1.0
 	ProxyURL       string  `yaml:"proxy_url,omitempty"`
 	AcceptLanguage string  `yaml:"accept_language,omitempty"` // e.g., "es-PE,es;q=0.9,en;q=0.8"
+
+	// BlockedMaxBytes is the body size under which a page containing block
+	// phrases ("access denied", "captcha") is flagged as blocked. Default: 2048
+	BlockedMaxBytes int `yaml:"blocked_max_bytes,omitempty"`
+
+	// Accept replaces the default HTML Accept header, e.g. "application/json"
+	// for API sources. Headers are added to every request to the domain.
+	Accept  string            `yaml:"accept,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// MaxRedirects caps redirects per request. Default: 10
+	MaxRedirects int `yaml:"max_redirects,omitempty"`
+	// RestrictRedirectsToOrigin refuses redirects that leave the requested
+	// host's registrable domain (www.x.gov.pe may go to convocatorias.x.gov.pe,
+	// not elsewhere).
+	RestrictRedirectsToOrigin bool `yaml:"restrict_redirects_to_origin,omitempty"`
 }
 
 // SourceConfig defines a single data source for ingestion.
@@ -46,12 +66,38 @@ type SourceConfig struct {
 	Pagination PaginationConfig `yaml:"pagination,omitempty"`
 	MaxPages   int              `yaml:"max_pages,omitempty"`
 	Detail     DetailConfig     `yaml:"detail,omitempty"`
+
+	// Query params CanonicalizeURL keeps (when the param is the opportunity
+	// identifier) or drops (session or tracking params) for this source, on
+	// top of the default tracking-param list.
+	CanonicalKeepParams []string `yaml:"canonical_keep_params,omitempty"`
+	CanonicalDropParams []string `yaml:"canonical_drop_params,omitempty"`
+	// IncludeClosed ingests past-deadline grants as closed instead of
+	// skipping them (api_grants_gov only).
+	IncludeClosed bool `yaml:"include_closed,omitempty"`
+	// DedupByNumber makes opportunity_number a second identity key within
+	// the source: a record whose number is already held by another row
+	// (synopsis vs. forecast vs. amendment URLs) updates that row instead of
+	// adding a near-duplicate.
+	DedupByNumber bool `yaml:"dedup_by_number,omitempty"`
 }
 
 type PaginationConfig struct {
 	Next string `yaml:"next,omitempty"` // CSS selector for the next page link
+
+	// Query-param pagination for sites without a next link: page i is
+	// base_url with Param set to Start + i*Step (step defaults to 1).
+	// Crawling stops at MaxPages (falling back to the source's max_pages)
+	// or at the first page that yields no items.
+	Param    string `yaml:"param,omitempty"`
+	Start    int    `yaml:"start,omitempty"`
+	Step     int    `yaml:"step,omitempty"`
+	MaxPages int    `yaml:"max_pages,omitempty"`
 }
 
+// SelectorConfig holds the list-page selectors. Container, Link, Title and
+// Content accept pipe-separated alternatives ("div.card | li.result"), tried
+// in order so a markup change on the site doesn't empty the crawl.
 type SelectorConfig struct {
 	Container string `yaml:"container,omitempty"` // CSS selector for the list item wrapper
 	Link      string `yaml:"link,omitempty"`
@@ -66,21 +112,59 @@ type DetailParseConfig struct {
 	DateLocales     []string `yaml:"date_locales,omitempty"`     // ["en", "es", "pt"]
 	CurrencyDefault string   `yaml:"currency_default,omitempty"` // "USD", "EUR", "GBP"
 	DateFormats     []string `yaml:"date_formats,omitempty"`     // Custom date formats
+	NumberLocale    string   `yaml:"number_locale,omitempty"`    // "de", "es-ES" for "1.000,50"; default reads "1,000.50"
+	// DefaultDeadlineTime is when a date-only deadline closes ("17:00"),
+	// read in the source's timezone. Defaults to the end of the day.
+	DefaultDeadlineTime string `yaml:"default_deadline_time,omitempty"`
 }
 
 type DetailConfig struct {
 	Enabled   bool                 `yaml:"enabled"`
 	Selectors DetailSelectorConfig `yaml:"selectors,omitempty"`
 	Parse     DetailParseConfig    `yaml:"parse,omitempty"`
+	// MaxItems caps detail-page fetches per run. Defaults to MaxPages * 100;
+	// items past the budget are saved from list data only.
+	MaxItems int `yaml:"max_items,omitempty"`
+	// FollowLinks are CSS selectors for links (e.g. a "Cronograma" tab or a
+	// "Bases" PDF) fetched one hop deeper and merged into deadline evidence.
+	FollowLinks []string `yaml:"follow_links,omitempty"`
+	// LLMResultsCheck asks the LLM to classify pages whose results-page keyword
+	// detection is ambiguous. Off by default to control LLM cost.
+	LLMResultsCheck bool `yaml:"llm_results_check,omitempty"`
+	// Attachments tunes which linked documents are fetched for PDF deadline
+	// evidence.
+	Attachments AttachmentConfig `yaml:"attachments,omitempty"`
+	// MultiItemSelector splits a detail page that announces several calls
+	// (a program page with one section per funding line) into one
+	// opportunity per matching block. "|" separates alternatives; pages
+	// matching fewer than two blocks are kept whole.
+	MultiItemSelector string `yaml:"multi_item_selector,omitempty"`
+	// MultiItemTitle selects each block's title. Defaults to the block's
+	// first heading.
+	MultiItemTitle string `yaml:"multi_item_title,omitempty"`
+}
+
+// AttachmentConfig adjusts attachment-link detection for one source.
+type AttachmentConfig struct {
+	// AnchorKeywords are extra link-text keywords ("Calendario de
+	// actividades", "Annexe I") matched case-insensitively alongside the
+	// built-in schedule/annex pattern.
+	AnchorKeywords []string `yaml:"anchor_keywords,omitempty"`
+	// Exclude is a case-insensitive regex tested against link text and URL;
+	// matching links (application forms, templates) are never fetched.
+	Exclude string `yaml:"exclude,omitempty"`
 }
 
 type DetailSelectorConfig struct {
 	Container   string `yaml:"container,omitempty"` // Wrapper for detail content
 	Description string `yaml:"description,omitempty"`
 	Deadline    string `yaml:"deadline,omitempty"`
-	Amount      string `yaml:"amount,omitempty"`
-	Eligibility string `yaml:"eligibility,omitempty"`
-	Category    string `yaml:"category,omitempty"`
+	// Deadlines lists extra deadline selectors tried alongside Deadline,
+	// for pages that spread the schedule over several blocks.
+	Deadlines   []string `yaml:"deadlines,omitempty"`
+	Amount      string   `yaml:"amount,omitempty"`
+	Eligibility string   `yaml:"eligibility,omitempty"`
+	Category    string   `yaml:"category,omitempty"`
 }
 
 // LoadRegistry reads the embedded sources.yaml and returns a Registry.
@@ -94,7 +178,10 @@ func LoadRegistry(path string) (*Registry, error) {
 			return nil, err
 		}
 	}
+	return parseRegistry(data)
+}
 
+func parseRegistry(data []byte) (*Registry, error) {
 	// Expand environment variables within the YAML content (e.g. ${API_KEY})
 	expanded := os.ExpandEnv(string(data))
 
@@ -105,3 +192,94 @@ func LoadRegistry(path string) (*Registry, error) {
 
 	return &reg, nil
 }
+
+// loadConfiguredRegistry reads SOURCES_REGISTRY_PATH when set, so the registry
+// can be edited and reloaded without a rebuild, and the embedded file otherwise.
+func loadConfiguredRegistry() (*Registry, error) {
+	if path := os.Getenv("SOURCES_REGISTRY_PATH"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read registry %s: %w", path, err)
+		}
+		return parseRegistry(data)
+	}
+	return LoadRegistry("internal/config/sources.yaml")
+}
+
+var registryCache struct {
+	sync.RWMutex
+	reg *Registry
+}
+
+// ReloadRegistry re-reads and validates the registry and, if it parses,
+// replaces the in-memory copy used by ingests. Sources that fail validation
+// stay in the registry and are refused when ingested.
+func ReloadRegistry() (*Registry, []SourceValidation, error) {
+	reg, err := loadConfiguredRegistry()
+	if err != nil {
+		return nil, nil, err
+	}
+	validation := ValidateRegistry(reg)
+
+	registryCache.Lock()
+	registryCache.reg = reg
+	registryCache.Unlock()
+
+	return reg, validation, nil
+}
+
+// currentRegistry returns the cached registry, loading it once on first use.
+// The lock is held across the load so concurrent first callers parse once.
+func currentRegistry() (*Registry, error) {
+	registryCache.RLock()
+	reg := registryCache.reg
+	registryCache.RUnlock()
+	if reg != nil {
+		return reg, nil
+	}
+
+	registryCache.Lock()
+	defer registryCache.Unlock()
+	if registryCache.reg != nil {
+		return registryCache.reg, nil
+	}
+	reg, err := loadConfiguredRegistry()
+	if err != nil {
+		return nil, err
+	}
+	registryCache.reg = reg
+	return reg, nil
+}
+
+// SourceForURL returns the first source whose base_url is on the same host as
+// rawURL (ignoring a leading "www."), or nil.
+func (r *Registry) SourceForURL(rawURL string) *SourceConfig {
+	host := normalizedHost(rawURL)
+	if host == "" {
+		return nil
+	}
+	for i := range r.Sources {
+		if normalizedHost(r.Sources[i].BaseURL) == host {
+			return &r.Sources[i]
+		}
+	}
+	return nil
+}
+
+func normalizedHost(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// Source returns the source with the given id, or nil.
+func (r *Registry) Source(id string) *SourceConfig {
+	for i := range r.Sources {
+		if r.Sources[i].ID == id {
+			return &r.Sources[i]
+		}
+	}
+	return nil
+}
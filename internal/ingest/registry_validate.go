@@ -0,0 +1,192 @@
+package ingest
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+)
+
+// SourceValidation is the result of validating one registry source.
+type SourceValidation struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Strategy string   `json:"strategy"`
+	Valid    bool     `json:"valid"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// baseURLStrategies need base_url to do anything; grants.gov has its own endpoint.
+var baseURLStrategies = map[string]bool{
+	"api_eu_ft":      true,
+	"html_generic":   true,
+	"wordpress_rest": true,
+}
+
+// ValidateRegistry checks every source in reg and reports problems that would
+// otherwise only surface at crawl time.
+func ValidateRegistry(reg *Registry) []SourceValidation {
+	out := make([]SourceValidation, 0, len(reg.Sources))
+	seen := map[string]bool{}
+	for _, src := range reg.Sources {
+		errs := ValidateSource(src)
+		if src.ID != "" {
+			if seen[src.ID] {
+				errs = append(errs, "duplicate id")
+			}
+			seen[src.ID] = true
+		}
+		out = append(out, SourceValidation{
+			ID:       src.ID,
+			Name:     src.Name,
+			Strategy: src.Strategy,
+			Valid:    len(errs) == 0,
+			Errors:   errs,
+		})
+	}
+	return out
+}
+
+// ValidateSource returns the problems found in a single source config.
+func ValidateSource(src SourceConfig) []string {
+	var errs []string
+	if strings.TrimSpace(src.ID) == "" {
+		errs = append(errs, "id is required")
+	}
+	if strings.TrimSpace(src.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+	if _, err := GlobalStrategyFactory.Get(src.Strategy); err != nil {
+		errs = append(errs, fmt.Sprintf("unknown strategy %q", src.Strategy))
+	}
+
+	if baseURLStrategies[src.Strategy] && strings.TrimSpace(src.BaseURL) == "" {
+		errs = append(errs, "base_url is required for strategy "+src.Strategy)
+	} else if src.BaseURL != "" {
+		if err := validateSourceURL(src.BaseURL); err != nil {
+			errs = append(errs, "base_url: "+err.Error())
+		}
+	}
+	for _, seed := range src.Seeds {
+		if err := validateSourceURL(seed); err != nil {
+			errs = append(errs, fmt.Sprintf("seed_urls %q: %v", seed, err))
+		}
+	}
+
+	if src.Strategy == "html_generic" {
+		if src.Selectors.Container == "" {
+			errs = append(errs, "selectors.container is required for html_generic")
+		}
+		if src.Selectors.Link == "" {
+			errs = append(errs, "selectors.link is required for html_generic")
+		}
+	}
+	if src.Pagination.usesQueryParam() {
+		if src.Pagination.Next != "" {
+			errs = append(errs, "pagination.param and pagination.next are mutually exclusive")
+		}
+		if src.Pagination.Step < 0 || src.Pagination.MaxPages < 0 {
+			errs = append(errs, "pagination.step and pagination.max_pages must not be negative")
+		}
+	}
+
+	selectors := map[string]string{
+		"selectors.container":          src.Selectors.Container,
+		"selectors.link":               src.Selectors.Link,
+		"selectors.title":              src.Selectors.Title,
+		"selectors.date":               src.Selectors.Date,
+		"selectors.content":            src.Selectors.Content,
+		"pagination.next":              src.Pagination.Next,
+		"detail.selectors.container":   src.Detail.Selectors.Container,
+		"detail.selectors.description": src.Detail.Selectors.Description,
+		"detail.selectors.deadline":    src.Detail.Selectors.Deadline,
+		"detail.selectors.amount":      src.Detail.Selectors.Amount,
+		"detail.selectors.eligibility": src.Detail.Selectors.Eligibility,
+		"detail.selectors.category":    src.Detail.Selectors.Category,
+	}
+	for i, sel := range src.Detail.Selectors.Deadlines {
+		selectors[fmt.Sprintf("detail.selectors.deadlines[%d]", i)] = sel
+	}
+	for i, sel := range src.Detail.FollowLinks {
+		selectors[fmt.Sprintf("detail.follow_links[%d]", i)] = sel
+	}
+	for field, sel := range selectors {
+		// List selectors may be pipe-separated alternatives tried in order.
+		alts := []string{sel}
+		if strings.HasPrefix(field, "selectors.") {
+			alts = selectorAlternatives(sel)
+		}
+		for _, alt := range alts {
+			// "." means the container element itself (see runWithColly).
+			if alt == "" || alt == "." {
+				continue
+			}
+			if _, err := cascadia.ParseGroup(alt); err != nil {
+				errs = append(errs, fmt.Sprintf("%s: invalid selector %q: %v", field, alt, err))
+			}
+		}
+	}
+
+	for _, keep := range src.CanonicalKeepParams {
+		for _, drop := range src.CanonicalDropParams {
+			if keep == drop {
+				errs = append(errs, fmt.Sprintf("canonical param %q is both kept and dropped", keep))
+			}
+		}
+	}
+	if pattern := src.Detail.Attachments.Exclude; pattern != "" {
+		if _, err := regexp.Compile("(?i)" + pattern); err != nil {
+			errs = append(errs, fmt.Sprintf("detail.attachments.exclude: invalid pattern %q: %v", pattern, err))
+		}
+	}
+
+	if _, err := parseDeadlineCutoff(src.Detail.Parse.DefaultDeadlineTime); err != nil {
+		errs = append(errs, "detail.parse.default_deadline_time: "+err.Error())
+	}
+
+	if src.Schedule != "" && !validSchedule(src.Schedule) {
+		errs = append(errs, fmt.Sprintf("schedule %q is neither a duration nor a 5-field cron expression", src.Schedule))
+	}
+	if src.Fetch.TimeoutSeconds < 0 || src.Fetch.MaxRetries < 0 || src.Fetch.RateLimitRPS < 0 || src.Fetch.BlockedMaxBytes < 0 || src.Fetch.MaxRedirects < 0 {
+		errs = append(errs, "fetch settings must not be negative")
+	}
+
+	// Map iteration above is unordered; keep output stable for clients.
+	sort.Strings(errs)
+	return errs
+}
+
+func validateSourceURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	return nil
+}
+
+// validSchedule accepts a Go duration ("24h") or a 5-field cron expression.
+func validSchedule(s string) bool {
+	if d, err := time.ParseDuration(s); err == nil {
+		return d > 0
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return false
+	}
+	for _, f := range fields {
+		if strings.Trim(f, "0123456789*/,-") != "" {
+			return false
+		}
+	}
+	return true
+}
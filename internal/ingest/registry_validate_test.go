@@ -0,0 +1,176 @@
+package ingest
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestValidateRegistry_EmbeddedSourcesAreValid(t *testing.T) {
+	reg, err := LoadRegistry("config/sources.yaml")
+	if err != nil {
+		t.Fatalf("load registry: %v", err)
+	}
+	for _, v := range ValidateRegistry(reg) {
+		if !v.Valid {
+			t.Errorf("source %q: %v", v.ID, v.Errors)
+		}
+	}
+}
+
+func TestValidateRegistry_ReportsProblems(t *testing.T) {
+	reg := &Registry{Sources: []SourceConfig{
+		{ID: "ok", Name: "OK", Strategy: "wordpress_rest", BaseURL: "https://example.org"},
+		{ID: "ok", Name: "Dup", Strategy: "wordpress_rest", BaseURL: "https://example.org"},
+		{ID: "bad_html", Name: "Bad", Strategy: "html_generic", BaseURL: "ftp://example.org",
+			Selectors: SelectorConfig{Container: "div..item"}, Schedule: "every day"},
+		{ID: "bad_strategy", Name: "X", Strategy: "nope"},
+	}}
+
+	results := ValidateRegistry(reg)
+	if !results[0].Valid {
+		t.Fatalf("expected first source valid, got %v", results[0].Errors)
+	}
+
+	wants := map[int][]string{
+		1: {"duplicate id"},
+		2: {"base_url", "selectors.container", "selectors.link is required", "schedule"},
+		3: {"unknown strategy"},
+	}
+	for i, want := range wants {
+		joined := strings.Join(results[i].Errors, "\n")
+		for _, w := range want {
+			if !strings.Contains(joined, w) {
+				t.Errorf("source %d: expected error mentioning %q, got %v", i, w, results[i].Errors)
+			}
+		}
+	}
+}
+
+func TestValidateSource_SelectorAlternatives(t *testing.T) {
+	src := SourceConfig{ID: "alt", Name: "Alt", Strategy: "html_generic", BaseURL: "https://example.org",
+		Selectors: SelectorConfig{Container: "li.call | div.card", Link: "a | a.go", Title: ".card-title"}}
+	if errs := ValidateSource(src); len(errs) != 0 {
+		t.Fatalf("pipe-separated alternatives should validate, got %v", errs)
+	}
+
+	src.Selectors.Container = "li.call | div..card"
+	errs := ValidateSource(src)
+	if len(errs) != 1 || !strings.Contains(errs[0], `"div..card"`) {
+		t.Fatalf("expected the bad alternative reported, got %v", errs)
+	}
+}
+
+func TestValidateSource_AttachmentExcludePattern(t *testing.T) {
+	src := SourceConfig{ID: "att", Name: "Att", Strategy: "wordpress_rest", BaseURL: "https://example.org",
+		Detail: DetailConfig{Attachments: AttachmentConfig{Exclude: "formulario|plantilla"}}}
+	if errs := ValidateSource(src); len(errs) != 0 {
+		t.Fatalf("expected a valid exclude pattern, got %v", errs)
+	}
+
+	src.Detail.Attachments.Exclude = "formulario("
+	errs := ValidateSource(src)
+	if len(errs) != 1 || !strings.Contains(errs[0], "detail.attachments.exclude") {
+		t.Fatalf("expected the bad exclude pattern reported, got %v", errs)
+	}
+}
+
+func TestValidateSource_CanonicalParamConflict(t *testing.T) {
+	src := SourceConfig{ID: "canon", Name: "Canon", Strategy: "wordpress_rest", BaseURL: "https://example.org",
+		CanonicalKeepParams: []string{"id", "ref"}, CanonicalDropParams: []string{"sid"}}
+	if errs := ValidateSource(src); len(errs) != 0 {
+		t.Fatalf("expected valid canonical params, got %v", errs)
+	}
+
+	src.CanonicalDropParams = append(src.CanonicalDropParams, "ref")
+	errs := ValidateSource(src)
+	if len(errs) != 1 || !strings.Contains(errs[0], `"ref"`) {
+		t.Fatalf("expected the kept-and-dropped param reported, got %v", errs)
+	}
+}
+
+func TestValidSchedule(t *testing.T) {
+	for _, s := range []string{"24h", "0 6 * * *", "*/30 * * * 1-5"} {
+		if !validSchedule(s) {
+			t.Errorf("expected %q to be valid", s)
+		}
+	}
+	for _, s := range []string{"daily", "0 6 * *", "-1h"} {
+		if validSchedule(s) {
+			t.Errorf("expected %q to be invalid", s)
+		}
+	}
+}
+
+// resetRegistryCache empties the process-wide registry cache for the test
+// and restores the previous one afterwards.
+func resetRegistryCache(t *testing.T) {
+	t.Helper()
+	registryCache.Lock()
+	prev := registryCache.reg
+	registryCache.reg = nil
+	registryCache.Unlock()
+	t.Cleanup(func() {
+		registryCache.Lock()
+		registryCache.reg = prev
+		registryCache.Unlock()
+	})
+}
+
+func TestCurrentRegistry_CachedUntilReload(t *testing.T) {
+	path := t.TempDir() + "/sources.yaml"
+	write := func(id string) {
+		body := "sources:\n  - id: " + id + "\n    name: Test\n    strategy: wordpress_rest\n    base_url: https://example.org\n"
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("SOURCES_REGISTRY_PATH", path)
+	resetRegistryCache(t)
+
+	write("first")
+	reg, err := currentRegistry()
+	if err != nil || reg.Source("first") == nil {
+		t.Fatalf("expected first source, got %v %v", reg, err)
+	}
+
+	write("second")
+	if reg, _ := currentRegistry(); reg.Source("first") == nil {
+		t.Fatal("expected cached registry until reload")
+	}
+
+	if _, validation, err := ReloadRegistry(); err != nil || len(validation) != 1 || !validation[0].Valid {
+		t.Fatalf("reload failed: %v %+v", err, validation)
+	}
+	if reg, _ := currentRegistry(); reg.Source("second") == nil {
+		t.Fatal("expected reloaded registry")
+	}
+}
+
+func TestValidateSource_QueryParamPagination(t *testing.T) {
+	src := SourceConfig{ID: "paged", Name: "Paged", Strategy: "wordpress_rest", BaseURL: "https://example.org",
+		Pagination: PaginationConfig{Param: "page", Start: 1}}
+	if errs := ValidateSource(src); len(errs) != 0 {
+		t.Fatalf("expected valid query pagination, got %v", errs)
+	}
+
+	src.Pagination.Next = "a.next"
+	errs := ValidateSource(src)
+	if len(errs) != 1 || !strings.Contains(errs[0], "mutually exclusive") {
+		t.Fatalf("expected param and next conflict reported, got %v", errs)
+	}
+}
+
+func TestValidateSource_DefaultDeadlineTime(t *testing.T) {
+	src := SourceConfig{ID: "cutoff", Name: "Cutoff", Strategy: "wordpress_rest", BaseURL: "https://example.org"}
+	src.Detail.Parse.DefaultDeadlineTime = "17:00"
+	if errs := ValidateSource(src); len(errs) != 0 {
+		t.Fatalf("expected a valid cutoff, got %v", errs)
+	}
+
+	src.Detail.Parse.DefaultDeadlineTime = "close of business"
+	errs := ValidateSource(src)
+	if len(errs) != 1 || !strings.HasPrefix(errs[0], "detail.parse.default_deadline_time") {
+		t.Fatalf("expected the bad cutoff reported, got %v", errs)
+	}
+}
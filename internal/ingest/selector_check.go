@@ -0,0 +1,115 @@
+package ingest
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// SelectorMatch is how one configured list selector fared on a page.
+type SelectorMatch struct {
+	Field    string `json:"field"`
+	Selector string `json:"selector"`
+	// Matched is the alternative that was used, for pipe-separated selectors.
+	Matched string `json:"matched,omitempty"`
+	// Count is the number of elements the container matched, or for the
+	// other fields the number of items the selector found a value in.
+	Count int    `json:"count"`
+	Error string `json:"error,omitempty"`
+}
+
+// SelectorSample is one list item as html_generic would read it.
+type SelectorSample struct {
+	Title   string `json:"title"`
+	Link    string `json:"link"`
+	Date    string `json:"date,omitempty"`
+	Summary string `json:"summary,omitempty"`
+}
+
+// SelectorCheck is the result of running a source's list selectors against
+// a single page without saving anything.
+type SelectorCheck struct {
+	Items     int              `json:"items"`
+	Selectors []SelectorMatch  `json:"selectors"`
+	Samples   []SelectorSample `json:"samples"`
+}
+
+// CheckSelectors runs sel against a fetched list page the way runLegacy
+// reads it, returning per-selector match counts and up to maxSamples items.
+// Relative links are resolved against pageURL.
+func CheckSelectors(doc *goquery.Document, pageURL string, sel SelectorConfig, maxSamples int) SelectorCheck {
+	matched, items := firstMatchingSelector(doc.Selection, sel.Container)
+	out := SelectorCheck{
+		Items:   items.Length(),
+		Samples: []SelectorSample{},
+	}
+	out.Selectors = append(out.Selectors, SelectorMatch{
+		Field:    "container",
+		Selector: sel.Container,
+		Matched:  matched,
+		Count:    items.Length(),
+		Error:    selectorError(sel.Container),
+	})
+
+	linkAttr := sel.LinkAttr
+	if linkAttr == "" {
+		linkAttr = "href"
+	}
+	base, _ := url.Parse(pageURL)
+	counts := map[string]int{}
+	items.Each(func(_ int, item *goquery.Selection) {
+		sample := SelectorSample{
+			Title:   childText(item, sel.Title),
+			Link:    childAttr(item, sel.Link, linkAttr),
+			Date:    childText(item, sel.Date),
+			Summary: childText(item, sel.Content),
+		}
+		for field, value := range map[string]string{"title": sample.Title, "link": sample.Link, "date": sample.Date, "content": sample.Summary} {
+			if value != "" {
+				counts[field]++
+			}
+		}
+		if sample.Link != "" && base != nil {
+			if rel, err := url.Parse(sample.Link); err == nil {
+				sample.Link = base.ResolveReference(rel).String()
+			}
+		}
+		if len(out.Samples) < maxSamples {
+			out.Samples = append(out.Samples, sample)
+		}
+	})
+
+	for _, field := range []struct{ name, selector string }{
+		{"link", sel.Link},
+		{"title", sel.Title},
+		{"date", sel.Date},
+		{"content", sel.Content},
+	} {
+		if field.selector == "" && field.name != "link" {
+			continue
+		}
+		out.Selectors = append(out.Selectors, SelectorMatch{
+			Field:    field.name,
+			Selector: field.selector,
+			Count:    counts[field.name],
+			Error:    selectorError(field.selector),
+		})
+	}
+	return out
+}
+
+// selectorError reports the first alternative of selector that doesn't
+// parse; goquery silently matches nothing for those.
+func selectorError(selector string) string {
+	for _, alt := range selectorAlternatives(selector) {
+		if alt == "." {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(alt); err != nil {
+			return strings.TrimSpace(alt) + ": " + err.Error()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,49 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCheckSelectors_CountsAndSamples(t *testing.T) {
+	page := `<html><body>
+		<div class="call"><h3>Seed Fund</h3><a href="/calls/seed">More</a><span class="date">30 March 2026</span></div>
+		<div class="call"><h3>Growth Fund</h3><a href="https://grants.example.org/calls/growth">More</a></div>
+		<div class="call"><a href="/calls/untitled">More</a></div>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := CheckSelectors(doc, "https://grants.example.org/calls?page=1", SelectorConfig{
+		Container: "li.result | div.call",
+		Link:      "a",
+		Title:     "h3",
+		Date:      "span.date",
+		Content:   "p[",
+	}, 2)
+
+	if check.Items != 3 || len(check.Samples) != 2 {
+		t.Fatalf("expected 3 items and 2 samples, got %d and %d", check.Items, len(check.Samples))
+	}
+	if check.Samples[0].Title != "Seed Fund" || check.Samples[0].Link != "https://grants.example.org/calls/seed" {
+		t.Fatalf("unexpected first sample %+v", check.Samples[0])
+	}
+
+	got := map[string]SelectorMatch{}
+	for _, m := range check.Selectors {
+		got[m.Field] = m
+	}
+	if got["container"].Matched != "div.call" || got["container"].Count != 3 {
+		t.Fatalf("unexpected container match %+v", got["container"])
+	}
+	if got["link"].Count != 3 || got["title"].Count != 2 || got["date"].Count != 1 {
+		t.Fatalf("unexpected field counts %+v", check.Selectors)
+	}
+	if got["content"].Error == "" {
+		t.Fatalf("expected the broken content selector reported, got %+v", got["content"])
+	}
+}
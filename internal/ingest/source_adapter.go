@@ -18,6 +18,7 @@ type SourceAdapterRaw struct {
 	BodyHTML        string
 	AttachmentURLs  []string
 	AttachmentTexts map[string]string
+	LinkedTexts     map[string]string // Structured text of followed HTML pages
 	FetchMeta       map[string]interface{}
 }
 
@@ -43,6 +44,32 @@ type SourceAdapter interface {
 
 type GenericSourceAdapter struct {
 	Fetcher Fetcher
+
+	// BlockedMaxBytes is the root body size under which block phrases mark
+	// the fetch as blocked. Zero means DefaultBlockedMaxBytes.
+	BlockedMaxBytes int
+	// Attachments adds source-specific anchor keywords and exclusions to
+	// attachment-link detection.
+	Attachments AttachmentConfig
+	// DeadlineCutoff is when date-only deadlines on the page close.
+	DeadlineCutoff deadlineCutoff
+	// DateLocales is the source's date_locales for reading page dates.
+	DateLocales []string
+}
+
+// DefaultBlockedMaxBytes is the default size below which a 200 response is
+// checked for anti-bot wording. Real listing pages are far larger.
+const DefaultBlockedMaxBytes = 2048
+
+var blockPageRegex = regexp.MustCompile(`(?i)access denied|captcha|cloudflare|forbidden|attention required`)
+
+// looksBlocked reports whether body is a tiny anti-bot or error page rather
+// than content.
+func looksBlocked(body []byte, maxBytes int) bool {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBlockedMaxBytes
+	}
+	return len(body) < maxBytes && blockPageRegex.Match(body)
 }
 
 var attachmentAnchorRegex = regexp.MustCompile(`(?i)(calendar|schedule|timeline|dates|deadlines|guidelines|bases|cronograma|calendario|fechas|anexos|annex|attachments?)`)
@@ -63,19 +90,41 @@ func (a *GenericSourceAdapter) FetchOpportunityRaw(ctx context.Context, idOrURL
 	if err != nil {
 		return nil, err
 	}
-	fetchMeta := map[string]interface{}{
-		"root_status_code": doc.StatusCode,
-		"root_bytes":       len(payload),
-		"root_duration_ms": time.Since(start).Milliseconds(),
-		"blocked_detected": false,
-	}
+	raw := NewPageRaw(idOrURL, payload, doc.StatusCode, time.Since(start), a.BlockedMaxBytes, a.Attachments)
+	a.FetchAttachments(ctx, raw)
+	return raw, nil
+}
 
+// NewPageRaw wraps an already fetched page so evidence can be extracted
+// without fetching it again, e.g. a detail page from the list crawl.
+func NewPageRaw(pageURL string, payload []byte, statusCode int, elapsed time.Duration, blockedMaxBytes int, attachments AttachmentConfig) *SourceAdapterRaw {
 	htmlBody := string(payload)
-	attachmentURLs := collectAttachmentPDFLinks(idOrURL, htmlBody)
-	attachmentTexts := map[string]string{}
+	return &SourceAdapterRaw{
+		URL:            pageURL,
+		Domain:         extractDomain(pageURL),
+		BodyHTML:       htmlBody,
+		AttachmentURLs: collectAttachmentPDFLinks(pageURL, htmlBody, attachments),
+		FetchMeta: map[string]interface{}{
+			"root_status_code": statusCode,
+			"root_bytes":       len(payload),
+			"root_duration_ms": elapsed.Milliseconds(),
+			"blocked_detected": looksBlocked(payload, blockedMaxBytes),
+		},
+	}
+}
+
+// FetchAttachments fetches the PDF attachments linked from the root page into
+// AttachmentTexts.
+func (a *GenericSourceAdapter) FetchAttachments(ctx context.Context, raw *SourceAdapterRaw) {
+	if raw.AttachmentTexts == nil {
+		raw.AttachmentTexts = map[string]string{}
+	}
+	if raw.FetchMeta == nil {
+		raw.FetchMeta = map[string]interface{}{}
+	}
 	pdfParseErrors := 0
 
-	for _, attachmentURL := range attachmentURLs {
+	for _, attachmentURL := range raw.AttachmentURLs {
 		attachmentStart := time.Now()
 		doc, err := a.Fetcher.Fetch(ctx, attachmentURL)
 		if err != nil {
@@ -92,27 +141,76 @@ func (a *GenericSourceAdapter) FetchOpportunityRaw(ctx context.Context, idOrURL
 			pdfParseErrors++
 			continue
 		}
-		attachmentTexts[attachmentURL] = text
-		fetchMeta[fmt.Sprintf("pdf_%s_duration_ms", attachmentURL)] = time.Since(attachmentStart).Milliseconds()
+		raw.AttachmentTexts[attachmentURL] = text
+		raw.FetchMeta[fmt.Sprintf("pdf_%s_duration_ms", attachmentURL)] = time.Since(attachmentStart).Milliseconds()
 	}
-	fetchMeta["attachment_count"] = len(attachmentURLs)
-	fetchMeta["pdfs_parsed"] = len(attachmentTexts)
-	fetchMeta["pdf_parse_errors"] = pdfParseErrors
-	fetchMeta["pdf_unparseable"] = pdfParseErrors > 0
+	raw.FetchMeta["attachment_count"] = len(raw.AttachmentURLs)
+	raw.FetchMeta["pdfs_parsed"] = len(raw.AttachmentTexts)
+	raw.FetchMeta["pdf_parse_errors"] = pdfParseErrors
+	raw.FetchMeta["pdf_unparseable"] = pdfParseErrors > 0
+}
 
-	return &SourceAdapterRaw{
-		URL:             idOrURL,
-		Domain:          extractDomain(idOrURL),
-		BodyHTML:        htmlBody,
-		AttachmentURLs:  attachmentURLs,
-		AttachmentTexts: attachmentTexts,
-		FetchMeta:       fetchMeta,
-	}, nil
+// FetchFollowLinks fetches configured follow links exactly one hop deep. PDFs are
+// merged into AttachmentTexts; HTML pages into LinkedTexts. Links already fetched
+// as the root page or as attachments are skipped, and links found on followed
+// pages are never followed further.
+func (a *GenericSourceAdapter) FetchFollowLinks(ctx context.Context, raw *SourceAdapterRaw, links []string) {
+	seen := map[string]bool{CanonicalizeURL(raw.URL): true}
+	for _, attachmentURL := range raw.AttachmentURLs {
+		seen[CanonicalizeURL(attachmentURL)] = true
+	}
+	if raw.AttachmentTexts == nil {
+		raw.AttachmentTexts = map[string]string{}
+	}
+	if raw.LinkedTexts == nil {
+		raw.LinkedTexts = map[string]string{}
+	}
+
+	followed := make([]string, 0, len(links))
+	failures := 0
+	for _, link := range links {
+		canonical := CanonicalizeURL(link)
+		if seen[canonical] {
+			continue
+		}
+		seen[canonical] = true
+
+		doc, err := a.Fetcher.Fetch(ctx, link)
+		if err != nil {
+			failures++
+			continue
+		}
+		contentType := strings.ToLower(doc.ContentType)
+		if strings.Contains(contentType, "pdf") || strings.Contains(strings.ToLower(link), ".pdf") {
+			text, err := readPDFText(doc.Body)
+			doc.Body.Close()
+			if err != nil {
+				failures++
+				continue
+			}
+			raw.AttachmentTexts[link] = text
+		} else {
+			payload, err := io.ReadAll(doc.Body)
+			doc.Body.Close()
+			if err != nil {
+				failures++
+				continue
+			}
+			raw.LinkedTexts[link] = buildStructuredExtractionText(string(payload))
+		}
+		followed = append(followed, link)
+	}
+
+	if raw.FetchMeta == nil {
+		raw.FetchMeta = map[string]interface{}{}
+	}
+	raw.FetchMeta["followed_links"] = followed
+	raw.FetchMeta["follow_link_errors"] = failures
 }
 
 func (a *GenericSourceAdapter) ExtractCandidates(raw *SourceAdapterRaw) (*SourceAdapterCandidates, error) {
 	text := strings.ToLower(buildStructuredExtractionText(raw.BodyHTML))
-	htmlEvidence := parseDeadlineEvidenceFromText(text, "html", raw.URL, 0.8)
+	htmlEvidence := parseDeadlineEvidenceFromText(text, "html", raw.URL, a.DateLocales, 0.8, a.DeadlineCutoff)
 	htmlCandidates := parseDateCandidatesFromText(text)
 	candidates := make([]string, 0, len(htmlCandidates))
 	candidates = append(candidates, htmlCandidates...)
@@ -142,13 +240,27 @@ func (a *GenericSourceAdapter) ExtractCandidates(raw *SourceAdapterRaw) (*Source
 		pdfsParsed++
 		before := len(candidates)
 		candidates = mergeUniqueFold(candidates, parseDateCandidatesFromText(strings.ToLower(attachmentText)))
-		pdfEvidence := parseDeadlineEvidenceFromText(strings.ToLower(attachmentText), "pdf", raw.URL, 0.85)
+		pdfEvidence := parseDeadlineEvidenceFromText(strings.ToLower(attachmentText), "pdf", raw.URL, a.DateLocales, 0.85, a.DeadlineCutoff)
 		deadlineEvidence = append(deadlineEvidence, pdfEvidence...)
 		if len(candidates) > before {
 			attachmentCandidatesFound = true
 		}
 	}
 
+	linkedCandidatesFound := false
+	for linkURL, linkedText := range raw.LinkedTexts {
+		before := len(candidates)
+		lower := strings.ToLower(linkedText)
+		candidates = mergeUniqueFold(candidates, parseDateCandidatesFromText(lower))
+		deadlineEvidence = append(deadlineEvidence, parseDeadlineEvidenceFromText(lower, "linked_html", linkURL, a.DateLocales, 0.8, a.DeadlineCutoff)...)
+		if len(candidates) > before {
+			linkedCandidatesFound = true
+		}
+	}
+	if len(raw.LinkedTexts) > 0 {
+		evidence["followed_links"] = len(raw.LinkedTexts)
+	}
+
 	statusRaw := ""
 	if strings.Contains(text, "closed") || strings.Contains(text, "cerrad") || strings.Contains(text, "finalizada") {
 		statusRaw = "closed"
@@ -170,6 +282,10 @@ func (a *GenericSourceAdapter) ExtractCandidates(raw *SourceAdapterRaw) (*Source
 		confidence = 0.7
 		evidence["authority"] = "attachments"
 	}
+	if linkedCandidatesFound && len(htmlCandidates) == 0 && !attachmentCandidatesFound {
+		confidence = 0.75
+		evidence["authority"] = "linked_page_html"
+	}
 	if isResults {
 		confidence = 0.95
 	}
@@ -197,12 +313,29 @@ func (a *GenericSourceAdapter) ExtractCandidates(raw *SourceAdapterRaw) (*Source
 	}, nil
 }
 
-func collectAttachmentPDFLinks(baseURL, htmlBody string) []string {
+// collectAttachmentPDFLinks returns links that look like schedule or annex
+// documents: the built-in anchor pattern, the source's extra anchor keywords,
+// or a PDF/download URL, minus anything the source's exclude pattern matches.
+func collectAttachmentPDFLinks(baseURL, htmlBody string, rules AttachmentConfig) []string {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlBody))
 	if err != nil {
 		return nil
 	}
 
+	var exclude *regexp.Regexp
+	if rules.Exclude != "" {
+		// ValidateSource rejects bad patterns; one that slips through is ignored.
+		if re, err := regexp.Compile("(?i)" + rules.Exclude); err == nil {
+			exclude = re
+		}
+	}
+	extraAnchors := make([]string, 0, len(rules.AnchorKeywords))
+	for _, keyword := range rules.AnchorKeywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			extraAnchors = append(extraAnchors, keyword)
+		}
+	}
+
 	baseParsed, _ := url.Parse(baseURL)
 	seen := map[string]bool{}
 	var out []string
@@ -214,10 +347,13 @@ func collectAttachmentPDFLinks(baseURL, htmlBody string) []string {
 		}
 		hrefLower := strings.ToLower(strings.TrimSpace(href))
 		anchorText := strings.TrimSpace(strings.ToLower(sel.Text()))
-		isLikelyDoc := attachmentAnchorRegex.MatchString(anchorText) || strings.Contains(hrefLower, ".pdf") || strings.Contains(hrefLower, "download") || strings.Contains(hrefLower, "/document/")
+		isLikelyDoc := attachmentAnchorRegex.MatchString(anchorText) || containsAny(anchorText, extraAnchors) || strings.Contains(hrefLower, ".pdf") || strings.Contains(hrefLower, "download") || strings.Contains(hrefLower, "/document/")
 		if !isLikelyDoc {
 			return
 		}
+		if exclude != nil && (exclude.MatchString(anchorText) || exclude.MatchString(href)) {
+			return
+		}
 
 		ref, err := url.Parse(strings.TrimSpace(href))
 		if err != nil {
@@ -263,14 +399,13 @@ func buildStructuredExtractionText(htmlBody string) string {
 		parts = append(parts, cells[0]+": "+strings.Join(cells[1:], " | "))
 	})
 
-	labelKeywords := []string{"cierre", "postul", "deadline", "closing", "submission", "fecha límite", "fecha maxima", "cronograma", "calendario", "opening", "apertura"}
 	doc.Find("p, li, div, td, th, h1, h2, h3, h4, h5, h6, strong").Each(func(_ int, sel *goquery.Selection) {
 		text := cleanText(sel.Text())
 		if text == "" || len(text) > 220 {
 			return
 		}
 		lower := strings.ToLower(text)
-		for _, keyword := range labelKeywords {
+		for _, keyword := range deadlineLabelKeywords {
 			if strings.Contains(lower, keyword) {
 				nextText := cleanText(sel.Next().Text())
 				if nextText != "" && nextText != text {
@@ -0,0 +1,194 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestCollectFollowLinks_ResolvesAndDedupes(t *testing.T) {
+	html := `<html><body>
+		<a class="more" href="/convocatoria/cronograma">Cronograma</a>
+		<a class="more" href="/convocatoria/cronograma?utm_source=x">Cronograma</a>
+		<a class="more" href="#top">Top</a>
+		<a class="more" href="/convocatoria">Self</a>
+		<div class="bases"><a href="https://files.example.org/bases.pdf">Bases</a></div>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	links := collectFollowLinks("https://example.org/convocatoria", doc, []string{"a.more", "div.bases"})
+	want := []string{"https://example.org/convocatoria/cronograma", "https://files.example.org/bases.pdf"}
+	if len(links) != len(want) {
+		t.Fatalf("expected %v, got %v", want, links)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, links)
+		}
+	}
+}
+
+func TestFetchFollowLinks_MergesLinkedPageEvidence(t *testing.T) {
+	root := "https://example.org/convocatoria"
+	tab := "https://example.org/convocatoria/cronograma"
+	mock := &MockFetcher{Data: map[string][]byte{
+		tab: []byte(`<html><body><table><tr><td>Cierre de postulaciones</td><td>30/03/2030</td></tr></table></body></html>`),
+	}}
+	adapter := NewGenericSourceAdapter(mock)
+	raw := &SourceAdapterRaw{URL: root, BodyHTML: "<html><body>Programa de innovación</body></html>"}
+
+	adapter.FetchFollowLinks(context.Background(), raw, []string{root, tab, tab})
+	if len(raw.LinkedTexts) != 1 {
+		t.Fatalf("expected exactly one followed page, got %d", len(raw.LinkedTexts))
+	}
+
+	candidates, err := adapter.ExtractCandidates(raw)
+	if err != nil {
+		t.Fatalf("extract failed: %v", err)
+	}
+	if len(candidates.DeadlineCandidates) == 0 {
+		t.Fatal("expected deadline candidates from the followed page")
+	}
+	if candidates.Evidence["authority"] != "linked_page_html" {
+		t.Fatalf("expected linked_page_html authority, got %v", candidates.Evidence["authority"])
+	}
+}
+
+// countingFetcher records how many times each URL was fetched.
+type countingFetcher struct {
+	Fetcher
+	hits map[string]int
+}
+
+func (f *countingFetcher) Fetch(ctx context.Context, url string, opts ...FetchOption) (*FetchedDocument, error) {
+	f.hits[url]++
+	return f.Fetcher.Fetch(ctx, url, opts...)
+}
+
+func TestFetchFollowLinks_FetchesPDFOnce(t *testing.T) {
+	pdf := "https://example.org/bases.pdf"
+	fetcher := &countingFetcher{
+		Fetcher: &MockFetcher{Data: map[string][]byte{pdf: []byte("not really a pdf")}},
+		hits:    map[string]int{},
+	}
+	adapter := NewGenericSourceAdapter(fetcher)
+	raw := &SourceAdapterRaw{URL: "https://example.org/convocatoria"}
+
+	adapter.FetchFollowLinks(context.Background(), raw, []string{pdf})
+	if fetcher.hits[pdf] != 1 {
+		t.Fatalf("expected the PDF to be fetched once, got %d", fetcher.hits[pdf])
+	}
+	if raw.FetchMeta["follow_link_errors"] != 1 {
+		t.Fatalf("expected the unreadable PDF to count as a failure, got %v", raw.FetchMeta["follow_link_errors"])
+	}
+}
+
+func TestFetchOpportunityRaw_FlagsTinyBlockPage(t *testing.T) {
+	blocked := "https://example.org/blocked"
+	content := "https://example.org/convocatoria"
+	mock := &MockFetcher{Data: map[string][]byte{
+		blocked: []byte(`<html><head><title>Attention Required! | Cloudflare</title></head><body>Please complete the captcha. Access denied.</body></html>`),
+		content: []byte(`<html><body><h1>Convocatoria</h1><p>Access denied to late submissions.</p>` + strings.Repeat("<p>Bases y requisitos</p>", 200) + `</body></html>`),
+	}}
+	adapter := NewGenericSourceAdapter(mock)
+
+	raw, err := adapter.FetchOpportunityRaw(context.Background(), blocked)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if raw.FetchMeta["blocked_detected"] != true {
+		t.Fatalf("expected tiny block page to be flagged, got %v", raw.FetchMeta["blocked_detected"])
+	}
+	_, _, _, flagged := extractFetchMeta(map[string]interface{}{"fetch_meta": raw.FetchMeta})
+	if flagged == nil || !*flagged {
+		t.Fatal("expected extractFetchMeta to read blocked_detected=true")
+	}
+
+	raw, err = adapter.FetchOpportunityRaw(context.Background(), content)
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if raw.FetchMeta["blocked_detected"] != false {
+		t.Fatal("a full page mentioning a block phrase must not be flagged")
+	}
+
+	adapter.BlockedMaxBytes = 64
+	raw, _ = adapter.FetchOpportunityRaw(context.Background(), blocked)
+	if raw.FetchMeta["blocked_detected"] != false {
+		t.Fatal("expected a lower threshold to let the page through")
+	}
+}
+
+func TestApplyEvidenceEnrichment_ReusesDetailPage(t *testing.T) {
+	pageURL := "https://example.org/convocatoria"
+	body := []byte(`<html><body><p>Cierre de postulaciones: 30/03/2030</p></body></html>`)
+	// The mock has no entry for the page, so any refetch would fail.
+	p := &Pipeline{Fetcher: &MockFetcher{Data: map[string][]byte{}}}
+	opp := Opportunity{
+		ExternalURL: pageURL,
+		DetailPage:  NewPageRaw(pageURL, body, 200, 0, 0, AttachmentConfig{}),
+	}
+
+	if err := p.applyEvidenceEnrichment(context.Background(), &opp); err != nil {
+		t.Fatalf("enrichment failed: %v", err)
+	}
+	if opp.LastEnrichedAt == nil {
+		t.Fatal("expected last_enriched_at to be set")
+	}
+	if len(opp.DeadlineEvidence) == 0 {
+		t.Fatal("expected deadline evidence from the reused page")
+	}
+	status, _, _, blocked := extractFetchMeta(opp.SourceEvidenceJSON)
+	if status == nil || *status != 200 || blocked == nil || *blocked {
+		t.Fatalf("expected fetch_meta from the crawl, got status=%v blocked=%v", status, blocked)
+	}
+}
+
+func TestCollectAttachmentPDFLinks_SourceAnchorsAndExclude(t *testing.T) {
+	html := `<html><body>
+		<a href="/docs/actividades">Calendario de actividades</a>
+		<a href="/docs/dt">Documento Técnico</a>
+		<a href="/docs/conv.pdf">Convocatoria PDF</a>
+		<a href="/docs/formulario-postulacion.pdf">Formulario de postulación</a>
+		<a href="/docs/plantilla.pdf">Plantilla de presupuesto</a>
+		<a href="/noticias">Noticias</a>
+	</body></html>`
+
+	defaults := collectAttachmentPDFLinks("https://example.org/call", html, AttachmentConfig{})
+	for _, link := range defaults {
+		if strings.HasSuffix(link, "/docs/dt") {
+			t.Fatalf("built-in pattern should not match %q", link)
+		}
+	}
+
+	got := collectAttachmentPDFLinks("https://example.org/call", html, AttachmentConfig{
+		AnchorKeywords: []string{"Documento técnico"},
+		Exclude:        `formulario|plantilla`,
+	})
+	want := []string{
+		"https://example.org/docs/actividades",
+		"https://example.org/docs/dt",
+		"https://example.org/docs/conv.pdf",
+	}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("attachments = %v, want %v", got, want)
+	}
+}
+
+func TestRegistrySourceForURL(t *testing.T) {
+	reg := &Registry{Sources: []SourceConfig{
+		{ID: "a", BaseURL: "https://www.proinnovate.gob.pe/convocatorias"},
+		{ID: "b", BaseURL: "https://example.org"},
+	}}
+	if src := reg.SourceForURL("https://proinnovate.gob.pe/convocatoria/123"); src == nil || src.ID != "a" {
+		t.Fatalf("expected source a, got %+v", src)
+	}
+	if src := reg.SourceForURL("https://other.org/x"); src != nil {
+		t.Fatalf("expected no source, got %+v", src)
+	}
+}
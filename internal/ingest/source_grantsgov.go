@@ -15,16 +15,20 @@ import (
 
 // GrantsGovFetcher fetches opportunities from the Grants.gov search2 API.
 type GrantsGovFetcher struct {
-	Client  *http.Client
-	BaseURL string
+	Client    *http.Client
+	BaseURL   string
+	DetailURL string
+
+	// IncludeClosed also requests closed opportunities and keeps records whose
+	// close date has passed (with OppStatus "closed") instead of skipping them.
+	IncludeClosed bool
 }
 
 func NewGrantsGovFetcher() *GrantsGovFetcher {
 	return &GrantsGovFetcher{
-		Client: &http.Client{
-			Timeout: 60 * time.Second,
-		},
-		BaseURL: "https://api.grants.gov/v1/api/search2",
+		Client:    newSafeHTTPClient(60 * time.Second),
+		BaseURL:   "https://api.grants.gov/v1/api/search2",
+		DetailURL: "https://api.grants.gov/v1/api/fetchOpportunity",
 	}
 }
 
@@ -65,9 +69,13 @@ type GrantsGovRecord struct {
 
 // FetchOpportunities fetches a page of opportunities from Grants.gov search2 API.
 func (f *GrantsGovFetcher) FetchOpportunities(ctx context.Context, keyword string, rows, startRecord int) ([]Opportunity, int, error) {
+	oppStatuses := "posted"
+	if f.IncludeClosed {
+		oppStatuses = "posted|closed"
+	}
 	searchReq := GrantsGovSearchRequest{
 		Keyword:        keyword,
-		OppStatuses:    "posted",
+		OppStatuses:    oppStatuses,
 		SortBy:         "openDate|desc",
 		Rows:           rows,
 		StartRecordNum: startRecord,
@@ -147,7 +155,12 @@ func (f *GrantsGovFetcher) FetchOpportunities(ctx context.Context, keyword strin
 				// CloseDate is strictly a date (00:00:00). We assume it expires at the end of that day.
 				expiration := t.Add(24 * time.Hour)
 				if expiration.Before(time.Now().UTC()) {
-					continue
+					if !f.IncludeClosed {
+						continue
+					}
+					// Keep it for historical coverage; the status engine
+					// marks it closed from the status and past deadline.
+					opp.OppStatus = "closed"
 				}
 				opp.DeadlineAt = &t
 				opp.DeadlineStr = rec.CloseDate
@@ -170,21 +183,7 @@ func (f *GrantsGovFetcher) FetchOpportunities(ctx context.Context, keyword strin
 				if elig, ok := syn["applicantEligibilityDesc"].(string); ok {
 					opp.Eligibility = []string{elig}
 				}
-				// Try to parse amounts if present in synopsis
-				// They come as strings or numbers, need robust handling
-				if ceiling, ok := syn["awardCeiling"].(string); ok && ceiling != "" {
-					// Remove $ and ,
-					clean := strings.ReplaceAll(strings.ReplaceAll(ceiling, "$", ""), ",", "")
-					if val, err := strconv.ParseFloat(clean, 64); err == nil {
-						opp.AmountMax = val
-					}
-				}
-				if floor, ok := syn["awardFloor"].(string); ok && floor != "" {
-					clean := strings.ReplaceAll(strings.ReplaceAll(floor, "$", ""), ",", "")
-					if val, err := strconv.ParseFloat(clean, 64); err == nil {
-						opp.AmountMin = val
-					}
-				}
+				applyGrantsGovSynopsisAmounts(&opp, syn)
 			}
 		} else {
 			log.Printf("[GrantsGov] Failed to fetch details for %s: %v", rec.ID, err)
@@ -196,9 +195,69 @@ func (f *GrantsGovFetcher) FetchOpportunities(ctx context.Context, keyword strin
 	return opportunities, apiResp.Data.HitCount, nil
 }
 
+// applyGrantsGovSynopsisAmounts copies award amounts, total program funding
+// and the expected number of awards from a fetchOpportunity synopsis. The API
+// returns these as numbers or as formatted strings ("$1,500,000") depending on
+// the record, so both are accepted.
+func applyGrantsGovSynopsisAmounts(opp *Opportunity, syn map[string]interface{}) {
+	if val, ok := synopsisAmount(syn["awardCeiling"]); ok {
+		opp.AmountMax = val
+	}
+	if val, ok := synopsisAmount(syn["awardFloor"]); ok {
+		opp.AmountMin = val
+	}
+	if currency, ok := syn["currency"].(string); ok && len(strings.TrimSpace(currency)) == 3 {
+		opp.Currency = strings.ToUpper(strings.TrimSpace(currency))
+	}
+
+	total, hasTotal := synopsisAmount(syn["estimatedFunding"])
+	if t, ok := synopsisAmount(syn["estimatedTotalProgramFunding"]); ok {
+		total, hasTotal = t, true
+	}
+	awards, hasAwards := synopsisAmount(syn["expectedNumberOfAwards"])
+	if !hasTotal && !hasAwards {
+		return
+	}
+	if opp.SourceEvidenceJSON == nil {
+		opp.SourceEvidenceJSON = map[string]interface{}{}
+	}
+	if hasTotal {
+		opp.SourceEvidenceJSON["total_program_funding"] = total
+	}
+	if hasAwards {
+		opp.SourceEvidenceJSON["expected_number_of_awards"] = int(awards)
+	}
+}
+
+// synopsisAmount reads a positive amount from a JSON number or a formatted
+// string. Zero means "not specified" in Grants.gov synopses.
+func synopsisAmount(v interface{}) (float64, bool) {
+	var val float64
+	switch n := v.(type) {
+	case float64:
+		val = n
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		val = f
+	case string:
+		clean := strings.NewReplacer("$", "", ",", "", " ", "").Replace(strings.TrimSpace(n))
+		f, err := strconv.ParseFloat(clean, 64)
+		if err != nil {
+			return 0, false
+		}
+		val = f
+	default:
+		return 0, false
+	}
+	return val, val > 0
+}
+
 // FetchOpportunityDetails fetches detailed information for a specific opportunity ID
 func (f *GrantsGovFetcher) FetchOpportunityDetails(ctx context.Context, oppID string) (map[string]interface{}, error) {
-	url := "https://api.grants.gov/v1/api/fetchOpportunity"
+	url := f.DetailURL
 	reqBody := map[string]string{"id": oppID}
 
 	jsonBody, _ := json.Marshal(reqBody)
@@ -215,7 +274,9 @@ func (f *GrantsGovFetcher) FetchOpportunityDetails(ctx context.Context, oppID st
 	defer resp.Body.Close()
 
 	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber() // keep large funding amounts exact
+	if err := dec.Decode(&result); err != nil {
 		return nil, err
 	}
 	return result, nil
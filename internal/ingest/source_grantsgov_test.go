@@ -0,0 +1,108 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestApplyGrantsGovSynopsisAmounts_NumericAndStringFields(t *testing.T) {
+	body := `{"synopsis": {
+		"awardCeiling": 750000,
+		"awardFloor": "$50,000",
+		"estimatedTotalProgramFunding": "12,500,000",
+		"expectedNumberOfAwards": 25,
+		"currency": "usd"
+	}}`
+	var details map[string]interface{}
+	dec := json.NewDecoder(strings.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&details); err != nil {
+		t.Fatal(err)
+	}
+
+	opp := Opportunity{Currency: "EUR"}
+	applyGrantsGovSynopsisAmounts(&opp, details["synopsis"].(map[string]interface{}))
+
+	if opp.AmountMax != 750000 || opp.AmountMin != 50000 {
+		t.Fatalf("expected 50000-750000, got %v-%v", opp.AmountMin, opp.AmountMax)
+	}
+	if opp.Currency != "USD" {
+		t.Fatalf("expected synopsis currency, got %s", opp.Currency)
+	}
+	if opp.SourceEvidenceJSON["total_program_funding"] != 12500000.0 || opp.SourceEvidenceJSON["expected_number_of_awards"] != 25 {
+		t.Fatalf("unexpected funding evidence: %v", opp.SourceEvidenceJSON)
+	}
+}
+
+func TestApplyGrantsGovSynopsisAmounts_PlainFloatsAndBlanks(t *testing.T) {
+	// Decoded without UseNumber, numbers arrive as float64.
+	syn := map[string]interface{}{
+		"awardCeiling":           float64(300000),
+		"awardFloor":             "0",
+		"expectedNumberOfAwards": "",
+		"currency":               "",
+	}
+	opp := Opportunity{Currency: "USD"}
+	applyGrantsGovSynopsisAmounts(&opp, syn)
+
+	if opp.AmountMax != 300000 || opp.AmountMin != 0 {
+		t.Fatalf("expected ceiling only, got %v-%v", opp.AmountMin, opp.AmountMax)
+	}
+	if opp.Currency != "USD" || opp.SourceEvidenceJSON != nil {
+		t.Fatalf("expected no currency change or evidence, got %s %v", opp.Currency, opp.SourceEvidenceJSON)
+	}
+}
+
+func TestGrantsGovFetcher_IncludeClosed(t *testing.T) {
+	var statuses []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/search2", func(w http.ResponseWriter, r *http.Request) {
+		var req GrantsGovSearchRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		statuses = append(statuses, req.OppStatuses)
+		fmt.Fprint(w, `{"data": {"hitCount": 2, "oppHits": [
+			{"id": "1", "number": "OPEN-1", "title": "Open grant", "closeDate": "12/31/2099", "oppStatus": "posted"},
+			{"id": "2", "number": "PAST-1", "title": "Past grant", "closeDate": "01/15/2020", "oppStatus": "posted"}
+		]}}`)
+	})
+	mux.HandleFunc("/fetchOpportunity", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"synopsis": {}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetcher := NewGrantsGovFetcher()
+	fetcher.Client = newSafeHTTPClient(5*time.Second, testServerHost(t, server.URL))
+	fetcher.BaseURL = server.URL + "/search2"
+	fetcher.DetailURL = server.URL + "/fetchOpportunity"
+
+	opps, _, err := fetcher.FetchOpportunities(context.Background(), "", 25, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opps) != 1 || opps[0].OpportunityNumber != "OPEN-1" || statuses[0] != "posted" {
+		t.Fatalf("expected the past grant skipped by default, got %d opps (statuses %q)", len(opps), statuses[0])
+	}
+
+	fetcher.IncludeClosed = true
+	opps, _, err = fetcher.FetchOpportunities(context.Background(), "", 25, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opps) != 2 || statuses[1] != "posted|closed" {
+		t.Fatalf("expected both grants with closed requested, got %d opps (statuses %q)", len(opps), statuses[1])
+	}
+	past := opps[1]
+	if past.OppStatus != "closed" || past.DeadlineAt == nil {
+		t.Fatalf("expected the past grant kept as closed with its deadline, got %q %v", past.OppStatus, past.DeadlineAt)
+	}
+	if decision := ComputeStatusDecision(past, time.Now()); decision.NormalizedStatus != "closed" {
+		t.Fatalf("expected the status engine to close it, got %s/%s", decision.NormalizedStatus, decision.StatusReason)
+	}
+}
@@ -1,6 +1,8 @@
 package ingest
 
 import (
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -11,6 +13,10 @@ type StatusDecision struct {
 	StatusConfidence float64
 	NextDeadlineAt   *time.Time
 	IsResultsPage    bool
+
+	// TimelineInversion holds the offending dates when the reason is
+	// inconsistent_open_close, for recording in source evidence.
+	TimelineInversion map[string]interface{}
 }
 
 // resultsKeywords are phrases that indicate a page is displaying results/winners
@@ -49,15 +55,20 @@ func ComputeStatusDecision(opp Opportunity, now time.Time) StatusDecision {
 		}
 	}
 
-	mappedSource := mapSourceStatusRaw(opp.SourceStatusRaw)
-	if mappedSource == "" {
-		mappedSource = mapSourceStatusRaw(opp.OppStatus)
-	}
+	mappedSource := mappedSourceStatus(opp)
 
 	if mappedSource == "archived" {
 		return StatusDecision{NormalizedStatus: "archived", StatusReason: "source_archived", StatusConfidence: 0.95, NextDeadlineAt: nextDeadline}
 	}
 
+	if mappedSource == "funded" {
+		return StatusDecision{NormalizedStatus: "funded", StatusReason: "source_funded", StatusConfidence: 0.95, NextDeadlineAt: nextDeadline}
+	}
+
+	if inversion := timelineInversion(opp, nextDeadline); inversion != nil {
+		return StatusDecision{NormalizedStatus: "needs_review", StatusReason: "inconsistent_open_close", StatusConfidence: 0.3, NextDeadlineAt: nextDeadline, TimelineInversion: inversion}
+	}
+
 	if mappedSource == "closed" {
 		if effectiveRolling || (nextDeadline != nil && nextDeadline.After(now)) || (opp.CloseAt != nil && opp.CloseAt.After(now)) {
 			return StatusDecision{NormalizedStatus: "needs_review", StatusReason: "inconsistent_dates", StatusConfidence: 0.35, NextDeadlineAt: nextDeadline}
@@ -65,6 +76,10 @@ func ComputeStatusDecision(opp Opportunity, now time.Time) StatusDecision {
 		return StatusDecision{NormalizedStatus: "closed", StatusReason: "source_closed", StatusConfidence: 0.92, NextDeadlineAt: nextDeadline}
 	}
 
+	if informationalPageCheck && !exemptFromInformationalCheck(opp, mappedSource, now) && !isActionableOpportunity(opp) {
+		return StatusDecision{NormalizedStatus: "needs_review", StatusReason: informationalPageReason, StatusConfidence: 0.3, NextDeadlineAt: nextDeadline}
+	}
+
 	if opp.OpenAt != nil && opp.OpenAt.After(now) {
 		return StatusDecision{NormalizedStatus: "upcoming", StatusReason: "open_date_in_future", StatusConfidence: 0.9, NextDeadlineAt: nextDeadline}
 	}
@@ -106,6 +121,11 @@ func ComputeStatusDecision(opp Opportunity, now time.Time) StatusDecision {
 	}
 
 	if mappedSource == "open" {
+		// The source says open but we extracted no date at all: an extraction
+		// gap, reported separately so enrichment can target it first.
+		if !hasAnyDeadlineEvidence(opp) && opp.CloseAt == nil && opp.ExpirationAt == nil {
+			return StatusDecision{NormalizedStatus: "needs_review", StatusReason: "open_source_no_date_parsed", StatusConfidence: 0.3, NextDeadlineAt: nextDeadline}
+		}
 		return StatusDecision{NormalizedStatus: "needs_review", StatusReason: "source_open_without_time_evidence", StatusConfidence: 0.3, NextDeadlineAt: nextDeadline}
 	}
 
@@ -116,6 +136,41 @@ func ComputeStatusDecision(opp Opportunity, now time.Time) StatusDecision {
 	return StatusDecision{NormalizedStatus: "needs_review", StatusReason: "inconsistent_dates", StatusConfidence: 0.4, NextDeadlineAt: nextDeadline}
 }
 
+// timelineInversion reports dates that can't both be right: opening after the
+// close date, or a next deadline before the call opens. Either usually means
+// the parser swapped the open and close dates.
+func timelineInversion(opp Opportunity, nextDeadline *time.Time) map[string]interface{} {
+	if opp.OpenAt == nil {
+		return nil
+	}
+	openAt := opp.OpenAt.UTC()
+	if opp.CloseAt != nil && openAt.After(opp.CloseAt.UTC()) {
+		return map[string]interface{}{
+			"check":    "open_after_close",
+			"open_at":  openAt.Format(time.RFC3339),
+			"close_at": opp.CloseAt.UTC().Format(time.RFC3339),
+		}
+	}
+	if nextDeadline != nil && nextDeadline.UTC().Before(openAt) {
+		return map[string]interface{}{
+			"check":            "deadline_before_open",
+			"open_at":          openAt.Format(time.RFC3339),
+			"next_deadline_at": nextDeadline.UTC().Format(time.RFC3339),
+		}
+	}
+	return nil
+}
+
+// recordTimelineInversion stores the dates behind an inconsistent_open_close
+// decision in the evidence map, and clears a stale record otherwise.
+func recordTimelineInversion(evidence map[string]interface{}, decision StatusDecision) {
+	if decision.TimelineInversion != nil {
+		evidence["timeline_inversion"] = decision.TimelineInversion
+		return
+	}
+	delete(evidence, "timeline_inversion")
+}
+
 func detectRollingEvidence(opp Opportunity) bool {
 	if opp.RollingEvidence {
 		return true
@@ -147,54 +202,82 @@ func detectRollingEvidence(opp Opportunity) bool {
 	return false
 }
 
+// Deadline candidates carry the confidence of the evidence they came from so
+// a weak regex hit cannot displace a stronger date just by being earlier.
+const (
+	// weakDeadlineConfidence is the score of loose text matches such as
+	// legacy_deadline rows; at or below it evidence never beats an
+	// authoritative date.
+	weakDeadlineConfidence = 0.5
+	// authoritativeDeadlineConfidence marks API dates and structured
+	// (JSON-LD, detail table) evidence.
+	authoritativeDeadlineConfidence = 0.9
+	// defaultDeadlineConfidence is assumed for evidence without a score and
+	// for plain deadline fields from scraped sources.
+	defaultDeadlineConfidence = 0.7
+	// apiDeadlineConfidence is given to plain deadline fields of API-first
+	// sources, which come straight from the funder's own records.
+	apiDeadlineConfidence = 0.95
+	// deadlineConfidenceWindow is how far past the earliest candidate a
+	// more confident one may be and still win.
+	deadlineConfidenceWindow = 7 * 24 * time.Hour
+)
+
+type deadlineCandidate struct {
+	at         time.Time
+	confidence float64
+}
+
 func pickNextDeadline(opp Opportunity, now time.Time) *time.Time {
-	var best *time.Time
-	var labeledCloseBest *time.Time
+	plainConfidence := defaultDeadlineConfidence
+	if isAPIFirstSource(opp.SourceDomain) || opp.SourceEvidenceJSON["authority"] == "api" {
+		plainConfidence = apiDeadlineConfidence
+	}
 
-	candidates := make([]time.Time, 0, len(opp.Deadlines)+2)
+	candidates := make([]deadlineCandidate, 0, len(opp.Deadlines)+len(opp.DeadlineEvidence)+2)
+	labeledClose := make([]deadlineCandidate, 0, len(opp.DeadlineEvidence))
 	for _, raw := range opp.Deadlines {
 		if t, ok := parseDeadlineCandidate(raw); ok {
-			candidates = append(candidates, t.UTC())
+			candidates = append(candidates, deadlineCandidate{at: t.UTC(), confidence: plainConfidence})
 		}
 	}
 	for _, ev := range opp.DeadlineEvidence {
 		if t, ok := parseDeadlineCandidate(ev.ParsedDateISO); ok {
-			candidates = append(candidates, t.UTC())
-			label := strings.ToLower(ev.Label + " " + ev.Snippet)
-			isStartLike := strings.Contains(label, "inicio") || strings.Contains(label, "apertura") || strings.Contains(label, "start") || strings.Contains(label, "opening")
-			isCloseLike := strings.Contains(label, "cierre") || strings.Contains(label, "deadline") || strings.Contains(label, "closes") || strings.Contains(label, "submission") || (strings.Contains(label, "postul") && !isStartLike)
-			if isCloseLike && !isStartLike && t.After(now) {
-				tu := t.UTC()
-				if labeledCloseBest == nil || tu.Before(*labeledCloseBest) {
-					labeledCloseBest = &tu
-				}
+			confidence := ev.Confidence
+			if confidence <= 0 {
+				confidence = defaultDeadlineConfidence
+			}
+			candidate := deadlineCandidate{at: t.UTC(), confidence: confidence}
+			candidates = append(candidates, candidate)
+			if evidenceRole(ev) == "close" {
+				labeledClose = append(labeledClose, candidate)
 			}
 		}
 	}
 
-	if labeledCloseBest != nil {
-		return labeledCloseBest
-	}
-
 	if opp.NextDeadlineAt != nil {
-		candidates = append(candidates, opp.NextDeadlineAt.UTC())
+		candidates = append(candidates, deadlineCandidate{at: opp.NextDeadlineAt.UTC(), confidence: plainConfidence})
 	}
 	if opp.DeadlineAt != nil {
-		candidates = append(candidates, opp.DeadlineAt.UTC())
+		candidates = append(candidates, deadlineCandidate{at: opp.DeadlineAt.UTC(), confidence: plainConfidence})
+	}
+	if hasAuthoritativeDeadline(candidates, now) {
+		candidates = dropWeakDeadlines(candidates)
+		labeledClose = dropWeakDeadlines(labeledClose)
 	}
 
-	for _, candidate := range candidates {
-		if !candidate.After(now) {
-			continue
-		}
-		if best == nil || candidate.Before(*best) {
-			c := candidate
-			best = &c
-		}
+	// A labeled close date beats the other candidates only when it is at
+	// least as confident as the one they would pick.
+	best := bestDeadlineCandidate(candidates, now)
+	if closing := bestDeadlineCandidate(labeledClose, now); closing != nil && (best == nil || closing.confidence >= best.confidence) {
+		return &closing.at
+	}
+	if _, closeAt, ok := openClosePair(opp.DeadlineEvidence); ok && closeAt.After(now) {
+		return &closeAt
 	}
 
 	if best != nil {
-		return best
+		return &best.at
 	}
 
 	if opp.NextDeadlineAt != nil {
@@ -205,6 +288,113 @@ func pickNextDeadline(opp Opportunity, now time.Time) *time.Time {
 	return nil
 }
 
+// bestDeadlineCandidate returns the earliest future candidate, unless a more
+// confident one falls within deadlineConfidenceWindow of it.
+func bestDeadlineCandidate(candidates []deadlineCandidate, now time.Time) *deadlineCandidate {
+	var earliest *deadlineCandidate
+	for i := range candidates {
+		c := &candidates[i]
+		if !c.at.After(now) {
+			continue
+		}
+		if earliest == nil || c.at.Before(earliest.at) || (c.at.Equal(earliest.at) && c.confidence > earliest.confidence) {
+			earliest = c
+		}
+	}
+	if earliest == nil {
+		return nil
+	}
+
+	best := *earliest
+	for _, c := range candidates {
+		if !c.at.After(now) || c.at.Sub(earliest.at) > deadlineConfidenceWindow {
+			continue
+		}
+		if c.confidence > best.confidence {
+			best = c
+		}
+	}
+	return &best
+}
+
+// hasAuthoritativeDeadline reports whether any future candidate comes from an
+// API or structured source.
+func hasAuthoritativeDeadline(candidates []deadlineCandidate, now time.Time) bool {
+	for _, c := range candidates {
+		if c.at.After(now) && c.confidence >= authoritativeDeadlineConfidence {
+			return true
+		}
+	}
+	return false
+}
+
+func dropWeakDeadlines(candidates []deadlineCandidate) []deadlineCandidate {
+	out := candidates[:0]
+	for _, c := range candidates {
+		if c.confidence > weakDeadlineConfidence {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// evidenceRole classifies deadline evidence as "open", "close" or "". A
+// specific label is trusted on its own, since for "inicio … cierre …" rows the
+// snippet mentions both; the generic "deadline" label defers to the snippet.
+func evidenceRole(ev DeadlineEvidence) string {
+	label := strings.ToLower(ev.Label)
+	if label != "" && label != "deadline" {
+		if role := classifyScheduleLabel(ev.Label); role == "open" || role == "close" {
+			return role
+		}
+	} else {
+		label += " " + strings.ToLower(ev.Snippet)
+	}
+	isStartLike := strings.Contains(label, "inicio") || strings.Contains(label, "apertura") || strings.Contains(label, "start") || strings.Contains(label, "open")
+	isCloseLike := strings.Contains(label, "cierre") || strings.Contains(label, "deadline") || strings.Contains(label, "closes") || strings.Contains(label, "submission") || (strings.Contains(label, "postul") && !isStartLike)
+	switch {
+	case isCloseLike && !isStartLike:
+		return "close"
+	case isStartLike && !isCloseLike:
+		return "open"
+	}
+	return ""
+}
+
+// openClosePair handles evidence with exactly two dates where one is clearly
+// the start: the other one is the close date, however weakly it is labeled.
+func openClosePair(evidence []DeadlineEvidence) (time.Time, time.Time, bool) {
+	dates := map[time.Time]string{}
+	for _, ev := range evidence {
+		t, ok := parseDeadlineCandidate(ev.ParsedDateISO)
+		if !ok {
+			continue
+		}
+		t = t.UTC()
+		if role := evidenceRole(ev); role != "" || dates[t] == "" {
+			dates[t] = role
+		}
+	}
+	if len(dates) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+
+	var openAt, closeAt time.Time
+	opens := 0
+	for t, role := range dates {
+		if role == "open" {
+			openAt = t
+			opens++
+		} else {
+			closeAt = t
+		}
+	}
+	if opens != 1 || !closeAt.After(openAt) {
+		return time.Time{}, time.Time{}, false
+	}
+	return openAt, closeAt, true
+}
+
 func hasAnyDeadlineEvidence(opp Opportunity) bool {
 	if len(opp.DeadlineEvidence) > 0 || len(opp.Deadlines) > 0 {
 		return true
@@ -233,9 +423,16 @@ func detectResultsPage(opp Opportunity) bool {
 		opp.SourceStatusRaw,
 	}, " \n "))
 
-	// If the source explicitly says it's posted/active/open, it's not a results page
-	srcLower := strings.ToLower(opp.OppStatus)
-	if srcLower == "posted" || srcLower == "active" || srcLower == "open" || srcLower == "forecasted" {
+	// An LLM verdict recorded at ingest (detail.llm_results_check) survives recomputes.
+	if llmResults, ok := opp.SourceEvidenceJSON["results_page_llm"].(bool); ok && llmResults {
+		return true
+	}
+
+	// A source status that reads as open ("posted", "vigente", "abierta") means
+	// results wording on the page belongs to past calls. Results labels map
+	// to archived, never open, so they still fall through. Grants.gov's
+	// "forecasted" is a live call the mapping leaves alone.
+	if mapSourceStatusRaw(opp.OppStatus) == "open" || strings.EqualFold(strings.TrimSpace(opp.OppStatus), "forecasted") {
 		return false
 	}
 
@@ -253,13 +450,51 @@ func detectResultsPage(opp Opportunity) bool {
 	return false
 }
 
+// mappedSourceStatus maps the source's own status label, falling back to
+// OppStatus when source_status_raw says nothing.
+func mappedSourceStatus(opp Opportunity) string {
+	if mapped := mapSourceStatusRaw(opp.SourceStatusRaw); mapped != "" {
+		return mapped
+	}
+	return mapSourceStatusRaw(opp.OppStatus)
+}
+
+// StatusSignals are the intermediate inputs ComputeStatusDecision branches on,
+// exposed so a surprising decision can be traced to the rule that fired.
+type StatusSignals struct {
+	HasRollingEvidence bool       `json:"has_rolling_evidence"`
+	MappedSource       string     `json:"mapped_source"`
+	PickedNextDeadline *time.Time `json:"picked_next_deadline"`
+	DetectedResults    bool       `json:"detected_results_page"`
+}
+
+// ComputeStatusSignals evaluates the same helpers as ComputeStatusDecision
+// without deciding anything.
+func ComputeStatusSignals(opp Opportunity, now time.Time) StatusSignals {
+	return StatusSignals{
+		HasRollingEvidence: detectRollingEvidence(opp),
+		MappedSource:       mappedSourceStatus(opp),
+		PickedNextDeadline: pickNextDeadline(opp, now.UTC()),
+		DetectedResults:    detectResultsPage(opp),
+	}
+}
+
 func mapSourceStatusRaw(raw string) string {
 	raw = strings.ToLower(strings.TrimSpace(raw))
 	if raw == "" {
 		return ""
 	}
 
-	closedHints := []string{"closed", "cerrad", "finaliz", "cancel", "funded", "expired", "no longer accepting"}
+	// Checked before closed: an awarded call is closed too, but "funded"
+	// keeps it apart from calls still waiting on decisions.
+	fundedHints := []string{"funded", "awarded", "adjudicad"}
+	for _, hint := range fundedHints {
+		if strings.Contains(raw, hint) {
+			return "funded"
+		}
+	}
+
+	closedHints := []string{"closed", "cerrad", "finaliz", "cancel", "expired", "no longer accepting"}
 	for _, hint := range closedHints {
 		if strings.Contains(raw, hint) {
 			return "closed"
@@ -273,14 +508,15 @@ func mapSourceStatusRaw(raw string) string {
 		}
 	}
 
-	upcomingHints := []string{"forthcoming", "upcoming", "coming soon", "próxim", "anticipated"}
+	// Checked before open so "Not yet recruiting" / "not yet open" stay upcoming.
+	upcomingHints := []string{"forthcoming", "upcoming", "coming soon", "próxim", "anticipated", "not yet"}
 	for _, hint := range upcomingHints {
 		if strings.Contains(raw, hint) {
 			return "upcoming"
 		}
 	}
 
-	openHints := []string{"open", "posted", "active", "abierta", "vigente", "rolling"}
+	openHints := []string{"open", "posted", "active", "abierta", "vigente", "rolling", "recruiting"}
 	for _, hint := range openHints {
 		if strings.Contains(raw, hint) {
 			return "open"
@@ -290,22 +526,42 @@ func mapSourceStatusRaw(raw string) string {
 	return ""
 }
 
+// deadlineCandidateFormats are the date and datetime shapes our sources and
+// the LLM emit, tried in order. Zoneless datetimes are read as UTC.
+var deadlineCandidateFormats = []string{
+	time.RFC3339, // also takes fractional seconds and numeric offsets
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z0700",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+// unixMillisRegex matches the millisecond timestamps the EU portal returns;
+// shorter digit runs are left alone so compact dates aren't misread.
+var unixMillisRegex = regexp.MustCompile(`^\d{12,13}$`)
+
+// parseDeadlineCandidate parses a stored or extracted date and returns it in
+// UTC.
 func parseDeadlineCandidate(raw string) (time.Time, bool) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
 		return time.Time{}, false
 	}
 
-	formats := []string{
-		time.RFC3339,
-		"2006-01-02",
-		"2006-01-02 15:04:05",
-		"2006-01-02T15:04:05",
+	if unixMillisRegex.MatchString(raw) {
+		if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC(), true
+		}
 	}
 
-	for _, format := range formats {
+	for _, format := range deadlineCandidateFormats {
 		if t, err := time.Parse(format, raw); err == nil {
-			return t, true
+			return t.UTC(), true
 		}
 	}
 
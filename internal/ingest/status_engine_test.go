@@ -35,6 +35,28 @@ func TestComputeStatusDecision_PastDeadlineClosed(t *testing.T) {
 	}
 }
 
+func TestComputeStatusDecision_SourceFundedKeptApartFromClosed(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-30 * 24 * time.Hour)
+
+	for _, raw := range []string{"Funded", "Awarded", "Convocatoria adjudicada", "adjudicado"} {
+		decision := ComputeStatusDecision(Opportunity{SourceStatusRaw: raw, DeadlineAt: &past}, now)
+		if decision.NormalizedStatus != "funded" || decision.StatusReason != "source_funded" {
+			t.Fatalf("%q: expected funded/source_funded, got %s/%s", raw, decision.NormalizedStatus, decision.StatusReason)
+		}
+	}
+
+	decision := ComputeStatusDecision(Opportunity{OppStatus: "funded"}, now)
+	if decision.NormalizedStatus != "funded" {
+		t.Fatalf("expected LLM opp_status funded to map to funded, got %s", decision.NormalizedStatus)
+	}
+
+	decision = ComputeStatusDecision(Opportunity{SourceStatusRaw: "Closed", DeadlineAt: &past}, now)
+	if decision.NormalizedStatus != "closed" {
+		t.Fatalf("expected closed, got %s", decision.NormalizedStatus)
+	}
+}
+
 func TestComputeStatusDecision_RollingOpen(t *testing.T) {
 	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
 
@@ -65,6 +87,24 @@ func TestComputeStatusDecision_SourceOpenWithoutDatesNeedsReview(t *testing.T) {
 	}
 }
 
+func TestComputeStatusDecision_OpenSourceNoDateParsed(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+
+	decision := ComputeStatusDecision(Opportunity{SourceStatusRaw: "Abierta"}, now)
+	if decision.NormalizedStatus != "needs_review" {
+		t.Fatalf("expected needs_review, got %s", decision.NormalizedStatus)
+	}
+	if decision.StatusReason != "open_source_no_date_parsed" {
+		t.Fatalf("expected reason open_source_no_date_parsed, got %s", decision.StatusReason)
+	}
+
+	// No source status at all is still the generic missing_deadline case.
+	decision = ComputeStatusDecision(Opportunity{Title: "Fondo concursable"}, now)
+	if decision.StatusReason != "missing_deadline" {
+		t.Fatalf("expected reason missing_deadline, got %s", decision.StatusReason)
+	}
+}
+
 func TestComputeStatusDecision_InconsistentNeedsReview(t *testing.T) {
 	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
 	future := now.Add(72 * time.Hour)
@@ -102,3 +142,378 @@ func TestComputeStatusDecision_MultipleDeadlinesUsesNextFutureMin(t *testing.T)
 		t.Fatalf("expected %s, got %s", expected, decision.NextDeadlineAt.UTC())
 	}
 }
+
+func TestComputeStatusDecision_FutureOpenWithoutDeadlineUpcoming(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	opensAt := now.Add(14 * 24 * time.Hour)
+
+	decision := ComputeStatusDecision(Opportunity{Title: "Call opens in March", OpenAt: &opensAt}, now)
+	if decision.NormalizedStatus != "upcoming" {
+		t.Fatalf("expected upcoming, got %s", decision.NormalizedStatus)
+	}
+	if decision.NextDeadlineAt != nil {
+		t.Fatalf("expected no deadline, got %v", decision.NextDeadlineAt)
+	}
+}
+
+func TestComputeStatusDecision_NotYetRecruitingIsUpcoming(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+
+	for raw, want := range map[string]string{
+		"Not yet recruiting": "upcoming",
+		"Not yet open":       "upcoming",
+		"Recruiting":         "open",
+	} {
+		if got := mapSourceStatusRaw(raw); got != want {
+			t.Errorf("mapSourceStatusRaw(%q) = %q, want %q", raw, got, want)
+		}
+	}
+
+	decision := ComputeStatusDecision(Opportunity{SourceStatusRaw: "Not yet recruiting"}, now)
+	if decision.NormalizedStatus != "upcoming" || decision.StatusReason != "source_upcoming" {
+		t.Fatalf("expected upcoming, got %s (%s)", decision.NormalizedStatus, decision.StatusReason)
+	}
+}
+
+func TestComputeStatusDecision_LLMResultsEvidenceSurvivesRecompute(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	future := now.Add(30 * 24 * time.Hour)
+	opp := Opportunity{
+		Title:              "Proyectos 2025",
+		OppStatus:          "posted",
+		CloseAt:            &future,
+		SourceEvidenceJSON: map[string]interface{}{"results_page_llm": true, "results_page_confidence": 0.85},
+	}
+
+	decision := ComputeStatusDecision(opp, now)
+	if decision.NormalizedStatus != "closed" || !decision.IsResultsPage {
+		t.Fatalf("expected closed results page, got %s (results=%v)", decision.NormalizedStatus, decision.IsResultsPage)
+	}
+}
+
+func TestIsResultsPageAmbiguous(t *testing.T) {
+	withDeadline := &RawOpportunity{
+		Extra:            map[string]string{},
+		DeadlineEvidence: []DeadlineEvidence{{ParsedDateISO: "2026-05-01T23:59:59Z"}},
+	}
+	if isResultsPageAmbiguous(withDeadline, "Apply by May 1. Eligibility: universities.") {
+		t.Fatal("page with deadline evidence and no hints should not be ambiguous")
+	}
+	if !isResultsPageAmbiguous(withDeadline, "Proyectos financiados 2024: tabla de investigadores") {
+		t.Fatal("weak funded-projects hint should be ambiguous")
+	}
+	if !isResultsPageAmbiguous(&RawOpportunity{Extra: map[string]string{}}, "Programa de innovación") {
+		t.Fatal("page without any deadline or rolling evidence should be ambiguous")
+	}
+	if isResultsPageAmbiguous(&RawOpportunity{IsResultsPage: true, Extra: map[string]string{}}, "beneficiarios") {
+		t.Fatal("page already flagged by keywords is not ambiguous")
+	}
+}
+
+func TestPickNextDeadline_OpenClosePairPrefersClose(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	text := "convocatoria 2030: inicio 1 de marzo de 2030 / cierre 30 de marzo de 2030"
+	evidence := parseDeadlineEvidenceFromText(text, "detail_html", "https://example.org/convocatoria", nil, 0.82, deadlineCutoff{})
+	if len(evidence) != 2 {
+		t.Fatalf("expected two evidence dates, got %+v", evidence)
+	}
+
+	decision := ComputeStatusDecision(Opportunity{DeadlineEvidence: evidence}, now)
+	if decision.NextDeadlineAt == nil || decision.NextDeadlineAt.Format("2006-01-02") != "2030-03-30" {
+		t.Fatalf("expected next deadline 2030-03-30, got %v", decision.NextDeadlineAt)
+	}
+
+	openAt, closeAt, ok := openClosePair(evidence)
+	if !ok || openAt.Format("2006-01-02") != "2030-03-01" || closeAt.Format("2006-01-02") != "2030-03-30" {
+		t.Fatalf("expected open 2030-03-01 / close 2030-03-30, got %v %v %v", openAt, closeAt, ok)
+	}
+}
+
+func TestOpenClosePair_WeakCloseLabel(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	evidence := []DeadlineEvidence{
+		{ParsedDateISO: "2030-03-01T23:59:59Z", Label: "inicio", Snippet: "inicio 1 de marzo / calendario 30 de marzo"},
+		{ParsedDateISO: "2030-03-30T23:59:59Z", Label: "calendario", Snippet: "inicio 1 de marzo / calendario 30 de marzo"},
+	}
+
+	next := pickNextDeadline(Opportunity{DeadlineEvidence: evidence}, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-30" {
+		t.Fatalf("expected the non-start date to win, got %v", next)
+	}
+}
+
+func TestOpenClosePair_PlainOpenLabel(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	evidence := []DeadlineEvidence{
+		{ParsedDateISO: "2030-03-01T23:59:59Z", Label: "Open", Snippet: "Open: 1 March 2030"},
+		{ParsedDateISO: "2030-03-30T23:59:59Z", Label: "calendar", Snippet: "Calendar: 30 March 2030"},
+	}
+	if role := evidenceRole(evidence[0]); role != "open" {
+		t.Fatalf("expected a plain \"Open\" label to be a start date, got %q", role)
+	}
+
+	next := pickNextDeadline(Opportunity{DeadlineEvidence: evidence}, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-30" {
+		t.Fatalf("expected the non-start date to win, got %v", next)
+	}
+}
+
+func TestPickNextDeadline_PrefersConfidentDateWithinWindow(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	evidence := []DeadlineEvidence{
+		{Source: "html", ParsedDateISO: "2030-03-28T23:59:59Z", Label: "deadline", Snippet: "deadline 28 march", Confidence: 0.5},
+		{Source: "detail_table", ParsedDateISO: "2030-03-30T23:59:59Z", Label: "deadline", Snippet: "deadline 30 march", Confidence: 0.95},
+	}
+
+	next := pickNextDeadline(Opportunity{DeadlineEvidence: evidence}, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-30" {
+		t.Fatalf("expected the high-confidence later date, got %v", next)
+	}
+
+	// Outside the window the earlier date wins unless the evidence is weak
+	// against an authoritative one.
+	evidence[0].Confidence = 0.8
+	evidence[0].ParsedDateISO = "2030-03-01T23:59:59Z"
+	next = pickNextDeadline(Opportunity{DeadlineEvidence: evidence}, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-01" {
+		t.Fatalf("expected the earlier date outside the window, got %v", next)
+	}
+}
+
+func TestPickNextDeadline_WeakEvidenceDoesNotOverrideAPIDate(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	closeAt := time.Date(2030, 5, 15, 23, 59, 59, 0, time.UTC)
+	opp := Opportunity{
+		SourceDomain: "grants.gov",
+		DeadlineAt:   &closeAt,
+		DeadlineEvidence: []DeadlineEvidence{
+			{Source: "legacy", ParsedDateISO: "2030-03-01T23:59:59Z", Label: "legacy_deadline", Confidence: 0.5},
+		},
+	}
+
+	next := pickNextDeadline(opp, now)
+	if next == nil || !next.Equal(closeAt) {
+		t.Fatalf("expected the API close date, got %v", next)
+	}
+
+	opp.SourceDomain = "example.org"
+	next = pickNextDeadline(opp, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-01" {
+		t.Fatalf("expected the earlier date without an authoritative source, got %v", next)
+	}
+}
+
+func TestPickNextDeadline_LabeledCloseNeedsConfidenceToOverride(t *testing.T) {
+	now := time.Date(2030, 2, 12, 12, 0, 0, 0, time.UTC)
+	apiClose := time.Date(2030, 3, 14, 23, 59, 59, 0, time.UTC)
+	opp := Opportunity{
+		SourceDomain: "grants.gov",
+		DeadlineAt:   &apiClose,
+		DeadlineEvidence: []DeadlineEvidence{
+			{Source: "html", ParsedDateISO: "2030-03-10T23:59:59Z", Label: "cierre de postulaciones", Confidence: 0.6},
+		},
+	}
+
+	next := pickNextDeadline(opp, now)
+	if next == nil || !next.Equal(apiClose) {
+		t.Fatalf("expected the API date over a less confident close label, got %v", next)
+	}
+
+	opp.DeadlineEvidence[0].Confidence = apiDeadlineConfidence
+	next = pickNextDeadline(opp, now)
+	if next == nil || next.Format("2006-01-02") != "2030-03-10" {
+		t.Fatalf("expected an equally confident close label to win, got %v", next)
+	}
+}
+
+func TestComputeStatusDecision_OpenAfterCloseNeedsReview(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	openAt := time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC)
+	closeAt := time.Date(2026, 3, 1, 23, 59, 59, 0, time.UTC)
+
+	decision := ComputeStatusDecision(Opportunity{OpenAt: &openAt, CloseAt: &closeAt}, now)
+	if decision.NormalizedStatus != "needs_review" || decision.StatusReason != "inconsistent_open_close" {
+		t.Fatalf("expected needs_review/inconsistent_open_close, got %s/%s", decision.NormalizedStatus, decision.StatusReason)
+	}
+	if decision.TimelineInversion["check"] != "open_after_close" ||
+		decision.TimelineInversion["open_at"] != "2026-04-30T00:00:00Z" ||
+		decision.TimelineInversion["close_at"] != "2026-03-01T23:59:59Z" {
+		t.Fatalf("unexpected inversion evidence: %v", decision.TimelineInversion)
+	}
+
+	evidence := map[string]interface{}{}
+	recordTimelineInversion(evidence, decision)
+	if evidence["timeline_inversion"] == nil {
+		t.Fatal("expected the offending dates recorded in evidence")
+	}
+}
+
+func TestComputeStatusDecision_DeadlineBeforeOpenNeedsReview(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	openAt := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	decision := ComputeStatusDecision(Opportunity{OpenAt: &openAt, Deadlines: []string{"2026-03-15"}}, now)
+	if decision.StatusReason != "inconsistent_open_close" {
+		t.Fatalf("expected inconsistent_open_close, got %s", decision.StatusReason)
+	}
+	if decision.TimelineInversion["check"] != "deadline_before_open" {
+		t.Fatalf("unexpected inversion evidence: %v", decision.TimelineInversion)
+	}
+
+	// A consistent timeline leaves no inversion record behind.
+	openAt = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	decision = ComputeStatusDecision(Opportunity{OpenAt: &openAt, Deadlines: []string{"2026-03-15"}}, now)
+	if decision.StatusReason == "inconsistent_open_close" {
+		t.Fatal("open before deadline must not be flagged")
+	}
+	evidence := map[string]interface{}{"timeline_inversion": map[string]interface{}{"check": "old"}}
+	recordTimelineInversion(evidence, decision)
+	if _, ok := evidence["timeline_inversion"]; ok {
+		t.Fatal("expected a stale inversion record to be cleared")
+	}
+}
+
+func TestComputeStatusDecision_InformationalPageNeedsReview(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	opp := Opportunity{
+		Title:           "Programa de Apoyo a la Innovación",
+		Description:     "<p>El programa apoya a empresas innovadoras de forma permanente. Conoce nuestra historia y los proyectos financiados.</p>",
+		RollingEvidence: true,
+	}
+
+	decision := ComputeStatusDecision(opp, now)
+	if decision.NormalizedStatus != "needs_review" {
+		t.Fatalf("expected needs_review, got %s", decision.NormalizedStatus)
+	}
+	if decision.StatusReason != informationalPageReason {
+		t.Fatalf("expected reason %s, got %s", informationalPageReason, decision.StatusReason)
+	}
+
+	informationalPageCheck = false
+	defer func() { informationalPageCheck = true }()
+	decision = ComputeStatusDecision(opp, now)
+	if decision.StatusReason == informationalPageReason {
+		t.Fatal("expected check to be disabled")
+	}
+}
+
+func TestComputeStatusDecision_RealCallStaysOpen(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	future := now.Add(30 * 24 * time.Hour)
+	cases := map[string]Opportunity{
+		"application link": {
+			Title:           "Innovation Voucher",
+			Description:     `<p>Funding for SMEs, available year round.</p><a href="https://portal.example.org/form">Start your application</a>`,
+			RollingEvidence: true,
+		},
+		"call to action": {
+			Title:           "Fondo Semilla",
+			Description:     "<p>Postula tu proyecto durante todo el año.</p>",
+			RollingEvidence: true,
+		},
+		"deadline": {
+			Title:       "Research Grant",
+			Description: "<p>Support for early career researchers.</p>",
+			DeadlineAt:  &future,
+		},
+	}
+	for name, opp := range cases {
+		decision := ComputeStatusDecision(opp, now)
+		if decision.NormalizedStatus != "open" {
+			t.Fatalf("%s: expected open, got %s (%s)", name, decision.NormalizedStatus, decision.StatusReason)
+		}
+	}
+}
+
+func TestComputeStatusDecision_InformationalCheckExemptions(t *testing.T) {
+	now := time.Date(2026, 2, 12, 12, 0, 0, 0, time.UTC)
+	openAt := now.Add(14 * 24 * time.Hour)
+	landing := "<p>The programme supports research partnerships. Read about past projects.</p>"
+	cases := map[string]struct {
+		opp  Opportunity
+		want string
+	}{
+		"forecasted grants.gov row": {
+			Opportunity{Title: "Soil Health Research", Description: landing, SourceDomain: "www.grants.gov", OppStatus: "forecasted"},
+			"",
+		},
+		"posted grants.gov row": {
+			Opportunity{Title: "Soil Health Research", Description: landing, SourceDomain: "www.grants.gov", OppStatus: "posted"},
+			"",
+		},
+		"source says open": {
+			Opportunity{Title: "Fondo Regional", Description: landing, SourceDomain: "fondos.example.pe", SourceStatusRaw: "Vigente"},
+			"",
+		},
+		"future open date": {
+			Opportunity{Title: "Innovation Call", Description: landing, SourceDomain: "example.org", OpenAt: &openAt},
+			"upcoming",
+		},
+	}
+	for name, tc := range cases {
+		decision := ComputeStatusDecision(tc.opp, now)
+		if decision.StatusReason == informationalPageReason {
+			t.Errorf("%s: should be exempt from the informational-page check", name)
+		}
+		if tc.want != "" && decision.NormalizedStatus != tc.want {
+			t.Errorf("%s: expected %s, got %s (%s)", name, tc.want, decision.NormalizedStatus, decision.StatusReason)
+		}
+	}
+}
+
+func TestParseDeadlineCandidate_Formats(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want string // RFC3339 in UTC; empty means unparseable
+	}{
+		{"2026-03-15T17:00:00Z", "2026-03-15T17:00:00Z"},          // RFC3339 from the API sources
+		{"2026-03-15T17:00:00-05:00", "2026-03-15T22:00:00Z"},     // Lima offset
+		{"2026-03-15T17:00:00.000+01:00", "2026-03-15T16:00:00Z"}, // fractional seconds (EU)
+		{"2026-03-15T17:00Z", "2026-03-15T17:00:00Z"},             // no seconds (LLM)
+		{"2026-03-15T17:00-05:00", "2026-03-15T22:00:00Z"},        // no seconds, offset
+		{"2026-03-15T17:00:00-0500", "2026-03-15T22:00:00Z"},      // offset without colon
+		{"2026-03-15 17:00:00+00:00", "2026-03-15T17:00:00Z"},     // space separator, offset
+		{"2026-03-15 17:00:00", "2026-03-15T17:00:00Z"},           // zoneless
+		{"2026-03-15T17:00", "2026-03-15T17:00:00Z"},              // zoneless, no seconds
+		{"2026-03-15", "2026-03-15T00:00:00Z"},                    // date only
+		{"1773594000000", "2026-03-15T17:00:00Z"},                 // Unix millis (EU)
+		{"20260315", ""}, // too short for millis
+		{"next friday", ""},
+	}
+	for _, tc := range cases {
+		got, ok := parseDeadlineCandidate(tc.raw)
+		if tc.want == "" {
+			if ok {
+				t.Errorf("%q: expected no parse, got %s", tc.raw, got)
+			}
+			continue
+		}
+		if !ok || got.Format(time.RFC3339) != tc.want || got.Location() != time.UTC {
+			t.Errorf("%q: got %s (ok=%v), want %s", tc.raw, got, ok, tc.want)
+		}
+	}
+}
+
+func TestDetectResultsPage_OpenSynonymShortCircuits(t *testing.T) {
+	opp := Opportunity{
+		Title:       "Concurso de Proyectos de Innovación 2026",
+		OppStatus:   "abierta",
+		Description: `<main><p>Postula hasta el 30 de abril.</p></main><aside><h4>Convocatorias anteriores</h4><p>Resultados finales 2025: ganadores</p></aside>`,
+	}
+	if detectResultsPage(opp) {
+		t.Fatal("an open call with a results sidebar should not be a results page")
+	}
+
+	for _, status := range []string{"Vigente", "recruiting", "forecasted"} {
+		opp.OppStatus = status
+		if detectResultsPage(opp) {
+			t.Errorf("status %q should short-circuit to not results", status)
+		}
+	}
+
+	// A results label never maps to open, so the keywords still count.
+	opp.OppStatus = "resultados"
+	if !detectResultsPage(opp) {
+		t.Fatal("expected a results status with results wording to be a results page")
+	}
+}
@@ -2,6 +2,7 @@ package ingest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 )
 
@@ -10,6 +11,81 @@ type IngestionStats struct {
 	TotalSaved int
 	TotalFound int
 	Errors     int
+	// Inserted and Updated split TotalSaved into new rows and refreshes of
+	// rows already stored; dry runs count toward neither.
+	Inserted int
+	Updated  int
+	// Skipped counts items dropped before the write; SkipReasons breaks it
+	// down by reason (SkipMissingTitleOrLink, SkipMissingSourceID).
+	Skipped     int
+	SkipReasons map[string]int
+}
+
+// SaveOutcome is what became of one item handed to the pipeline.
+type SaveOutcome string
+
+const (
+	// OutcomeSaved is a save whose insert/update split is unknown (dry runs).
+	OutcomeSaved    SaveOutcome = "saved"
+	OutcomeInserted SaveOutcome = "inserted"
+	OutcomeUpdated  SaveOutcome = "updated"
+	OutcomeSkipped  SaveOutcome = "skipped"
+	OutcomeError    SaveOutcome = "error"
+)
+
+// Skip reasons recorded in IngestionStats.SkipReasons.
+const (
+	SkipMissingTitleOrLink = "missing_title_or_link"
+	SkipMissingSourceID    = "missing_source_id"
+	// SkipSupersededNumber is a dedup_by_number record posted before the
+	// one already stored for its opportunity number.
+	SkipSupersededNumber = "superseded_by_number"
+)
+
+// SkipError marks an item the pipeline refused to write for a known reason,
+// as opposed to a failed write.
+type SkipError struct {
+	Reason string
+	Err    error
+}
+
+func (e *SkipError) Error() string { return e.Err.Error() }
+
+func (e *SkipError) Unwrap() error { return e.Err }
+
+// Skip counts one item dropped for reason.
+func (s *IngestionStats) Skip(reason string) {
+	if s.SkipReasons == nil {
+		s.SkipReasons = make(map[string]int)
+	}
+	s.Skipped++
+	s.SkipReasons[reason]++
+}
+
+// Record folds the result of one save into the stats: nil is a save counted
+// by its outcome, a SkipError a skip under its reason, anything else an
+// error. It takes SaveRaw's results directly.
+func (s *IngestionStats) Record(outcome SaveOutcome, err error) SaveOutcome {
+	var skip *SkipError
+	switch {
+	case err == nil:
+		s.TotalSaved++
+		switch outcome {
+		case OutcomeInserted:
+			s.Inserted++
+		case OutcomeUpdated:
+			s.Updated++
+		default:
+			outcome = OutcomeSaved
+		}
+		return outcome
+	case errors.As(err, &skip):
+		s.Skip(skip.Reason)
+		return OutcomeSkipped
+	default:
+		s.Errors++
+		return OutcomeError
+	}
 }
 
 // FetcherStrategy defines the contract for any ingestion source.
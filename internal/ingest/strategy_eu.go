@@ -1,13 +1,11 @@
 package ingest
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"strings"
 	"time"
 )
 
@@ -40,132 +38,218 @@ type euOpportunity struct {
 // The search API usually returns:
 // { "fundingOpportunities": [ ... ], "totalCount": 123 }
 
+const (
+	euPageSize = 50
+	// euMaxPages bounds a run when totalCount is misreported.
+	euMaxPages = 200
+	// euMaxConsecutivePageErrors ends a run whose pages keep failing; a
+	// single bad page is skipped.
+	euMaxConsecutivePageErrors = 3
+)
+
 func (s *EuFundingTendersStrategy) Run(ctx context.Context, config SourceConfig, p *Pipeline) (IngestionStats, error) {
 	stats := IngestionStats{}
+	fetcher, ok := p.Fetcher.(PostFetcher)
+	if !ok {
+		return stats, fmt.Errorf("EU API: fetcher %T cannot send POST requests", p.Fetcher)
+	}
+	p.configureFetch(config.BaseURL, euFetchConfig(config))
 
-	// Default to fetching explicitly OPEN grants
-	// We paginate until no more results.
-	page := 1
-	pageSize := 50
-
-	for {
-		reqBody := map[string]interface{}{
-			"query":    "", // fetch all
-			"page":     page,
-			"pageSize": pageSize,
-			"status":   []string{"OPEN", "FORTHCOMING"}, // Only want open or soon-to-open
-			"program":  []string{"HORIZON"},             // Focus on Horizon Europe mostly? Or all? User said "EU Funding & Tenders". Let's omit to get all.
-		}
-
-		jsonBody, err := json.Marshal(reqBody)
-		if err != nil {
-			return stats, fmt.Errorf("marshal error: %w", err)
+	err := s.walkPages(ctx, fetcher, config, &stats, func(page int, items []euOpportunity) {
+		batch := make([]Opportunity, 0, len(items))
+		for _, item := range items {
+			batch = append(batch, euOpportunityToOpportunity(item))
 		}
+		p.saveBatch(ctx, "EU", batch, &stats)
+		log.Printf("[EU] Page %d: saved %d, total found %d", page, stats.TotalSaved, stats.TotalFound)
+	})
+	return stats, err
+}
 
-		// API Key might be needed in URL or Header
-		url := config.BaseURL
-		if config.APIKey != "" {
-			// Often passed as query param? or header?
-			// curl -H "Content-Type: application/json" ...
-			// Let's assume header for now if user provides it, generic key "ApiKey" or similar
-		}
+// euFetchConfig is the source's fetch config with the API key added to its
+// headers, so every request to the search host carries it.
+func euFetchConfig(config SourceConfig) FetchConfig {
+	fetch := config.Fetch
+	if config.APIKey == "" {
+		return fetch
+	}
+	headers := make(map[string]string, len(fetch.Headers)+1)
+	for name, value := range fetch.Headers {
+		headers[name] = value
+	}
+	headers["apikey"] = config.APIKey
+	fetch.Headers = headers
+	return fetch
+}
 
-		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+// walkPages requests result pages until a short or empty page, the reported
+// total, or euMaxPages. A page that still fails after the fetcher's retries
+// is counted in stats.Errors and skipped so earlier progress is kept; the run
+// only fails after euMaxConsecutivePageErrors in a row.
+func (s *EuFundingTendersStrategy) walkPages(ctx context.Context, fetcher PostFetcher, config SourceConfig, stats *IngestionStats, handle func(page int, items []euOpportunity)) error {
+	consecutiveErrors := 0
+	for page := 1; page <= euMaxPages; page++ {
+		apiResp, err := s.fetchPage(ctx, fetcher, config, page)
 		if err != nil {
-			return stats, fmt.Errorf("create request error: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		if config.APIKey != "" {
-			req.Header.Set("apikey", config.APIKey)
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			stats.Errors++
+			consecutiveErrors++
+			if consecutiveErrors >= euMaxConsecutivePageErrors {
+				return fmt.Errorf("EU API: %d consecutive pages failed, last: %w", consecutiveErrors, err)
+			}
+			log.Printf("[EU] Page %d failed, skipping: %v", page, err)
+			continue
 		}
+		consecutiveErrors = 0
 
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			return stats, fmt.Errorf("api request failed: %w", err)
+		if apiResp.TotalCount > stats.TotalFound {
+			stats.TotalFound = apiResp.TotalCount
 		}
-		defer resp.Body.Close()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			return stats, fmt.Errorf("EU API returned %d: %s", resp.StatusCode, string(body))
+		items := apiResp.FundingOpportunities
+		if len(items) > 0 {
+			handle(page, items)
 		}
 
-		// Decode response
-		var apiResp euResponse
-		// If structure is unsure, we could decode to map[string]interface{} first to debug log
-		// but let's try the struct.
-		if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-			return stats, fmt.Errorf("decode error: %w", err)
+		// Stop on a short page rather than trusting totalCount alone: a total
+		// that never matches what the API actually serves would loop forever.
+		if len(items) < euPageSize || (stats.TotalFound > 0 && page*euPageSize >= stats.TotalFound) {
+			break
 		}
+	}
+	return nil
+}
 
-		stats.TotalFound = apiResp.TotalCount
-
-		for _, item := range apiResp.FundingOpportunities {
-			// Mapping
-			opp := Opportunity{
-				Title:             item.Title,
-				Summary:           item.Description, // might need cleanup (often HTML)
-				Description:       item.Description,
-				ExternalURL:       fmt.Sprintf("https://ec.europa.eu/info/funding-tenders/opportunities/portal/screen/opportunities/topic-details/%s", item.TopicIdentifier),
-				SourceDomain:      "ec.europa.eu",
-				SourceID:          item.TopicIdentifier,
-				OpportunityNumber: item.CallIdentifier,
-				AgencyName:        "European Commission",
-				AgencyCode:        "EC",
-				FunderType:        "Government",
-				Region:            "Europe",
-				Country:           "European Union",
-				Currency:          "EUR",
-				OppStatus:         item.Status, // OPEN, FORTHCOMING match our enums roughly? (posted/open)
-				Type:              "grant",     // item.Type might differentiate, defaulting to grant
-			}
+// fetchPage POSTs one search page through the pipeline fetcher, which rate
+// limits the host and retries timeouts and 429/5xx responses.
+func (s *EuFundingTendersStrategy) fetchPage(ctx context.Context, fetcher PostFetcher, config SourceConfig, page int) (euResponse, error) {
+	var apiResp euResponse
+	reqBody := map[string]interface{}{
+		"query":    "", // fetch all
+		"page":     page,
+		"pageSize": euPageSize,
+		"status":   []string{"OPEN", "FORTHCOMING"}, // Only want open or soon-to-open
+		"program":  []string{"HORIZON"},
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return apiResp, fmt.Errorf("marshal error: %w", err)
+	}
 
-			if item.Type == "Tenders" {
-				opp.DocType = "Tender"
-				// User might want to exclude tenders?
-				// "The user mentioned 'Funded Projects' confusion. Tenders are contracts.
-				// We keep them if they are OPEN opportunities, but label as Tender."
-			} else {
-				opp.DocType = "Grant"
-			}
+	doc, err := fetcher.Post(ctx, config.BaseURL, "application/json", jsonBody)
+	if err != nil {
+		return apiResp, fmt.Errorf("api request failed: %w", err)
+	}
+	defer doc.Body.Close()
 
-			// Dates: EU returns timestamps in ms
-			if len(item.DeadlineDate) > 0 {
-				ts := int64(item.DeadlineDate[0])
-				// Ensure it's not 0
-				if ts > 0 {
-					t := time.UnixMilli(ts)
-					opp.DeadlineAt = &t
-					opp.DeadlineStr = t.Format("2006-01-02")
-				}
-			}
-			if len(item.OpeningDate) > 0 {
-				ts := int64(item.OpeningDate[0])
-				if ts > 0 {
-					t := time.UnixMilli(ts)
-					opp.OpenDate = &t
-				}
-			}
+	if err := json.NewDecoder(doc.Body).Decode(&apiResp); err != nil {
+		return apiResp, fmt.Errorf("decode error: %w", err)
+	}
+	return apiResp, nil
+}
 
-			if err := p.SaveOpportunity(ctx, opp); err != nil {
-				log.Printf("[EU] Failed to save %q: %v", opp.Title, err)
-				stats.Errors++
-			} else {
-				stats.TotalSaved++
-			}
-		}
+// euOpportunityToOpportunity maps one search result onto an Opportunity.
+func euOpportunityToOpportunity(item euOpportunity) Opportunity {
+	opp := Opportunity{
+		Title:             item.Title,
+		Summary:           item.Description, // might need cleanup (often HTML)
+		Description:       item.Description,
+		ExternalURL:       fmt.Sprintf("https://ec.europa.eu/info/funding-tenders/opportunities/portal/screen/opportunities/topic-details/%s", item.TopicIdentifier),
+		SourceDomain:      "ec.europa.eu",
+		SourceID:          item.TopicIdentifier,
+		OpportunityNumber: item.CallIdentifier,
+		AgencyName:        "European Commission",
+		AgencyCode:        "EC",
+		FunderType:        "Government",
+		Region:            "Europe",
+		Country:           "European Union",
+		Currency:          "EUR",
+		OppStatus:         euOppStatus(item.Status),
+		SourceStatusRaw:   euStatusLabel(item.Status),
+		Type:              "grant", // item.Type might differentiate, defaulting to grant
+	}
+	opp.AmountMin, opp.AmountMax = parseEUBudget(item.Budget)
 
-		log.Printf("[EU] Page %d: saved %d, total found %d", page, stats.TotalSaved, stats.TotalFound)
+	// Tenders are procurement contracts, not funding. We keep them if they are
+	// OPEN opportunities but label them so the API can filter them out.
+	opp.DocType = normalizeEUDocType(item.Type)
+	if opp.DocType == "Tender" {
+		opp.Type = "tender"
+	}
 
-		if len(apiResp.FundingOpportunities) == 0 {
-			break
+	// Dates: EU returns timestamps in ms
+	if len(item.DeadlineDate) > 0 {
+		ts := int64(item.DeadlineDate[0])
+		// Ensure it's not 0
+		if ts > 0 {
+			t := time.UnixMilli(ts)
+			opp.DeadlineAt = &t
+			opp.DeadlineStr = t.Format("2006-01-02")
 		}
-		if stats.TotalSaved >= stats.TotalFound {
-			break
+	}
+	if len(item.OpeningDate) > 0 {
+		ts := int64(item.OpeningDate[0])
+		if ts > 0 {
+			t := time.UnixMilli(ts)
+			opp.OpenDate = &t
 		}
+	}
+	return opp
+}
+
+// euStatusCodes are the portal's numeric status ids, which some search
+// responses return instead of the label.
+var euStatusCodes = map[string]string{
+	"31094501": "FORTHCOMING",
+	"31094502": "OPEN",
+	"31094503": "CLOSED",
+}
+
+// euStatusLabel resolves a numeric status id to its label; the label is what
+// gets stored as source_status_raw and read by the status engine.
+func euStatusLabel(raw string) string {
+	status := strings.ToUpper(strings.TrimSpace(raw))
+	if label, ok := euStatusCodes[status]; ok {
+		return label
+	}
+	return status
+}
+
+// euOppStatus maps the portal's call status onto our OppStatus values: OPEN is
+// posted, FORTHCOMING is forecasted (Grants.gov's word for announced but not
+// yet open) and CLOSED is closed. Anything else is left for the status engine
+// to decide from dates.
+func euOppStatus(raw string) string {
+	switch euStatusLabel(raw) {
+	case "OPEN":
+		return "posted"
+	case "FORTHCOMING":
+		return "forecasted"
+	case "CLOSED":
+		return "closed"
+	default:
+		return ""
+	}
+}
 
-		page++
+// parseEUBudget reads the budget string, which the portal writes European
+// style: "1.000.000", "EUR 1 000 000", "2,5 Mio EUR" or a range
+// "500 000 - 1 000 000". A single figure is the maximum.
+func parseEUBudget(budget string) (float64, float64) {
+	if strings.TrimSpace(budget) == "" {
+		return 0, 0
 	}
+	parsed := parseAmountLocalized(budget, "EUR", "de")
+	return parsed.Min, parsed.Max
+}
 
-	return stats, nil
+// normalizeEUDocType maps the portal's free-form type labels ("Tenders", "TENDER",
+// "Call for tenders", "Grant", "grants") onto the two doc types we store.
+func normalizeEUDocType(raw string) string {
+	t := strings.ToLower(strings.TrimSpace(raw))
+	if strings.Contains(t, "tender") || strings.Contains(t, "procurement") || strings.Contains(t, "contract") {
+		return "Tender"
+	}
+	return "Grant"
 }
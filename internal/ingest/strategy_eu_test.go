@@ -0,0 +1,178 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyEUServer serves pages of euPageSize results with a misreported
+// totalCount. failures[page] responses for that page are 503s first;
+// page brokenPage always answers 400.
+func flakyEUServer(t *testing.T, lastPage, lastPageSize, brokenPage int, failures map[int]int) (*httptest.Server, map[int]int) {
+	t.Helper()
+	var mu sync.Mutex
+	hits := map[int]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("expected a JSON POST, got %s %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		var body struct {
+			Page int `json:"page"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("bad request body: %v", err)
+		}
+		mu.Lock()
+		hits[body.Page]++
+		n := hits[body.Page]
+		mu.Unlock()
+
+		if body.Page == brokenPage {
+			http.Error(w, "bad page", http.StatusBadRequest)
+			return
+		}
+		if n <= failures[body.Page] {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		size := euPageSize
+		if body.Page == lastPage {
+			size = lastPageSize
+		} else if body.Page > lastPage {
+			size = 0
+		}
+		resp := euResponse{TotalCount: 100000}
+		for i := 0; i < size; i++ {
+			resp.FundingOpportunities = append(resp.FundingOpportunities, euOpportunity{
+				Title:           fmt.Sprintf("Topic %d-%d", body.Page, i),
+				TopicIdentifier: fmt.Sprintf("HORIZON-%d-%d", body.Page, i),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server, hits
+}
+
+// euTestFetcher is a testFetcher whose retries do not wait.
+func euTestFetcher(t *testing.T, serverURL string) *RateLimitedFetcher {
+	fetcher := testFetcher(t, serverURL)
+	fetcher.RetryBackoff = func(int) time.Duration { return time.Millisecond }
+	return fetcher
+}
+
+func TestEUWalkPages_RetriesAndStopsOnShortPage(t *testing.T) {
+	server, hits := flakyEUServer(t, 3, 10, 0, map[int]int{2: 2})
+
+	var stats IngestionStats
+	received := 0
+	err := (&EuFundingTendersStrategy{}).walkPages(context.Background(), euTestFetcher(t, server.URL), SourceConfig{BaseURL: server.URL}, &stats, func(page int, items []euOpportunity) {
+		received += len(items)
+	})
+	if err != nil {
+		t.Fatalf("walkPages: %v", err)
+	}
+	if received != 2*euPageSize+10 {
+		t.Errorf("received %d items, want %d", received, 2*euPageSize+10)
+	}
+	if hits[2] != 3 {
+		t.Errorf("page 2 requested %d times, want 3 (two 503s then success)", hits[2])
+	}
+	if hits[4] != 0 {
+		t.Errorf("walked past the short page despite totalCount=%d", stats.TotalFound)
+	}
+	if stats.Errors != 0 {
+		t.Errorf("Errors = %d, want 0 after successful retries", stats.Errors)
+	}
+}
+
+func TestEUWalkPages_SkipsFailedPageAndKeepsProgress(t *testing.T) {
+	server, hits := flakyEUServer(t, 3, 5, 2, nil)
+
+	var stats IngestionStats
+	var pages []int
+	err := (&EuFundingTendersStrategy{}).walkPages(context.Background(), euTestFetcher(t, server.URL), SourceConfig{BaseURL: server.URL}, &stats, func(page int, items []euOpportunity) {
+		pages = append(pages, page)
+	})
+	if err != nil {
+		t.Fatalf("walkPages: %v", err)
+	}
+	if fmt.Sprint(pages) != "[1 3]" {
+		t.Errorf("handled pages %v, want [1 3]", pages)
+	}
+	if hits[2] != 1 {
+		t.Errorf("non-retryable 400 was requested %d times, want 1", hits[2])
+	}
+	if stats.Errors != 1 {
+		t.Errorf("Errors = %d, want 1 for the skipped page", stats.Errors)
+	}
+}
+
+func TestParseEUBudget(t *testing.T) {
+	cases := []struct {
+		budget           string
+		wantMin, wantMax float64
+	}{
+		{"1.000.000", 0, 1000000},
+		{"EUR 1 000 000", 0, 1000000},
+		{"€ 2.500.000,50", 0, 2500000.50},
+		{"2,5 Mio EUR", 0, 2500000},
+		{"EUR 1.5 million", 0, 1500000},
+		{"1.000.000 - 3.000.000", 1000000, 3000000},
+		{"EUR 500 000 to 1 000 000", 500000, 1000000},
+		{"15000000", 0, 15000000},
+		{"", 0, 0},
+		{"n/a", 0, 0},
+	}
+	for _, tc := range cases {
+		min, max := parseEUBudget(tc.budget)
+		if min != tc.wantMin || max != tc.wantMax {
+			t.Errorf("parseEUBudget(%q) = %v..%v, want %v..%v", tc.budget, min, max, tc.wantMin, tc.wantMax)
+		}
+	}
+}
+
+func TestEUOpportunityToOpportunity_StatusAndBudget(t *testing.T) {
+	cases := []struct {
+		status, wantOppStatus, wantMapped string
+	}{
+		{"OPEN", "posted", "open"},
+		{"Forthcoming", "forecasted", "upcoming"},
+		{"CLOSED", "closed", "closed"},
+		{"31094502", "posted", "open"},
+		{"31094501", "forecasted", "upcoming"},
+		{"", "", ""},
+	}
+	for _, tc := range cases {
+		opp := euOpportunityToOpportunity(euOpportunity{TopicIdentifier: "HORIZON-X", Status: tc.status, Budget: "EUR 3 000 000"})
+		if opp.OppStatus != tc.wantOppStatus {
+			t.Errorf("status %q: OppStatus = %q, want %q", tc.status, opp.OppStatus, tc.wantOppStatus)
+		}
+		if got := mappedSourceStatus(opp); got != tc.wantMapped {
+			t.Errorf("status %q: engine reads %q, want %q", tc.status, got, tc.wantMapped)
+		}
+		if opp.AmountMax != 3000000 || opp.Currency != "EUR" {
+			t.Errorf("status %q: amount = %s %v, want EUR 3000000", tc.status, opp.Currency, opp.AmountMax)
+		}
+	}
+}
+
+func TestEUFetchConfig_AddsAPIKeyHeader(t *testing.T) {
+	config := SourceConfig{APIKey: "k-123", Fetch: FetchConfig{Headers: map[string]string{"X-Trace": "1"}}}
+	fetch := euFetchConfig(config)
+	if fetch.Headers["apikey"] != "k-123" || fetch.Headers["X-Trace"] != "1" {
+		t.Fatalf("expected the API key next to the source's headers, got %v", fetch.Headers)
+	}
+	if _, leaked := config.Fetch.Headers["apikey"]; leaked {
+		t.Fatal("euFetchConfig modified the source's own header map")
+	}
+	if fetch := euFetchConfig(SourceConfig{}); fetch.Headers != nil {
+		t.Fatalf("expected no headers without an API key, got %v", fetch.Headers)
+	}
+}
@@ -11,6 +11,7 @@ type GrantsGovStrategy struct{}
 func (s *GrantsGovStrategy) Run(ctx context.Context, config SourceConfig, p *Pipeline) (IngestionStats, error) {
 	stats := IngestionStats{}
 	fetcher := NewGrantsGovFetcher()
+	fetcher.IncludeClosed = config.IncludeClosed
 
 	// Default to fetching all if not specified, or use schedule/config to limit?
 	// For MVP of Registry, we fetch all open variants.
@@ -31,17 +32,8 @@ func (s *GrantsGovStrategy) Run(ctx context.Context, config SourceConfig, p *Pip
 
 		stats.TotalFound = totalHits
 
-		for _, opp := range opportunities {
-			// Ensure source domain matches registry config if needed,
-			// but GrantsGovFetcher already sets it to "grants.gov"
-
-			if err := p.SaveOpportunity(ctx, opp); err != nil {
-				log.Printf("[GrantsGov] Failed to save %q: %v", opp.Title, err)
-				stats.Errors++
-			} else {
-				stats.TotalSaved++
-			}
-		}
+		// GrantsGovFetcher already sets the source domain to "grants.gov".
+		p.saveBatch(ctx, "GrantsGov", opportunities, &stats)
 
 		offset += len(opportunities)
 		log.Printf("[GrantsGov] Progress: saved %d, fetched %d/%d", stats.TotalSaved, offset, totalHits)
@@ -1,18 +1,23 @@
 package ingest
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"log"
 	"net/url"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
+	"github.com/david/grant-finder/internal/ai"
 	"github.com/gocolly/colly/v2"
 )
 
@@ -21,6 +26,18 @@ type HtmlGenericStrategy struct {
 	UseColly bool
 }
 
+// defaultDetailItemsPerPage derives the detail-fetch budget when
+// detail.max_items is not set.
+const defaultDetailItemsPerPage = 100
+
+// detailFetchBudget returns how many detail pages a run may fetch.
+func detailFetchBudget(config SourceConfig, maxPages int) int {
+	if config.Detail.MaxItems > 0 {
+		return config.Detail.MaxItems
+	}
+	return maxPages * defaultDetailItemsPerPage
+}
+
 func (s *HtmlGenericStrategy) Run(ctx context.Context, config SourceConfig, p *Pipeline) (IngestionStats, error) {
 	// Use Colly-based scraping by default
 	if s.UseColly || true { // Always use Colly now
@@ -33,21 +50,24 @@ func (s *HtmlGenericStrategy) Run(ctx context.Context, config SourceConfig, p *P
 func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceConfig, p *Pipeline) (IngestionStats, error) {
 	stats := IngestionStats{}
 
-	maxPages := config.MaxPages
-	if maxPages == 0 {
-		maxPages = 1
-	}
+	maxPages := listPageLimit(config)
 
 	// Parse base URL to get domain
 	parsedURL, err := url.Parse(config.BaseURL)
 	if err != nil {
 		return stats, fmt.Errorf("invalid base URL: %w", err)
 	}
+	// Colly matches AllowedDomains against each request's hostname, without
+	// the port, so a source served on an explicit port needs the hostname
+	// here; its Host would refuse every page, the base URL included.
+	allowedDomain := parsedURL.Hostname()
+
+	maxDetailItems := detailFetchBudget(config, maxPages)
 
 	// Configure Colly scraper
 	scraperConfig := CollyScraperConfig{
-		AllowedDomains:  []string{parsedURL.Host},
-		MaxPages:        maxPages * 100, // Allow for detail pages
+		AllowedDomains:  []string{allowedDomain},
+		MaxPages:        maxPages + maxDetailItems,
 		DomainDelay:     1 * time.Second,
 		ParallelThreads: 1, // Sequential for politeness
 		UserAgent:       "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
@@ -64,7 +84,7 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 
 	// Create main collector for list pages
 	collector := colly.NewCollector(
-		colly.AllowedDomains(parsedURL.Host),
+		colly.AllowedDomains(allowedDomain),
 		colly.UserAgent(scraperConfig.UserAgent),
 		colly.DetectCharset(),
 	)
@@ -78,52 +98,49 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 	})
 
 	collector.SetRequestTimeout(scraperConfig.RequestTimeout)
+	collector.WithTransport(newSafeTransport(p.allowedHosts()...))
 
 	// Detail collector (cloned with same settings)
 	detailCollector := collector.Clone()
 
 	visitedURLs := make(map[string]bool)
+	sourceIDs := newSourceIDGuard(config.ID)
 	pageCount := 0
+	detailFetches := 0
+	budgetLogged := false
 	var nextPageURL string
+	pageItems := 0
 
 	sel := config.Selectors
 	if sel.Container == "" {
 		return stats, fmt.Errorf("selector 'container' is required for html_generic strategy")
 	}
 
-	// Process items on list pages
-	collector.OnHTML(sel.Container, func(e *colly.HTMLElement) {
-		title := strings.TrimSpace(e.ChildText(config.Selectors.Title))
-
-		// Link extraction
-		linkAttr := config.Selectors.LinkAttr
-		if linkAttr == "" {
-			linkAttr = "href"
-		}
+	// Link extraction
+	linkAttr := config.Selectors.LinkAttr
+	if linkAttr == "" {
+		linkAttr = "href"
+	}
 
-		var link string
-		if config.Selectors.Link == "" || config.Selectors.Link == "." {
-			link = strings.TrimSpace(e.Attr(linkAttr))
-		} else {
-			link = strings.TrimSpace(e.ChildAttr(config.Selectors.Link, linkAttr))
-		}
+	// Process items on list pages
+	handleItem := func(e *colly.HTMLElement) {
+		title := childText(e.DOM, config.Selectors.Title)
+		link := childAttr(e.DOM, config.Selectors.Link, linkAttr)
 
-		summary := ""
-		if config.Selectors.Content != "" {
-			summary = strings.TrimSpace(e.ChildText(config.Selectors.Content))
-		}
+		summary := childText(e.DOM, config.Selectors.Content)
 
+		stats.TotalFound++
 		if title == "" || link == "" {
+			stats.Skip(SkipMissingTitleOrLink)
 			return
 		}
 
 		// Resolve relative URL
 		fullURL := e.Request.AbsoluteURL(link)
-		canonicalURL := CanonicalizeURL(fullURL)
+		canonicalURL := config.CanonicalizeURL(fullURL)
 
-		// Generate stable SourceID
-		hash := sha1.Sum([]byte(canonicalURL))
-		sourceID := hex.EncodeToString(hash[:])
+		// Generate stable SourceID, kept distinct per title within the crawl
+		sourceID, canonicalURL := sourceIDs.assign(fullURL, canonicalURL, title)
 
 		raw := RawOpportunity{
 			Title:        title,
@@ -138,22 +155,72 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 		if len(config.Detail.Parse.DateLocales) > 0 {
 			raw.Extra["date_locales"] = strings.Join(config.Detail.Parse.DateLocales, ",")
 		}
+		if config.Detail.Parse.NumberLocale != "" {
+			raw.Extra["number_locale"] = config.Detail.Parse.NumberLocale
+		}
+		if config.Detail.Parse.DefaultDeadlineTime != "" {
+			raw.Extra["default_deadline_time"] = config.Detail.Parse.DefaultDeadlineTime
+		}
+		if len(config.CanonicalKeepParams) > 0 {
+			// Default canonicalization would strip the kept params again
+			raw.Extra["canonical_url"] = canonicalURL
+		}
 
-		stats.TotalFound++
+		items := []RawOpportunity{raw}
 
 		// Detail Enrichment with Colly
-		if config.Detail.Enabled {
-			if err := s.enrichOpportunityColly(ctx, &raw, config.Detail, detailCollector); err != nil {
+		if config.Detail.Enabled && detailFetches >= maxDetailItems {
+			if !budgetLogged {
+				log.Printf("[%s] Detail fetch budget of %d reached; saving remaining items from list data only", config.ID, maxDetailItems)
+				budgetLogged = true
+			}
+		} else if config.Detail.Enabled {
+			detailFetches++
+			base := cloneRawOpportunity(raw)
+			if err := s.enrichOpportunityColly(ctx, &raw, config.Detail, detailCollector, p.blockedMaxBytes(raw.ExternalURL)); err != nil {
 				log.Printf("[%s] Detail fetch failed for %s: %v", config.ID, raw.ExternalURL, err)
+				items[0] = raw
+			} else {
+				items = s.detailItems(config.ID, base, raw, config.Detail)
+				stats.TotalFound += len(items) - 1
+				if config.Detail.LLMResultsCheck && p.AI.Available() {
+					for i := range items {
+						s.classifyResultsPageLLM(ctx, &items[i], p.AI)
+					}
+				}
 			}
 		}
 
-		if err := p.SaveRaw(ctx, raw); err != nil {
-			log.Printf("[%s] Failed to save %q: %v", config.ID, title, err)
-			stats.Errors++
-		} else {
-			stats.TotalSaved++
+		for _, item := range items {
+			outcome, err := p.SaveRaw(ctx, item)
+			switch stats.Record(outcome, err) {
+			case OutcomeSkipped:
+				log.Printf("[%s] Skipped %q: %v", config.ID, item.Title, err)
+			case OutcomeError:
+				log.Printf("[%s] Failed to save %q: %v", config.ID, item.Title, err)
+			}
+		}
+	}
+
+	// The container may list alternatives; the first one matching anything on
+	// the page is used, so a markup change doesn't zero out the crawl.
+	collector.OnHTML("html", func(page *colly.HTMLElement) {
+		matched, items := firstMatchingSelector(page.DOM, sel.Container)
+		if matched == "" {
+			log.Printf("[%s] No container selector matched on %s (tried %q)", config.ID, page.Request.URL, sel.Container)
+			return
 		}
+		log.Printf("[%s] Container selector %q matched %d items", config.ID, matched, items.Length())
+		pageItems = items.Length()
+		items.Each(func(i int, item *goquery.Selection) {
+			if title, link := childText(item, sel.Title), childAttr(item, sel.Link, linkAttr); title != "" && link != "" {
+				fullURL := page.Request.AbsoluteURL(link)
+				sourceIDs.note(fullURL, config.CanonicalizeURL(fullURL), title)
+			}
+		})
+		items.Each(func(i int, item *goquery.Selection) {
+			handleItem(colly.NewHTMLElementFromSelectionNode(page.Response, item, item.Nodes[0], i))
+		})
 	})
 
 	// Handle pagination
@@ -176,7 +243,12 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 	currentURL := config.BaseURL
 
 	for pageCount < maxPages {
-		canonPage := CanonicalizeURL(currentURL)
+		if config.Pagination.usesQueryParam() {
+			if currentURL, err = config.Pagination.pageURL(config.BaseURL, pageCount); err != nil {
+				return stats, err
+			}
+		}
+		canonPage := config.CanonicalizeURL(currentURL)
 		if visitedURLs[canonPage] {
 			log.Printf("[%s] Pagination cycle detected at %s. Stopping.", config.ID, canonPage)
 			break
@@ -186,6 +258,7 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 
 		log.Printf("[%s] Fetching page %d: %s", config.ID, pageCount, currentURL)
 		nextPageURL = "" // Reset
+		pageItems = 0
 
 		if err := collector.Visit(currentURL); err != nil {
 			log.Printf("[%s] Fetch error on page %d: %v", config.ID, pageCount, err)
@@ -194,6 +267,13 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 
 		collector.Wait()
 
+		if config.Pagination.usesQueryParam() {
+			if pageItems == 0 {
+				log.Printf("[%s] Page %d yielded no items. Stopping.", config.ID, pageCount)
+				break
+			}
+			continue
+		}
 		if nextPageURL == "" || config.Pagination.Next == "" {
 			break
 		}
@@ -204,11 +284,12 @@ func (s *HtmlGenericStrategy) runWithColly(ctx context.Context, config SourceCon
 }
 
 // enrichOpportunityColly fetches detail page using Colly collector.
-func (s *HtmlGenericStrategy) enrichOpportunityColly(ctx context.Context, raw *RawOpportunity, config DetailConfig, c *colly.Collector) error {
+func (s *HtmlGenericStrategy) enrichOpportunityColly(ctx context.Context, raw *RawOpportunity, config DetailConfig, c *colly.Collector, blockedMaxBytes int) error {
 	log.Printf("Fetching details for: %s", raw.ExternalURL)
 
 	var enrichErr error
 	enriched := false
+	start := time.Now()
 
 	clone := c.Clone()
 	clone.OnResponse(func(r *colly.Response) {
@@ -219,6 +300,9 @@ func (s *HtmlGenericStrategy) enrichOpportunityColly(ctx context.Context, raw *R
 		}
 
 		s.extractDetailContent(raw, config, doc)
+		// Hand the page to evidence enrichment so SaveOpportunity doesn't
+		// fetch it a second time.
+		raw.DetailPage = NewPageRaw(raw.ExternalURL, r.Body, r.StatusCode, time.Since(start), blockedMaxBytes, config.Attachments)
 		enriched = true
 	})
 
@@ -243,7 +327,8 @@ func (s *HtmlGenericStrategy) enrichOpportunityColly(ctx context.Context, raw *R
 	return nil
 }
 
-// extractDetailContent extracts metadata from a detail page document.
+// extractDetailContent extracts metadata from a detail page document. It is
+// shared by the Colly and legacy fetch paths so extraction fixes land in both.
 func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config DetailConfig, htmlDoc *goquery.Document) {
 	sel := config.Selectors
 	container := htmlDoc.Selection
@@ -269,13 +354,17 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 		}
 	}
 
-	// 2. Deadline
-	if sel.Deadline != "" {
-		deadlineSel := container.Find(sel.Deadline)
-		deadlineText := strings.TrimSpace(deadlineSel.Text())
-		if deadlineText != "" {
+	// 2. Deadline (first non-empty selector wins; all feed evidence)
+	var selectorEvidence []DeadlineEvidence
+	for _, deadlineSelector := range deadlineSelectors(sel) {
+		deadlineText := strings.TrimSpace(container.Find(deadlineSelector).Text())
+		if deadlineText == "" {
+			continue
+		}
+		if raw.RawDeadline == "" {
 			raw.RawDeadline = deadlineText
 		}
+		selectorEvidence = append(selectorEvidence, parseDeadlineEvidenceFromText(strings.ToLower(deadlineText), "detail_selector", raw.ExternalURL, config.Parse.DateLocales, 0.88, config.Parse.deadlineCutoff())...)
 	}
 
 	// 3. Amount
@@ -295,6 +384,9 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 		structuredText = buildStructuredExtractionText(htmlDoc.Selection.Text())
 	}
 
+	// 3b. No amount selector hit: look for a budget stated in prose.
+	applyAmountFromText(raw, structuredText, config.Parse.CurrencyDefault)
+
 	// 4. Detect rolling status from text
 	containerText := strings.ToLower(structuredText)
 	rollingKeywords := []string{
@@ -309,7 +401,8 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 		}
 	}
 
-	deadlineEvidence := parseDeadlineEvidenceFromText(strings.ToLower(structuredText), "detail_html", raw.ExternalURL, 0.82)
+	deadlineEvidence := parseDeadlineEvidenceFromText(strings.ToLower(structuredText), "detail_html", raw.ExternalURL, config.Parse.DateLocales, 0.82, config.Parse.deadlineCutoff())
+	deadlineEvidence = append(deadlineEvidence, selectorEvidence...)
 	if len(deadlineEvidence) > 0 {
 		raw.DeadlineEvidence = append(raw.DeadlineEvidence, deadlineEvidence...)
 		for _, ev := range deadlineEvidence {
@@ -340,8 +433,14 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 		}
 	}
 
-	// 5. Detect status from text
-	statusText := strings.ToLower(containerText)
+	// 4b. Labeled schedule rows ("Cierre de postulaciones | 30/03/2026") are more
+	// precise than free-text evidence, so they override open/close dates.
+	applyScheduleTable(raw, container, config.Parse)
+
+	// 5. Detect status from the call's own text: sidebars, footers and
+	// sentences about earlier rounds ("Results of previous rounds") don't count.
+	segments := currentCallSegments(container)
+	statusText := strings.ToLower(strings.Join(segments, "\n"))
 	if strings.Contains(statusText, "closed") || strings.Contains(statusText, "cerrada") ||
 		strings.Contains(statusText, "results") || strings.Contains(statusText, "awarded") ||
 		strings.Contains(statusText, "finalizada") {
@@ -353,20 +452,463 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 		raw.Extra["source_status_raw"] = "forthcoming"
 	}
 
-	if strings.Contains(statusText, "resultados finales") || strings.Contains(statusText, "ganadores") ||
-		strings.Contains(statusText, "winners") || strings.Contains(statusText, "awardees") ||
-		strings.Contains(statusText, "ranking") {
+	if announcesResults(segments) {
 		raw.IsResultsPage = true
 		raw.Extra["is_results_page"] = "true"
 	}
 
-	// 6. Extract eligibility if selector exists
+	// 6. Extract eligibility and categories as discrete list entries
 	if sel.Eligibility != "" {
-		eligibilitySel := container.Find(sel.Eligibility)
-		eligibilityText := strings.TrimSpace(eligibilitySel.Text())
-		if eligibilityText != "" {
-			raw.Extra["eligibility"] = eligibilityText
+		raw.Eligibility = mergeUniqueFold(raw.Eligibility, extractListItems(container.Find(sel.Eligibility)))
+	}
+	if sel.Category != "" {
+		raw.RawTags = mergeUniqueFold(raw.RawTags, extractListItems(container.Find(sel.Category)))
+	}
+
+	// 7. Queue configured "read more" links for one-hop evidence enrichment
+	if len(config.FollowLinks) > 0 {
+		raw.FollowURLs = collectFollowLinks(raw.ExternalURL, htmlDoc, config.FollowLinks)
+	}
+}
+
+// applyAmountFromText fills RawAmount from a budget phrase in the page text
+// ("awards of up to £1.5 million") when no selector produced one, recording
+// the snippet as evidence.
+func applyAmountFromText(raw *RawOpportunity, structuredText, currencyDefault string) {
+	if strings.TrimSpace(raw.RawAmount) != "" {
+		return
+	}
+	snippet, ok := findAmountSnippet(structuredText)
+	if !ok {
+		return
+	}
+	raw.RawAmount = snippet
+	if currencyDefault != "" && raw.RawCurrency == "" {
+		raw.RawCurrency = currencyDefault
+	}
+	if raw.SourceEvidenceJSON == nil {
+		raw.SourceEvidenceJSON = map[string]interface{}{}
+	}
+	raw.SourceEvidenceJSON["amount_evidence"] = map[string]interface{}{
+		"source":  "detail_text",
+		"snippet": snippet,
+	}
+}
+
+// deadlineSelectors merges the single Deadline selector with the Deadlines list.
+func deadlineSelectors(sel DetailSelectorConfig) []string {
+	out := make([]string, 0, len(sel.Deadlines)+1)
+	if strings.TrimSpace(sel.Deadline) != "" {
+		out = append(out, sel.Deadline)
+	}
+	for _, selector := range sel.Deadlines {
+		if strings.TrimSpace(selector) != "" {
+			out = append(out, selector)
+		}
+	}
+	return out
+}
+
+// Label hints for schedule table rows, checked in this order so that
+// "Publicación de resultados" is not mistaken for a close date.
+var (
+	scheduleResultsHints = []string{"resultado", "results", "ganadores", "winners", "adjudicación", "award announcement"}
+	scheduleCloseHints   = []string{"cierre", "deadline", "closing", "closes", "fecha límite", "fecha limite", "fecha máxima", "fecha maxima", "submission", "vencimiento"}
+	scheduleOpenHints    = []string{"inicio", "apertura", "opening", "opens", "lanzamiento", "launch"}
+)
+
+// classifyScheduleLabel maps a schedule row label to "results", "close" or "open".
+func classifyScheduleLabel(label string) string {
+	lower := strings.ToLower(label)
+	for _, group := range []struct {
+		kind  string
+		hints []string
+	}{
+		{"results", scheduleResultsHints},
+		{"close", scheduleCloseHints},
+		{"open", scheduleOpenHints},
+	} {
+		for _, hint := range group.hints {
+			if strings.Contains(lower, hint) {
+				return group.kind
+			}
+		}
+	}
+	return ""
+}
+
+// parseScheduleTable reads "label | value" table rows and returns evidence for
+// every row with a parseable date. Rows whose label is not an open, close or
+// results milestone ("Evaluación", "Firma de contrato") are labeled "milestone".
+func parseScheduleTable(container *goquery.Selection, locales []string, pageURL string, cutoff deadlineCutoff) []DeadlineEvidence {
+	if len(locales) == 0 {
+		locales = []string{"en", "es"}
+	}
+	var out []DeadlineEvidence
+	container.Find("table tr").Each(func(_ int, row *goquery.Selection) {
+		cells := make([]string, 0, 4)
+		row.Find("th, td").Each(func(_ int, cell *goquery.Selection) {
+			if value := cleanText(cell.Text()); value != "" {
+				cells = append(cells, value)
+			}
+		})
+		if len(cells) < 2 {
+			return
+		}
+		label := cells[0]
+		kind := classifyScheduleLabel(label)
+		if kind == "" {
+			kind = "milestone"
+		}
+		value := strings.Join(cells[1:], " ")
+		parsed, err := parseDateRobust(value, locales)
+		if err != nil {
+			return
+		}
+		if !hasExplicitTimeToken(value) {
+			parsed = normalizeDateOnlyBySource(parsed, pageURL, cutoff)
+		}
+		out = append(out, DeadlineEvidence{
+			Source:        "detail_table",
+			URL:           pageURL,
+			Snippet:       label + ": " + value,
+			ParsedDateISO: parsed.UTC().Format(time.RFC3339),
+			Label:         kind,
+			Confidence:    0.9,
+		})
+	})
+	return out
+}
+
+// applyScheduleTable maps labeled schedule rows onto OpenISO/CloseISO and
+// records every dated row as source evidence under "schedule_milestones", with
+// the results-publication date also kept as "schedule_results_iso".
+func applyScheduleTable(raw *RawOpportunity, container *goquery.Selection, parse DetailParseConfig) {
+	rows := parseScheduleTable(container, parse.DateLocales, raw.ExternalURL, parse.deadlineCutoff())
+	if len(rows) == 0 {
+		return
+	}
+
+	if raw.SourceEvidenceJSON == nil {
+		raw.SourceEvidenceJSON = map[string]interface{}{}
+	}
+	raw.SourceEvidenceJSON["schedule_milestones"] = rows
+
+	var closeRows []DeadlineEvidence
+	openSet := false
+	for _, ev := range rows {
+		switch ev.Label {
+		case "open":
+			if !openSet {
+				raw.OpenISO = ev.ParsedDateISO
+				openSet = true
+			}
+		case "close":
+			closeRows = append(closeRows, ev)
+			raw.DeadlineEvidence = append(raw.DeadlineEvidence, ev)
+			raw.DeadlineCandidates = appendUnique(raw.DeadlineCandidates, ev.ParsedDateISO)
+		case "results":
+			raw.SourceEvidenceJSON["schedule_results_iso"] = ev.ParsedDateISO
+		}
+	}
+
+	if best := pickPreferredCloseEvidence(closeRows); best != nil {
+		raw.CloseISO = best.ParsedDateISO
+		raw.RawDeadline = best.Snippet
+	}
+}
+
+// extractListItems returns one cleaned entry per <li> under the selection, so
+// bullet lists map to array items. Nested lists contribute their own items
+// rather than being folded into the parent entry. Without list markup it falls
+// back to splitting the text by line.
+func extractListItems(selection *goquery.Selection) []string {
+	items := selection.Find("li")
+	if selection.Is("li") {
+		items = selection.AddSelection(items)
+	}
+	if items.Length() == 0 {
+		return splitAndCleanList(selection.Text())
+	}
+
+	var out []string
+	items.Each(func(_ int, li *goquery.Selection) {
+		own := li.Clone()
+		own.Find("ul, ol").Remove()
+		if text := cleanListItem(own.Text()); text != "" {
+			out = append(out, text)
+		}
+	})
+	return mergeUniqueFold(nil, out)
+}
+
+// selectorAlternatives splits a list selector written as pipe-separated
+// alternatives ("div.card | li.result") into its parts, in order. Only a
+// space-delimited " | " outside brackets and parentheses separates
+// alternatives, so attribute selectors such as [lang|="es"] stay whole.
+func selectorAlternatives(selector string) []string {
+	var out []string
+	add := func(alt string) {
+		if alt = strings.TrimSpace(alt); alt != "" {
+			out = append(out, alt)
+		}
+	}
+
+	depth, start := 0, 0
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			if depth > 0 {
+				depth--
+			}
+		case '|':
+			if depth == 0 && i > 0 && selector[i-1] == ' ' && i+1 < len(selector) && selector[i+1] == ' ' {
+				add(selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(selector[start:])
+	return out
+}
+
+// firstMatchingSelector returns the first alternative of selector that
+// matches anything under root, with its matches. When none does it returns ""
+// and an empty selection.
+func firstMatchingSelector(root *goquery.Selection, selector string) (string, *goquery.Selection) {
+	for _, alt := range selectorAlternatives(selector) {
+		if found := root.Find(alt); found.Length() > 0 {
+			return alt, found
+		}
+	}
+	return "", root.Slice(0, 0)
+}
+
+// childText returns the trimmed text of the first alternative that yields any.
+func childText(item *goquery.Selection, selector string) string {
+	for _, alt := range selectorAlternatives(selector) {
+		if text := strings.TrimSpace(item.Find(alt).Text()); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+// childAttr returns attr from the first alternative that has it. An empty
+// selector or "." reads the item itself.
+func childAttr(item *goquery.Selection, selector, attr string) string {
+	alts := selectorAlternatives(selector)
+	if len(alts) == 0 {
+		alts = []string{"."}
+	}
+	for _, alt := range alts {
+		target := item
+		if alt != "." {
+			target = item.Find(alt)
 		}
+		if v := strings.TrimSpace(target.AttrOr(attr, "")); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// collectFollowLinks resolves hrefs matched by the follow_links selectors,
+// dropping duplicates and links back to the page itself.
+func collectFollowLinks(pageURL string, doc *goquery.Document, selectors []string) []string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{CanonicalizeURL(pageURL): true}
+	var out []string
+	for _, selector := range selectors {
+		if strings.TrimSpace(selector) == "" {
+			continue
+		}
+		doc.Find(selector).Each(func(_ int, sel *goquery.Selection) {
+			href, ok := sel.Attr("href")
+			if !ok {
+				href, ok = sel.Find("a[href]").First().Attr("href")
+			}
+			href = strings.TrimSpace(href)
+			if !ok || href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(strings.ToLower(href), "javascript:") {
+				return
+			}
+			ref, err := url.Parse(href)
+			if err != nil {
+				return
+			}
+			abs := base.ResolveReference(ref)
+			if abs.Scheme != "http" && abs.Scheme != "https" {
+				return
+			}
+			canonical := CanonicalizeURL(abs.String())
+			if seen[canonical] {
+				return
+			}
+			seen[canonical] = true
+			out = append(out, abs.String())
+		})
+	}
+	return out
+}
+
+// ambiguousResultsHints are weaker signals than resultsKeywords: they show up on
+// awarded-projects listings but also on active calls that mention past rounds.
+// A detail results keyword without announcement wording lands here too.
+var ambiguousResultsHints = []string{
+	"funded projects", "awarded projects", "funded research", "beneficiaries",
+	"proyectos financiados", "proyectos ganadores", "beneficiarios", "seleccionados",
+	"adjudicados", "financiados", "principal investigator",
+	"ganadores", "winners", "awardees", "ranking",
+}
+
+// pageChromeSelector matches sidebars, footers and navigation, whose text
+// (archives, "past winners" widgets) says nothing about the current call.
+const pageChromeSelector = "aside, footer, nav, [role=complementary], [role=contentinfo], [role=navigation], .sidebar, #sidebar, .widget"
+
+// detailResultsKeywords mark a results page when they share a sentence with
+// resultsAnnouncementHints. Both match whole words only.
+var detailResultsKeywords = wholeWordsRegex(`resultados finales`, `ganadores`, `winners`, `awardees`, `ranking`)
+
+// resultsAnnouncementHints are past-tense or announcement wording: results
+// that are out, not a description of how they will be decided ("Winners are
+// selected by", "se darán a conocer mediante publicación").
+var resultsAnnouncementHints = wholeWordsRegex(
+	`announced`, `published`, `congratulations`, `congratulate`, `final results`, `closed`, `concluded`,
+	`(?:have|has|were|was) been (?:selected|awarded|announced)`, `were (?:selected|awarded)`, `are now available`,
+	`anunci(?:a|an|amos|ó|aron|ad[oa]s?)`, `publica(?:n|mos|ron|d[oa]s?)?`, `publicó`, `felicit(?:a|amos|aciones)`,
+	`resultados finales`, `cerrad[oa]s?`, `conclu(?:id[oa]s?|yó)`,
+)
+
+// wholeWordsRegex matches any of alternatives as whole words. \b is ASCII
+// only in Go, so the boundaries are spelled out to cover accented letters.
+func wholeWordsRegex(alternatives ...string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(?:` + strings.Join(alternatives, "|") + `)(?:[^\p{L}\p{N}]|$)`)
+}
+
+// notCurrentRoundHints mark sentences about earlier editions or about results
+// still to come; neither makes the page itself a results listing.
+var notCurrentRoundHints = []string{
+	"previous", "past ", "prior ", "earlier", "last year", "anterior", "pasad", "año pasado", "ediciones",
+	"will be", "to be announced", "serán", "se publicar", "se anunciar",
+}
+
+// currentCallSegments splits the container's main content into sentences,
+// skipping page chrome and sentences about other rounds.
+func currentCallSegments(container *goquery.Selection) []string {
+	var blocks []string
+	container.Find("h1, h2, h3, h4, h5, h6, p, li, dt, dd, th, td, caption").Each(func(_ int, sel *goquery.Selection) {
+		if sel.Closest(pageChromeSelector).Length() > 0 {
+			return
+		}
+		// Leaf blocks only, so nested markup isn't counted twice.
+		if sel.Find("p, li, td, th").Length() > 0 {
+			return
+		}
+		blocks = append(blocks, cleanText(sel.Text()))
+	})
+	if len(blocks) == 0 {
+		// Bare text with no block markup: fall back to the whole container
+		// minus its chrome.
+		main := container.Clone()
+		main.Find(pageChromeSelector).Remove()
+		blocks = append(blocks, cleanText(main.Text()))
+	}
+
+	var segments []string
+	for _, block := range blocks {
+		for _, sentence := range splitSentences(block) {
+			if !containsAny(strings.ToLower(sentence), notCurrentRoundHints) {
+				segments = append(segments, sentence)
+			}
+		}
+	}
+	return segments
+}
+
+// announcesResults reports whether some sentence pairs a results keyword with
+// announcement or closing wording ("Ganadores publicados", "Ranking final").
+func announcesResults(segments []string) bool {
+	for _, segment := range segments {
+		lower := strings.ToLower(segment)
+		if detailResultsKeywords.MatchString(lower) && resultsAnnouncementHints.MatchString(lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// sentenceBreakRegex splits on sentence punctuation followed by whitespace, so
+// dates like 30.03.2026 stay whole.
+var sentenceBreakRegex = regexp.MustCompile(`[.!?;]\s+`)
+
+func splitSentences(text string) []string {
+	var out []string
+	for _, part := range sentenceBreakRegex.Split(text, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func containsAny(text string, needles []string) bool {
+	for _, needle := range needles {
+		if strings.Contains(text, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// llmResultsPageMinConfidence is the confidence needed before an LLM verdict
+// flips a page to results.
+const llmResultsPageMinConfidence = 0.7
+
+// isResultsPageAmbiguous reports whether keyword detection can't settle if the
+// page is a results listing: no strong keyword matched, and either a weak hint
+// is present or the page carries no deadline or rolling evidence at all.
+func isResultsPageAmbiguous(raw *RawOpportunity, structuredText string) bool {
+	if raw.IsResultsPage {
+		return false
+	}
+	text := strings.ToLower(structuredText)
+	for _, hint := range ambiguousResultsHints {
+		if strings.Contains(text, hint) {
+			return true
+		}
+	}
+	return len(raw.DeadlineEvidence) == 0 && raw.Extra["is_rolling"] != "true"
+}
+
+// classifyResultsPageLLM runs the optional LLM results-page check for ambiguous
+// detail pages and records the verdict and confidence as source evidence.
+func (s *HtmlGenericStrategy) classifyResultsPageLLM(ctx context.Context, raw *RawOpportunity, client *ai.OllamaClient) {
+	structuredText := buildStructuredExtractionText(raw.Description)
+	if strings.TrimSpace(structuredText) == "" || !isResultsPageAmbiguous(raw, structuredText) {
+		return
+	}
+
+	llmCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	isResults, confidence, err := ai.ClassifyResultsPage(llmCtx, client, raw.Title, structuredText)
+	cancel()
+	if err != nil {
+		log.Printf("LLM results-page check failed for %s: %v", raw.ExternalURL, err)
+		return
+	}
+
+	if raw.SourceEvidenceJSON == nil {
+		raw.SourceEvidenceJSON = map[string]interface{}{}
+	}
+	raw.SourceEvidenceJSON["results_page_confidence"] = confidence
+	if isResults && confidence >= llmResultsPageMinConfidence {
+		raw.IsResultsPage = true
+		raw.Extra["is_results_page"] = "true"
+		raw.SourceEvidenceJSON["results_page_llm"] = true
 	}
 }
 
@@ -374,20 +916,25 @@ func (s *HtmlGenericStrategy) extractDetailContent(raw *RawOpportunity, config D
 func (s *HtmlGenericStrategy) runLegacy(ctx context.Context, config SourceConfig, p *Pipeline) (IngestionStats, error) {
 	stats := IngestionStats{}
 
-	// Default MaxPages if not set
-	maxPages := config.MaxPages
-	if maxPages == 0 {
-		maxPages = 1
-	}
+	maxPages := listPageLimit(config)
 
 	currentURL := config.BaseURL
 	pageCount := 0
 
 	visitedURLs := make(map[string]bool)
+	sourceIDs := newSourceIDGuard(config.ID)
 
 	for pageCount < maxPages {
+		if config.Pagination.usesQueryParam() {
+			pageURL, err := config.Pagination.pageURL(config.BaseURL, pageCount)
+			if err != nil {
+				return stats, err
+			}
+			currentURL = pageURL
+		}
+
 		// Pagination Cycle Detection - canonicalize URL before comparing
-		canonPage := CanonicalizeURL(currentURL)
+		canonPage := config.CanonicalizeURL(currentURL)
 		if visitedURLs[canonPage] {
 			log.Printf("[%s] Pagination cycle detected at %s. Stopping.", config.ID, canonPage)
 			break
@@ -418,37 +965,18 @@ func (s *HtmlGenericStrategy) runLegacy(ctx context.Context, config SourceConfig
 			return stats, fmt.Errorf("selector 'container' is required for html_generic strategy")
 		}
 
-		container := doc.Find(sel.Container)
+		matched, container := firstMatchingSelector(doc.Selection, sel.Container)
 		itemCount := container.Length()
 		stats.TotalFound += itemCount
-		log.Printf("[%s] Page %d: Found %d items", config.ID, pageCount, itemCount)
-
-		container.Each(func(i int, sel *goquery.Selection) {
-			title := strings.TrimSpace(sel.Find(config.Selectors.Title).Text())
-
-			// Link extraction logic
-			linkAttr := config.Selectors.LinkAttr
-			if linkAttr == "" {
-				linkAttr = "href"
-			}
-
-			var link string
-			if config.Selectors.Link == "" || config.Selectors.Link == "." {
-				link = strings.TrimSpace(sel.AttrOr(linkAttr, ""))
-			} else {
-				link = strings.TrimSpace(sel.Find(config.Selectors.Link).AttrOr(linkAttr, ""))
-			}
-
-			summary := ""
-			if config.Selectors.Content != "" {
-				summary = strings.TrimSpace(sel.Find(config.Selectors.Content).Text())
-			}
-
-			if title == "" || link == "" {
-				return
-			}
+		log.Printf("[%s] Page %d: Found %d items (container %q)", config.ID, pageCount, itemCount, matched)
 
-			// Resolve relative URL
+		// Link extraction logic
+		linkAttr := config.Selectors.LinkAttr
+		if linkAttr == "" {
+			linkAttr = "href"
+		}
+		// Resolve relative URL
+		resolveLink := func(link string) string {
 			fullURL := link
 			if !strings.HasPrefix(link, "http") {
 				u, err := url.Parse(currentURL) // Resolve against current page URL
@@ -459,15 +987,34 @@ func (s *HtmlGenericStrategy) runLegacy(ctx context.Context, config SourceConfig
 					}
 				}
 			}
+			return fullURL
+		}
+
+		container.Each(func(i int, sel *goquery.Selection) {
+			if title, link := childText(sel, config.Selectors.Title), childAttr(sel, config.Selectors.Link, linkAttr); title != "" && link != "" {
+				fullURL := resolveLink(link)
+				sourceIDs.note(fullURL, config.CanonicalizeURL(fullURL), title)
+			}
+		})
+
+		container.Each(func(i int, sel *goquery.Selection) {
+			title := childText(sel, config.Selectors.Title)
+			link := childAttr(sel, config.Selectors.Link, linkAttr)
+
+			summary := childText(sel, config.Selectors.Content)
+
+			if title == "" || link == "" {
+				stats.Skip(SkipMissingTitleOrLink)
+				return
+			}
+
+			fullURL := resolveLink(link)
 
 			// Canonicalize URL and generate stable SourceID
-			canonicalURL := CanonicalizeURL(fullURL)
-			// hash := sha1.Sum([]byte(canonicalURL))
-			// sourceID := hex.EncodeToString(hash[:])
+			canonicalURL := config.CanonicalizeURL(fullURL)
 			// SourceID is generated in FromRaw or Pipeline if empty, but we can generate it here for consistency
 			// Actually, FromRaw expects SourceID.
-			hash := sha1.Sum([]byte(canonicalURL))
-			sourceID := hex.EncodeToString(hash[:])
+			sourceID, canonicalURL := sourceIDs.assign(fullURL, canonicalURL, title)
 
 			raw := RawOpportunity{
 				Title:        title,
@@ -482,26 +1029,51 @@ func (s *HtmlGenericStrategy) runLegacy(ctx context.Context, config SourceConfig
 			if len(config.Detail.Parse.DateLocales) > 0 {
 				raw.Extra["date_locales"] = strings.Join(config.Detail.Parse.DateLocales, ",")
 			}
+			if config.Detail.Parse.NumberLocale != "" {
+				raw.Extra["number_locale"] = config.Detail.Parse.NumberLocale
+			}
+			if config.Detail.Parse.DefaultDeadlineTime != "" {
+				raw.Extra["default_deadline_time"] = config.Detail.Parse.DefaultDeadlineTime
+			}
+			if len(config.CanonicalKeepParams) > 0 {
+				// Default canonicalization would strip the kept params again
+				raw.Extra["canonical_url"] = canonicalURL
+			}
+
+			items := []RawOpportunity{raw}
 
 			// Detail Enrichment
 			if config.Detail.Enabled {
 				// Be polite between detail fetches
 				time.Sleep(500 * time.Millisecond)
+				base := cloneRawOpportunity(raw)
 				if err := s.enrichOpportunity(ctx, &raw, config.Detail, p); err != nil {
 					log.Printf("[%s] Detail fetch failed for %s: %v", config.ID, raw.ExternalURL, err)
+					items[0] = raw
+				} else {
+					items = s.detailItems(config.ID, base, raw, config.Detail)
+					stats.TotalFound += len(items) - 1
 				}
 			}
 
-			if err := p.SaveRaw(ctx, raw); err != nil {
-				log.Printf("[%s] Failed to save %q: %v", config.ID, title, err)
-				stats.Errors++
-			} else {
-				stats.TotalSaved++
+			for _, item := range items {
+				outcome, err := p.SaveRaw(ctx, item)
+				switch stats.Record(outcome, err) {
+				case OutcomeSkipped:
+					log.Printf("[%s] Skipped %q: %v", config.ID, item.Title, err)
+				case OutcomeError:
+					log.Printf("[%s] Failed to save %q: %v", config.ID, item.Title, err)
+				}
 			}
 		})
 
 		// 4. Pagination
-		if config.Pagination.Next != "" {
+		if config.Pagination.usesQueryParam() {
+			if itemCount == 0 {
+				log.Printf("[%s] Page %d yielded no items. Stopping.", config.ID, pageCount)
+				break
+			}
+		} else if config.Pagination.Next != "" {
 			nextLink := doc.Find(config.Pagination.Next).AttrOr("href", "")
 			if nextLink == "" {
 				log.Printf("[%s] No next link found on page %d", config.ID, pageCount)
@@ -529,6 +1101,19 @@ func (s *HtmlGenericStrategy) runLegacy(ctx context.Context, config SourceConfig
 
 // CanonicalizeURL removes common tracking parameters to ensure stable URLs.
 func CanonicalizeURL(rawURL string) string {
+	return CanonicalizeURLWithParams(rawURL, nil, nil)
+}
+
+// CanonicalizeURL canonicalizes rawURL with the source's
+// canonical_keep_params and canonical_drop_params applied.
+func (c SourceConfig) CanonicalizeURL(rawURL string) string {
+	return CanonicalizeURLWithParams(rawURL, c.CanonicalKeepParams, c.CanonicalDropParams)
+}
+
+// CanonicalizeURLWithParams is CanonicalizeURL with per-source overrides:
+// params named in keep survive the default tracking-param removal, and params
+// named in drop are removed as well.
+func CanonicalizeURLWithParams(rawURL string, keep, drop []string) string {
 	u, err := url.Parse(rawURL)
 	if err != nil {
 		return rawURL
@@ -545,162 +1130,123 @@ func CanonicalizeURL(rawURL string) string {
 	exactParamsToRemove := []string{
 		"fbclid", "gclid", "mc_cid", "mc_eid", "mkt_tok", "ref", "session", "s_cid",
 	}
+	exactParamsToRemove = append(exactParamsToRemove, drop...)
 
 	for k := range q {
+		if slices.Contains(keep, k) {
+			continue
+		}
 		for _, prefix := range paramsToRemovePrefix {
 			if strings.HasPrefix(k, prefix) {
 				q.Del(k)
 			}
 		}
-	}
-
-	for _, p := range exactParamsToRemove {
-		q.Del(p)
+		if slices.Contains(exactParamsToRemove, k) {
+			q.Del(k)
+		}
 	}
 
 	u.RawQuery = q.Encode()
 	return u.String()
 }
 
-// enrichOpportunity fetches the detail page and extracts additional metadata.
-func (s *HtmlGenericStrategy) enrichOpportunity(ctx context.Context, raw *RawOpportunity, config DetailConfig, p *Pipeline) error {
-	log.Printf("Fetching details for: %s", raw.ExternalURL)
-	doc, err := p.Fetcher.Fetch(ctx, raw.ExternalURL)
-	if err != nil {
-		return err
-	}
-	defer doc.Body.Close()
-
-	htmlDoc, err := goquery.NewDocumentFromReader(doc.Body)
-	if err != nil {
-		return err
-	}
-
-	sel := config.Selectors
-	container := htmlDoc.Selection
-	if sel.Container != "" {
-		container = htmlDoc.Find(sel.Container)
-	}
+// sourceIDGuard hands out SourceIDs for one crawl. IDs are the sha1 of the
+// canonical URL, but canonicalization can merge distinct opportunities (a
+// stripped param or fragment was what told them apart), and the upsert would
+// then let one overwrite the other. When a canonical URL is listed under more
+// than one title, each of those items gets an ID derived from its own URL and
+// title: the original URL's ID if that differs from the canonical one and
+// no other title uses it, otherwise a title-hash suffix. Callers note a whole page before assigning,
+// so which item was crawled first doesn't decide the IDs.
+type sourceIDGuard struct {
+	sourceID string
+	titles   map[string]map[string]bool // canonical URL -> normalized titles listed under it
+	original map[string]map[string]bool // original URL -> normalized titles listed under it
+	assigned map[string]sourceIDAssignment
+}
 
-	// 1. Description (HTML or Text)
-	if sel.Description != "" {
-		htmlContent, _ := container.Find(sel.Description).Html()
-		desc := strings.TrimSpace(htmlContent)
-		if desc != "" {
-			// Sanitize UTF-8
-			if !utf8.ValidString(desc) {
-				desc = strings.ToValidUTF8(desc, "")
-			}
-			raw.Description = desc
-		}
-	}
+type sourceIDAssignment struct {
+	id, externalURL string
+}
 
-	if strings.TrimSpace(raw.Description) == "" {
-		if htmlContent, err := container.Html(); err == nil {
-			raw.Description = strings.TrimSpace(htmlContent)
-		}
+func newSourceIDGuard(sourceID string) *sourceIDGuard {
+	return &sourceIDGuard{
+		sourceID: sourceID,
+		titles:   make(map[string]map[string]bool),
+		original: make(map[string]map[string]bool),
+		assigned: make(map[string]sourceIDAssignment),
 	}
+}
 
-	// 2. Deadline
-	if sel.Deadline != "" {
-		deadlineSel := container.Find(sel.Deadline)
-		deadlineText := strings.TrimSpace(deadlineSel.Text())
-		if deadlineText != "" {
-			raw.RawDeadline = deadlineText
-		}
-	}
+// note records an item ahead of assign.
+func (g *sourceIDGuard) note(fullURL, canonicalURL, title string) {
+	key := sourceIDTitleKey(title)
+	addTitle(g.titles, canonicalURL, key)
+	addTitle(g.original, fullURL, key)
+}
 
-	// 3. Amount
-	if sel.Amount != "" {
-		amountSel := container.Find(sel.Amount)
-		amountText := strings.TrimSpace(amountSel.Text())
-		if amountText != "" {
-			raw.RawAmount = amountText
-			if config.Parse.CurrencyDefault != "" {
-				raw.RawCurrency = config.Parse.CurrencyDefault
-			}
-		}
+func addTitle(titles map[string]map[string]bool, u, key string) {
+	if titles[u] == nil {
+		titles[u] = make(map[string]bool)
 	}
+	titles[u][key] = true
+}
 
-	structuredText := buildStructuredExtractionText(raw.Description)
-	if strings.TrimSpace(structuredText) == "" {
-		structuredText = buildStructuredExtractionText(htmlDoc.Selection.Text())
+// assign returns the SourceID and the external URL to store for an item. The
+// same listing seen twice gets the same answer.
+func (g *sourceIDGuard) assign(fullURL, canonicalURL, title string) (string, string) {
+	key := sourceIDTitleKey(title)
+	memo := canonicalURL + "\x00" + key
+	if prev, ok := g.assigned[memo]; ok {
+		return prev.id, prev.externalURL
 	}
+	g.note(fullURL, canonicalURL, title)
 
-	// 4. Detect rolling status from text
-	containerText := strings.ToLower(structuredText)
-	rollingKeywords := []string{
-		"rolling basis", "rolling deadline", "open until filled",
-		"ventanilla abierta", "convocatoria permanente", "postula todo el año", "continuously",
-		"ongoing", "open-ended", "no deadline",
-	}
-	for _, keyword := range rollingKeywords {
-		if strings.Contains(containerText, keyword) {
-			raw.Extra["is_rolling"] = "true"
-			break
+	result := sourceIDAssignment{id: urlSourceID(canonicalURL), externalURL: canonicalURL}
+	if len(g.titles[canonicalURL]) > 1 {
+		if fullURL != canonicalURL && len(g.original[fullURL]) == 1 {
+			result = sourceIDAssignment{id: urlSourceID(fullURL), externalURL: fullURL}
+			log.Printf("[%s] source_id collision on %s: keeping original URL %s for %q", g.sourceID, canonicalURL, fullURL, title)
+		} else {
+			titleHash := sha1.Sum([]byte(key))
+			result.id += "-" + hex.EncodeToString(titleHash[:4])
+			log.Printf("[%s] source_id collision on %s: using %s for %q", g.sourceID, canonicalURL, result.id, title)
 		}
 	}
+	g.assigned[memo] = result
+	return result.id, result.externalURL
+}
 
-	deadlineEvidence := parseDeadlineEvidenceFromText(strings.ToLower(structuredText), "detail_html", raw.ExternalURL, 0.82)
-	if len(deadlineEvidence) > 0 {
-		raw.DeadlineEvidence = append(raw.DeadlineEvidence, deadlineEvidence...)
-		for _, ev := range deadlineEvidence {
-			raw.DeadlineCandidates = appendUnique(raw.DeadlineCandidates, ev.ParsedDateISO)
-		}
-
-		sort.Slice(raw.DeadlineEvidence, func(i, j int) bool {
-			ti, _ := time.Parse(time.RFC3339, raw.DeadlineEvidence[i].ParsedDateISO)
-			tj, _ := time.Parse(time.RFC3339, raw.DeadlineEvidence[j].ParsedDateISO)
-			return ti.Before(tj)
-		})
+func sourceIDTitleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
 
-		if bestClose := pickPreferredCloseEvidence(raw.DeadlineEvidence); bestClose != nil {
-			raw.CloseISO = bestClose.ParsedDateISO
-			raw.RawDeadline = bestClose.Snippet
-		} else if len(raw.DeadlineEvidence) > 0 {
-			raw.CloseISO = raw.DeadlineEvidence[len(raw.DeadlineEvidence)-1].ParsedDateISO
-			raw.RawDeadline = raw.DeadlineEvidence[len(raw.DeadlineEvidence)-1].Snippet
-		}
-		if raw.OpenISO == "" {
-			for _, ev := range raw.DeadlineEvidence {
-				label := strings.ToLower(ev.Label + " " + ev.Snippet)
-				if strings.Contains(label, "inicio") || strings.Contains(label, "opening") || strings.Contains(label, "apertura") {
-					raw.OpenISO = ev.ParsedDateISO
-					break
-				}
-			}
-		}
-	}
+func urlSourceID(u string) string {
+	hash := sha1.Sum([]byte(u))
+	return hex.EncodeToString(hash[:])
+}
 
-	// 5. Detect status from text
-	statusText := strings.ToLower(containerText)
-	if strings.Contains(statusText, "closed") || strings.Contains(statusText, "cerrada") ||
-		strings.Contains(statusText, "results") || strings.Contains(statusText, "awarded") ||
-		strings.Contains(statusText, "finalizada") {
-		raw.Extra["opp_status"] = "closed"
-		raw.Extra["source_status_raw"] = "closed"
-	} else if strings.Contains(statusText, "forthcoming") || strings.Contains(statusText, "upcoming") ||
-		strings.Contains(statusText, "próximamente") || strings.Contains(statusText, "coming soon") {
-		raw.Extra["opp_status"] = "forthcoming"
-		raw.Extra["source_status_raw"] = "forthcoming"
+// enrichOpportunity fetches the detail page and extracts additional metadata.
+func (s *HtmlGenericStrategy) enrichOpportunity(ctx context.Context, raw *RawOpportunity, config DetailConfig, p *Pipeline) error {
+	log.Printf("Fetching details for: %s", raw.ExternalURL)
+	start := time.Now()
+	doc, err := p.Fetcher.Fetch(ctx, raw.ExternalURL)
+	if err != nil {
+		return err
 	}
+	defer doc.Body.Close()
 
-	if strings.Contains(statusText, "resultados finales") || strings.Contains(statusText, "ganadores") ||
-		strings.Contains(statusText, "winners") || strings.Contains(statusText, "awardees") ||
-		strings.Contains(statusText, "ranking") {
-		raw.IsResultsPage = true
-		raw.Extra["is_results_page"] = "true"
+	payload, err := io.ReadAll(doc.Body)
+	if err != nil {
+		return err
 	}
-
-	// 6. Extract eligibility if selector exists
-	if sel.Eligibility != "" {
-		eligibilitySel := container.Find(sel.Eligibility)
-		eligibilityText := strings.TrimSpace(eligibilitySel.Text())
-		if eligibilityText != "" {
-			raw.Extra["eligibility"] = eligibilityText
-		}
+	htmlDoc, err := goquery.NewDocumentFromReader(bytes.NewReader(payload))
+	if err != nil {
+		return err
 	}
 
+	s.extractDetailContent(raw, config, htmlDoc)
+	raw.DetailPage = NewPageRaw(raw.ExternalURL, payload, doc.StatusCode, time.Since(start), p.blockedMaxBytes(raw.ExternalURL), config.Attachments)
 	return nil
 }
 
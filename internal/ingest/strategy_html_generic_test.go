@@ -0,0 +1,814 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly/v2"
+)
+
+func TestHtmlGenericStrategy_DetailFetchBudget(t *testing.T) {
+	const listItems = 6
+	var detailHits int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/calls", func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		b.WriteString("<html><body><ul>")
+		for i := 1; i <= listItems; i++ {
+			fmt.Fprintf(&b, `<li class="call"><a href="/calls/%d">Call %d</a></li>`, i, i)
+		}
+		b.WriteString("</ul></body></html>")
+		fmt.Fprint(w, b.String())
+	})
+	mux.HandleFunc("/calls/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&detailHits, 1)
+		fmt.Fprint(w, `<html><body><div class="body">Deadline: 2030-05-01</div></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:       "budget_test",
+		BaseURL:  server.URL + "/calls",
+		MaxPages: 1,
+		Fetch:    FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{
+			Container: "li.call",
+			Title:     "a",
+			Link:      "a",
+		},
+		Detail: DetailConfig{
+			Enabled:  true,
+			MaxItems: 2,
+		},
+	}
+
+	var saved, withDetailPage int
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		saved++
+		if raw.DetailPage != nil {
+			withDetailPage++
+		}
+		return OutcomeSaved, nil
+	}
+
+	stats, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&detailHits); got != 2 {
+		t.Fatalf("expected 2 detail fetches (budget), got %d", got)
+	}
+	if stats.TotalFound != listItems || saved != listItems {
+		t.Fatalf("expected all %d list items saved, got found=%d saved=%d", listItems, stats.TotalFound, saved)
+	}
+	if withDetailPage != 2 {
+		t.Fatalf("expected the 2 fetched detail pages handed on for evidence, got %d", withDetailPage)
+	}
+}
+
+func TestDetailFetchBudget_DefaultsFromMaxPages(t *testing.T) {
+	if got := detailFetchBudget(SourceConfig{}, 3); got != 3*defaultDetailItemsPerPage {
+		t.Fatalf("expected default budget %d, got %d", 3*defaultDetailItemsPerPage, got)
+	}
+	if got := detailFetchBudget(SourceConfig{Detail: DetailConfig{MaxItems: 7}}, 3); got != 7 {
+		t.Fatalf("expected explicit budget 7, got %d", got)
+	}
+}
+
+func TestExtractDetailContent_EligibilityBulletList(t *testing.T) {
+	html := `<html><body><div class="content">
+		<p>Programa de apoyo a la innovación.</p>
+		<ul class="eligibility">
+			<li>• Empresas peruanas con RUC activo</li>
+			<li>2) Universidades acreditadas
+				<ul><li>- Públicas o privadas</li></ul>
+			</li>
+			<li>Empresas peruanas con RUC activo</li>
+		</ul>
+		<ul class="tags"><li>Innovación</li><li>1. Tecnología</li></ul>
+	</div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw := &RawOpportunity{ExternalURL: "https://example.org/call", Extra: map[string]string{}}
+	config := DetailConfig{Selectors: DetailSelectorConfig{
+		Container:   "div.content",
+		Eligibility: "ul.eligibility",
+		Category:    "ul.tags",
+	}}
+	(&HtmlGenericStrategy{}).extractDetailContent(raw, config, doc)
+
+	wantEligibility := []string{"Empresas peruanas con RUC activo", "Universidades acreditadas", "Públicas o privadas"}
+	if strings.Join(raw.Eligibility, "|") != strings.Join(wantEligibility, "|") {
+		t.Fatalf("eligibility = %q, want %q", raw.Eligibility, wantEligibility)
+	}
+	wantTags := []string{"Innovación", "Tecnología"}
+	if strings.Join(raw.RawTags, "|") != strings.Join(wantTags, "|") {
+		t.Fatalf("categories = %q, want %q", raw.RawTags, wantTags)
+	}
+
+	opp := FromRaw(*raw)
+	if len(opp.Eligibility) != len(wantEligibility) {
+		t.Fatalf("expected eligibility to survive normalization, got %q", opp.Eligibility)
+	}
+}
+
+func TestExtractDetailContent_ScheduleTableAndDeadlineSelectors(t *testing.T) {
+	html := `<html><body><div class="content">
+		<p>Convocatoria Startup Perú.</p>
+		<table>
+			<tr><th>Etapa</th><th>Fecha</th></tr>
+			<tr><td>Inicio de postulaciones</td><td>15/03/2030</td></tr>
+			<tr><td>Cierre de postulaciones</td><td>30/03/2030</td></tr>
+			<tr><td>Evaluación de propuestas</td><td>20/04/2030</td></tr>
+			<tr><td>Publicación de resultados</td><td>15/05/2030</td></tr>
+		</table>
+		<div class="aside"><span class="fecha">Deadline: 2030-03-30</span></div>
+	</div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw := &RawOpportunity{ExternalURL: "https://example.org/call", Extra: map[string]string{}}
+	config := DetailConfig{
+		Selectors: DetailSelectorConfig{
+			Container: "div.content",
+			Deadline:  ".missing-selector",
+			Deadlines: []string{"span.fecha"},
+		},
+		Parse: DetailParseConfig{DateLocales: []string{"es"}},
+	}
+	(&HtmlGenericStrategy{}).extractDetailContent(raw, config, doc)
+
+	if raw.RawDeadline == "" {
+		t.Fatal("expected later deadline selector to be used when the first misses")
+	}
+	if !strings.HasPrefix(raw.OpenISO, "2030-03-15") {
+		t.Fatalf("expected open date from schedule row, got %q", raw.OpenISO)
+	}
+	if !strings.HasPrefix(raw.CloseISO, "2030-03-30") {
+		t.Fatalf("expected close date from schedule row, got %q", raw.CloseISO)
+	}
+	if got, _ := raw.SourceEvidenceJSON["schedule_results_iso"].(string); !strings.HasPrefix(got, "2030-05-15") {
+		t.Fatalf("expected results date recorded as evidence, got %q", got)
+	}
+	milestones, _ := raw.SourceEvidenceJSON["schedule_milestones"].([]DeadlineEvidence)
+	wantLabels := []string{"open", "close", "milestone", "results"}
+	if len(milestones) != len(wantLabels) {
+		t.Fatalf("expected every schedule row recorded, got %+v", milestones)
+	}
+	for i, want := range wantLabels {
+		if milestones[i].Label != want {
+			t.Fatalf("milestone %d: expected label %q, got %+v", i, want, milestones[i])
+		}
+	}
+	if !strings.HasPrefix(milestones[2].ParsedDateISO, "2030-04-20") || !strings.Contains(milestones[2].Snippet, "Evaluación") {
+		t.Fatalf("expected evaluation milestone kept, got %+v", milestones[2])
+	}
+}
+
+func TestHtmlGenericStrategy_PreviewSaverSkipsDB(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><ul>
+			<li class="call"><a href="/calls/1">Call 1</a></li>
+			<li class="call"><a href="/calls/2">Call 2</a></li>
+		</ul></body></html>`)
+	}))
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:        "preview",
+		BaseURL:   server.URL + "/calls",
+		MaxPages:  1,
+		Fetch:     FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{Container: "li.call", Title: "a", Link: "a"},
+	}
+
+	var previewed []RawOpportunity
+	// No DB: SaveRaw must hand items to the Saver instead of saving them.
+	p := &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: func(_ context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		previewed = append(previewed, raw)
+		return OutcomeSaved, nil
+	}}
+
+	stats, err := (&HtmlGenericStrategy{}).Run(context.Background(), config, p)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(previewed) != 2 || stats.TotalSaved != 2 {
+		t.Fatalf("expected 2 previewed items, got %d (saved=%d)", len(previewed), stats.TotalSaved)
+	}
+	if previewed[0].Title != "Call 1" || !strings.HasSuffix(previewed[0].ExternalURL, "/calls/1") {
+		t.Fatalf("unexpected preview item: %+v", previewed[0])
+	}
+}
+
+func TestExtractDetailContent_AmountFromProse(t *testing.T) {
+	html := `<html><body><div class="content">
+		<h1>Innovation Fund</h1>
+		<p>Awards of up to £1.5 million are available for collaborative projects.</p>
+	</div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw := &RawOpportunity{ExternalURL: "https://example.org/call", Extra: map[string]string{}}
+	config := DetailConfig{Selectors: DetailSelectorConfig{Container: "div.content"}}
+	(&HtmlGenericStrategy{}).extractDetailContent(raw, config, doc)
+
+	if raw.RawAmount == "" {
+		t.Fatal("expected amount from prose when no amount selector is configured")
+	}
+	evidence, _ := raw.SourceEvidenceJSON["amount_evidence"].(map[string]interface{})
+	if evidence["snippet"] != raw.RawAmount {
+		t.Fatalf("expected snippet recorded as evidence, got %v", raw.SourceEvidenceJSON["amount_evidence"])
+	}
+
+	opp := FromRaw(*raw)
+	if opp.Currency != "GBP" || opp.AmountMax != 1500000 {
+		t.Fatalf("expected GBP 1.5M, got %s %v", opp.Currency, opp.AmountMax)
+	}
+}
+
+func TestDetailExtraction_CollyAndLegacyPathsAgree(t *testing.T) {
+	page := `<html><body><main>
+		<div class="desc"><p>Fondo para proyectos de innovación. Presupuesto: S/ 500,000 por proyecto.</p>
+		<p>Inicio de postulaciones: 1 de marzo de 2030. Cierre de postulaciones: 30 de marzo de 2030.</p></div>
+		<ul class="elig"><li>Universidades</li><li>Empresas</li></ul>
+		<table><tr><td>Cierre de postulaciones</td><td>30/03/2030</td></tr></table>
+	</main></body></html>`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	config := DetailConfig{
+		Enabled: true,
+		Selectors: DetailSelectorConfig{
+			Container:   "main",
+			Description: ".desc",
+			Eligibility: ".elig",
+		},
+		Parse: DetailParseConfig{DateLocales: []string{"es"}, CurrencyDefault: "PEN"},
+	}
+	newRaw := func() RawOpportunity {
+		return RawOpportunity{Title: "Fondo", ExternalURL: server.URL + "/fondo", Extra: map[string]string{}}
+	}
+	strategy := &HtmlGenericStrategy{}
+
+	legacy := newRaw()
+	p := &Pipeline{Fetcher: testFetcher(t, server.URL)}
+	if err := strategy.enrichOpportunity(context.Background(), &legacy, config, p); err != nil {
+		t.Fatalf("legacy path failed: %v", err)
+	}
+
+	viaColly := newRaw()
+	collector := colly.NewCollector()
+	collector.WithTransport(newSafeTransport(testServerHost(t, server.URL)))
+	if err := strategy.enrichOpportunityColly(context.Background(), &viaColly, config, collector, 0); err != nil {
+		t.Fatalf("colly path failed: %v", err)
+	}
+
+	if legacy.CloseISO == "" || !strings.HasPrefix(legacy.CloseISO, "2030-03-30") {
+		t.Fatalf("expected close 2030-03-30, got %q", legacy.CloseISO)
+	}
+	if legacy.DetailPage == nil || viaColly.DetailPage == nil {
+		t.Fatal("both paths should hand the fetched page on for evidence")
+	}
+	legacy.DetailPage, viaColly.DetailPage = nil, nil
+	if !reflect.DeepEqual(legacy, viaColly) {
+		t.Fatalf("paths disagree:\nlegacy: %+v\ncolly:  %+v", legacy, viaColly)
+	}
+}
+
+func TestHtmlGenericStrategy_ContainerSelectorFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// New markup: the old "li.call" cards are gone.
+		fmt.Fprint(w, `<html><body>
+			<div class="card"><h3 class="card-title">Fondo A</h3><a class="go" href="/a">Ver</a></div>
+			<div class="card"><h3 class="card-title">Fondo B</h3><a class="go" href="/b">Ver</a></div>
+		</body></html>`)
+	}))
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:       "fallback_test",
+		BaseURL:  server.URL + "/calls",
+		MaxPages: 1,
+		Fetch:    FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{
+			Container: "li.call | div.card",
+			Title:     "a | .card-title",
+			Link:      "a | a.go",
+		},
+	}
+
+	var titles []string
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		titles = append(titles, raw.Title)
+		return OutcomeSaved, nil
+	}
+	stats, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if stats.TotalFound != 2 {
+		t.Fatalf("expected 2 items via the second container alternative, got %d", stats.TotalFound)
+	}
+	// "a" matches inside the card but its text is "Ver"; the first non-empty
+	// alternative wins, so order the title alternatives most specific first.
+	if len(titles) != 2 || titles[0] != "Ver" || titles[1] != "Ver" {
+		t.Fatalf("expected first non-empty title alternative for every card, got %v", titles)
+	}
+}
+
+func TestSelectorAlternatives_KeepsAttributeSelectorsWhole(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"li.call | div.card", []string{"li.call", "div.card"}},
+		{`h3[lang|="es"] | h3`, []string{`h3[lang|="es"]`, "h3"}},
+		{`a[data-x="a | b"] | a.go`, []string{`a[data-x="a | b"]`, "a.go"}},
+		{`div:not([lang|="en"])`, []string{`div:not([lang|="en"])`}},
+		{" | li.call | ", []string{"li.call"}},
+	}
+	for _, tt := range tests {
+		if got := selectorAlternatives(tt.selector); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("selectorAlternatives(%q) = %q, want %q", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func TestChildText_AttributeSelectorAlternative(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="card">
+		<h3 lang="en">Seed Fund</h3><h3 lang="es-PE">Fondo Semilla</h3></div>`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := childText(doc.Find("div.card"), `h3[lang|="es"] | h3`); got != "Fondo Semilla" {
+		t.Fatalf("expected the Spanish title, got %q", got)
+	}
+}
+
+func TestHtmlGenericStrategy_SourceIDCollisionKeepsDistinctGrants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Both links canonicalize to /fondo once the "ref" param is stripped.
+		fmt.Fprint(w, `<html><body><ul>
+			<li class="call"><a href="/fondo?ref=101">Fondo Semilla</a></li>
+			<li class="call"><a href="/fondo?ref=202">Fondo Escalamiento</a></li>
+			<li class="call"><a href="/fondo?ref=101">Fondo Semilla</a></li>
+		</ul></body></html>`)
+	}))
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:        "collision_test",
+		BaseURL:   server.URL + "/calls",
+		MaxPages:  1,
+		Fetch:     FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{Container: "li.call", Title: "a", Link: "a"},
+	}
+
+	ids := map[string]string{}
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		if prev, ok := ids[raw.SourceID]; ok && prev != raw.Title {
+			t.Fatalf("source_id %s shared by %q and %q", raw.SourceID, prev, raw.Title)
+		}
+		ids[raw.SourceID] = raw.Title
+		return OutcomeSaved, nil
+	}
+	if _, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save}); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 distinct source_ids, got %v", ids)
+	}
+}
+
+func TestCanonicalizeURL_SourceKeepAndDropParams(t *testing.T) {
+	raw := "https://Example.org/fondo?ref=101&sid=abc&utm_source=x&year=2026#top"
+
+	if got := CanonicalizeURL(raw); got != "https://example.org/fondo?sid=abc&year=2026" {
+		t.Fatalf("unexpected default canonical URL %s", got)
+	}
+
+	src := SourceConfig{CanonicalKeepParams: []string{"ref"}, CanonicalDropParams: []string{"sid"}}
+	if got := src.CanonicalizeURL(raw); got != "https://example.org/fondo?ref=101&year=2026" {
+		t.Fatalf("expected ref kept and sid dropped, got %s", got)
+	}
+
+	// Keep also overrides the utm_ prefix rule.
+	src = SourceConfig{CanonicalKeepParams: []string{"utm_source"}}
+	if got := src.CanonicalizeURL(raw); got != "https://example.org/fondo?sid=abc&utm_source=x&year=2026" {
+		t.Fatalf("expected utm_source kept, got %s", got)
+	}
+}
+
+func TestFromRaw_KeepsSourceCanonicalURL(t *testing.T) {
+	raw := RawOpportunity{
+		Title:       "Fondo",
+		ExternalURL: "https://example.org/fondo?ref=101",
+		Extra:       map[string]string{"canonical_url": "https://example.org/fondo?ref=101"},
+	}
+	if got := FromRaw(raw).CanonicalURL; got != "https://example.org/fondo?ref=101" {
+		t.Fatalf("expected the source canonical URL, got %s", got)
+	}
+}
+
+func TestSourceIDGuard_TitleHashSuffix(t *testing.T) {
+	guard := newSourceIDGuard("guard_test")
+	canonical := "https://example.org/fondos"
+
+	first, _ := guard.assign(canonical, canonical, "Fondo A")
+	if first != urlSourceID(canonical) {
+		t.Fatalf("expected the first title to keep the plain id, got %s", first)
+	}
+	if again, _ := guard.assign(canonical, canonical, "  fondo   a "); again != first {
+		t.Fatalf("expected the same title to reuse %s, got %s", first, again)
+	}
+
+	// Nothing was stripped, so only the title can tell Fondo B apart.
+	second, externalURL := guard.assign(canonical, canonical, "Fondo B")
+	if second == first || !strings.HasPrefix(second, first+"-") {
+		t.Fatalf("expected a title-hash suffix on %s, got %s", first, second)
+	}
+	if externalURL != canonical {
+		t.Fatalf("expected the canonical URL to be kept, got %s", externalURL)
+	}
+}
+
+func TestSourceIDGuard_CollisionIDsIgnoreCrawlOrder(t *testing.T) {
+	canonical := "https://example.org/fondos"
+	type item struct{ fullURL, title string }
+	page := []item{
+		{canonical + "?id=1", "Fondo A"},
+		{canonical + "?id=2", "Fondo B"},
+		{canonical, "Fondo C"},
+		{canonical + "?id=4", "Fondo D"},
+		{canonical + "?id=4", "Fondo E"},
+	}
+	assignAll := func(order []item) map[string]string {
+		guard := newSourceIDGuard("guard_test")
+		for _, it := range order {
+			guard.note(it.fullURL, canonical, it.title)
+		}
+		ids := map[string]string{}
+		for _, it := range order {
+			ids[it.title], _ = guard.assign(it.fullURL, canonical, it.title)
+		}
+		return ids
+	}
+
+	forward := assignAll(page)
+	reversed := make([]item, len(page))
+	for i, it := range page {
+		reversed[len(page)-1-i] = it
+	}
+	if backward := assignAll(reversed); !reflect.DeepEqual(forward, backward) {
+		t.Fatalf("IDs depend on crawl order:\nforward:  %v\nbackward: %v", forward, backward)
+	}
+
+	seen := map[string]string{}
+	for title, id := range forward {
+		if other, dup := seen[id]; dup {
+			t.Fatalf("%s and %s share id %s", title, other, id)
+		}
+		seen[id] = title
+	}
+	if forward["Fondo A"] != urlSourceID(canonical+"?id=1") {
+		t.Errorf("expected Fondo A to keep its original URL's id, got %s", forward["Fondo A"])
+	}
+	if !strings.HasPrefix(forward["Fondo D"], urlSourceID(canonical)+"-") {
+		t.Errorf("expected a title-hash id when the original URL is shared too, got %s", forward["Fondo D"])
+	}
+}
+
+func TestChildTextAndAttr_Alternatives(t *testing.T) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(`<div class="item" data-url="/self"><h2>Title</h2><a href="/x">x</a></div>`))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	item := doc.Find("div.item")
+	if got := childText(item, "h3.title | h2"); got != "Title" {
+		t.Fatalf("expected fallback title, got %q", got)
+	}
+	if got := childAttr(item, "a.missing | a", "href"); got != "/x" {
+		t.Fatalf("expected fallback link, got %q", got)
+	}
+	if got := childAttr(item, ".", "data-url"); got != "/self" {
+		t.Fatalf("expected the item's own attribute, got %q", got)
+	}
+}
+
+func TestExtractDetailContent_PriorRoundResultsNotAResultsPage(t *testing.T) {
+	html := `<html><body>
+	<main>
+		<h1>Fondo Semilla 2026</h1>
+		<p>Convocatoria abierta para startups de base tecnológica. Cierre de postulaciones: 30/06/2026.</p>
+		<p>Results of previous rounds are available in the archive.</p>
+		<p>Winners will be announced in September.</p>
+	</main>
+	<aside class="sidebar">
+		<h3>Ganadores 2025</h3>
+		<ul><li>Ranking final de la edición pasada</li><li>Results published</li></ul>
+	</aside>
+	<footer>Resultados finales de todas las convocatorias</footer>
+	</body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw := &RawOpportunity{ExternalURL: "https://example.org/fondo-semilla", Extra: map[string]string{}}
+	(&HtmlGenericStrategy{}).extractDetailContent(raw, DetailConfig{}, doc)
+
+	if raw.IsResultsPage || raw.Extra["is_results_page"] != "" {
+		t.Fatal("open call mentioning prior-round results must not be flagged as a results page")
+	}
+	if raw.Extra["opp_status"] == "closed" {
+		t.Fatal("prior-round results must not mark the call closed")
+	}
+	if !isResultsPageAmbiguous(raw, "Results of previous rounds. Ganadores 2025") {
+		t.Fatal("a bare results keyword should still be left to the LLM check")
+	}
+}
+
+func TestExtractDetailContent_AnnouncedWinnersIsResultsPage(t *testing.T) {
+	html := `<html><body><div class="content">
+		<h1>Concurso de Innovación 2025</h1>
+		<p>Se publican los ganadores de la convocatoria. Felicitaciones a todos los equipos.</p>
+		<table><tr><th>Proyecto</th><th>Monto</th></tr><tr><td>AgroSense</td><td>S/ 50 000</td></tr></table>
+	</div></body></html>`
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	raw := &RawOpportunity{ExternalURL: "https://example.org/concurso/ganadores", Extra: map[string]string{}}
+	(&HtmlGenericStrategy{}).extractDetailContent(raw, DetailConfig{Selectors: DetailSelectorConfig{Container: "div.content"}}, doc)
+
+	if !raw.IsResultsPage {
+		t.Fatal("expected announced winners to mark a results page")
+	}
+}
+
+func TestAnnouncesResults_WordingAboutFutureResults(t *testing.T) {
+	cases := map[string]bool{
+		"Los ganadores se darán a conocer mediante publicación en el sitio web": false,
+		"Winners are selected by an international jury":                         false,
+		"The final ranking determines funding":                                  false,
+		"Los rankings se anunciarán en mayo":                                    false,
+		"Se publican los ganadores de la convocatoria":                          true,
+		"Winners announced":                                                     true,
+		"Resultados finales":                                                    true,
+		"Ganadores publicados el 3 de marzo":                                    true,
+		"The awardees have been selected":                                       true,
+	}
+	for sentence, want := range cases {
+		if got := announcesResults([]string{sentence}); got != want {
+			t.Errorf("%q: announcesResults = %v, want %v", sentence, got, want)
+		}
+	}
+}
+
+func TestHtmlGenericStrategy_MultiItemDetailPage(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/calls", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><ul>
+			<li class="call"><a href="/programa">Programa de Innovación 2030</a></li>
+		</ul></body></html>`)
+	})
+	mux.HandleFunc("/programa", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><main>
+			<p>El programa financia dos líneas.</p>
+			<section class="linea"><h3>Línea 1: Semilla</h3>
+				<p>Cierre de postulaciones: 15 de abril de 2030.</p></section>
+			<section class="linea"><h3>Línea 2: Escalamiento</h3>
+				<p>Cierre de postulaciones: 30 de junio de 2030.</p></section>
+		</main></body></html>`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:        "multi_item_test",
+		BaseURL:   server.URL + "/calls",
+		MaxPages:  1,
+		Fetch:     FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{Container: "li.call", Title: "a", Link: "a"},
+		Detail: DetailConfig{
+			Enabled:           true,
+			Selectors:         DetailSelectorConfig{Container: "main"},
+			Parse:             DetailParseConfig{DateLocales: []string{"es"}},
+			MultiItemSelector: "section.linea",
+		},
+	}
+
+	var saved []RawOpportunity
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		saved = append(saved, raw)
+		return OutcomeSaved, nil
+	}
+	stats, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(saved) != 2 || stats.TotalFound != 2 || stats.TotalSaved != 2 {
+		t.Fatalf("expected 2 rows from the sub-calls, got %d (found=%d saved=%d)", len(saved), stats.TotalFound, stats.TotalSaved)
+	}
+
+	want := []struct{ title, close string }{
+		{"Línea 1: Semilla", "2030-04-15"},
+		{"Línea 2: Escalamiento", "2030-06-30"},
+	}
+	for i, w := range want {
+		got := saved[i]
+		if got.Title != w.title {
+			t.Errorf("row %d: title = %q, want %q", i, got.Title, w.title)
+		}
+		if !strings.HasPrefix(got.CloseISO, w.close) {
+			t.Errorf("row %d: close = %q, want %s", i, got.CloseISO, w.close)
+		}
+		if strings.Contains(got.DetailPage.BodyHTML, saved[1-i].Title) {
+			t.Errorf("row %d: evidence page should hold only its own block", i)
+		}
+	}
+	base, _, _ := strings.Cut(saved[0].SourceID, "#")
+	if saved[0].SourceID == saved[1].SourceID || !strings.HasPrefix(saved[1].SourceID, base+"#") {
+		t.Fatalf("expected per-block suffixes on one base source_id, got %q and %q", saved[0].SourceID, saved[1].SourceID)
+	}
+}
+
+func TestHtmlGenericStrategy_CountsSkipReasons(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><ul>
+			<li class="call"><a href="/calls/1">Call 1</a></li>
+			<li class="call"><a href="/calls/2"></a></li>
+			<li class="call"><span>Call 3, no link</span></li>
+			<li class="call"><a href="/calls/4">Call 4</a></li>
+		</ul></body></html>`)
+	}))
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:        "skip_test",
+		BaseURL:   server.URL + "/calls",
+		MaxPages:  1,
+		Fetch:     FetchConfig{RateLimitRPS: 1000},
+		Selectors: SelectorConfig{Container: "li.call", Title: "a | span", Link: "a"},
+	}
+
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		if raw.Title == "Call 4" {
+			return "", &SkipError{Reason: SkipMissingSourceID, Err: fmt.Errorf("missing source_id")}
+		}
+		return OutcomeSaved, nil
+	}
+	stats, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	want := map[string]int{SkipMissingTitleOrLink: 2, SkipMissingSourceID: 1}
+	if stats.TotalFound != 4 || stats.TotalSaved != 1 || stats.Skipped != 3 || stats.Errors != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+	if !reflect.DeepEqual(stats.SkipReasons, want) {
+		t.Fatalf("skip reasons = %v, want %v", stats.SkipReasons, want)
+	}
+}
+
+func TestEnrichOpportunity_DetailPageUsesSourceBlockedThreshold(t *testing.T) {
+	// A 3KB challenge page: over the 2KB default, under the source's 10KB.
+	page := "<html><body><p>Please complete the captcha to continue.</p>" + strings.Repeat("<!-- pad -->", 250) + "</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, page)
+	}))
+	defer server.Close()
+
+	fetcher := testFetcher(t, server.URL)
+	fetcher.SetDomainConfig(server.URL, FetchConfig{BlockedMaxBytes: 10000})
+	p := &Pipeline{Fetcher: fetcher}
+	raw := RawOpportunity{Title: "Fondo", ExternalURL: server.URL + "/fondo", Extra: map[string]string{}}
+	if err := (&HtmlGenericStrategy{}).enrichOpportunity(context.Background(), &raw, DetailConfig{Enabled: true}, p); err != nil {
+		t.Fatal(err)
+	}
+	if raw.DetailPage == nil || raw.DetailPage.FetchMeta["blocked_detected"] != true {
+		t.Fatalf("expected the source's threshold to flag the page, got %+v", raw.DetailPage)
+	}
+
+	body, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "DetailPage") || strings.Contains(string(body), "root_status_code") {
+		t.Fatalf("expected DetailPage left out of JSON, got %s", body)
+	}
+}
+
+func TestIngestionStats_Record(t *testing.T) {
+	var stats IngestionStats
+	outcomes := []SaveOutcome{
+		stats.Record(OutcomeSaved, nil),
+		stats.Record(OutcomeInserted, nil),
+		stats.Record(OutcomeUpdated, nil),
+		stats.Record(OutcomeUpdated, nil),
+		stats.Record("", fmt.Errorf("save: %w", &SkipError{Reason: SkipMissingSourceID, Err: fmt.Errorf("missing source_id")})),
+		stats.Record("", fmt.Errorf("connection refused")),
+	}
+	want := []SaveOutcome{OutcomeSaved, OutcomeInserted, OutcomeUpdated, OutcomeUpdated, OutcomeSkipped, OutcomeError}
+	if !reflect.DeepEqual(outcomes, want) {
+		t.Fatalf("outcomes = %v, want %v", outcomes, want)
+	}
+	if stats.TotalSaved != 4 || stats.Inserted != 1 || stats.Updated != 2 ||
+		stats.Skipped != 1 || stats.Errors != 1 || stats.SkipReasons[SkipMissingSourceID] != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	details := runDetailsJSON(stats, 1500*time.Millisecond)
+	if details != `{"duration_ms":1500,"inserted":1,"skip_reasons":{"missing_source_id":1},"skipped":1,"updated":2}` {
+		t.Fatalf("run details = %s", details)
+	}
+}
+
+func TestHtmlGenericStrategy_QueryParamPagination(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested = append(requested, page)
+		fmt.Fprint(w, `<html><body><ul>`)
+		if page == "1" || page == "2" {
+			for i := 1; i <= 2; i++ {
+				fmt.Fprintf(w, `<li class="call"><a href="/calls/%s-%d">Call %s-%d</a></li>`, page, i, page, i)
+			}
+		}
+		fmt.Fprint(w, `</ul></body></html>`)
+	}))
+	defer server.Close()
+
+	config := SourceConfig{
+		ID:         "query_pagination_test",
+		BaseURL:    server.URL + "/calls?lang=es",
+		Fetch:      FetchConfig{RateLimitRPS: 1000},
+		Selectors:  SelectorConfig{Container: "li.call", Title: "a", Link: "a"},
+		Pagination: PaginationConfig{Param: "page", Start: 1, MaxPages: 10},
+	}
+
+	var titles []string
+	strategy := &HtmlGenericStrategy{}
+	save := func(ctx context.Context, raw RawOpportunity) (SaveOutcome, error) {
+		titles = append(titles, raw.Title)
+		return OutcomeSaved, nil
+	}
+	stats, err := strategy.Run(context.Background(), config, &Pipeline{Fetcher: testFetcher(t, server.URL), Saver: save})
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	// Pages 1 and 2 hold items; page 3 is empty and ends the crawl.
+	if want := []string{"1", "2", "3"}; !reflect.DeepEqual(requested, want) {
+		t.Fatalf("requested pages %v, want %v", requested, want)
+	}
+	if stats.TotalFound != 4 || len(titles) != 4 {
+		t.Fatalf("expected 4 items across two pages, got %d (%v)", stats.TotalFound, titles)
+	}
+}
+
+func TestPaginationConfig_PageURL(t *testing.T) {
+	p := PaginationConfig{Param: "start", Step: 20}
+	got, err := p.pageURL("https://example.org/calls?q=grant", 2)
+	if err != nil {
+		t.Fatalf("pageURL failed: %v", err)
+	}
+	if got != "https://example.org/calls?q=grant&start=40" {
+		t.Fatalf("unexpected page URL %s", got)
+	}
+
+	if n := listPageLimit(SourceConfig{Pagination: PaginationConfig{Param: "page"}}); n != defaultQueryMaxPages {
+		t.Fatalf("expected default query page cap, got %d", n)
+	}
+	if n := listPageLimit(SourceConfig{MaxPages: 3, Pagination: PaginationConfig{Param: "page", MaxPages: 7}}); n != 7 {
+		t.Fatalf("expected pagination.max_pages to win, got %d", n)
+	}
+}
@@ -45,6 +45,14 @@ func (s *WordPressStrategy) Run(ctx context.Context, config SourceConfig, pipeli
 		apiURL = base + "/wp-json/wp/v2/posts"
 	}
 
+	// The REST API is JSON; send a matching Accept on the API requests only,
+	// unless the source sets one. Other fetches of the host keep theirs.
+	pipeline.configureFetch(apiURL, config.Fetch)
+	var fetchOpts []FetchOption
+	if config.Fetch.Accept == "" {
+		fetchOpts = append(fetchOpts, WithHeader("Accept", "application/json"))
+	}
+
 	page := 1
 	perPage := 20
 	// Hard limit to prevent infinite loops (can be config driven later)
@@ -54,7 +62,7 @@ func (s *WordPressStrategy) Run(ctx context.Context, config SourceConfig, pipeli
 		// Construct paginated URL
 		pagedURL := fmt.Sprintf("%s?page=%d&per_page=%d", apiURL, page, perPage)
 
-		doc, err := pipeline.Fetcher.Fetch(ctx, pagedURL)
+		doc, err := pipeline.Fetcher.Fetch(ctx, pagedURL, fetchOpts...)
 		if err != nil {
 			// Stop on 400/404 which usually indicates end of pagination
 			if strings.Contains(err.Error(), "400") || strings.Contains(err.Error(), "404") {
@@ -114,12 +122,11 @@ func (s *WordPressStrategy) Run(ctx context.Context, config SourceConfig, pipeli
 			}
 
 			// Save using pipeline.SaveRaw (handles normalization, deduplication, upsert)
-			if err := pipeline.SaveRaw(ctx, opp); err != nil {
-				stats.Errors++
+			outcome, err := pipeline.SaveRaw(ctx, opp)
+			if err != nil {
 				fmt.Printf("Failed to save WP post %d: %v\n", post.ID, err)
-			} else {
-				stats.TotalSaved++
 			}
+			stats.Record(outcome, err)
 		}
 
 		page++
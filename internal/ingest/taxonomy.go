@@ -0,0 +1,307 @@
+package ingest
+
+import (
+	"strings"
+)
+
+// Canonical funder types stored in opportunities.funder_type. The source's own
+// wording ("Gobierno", "Private Foundation") is kept in FunderTypeLabel.
+const (
+	FunderTypeGovernment   = "government"
+	FunderTypeFoundation   = "foundation"
+	FunderTypeCorporate    = "corporate"
+	FunderTypeMultilateral = "multilateral"
+	FunderTypeAcademic     = "academic"
+	FunderTypeNonprofit    = "nonprofit"
+)
+
+// funderTypeSynonyms maps whole accent-folded, lower-cased labels to a
+// canonical funder type.
+var funderTypeSynonyms = map[string]string{
+	"government":     FunderTypeGovernment,
+	"gov":            FunderTypeGovernment,
+	"govt":           FunderTypeGovernment,
+	"public":         FunderTypeGovernment,
+	"public body":    FunderTypeGovernment,
+	"federal":        FunderTypeGovernment,
+	"state":          FunderTypeGovernment,
+	"gobierno":       FunderTypeGovernment,
+	"publico":        FunderTypeGovernment,
+	"sector publico": FunderTypeGovernment,
+	"estatal":        FunderTypeGovernment,
+
+	"foundation":         FunderTypeFoundation,
+	"private foundation": FunderTypeFoundation,
+	"philanthropy":       FunderTypeFoundation,
+	"philanthropic":      FunderTypeFoundation,
+	"trust":              FunderTypeFoundation,
+	"fundacion":          FunderTypeFoundation,
+	"filantropia":        FunderTypeFoundation,
+
+	"corporate":      FunderTypeCorporate,
+	"corporation":    FunderTypeCorporate,
+	"company":        FunderTypeCorporate,
+	"private":        FunderTypeCorporate,
+	"private sector": FunderTypeCorporate,
+	"industry":       FunderTypeCorporate,
+	"business":       FunderTypeCorporate,
+	"empresa":        FunderTypeCorporate,
+	"empresarial":    FunderTypeCorporate,
+	"privado":        FunderTypeCorporate,
+	"sector privado": FunderTypeCorporate,
+
+	"multilateral":               FunderTypeMultilateral,
+	"intergovernmental":          FunderTypeMultilateral,
+	"international organization": FunderTypeMultilateral,
+	"development bank":           FunderTypeMultilateral,
+	"organismo multilateral":     FunderTypeMultilateral,
+	"organismo internacional":    FunderTypeMultilateral,
+	"cooperacion internacional":  FunderTypeMultilateral,
+
+	"academic":           FunderTypeAcademic,
+	"academia":           FunderTypeAcademic,
+	"university":         FunderTypeAcademic,
+	"research institute": FunderTypeAcademic,
+	"universidad":        FunderTypeAcademic,
+	"academico":          FunderTypeAcademic,
+
+	"nonprofit":                       FunderTypeNonprofit,
+	"non-profit":                      FunderTypeNonprofit,
+	"non profit":                      FunderTypeNonprofit,
+	"not-for-profit":                  FunderTypeNonprofit,
+	"ngo":                             FunderTypeNonprofit,
+	"charity":                         FunderTypeNonprofit,
+	"civil society":                   FunderTypeNonprofit,
+	"ong":                             FunderTypeNonprofit,
+	"sin fines de lucro":              FunderTypeNonprofit,
+	"organizacion sin fines de lucro": FunderTypeNonprofit,
+	"sociedad civil":                  FunderTypeNonprofit,
+}
+
+// funderTypeKeywords catch longer labels ("Ministerio de Cultura", "Bill &
+// Melinda Gates Foundation") by word prefix. The label's first word that
+// matches a stem decides.
+var funderTypeKeywords = []struct {
+	stem      string
+	canonical string
+}{
+	{"multilateral", FunderTypeMultilateral},
+	{"intergovernment", FunderTypeMultilateral},
+	{"universit", FunderTypeAcademic},
+	{"universidad", FunderTypeAcademic},
+	{"academ", FunderTypeAcademic},
+	{"nonprofit", FunderTypeNonprofit},
+	{"non-profit", FunderTypeNonprofit},
+	{"ngo", FunderTypeNonprofit},
+	{"charit", FunderTypeNonprofit},
+	{"foundation", FunderTypeFoundation},
+	{"fundacion", FunderTypeFoundation},
+	{"philanthrop", FunderTypeFoundation},
+	{"govern", FunderTypeGovernment},
+	{"gobierno", FunderTypeGovernment},
+	{"minist", FunderTypeGovernment},
+	{"federal", FunderTypeGovernment},
+	{"corporat", FunderTypeCorporate},
+	{"company", FunderTypeCorporate},
+	{"empresa", FunderTypeCorporate},
+}
+
+// normalizeFunderType maps a source's funder label onto the canonical set.
+// Unrecognized labels return "" so they don't add a stray facet value; the
+// caller keeps the original text as the display label.
+func normalizeFunderType(s string) string {
+	key := foldLabel(s)
+	if key == "" {
+		return ""
+	}
+	if canonical, ok := funderTypeSynonyms[key]; ok {
+		return canonical
+	}
+	for _, word := range strings.FieldsFunc(key, isLabelSeparator) {
+		for _, kw := range funderTypeKeywords {
+			if strings.HasPrefix(word, kw.stem) {
+				return kw.canonical
+			}
+		}
+	}
+	return ""
+}
+
+// accentFolder strips the Spanish and Portuguese diacritics that show up in
+// source labels, so "Fundación" and "Fundacion" compare equal.
+var accentFolder = strings.NewReplacer(
+	"á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n",
+	"à", "a", "â", "a", "ã", "a", "ê", "e", "ô", "o", "õ", "o", "ç", "c",
+)
+
+// foldLabel lower-cases, accent-folds and collapses whitespace in a label
+// for taxonomy lookups.
+func foldLabel(s string) string {
+	return accentFolder.Replace(strings.ToLower(cleanText(s)))
+}
+
+func isLabelSeparator(r rune) bool {
+	switch r {
+	case ' ', ',', ';', '/', '(', ')', '&', '.', ':':
+		return true
+	}
+	return false
+}
+
+// Canonical regions stored in opportunities.region. These are the values the
+// region facet shows, so they stay display-cased.
+const (
+	RegionNorthAmerica = "North America"
+	RegionLatinAmerica = "Latin America"
+	RegionEurope       = "Europe"
+	RegionAfrica       = "Africa"
+	RegionMiddleEast   = "Middle East"
+	RegionAsia         = "Asia"
+	RegionOceania      = "Oceania"
+	RegionGlobal       = "Global"
+)
+
+// regionSynonyms maps whole accent-folded, lower-cased labels to a canonical
+// region.
+var regionSynonyms = map[string]string{
+	"north america":     RegionNorthAmerica,
+	"northern america":  RegionNorthAmerica,
+	"norteamerica":      RegionNorthAmerica,
+	"america del norte": RegionNorthAmerica,
+	"us":                RegionNorthAmerica,
+	"usa":               RegionNorthAmerica,
+
+	"latin america":                   RegionLatinAmerica,
+	"latin america and the caribbean": RegionLatinAmerica,
+	"latin america & caribbean":       RegionLatinAmerica,
+	"latin america & the caribbean":   RegionLatinAmerica,
+	"latam":                           RegionLatinAmerica,
+	"lac":                             RegionLatinAmerica,
+	"south america":                   RegionLatinAmerica,
+	"central america":                 RegionLatinAmerica,
+	"caribbean":                       RegionLatinAmerica,
+	"the caribbean":                   RegionLatinAmerica,
+	"america latina":                  RegionLatinAmerica,
+	"latinoamerica":                   RegionLatinAmerica,
+	"america latina y el caribe":      RegionLatinAmerica,
+	"sudamerica":                      RegionLatinAmerica,
+	"suramerica":                      RegionLatinAmerica,
+	"america del sur":                 RegionLatinAmerica,
+	"centroamerica":                   RegionLatinAmerica,
+	"america central":                 RegionLatinAmerica,
+	"caribe":                          RegionLatinAmerica,
+	"iberoamerica":                    RegionLatinAmerica,
+
+	"europe":         RegionEurope,
+	"eu":             RegionEurope,
+	"european union": RegionEurope,
+	"emea":           RegionEurope,
+	"europa":         RegionEurope,
+	"union europea":  RegionEurope,
+
+	"africa":              RegionAfrica,
+	"sub-saharan africa":  RegionAfrica,
+	"subsaharan africa":   RegionAfrica,
+	"africa subsahariana": RegionAfrica,
+
+	"middle east":                  RegionMiddleEast,
+	"middle east and north africa": RegionMiddleEast,
+	"mena":                         RegionMiddleEast,
+	"medio oriente":                RegionMiddleEast,
+	"oriente medio":                RegionMiddleEast,
+
+	"asia":           RegionAsia,
+	"asia pacific":   RegionAsia,
+	"asia-pacific":   RegionAsia,
+	"apac":           RegionAsia,
+	"south asia":     RegionAsia,
+	"east asia":      RegionAsia,
+	"southeast asia": RegionAsia,
+
+	"oceania":                   RegionOceania,
+	"australia and new zealand": RegionOceania,
+	"pacific":                   RegionOceania,
+	"pacific islands":           RegionOceania,
+
+	"global":        RegionGlobal,
+	"worldwide":     RegionGlobal,
+	"international": RegionGlobal,
+	"world":         RegionGlobal,
+	"all regions":   RegionGlobal,
+	"mundial":       RegionGlobal,
+	"internacional": RegionGlobal,
+	"todo el mundo": RegionGlobal,
+}
+
+// countryRegions derives a region for rows that only name a country. Keys
+// are accent-folded and lower-cased; English and Spanish names and the common
+// codes are listed.
+var countryRegions = map[string]string{
+	"united states": RegionNorthAmerica, "united states of america": RegionNorthAmerica,
+	"usa": RegionNorthAmerica, "us": RegionNorthAmerica, "estados unidos": RegionNorthAmerica,
+	"canada": RegionNorthAmerica,
+
+	"mexico": RegionLatinAmerica, "guatemala": RegionLatinAmerica, "honduras": RegionLatinAmerica,
+	"el salvador": RegionLatinAmerica, "nicaragua": RegionLatinAmerica, "costa rica": RegionLatinAmerica,
+	"panama": RegionLatinAmerica, "belize": RegionLatinAmerica, "cuba": RegionLatinAmerica,
+	"dominican republic": RegionLatinAmerica, "republica dominicana": RegionLatinAmerica,
+	"haiti": RegionLatinAmerica, "jamaica": RegionLatinAmerica, "puerto rico": RegionLatinAmerica,
+	"trinidad and tobago": RegionLatinAmerica, "colombia": RegionLatinAmerica,
+	"venezuela": RegionLatinAmerica, "ecuador": RegionLatinAmerica, "peru": RegionLatinAmerica,
+	"bolivia": RegionLatinAmerica, "chile": RegionLatinAmerica, "argentina": RegionLatinAmerica,
+	"uruguay": RegionLatinAmerica, "paraguay": RegionLatinAmerica, "brazil": RegionLatinAmerica,
+	"brasil": RegionLatinAmerica, "guyana": RegionLatinAmerica, "suriname": RegionLatinAmerica,
+
+	"european union": RegionEurope, "union europea": RegionEurope, "eu": RegionEurope,
+	"united kingdom": RegionEurope, "uk": RegionEurope, "reino unido": RegionEurope,
+	"ireland": RegionEurope, "irlanda": RegionEurope, "france": RegionEurope, "francia": RegionEurope,
+	"germany": RegionEurope, "alemania": RegionEurope, "spain": RegionEurope, "espana": RegionEurope,
+	"portugal": RegionEurope, "italy": RegionEurope, "italia": RegionEurope,
+	"netherlands": RegionEurope, "paises bajos": RegionEurope, "belgium": RegionEurope,
+	"belgica": RegionEurope, "switzerland": RegionEurope, "suiza": RegionEurope,
+	"austria": RegionEurope, "sweden": RegionEurope, "suecia": RegionEurope, "norway": RegionEurope,
+	"noruega": RegionEurope, "denmark": RegionEurope, "dinamarca": RegionEurope,
+	"finland": RegionEurope, "finlandia": RegionEurope, "poland": RegionEurope, "polonia": RegionEurope,
+
+	"south africa": RegionAfrica, "sudafrica": RegionAfrica, "nigeria": RegionAfrica,
+	"kenya": RegionAfrica, "ghana": RegionAfrica, "ethiopia": RegionAfrica, "uganda": RegionAfrica,
+	"tanzania": RegionAfrica, "rwanda": RegionAfrica, "senegal": RegionAfrica, "morocco": RegionAfrica,
+	"marruecos": RegionAfrica, "egypt": RegionAfrica, "egipto": RegionAfrica,
+
+	"israel": RegionMiddleEast, "jordan": RegionMiddleEast, "lebanon": RegionMiddleEast,
+	"saudi arabia": RegionMiddleEast, "united arab emirates": RegionMiddleEast, "uae": RegionMiddleEast,
+	"qatar": RegionMiddleEast, "turkey": RegionMiddleEast, "turquia": RegionMiddleEast,
+
+	"china": RegionAsia, "japan": RegionAsia, "japon": RegionAsia, "india": RegionAsia,
+	"south korea": RegionAsia, "korea": RegionAsia, "corea del sur": RegionAsia,
+	"singapore": RegionAsia, "singapur": RegionAsia, "indonesia": RegionAsia,
+	"philippines": RegionAsia, "filipinas": RegionAsia, "vietnam": RegionAsia,
+	"thailand": RegionAsia, "tailandia": RegionAsia, "malaysia": RegionAsia, "pakistan": RegionAsia,
+	"bangladesh": RegionAsia,
+
+	"australia": RegionOceania, "new zealand": RegionOceania, "nueva zelanda": RegionOceania,
+	"fiji": RegionOceania,
+
+	"global": RegionGlobal, "worldwide": RegionGlobal, "international": RegionGlobal,
+	"multiple": RegionGlobal, "mundial": RegionGlobal, "internacional": RegionGlobal,
+}
+
+// normalizeRegion maps a source's region label onto the canonical set. A
+// label that is really a country ("Chile") maps to that country's region.
+// Unrecognized labels return "" so the caller can fall back to the country.
+func normalizeRegion(s string) string {
+	key := foldLabel(s)
+	if key == "" {
+		return ""
+	}
+	if canonical, ok := regionSynonyms[key]; ok {
+		return canonical
+	}
+	return regionForCountry(s)
+}
+
+// regionForCountry derives the region of a country name or code, or "" when
+// the country isn't in the table.
+func regionForCountry(country string) string {
+	return countryRegions[foldLabel(country)]
+}
@@ -0,0 +1,127 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// TaxonomyBackfillStats reports a rewrite of one free-text column onto its
+// canonical set. Unmapped lists the distinct stored values no rule matched.
+// Where the column has a label column those rows are cleared and the value is
+// kept as the label; otherwise they are left as they are.
+type TaxonomyBackfillStats struct {
+	DistinctValues int      `json:"distinct_values"`
+	RowsUpdated    int      `json:"rows_updated"`
+	Unmapped       []string `json:"unmapped,omitempty"`
+	// DerivedFromCountry counts rows given a region from their country.
+	DerivedFromCountry int `json:"derived_from_country,omitempty"`
+}
+
+// BackfillFunderTypes rewrites stored funder_type values onto the canonical
+// taxonomy, moving the original wording into funder_type_label.
+func (p *Pipeline) BackfillFunderTypes(ctx context.Context) (TaxonomyBackfillStats, error) {
+	return p.backfillCanonicalColumn(ctx, "funder_type", "funder_type_label", normalizeFunderType)
+}
+
+// BackfillRegions rewrites stored regions onto the canonical set, then fills
+// in a region for rows that only have a country. Regions no rule maps are
+// kept: region has no label column to move them to.
+func (p *Pipeline) BackfillRegions(ctx context.Context) (TaxonomyBackfillStats, error) {
+	stats, err := p.backfillCanonicalColumn(ctx, "region", "", normalizeRegion)
+	if err != nil {
+		return stats, err
+	}
+
+	rows, err := p.DB.Query(ctx, `
+		SELECT DISTINCT country FROM opportunities
+		WHERE (region IS NULL OR region = '') AND country IS NOT NULL AND country <> ''`)
+	if err != nil {
+		return stats, fmt.Errorf("backfill region: list countries: %w", err)
+	}
+	var countries []string
+	for rows.Next() {
+		var country string
+		if err := rows.Scan(&country); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("backfill region: scan: %w", err)
+		}
+		countries = append(countries, country)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("backfill region: list countries: %w", err)
+	}
+
+	for _, country := range countries {
+		region := regionForCountry(country)
+		if region == "" {
+			continue
+		}
+		tag, err := p.DB.Exec(ctx, `
+			UPDATE opportunities SET region = $2
+			WHERE (region IS NULL OR region = '') AND country = $1`, country, region)
+		if err != nil {
+			return stats, fmt.Errorf("backfill region: derive from %q: %w", country, err)
+		}
+		stats.DerivedFromCountry += int(tag.RowsAffected())
+	}
+	return stats, nil
+}
+
+// backfillCanonicalColumn maps each distinct value of column through
+// normalize and rewrites rows whose stored value differs. Distinct values are
+// few, so this is one UPDATE per variant rather than a pass over every row.
+// column and labelColumn are fixed identifiers from this package, never input.
+func (p *Pipeline) backfillCanonicalColumn(ctx context.Context, column, labelColumn string, normalize func(string) string) (TaxonomyBackfillStats, error) {
+	var stats TaxonomyBackfillStats
+
+	rows, err := p.DB.Query(ctx, fmt.Sprintf(
+		"SELECT DISTINCT %[1]s FROM opportunities WHERE %[1]s IS NOT NULL AND %[1]s <> ''", column))
+	if err != nil {
+		return stats, fmt.Errorf("backfill %s: list values: %w", column, err)
+	}
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			rows.Close()
+			return stats, fmt.Errorf("backfill %s: scan: %w", column, err)
+		}
+		values = append(values, v)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return stats, fmt.Errorf("backfill %s: list values: %w", column, err)
+	}
+	stats.DistinctValues = len(values)
+
+	update := fmt.Sprintf("UPDATE opportunities SET %[1]s = NULLIF($2, '') WHERE %[1]s = $1", column)
+	if labelColumn != "" {
+		update = fmt.Sprintf("UPDATE opportunities SET %[1]s = NULLIF($2, ''), %[2]s = COALESCE(%[2]s, $3) WHERE %[1]s = $1", column, labelColumn)
+	}
+
+	for _, raw := range values {
+		canonical := normalize(raw)
+		if canonical == raw {
+			continue
+		}
+		if canonical == "" {
+			stats.Unmapped = append(stats.Unmapped, raw)
+			if labelColumn == "" {
+				continue
+			}
+		}
+		args := []interface{}{raw, canonical}
+		if labelColumn != "" {
+			args = append(args, cleanText(raw))
+		}
+		tag, err := p.DB.Exec(ctx, update, args...)
+		if err != nil {
+			return stats, fmt.Errorf("backfill %s: rewrite %q: %w", column, raw, err)
+		}
+		stats.RowsUpdated += int(tag.RowsAffected())
+	}
+	sort.Strings(stats.Unmapped)
+	return stats, nil
+}
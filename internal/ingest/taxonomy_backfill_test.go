@@ -0,0 +1,63 @@
+package ingest
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TestBackfillRegions_KeepsUnmappedRegions runs against a migrated database;
+// set TEST_DATABASE_URL to enable it.
+func TestBackfillRegions_KeepsUnmappedRegions(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+
+	const domain = "region-backfill-test.example.org"
+	cleanup := func() {
+		if _, err := pool.Exec(ctx, `DELETE FROM opportunities WHERE source_domain = $1`, domain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cleanup()
+	defer cleanup()
+
+	for id, region := range map[string]string{"latam": "LATAM", "country": "Chile", "unmapped": "Lima Metropolitana"} {
+		if _, err := pool.Exec(ctx, `
+			INSERT INTO opportunities (title, external_url, source_domain, source_id, region)
+			VALUES ($1, $2, $3, $1, $4)`, id, "https://"+domain+"/"+id, domain, region); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := NewPipeline(pool, nil, nil, nil).BackfillRegions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"latam": RegionLatinAmerica, "country": RegionLatinAmerica, "unmapped": "Lima Metropolitana"}
+	for id, region := range want {
+		var got *string
+		if err := pool.QueryRow(ctx, `SELECT region FROM opportunities WHERE source_domain = $1 AND source_id = $2`, domain, id).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got == nil || *got != region {
+			t.Errorf("%s: region = %v, want %q", id, got, region)
+		}
+	}
+	var reported bool
+	for _, v := range stats.Unmapped {
+		reported = reported || v == "Lima Metropolitana"
+	}
+	if !reported {
+		t.Errorf("expected the unmapped region reported, got %v", stats.Unmapped)
+	}
+}
@@ -0,0 +1,122 @@
+package ingest
+
+import "testing"
+
+func TestNormalizeFunderType(t *testing.T) {
+	cases := map[string]string{
+		"Government":                       FunderTypeGovernment,
+		"Gov":                              FunderTypeGovernment,
+		"  gobierno ":                      FunderTypeGovernment,
+		"Sector Público":                   FunderTypeGovernment,
+		"Ministerio de Cultura":            FunderTypeGovernment,
+		"Federal Agency":                   FunderTypeGovernment,
+		"Foundation":                       FunderTypeFoundation,
+		"foundation":                       FunderTypeFoundation,
+		"Fundación":                        FunderTypeFoundation,
+		"Bill & Melinda Gates Foundation":  FunderTypeFoundation,
+		"Private Sector":                   FunderTypeCorporate,
+		"Empresa":                          FunderTypeCorporate,
+		"Corporate":                        FunderTypeCorporate,
+		"Multilateral":                     FunderTypeMultilateral,
+		"Organismo Internacional":          FunderTypeMultilateral,
+		"Development Bank":                 FunderTypeMultilateral,
+		"University":                       FunderTypeAcademic,
+		"Universidad Nacional de Colombia": FunderTypeAcademic,
+		"NGO":                              FunderTypeNonprofit,
+		"ONG":                              FunderTypeNonprofit,
+		"Sin fines de lucro":               FunderTypeNonprofit,
+		"Non-Profit":                       FunderTypeNonprofit,
+		"":                                 "",
+		"Other":                            "",
+	}
+	for in, want := range cases {
+		if got := normalizeFunderType(in); got != want {
+			t.Errorf("normalizeFunderType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNormalizeOpportunity_KeepsFunderTypeLabel(t *testing.T) {
+	opp := Opportunity{FunderType: "Gobierno"}
+	NormalizeOpportunity(&opp)
+	if opp.FunderType != FunderTypeGovernment || opp.FunderTypeLabel != "Gobierno" {
+		t.Fatalf("got type %q label %q, want government / Gobierno", opp.FunderType, opp.FunderTypeLabel)
+	}
+
+	// A second pass (FromRaw then SaveOpportunity) must not overwrite the label.
+	NormalizeOpportunity(&opp)
+	if opp.FunderType != FunderTypeGovernment || opp.FunderTypeLabel != "Gobierno" {
+		t.Fatalf("second pass changed type %q label %q", opp.FunderType, opp.FunderTypeLabel)
+	}
+}
+
+func TestNormalizeRegion(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"North America", RegionNorthAmerica},
+		{"north america", RegionNorthAmerica},
+		{"Norteamérica", RegionNorthAmerica},
+		{"LATAM", RegionLatinAmerica},
+		{"Latin America and the Caribbean", RegionLatinAmerica},
+		{"Latin America & Caribbean", RegionLatinAmerica},
+		{"South America", RegionLatinAmerica},
+		{"América Latina y el Caribe", RegionLatinAmerica},
+		{"Sudamérica", RegionLatinAmerica},
+		{"Centroamérica", RegionLatinAmerica},
+		{"Europe", RegionEurope},
+		{"Europa", RegionEurope},
+		{"EU", RegionEurope},
+		{"Sub-Saharan Africa", RegionAfrica},
+		{"MENA", RegionMiddleEast},
+		{"Asia-Pacific", RegionAsia},
+		{"Oceania", RegionOceania},
+		{"Worldwide", RegionGlobal},
+		{"Internacional", RegionGlobal},
+		{"Chile", RegionLatinAmerica},
+		{"Reino Unido", RegionEurope},
+		{"", ""},
+		{"Lima Metropolitana", ""},
+	}
+	for _, tc := range cases {
+		if got := normalizeRegion(tc.in); got != tc.want {
+			t.Errorf("normalizeRegion(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRegionForCountry(t *testing.T) {
+	cases := []struct {
+		in, want string
+	}{
+		{"Peru", RegionLatinAmerica},
+		{"Perú", RegionLatinAmerica},
+		{"USA", RegionNorthAmerica},
+		{"Estados Unidos", RegionNorthAmerica},
+		{"European Union", RegionEurope},
+		{"España", RegionEurope},
+		{"Kenya", RegionAfrica},
+		{"Japón", RegionAsia},
+		{"Atlantis", ""},
+	}
+	for _, tc := range cases {
+		if got := regionForCountry(tc.in); got != tc.want {
+			t.Errorf("regionForCountry(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestNormalizeOpportunity_DerivesRegionFromCountry(t *testing.T) {
+	opp := Opportunity{Country: "Perú"}
+	NormalizeOpportunity(&opp)
+	if opp.Region != RegionLatinAmerica {
+		t.Fatalf("Region = %q, want %q", opp.Region, RegionLatinAmerica)
+	}
+
+	// An explicit region wins over the country.
+	opp = Opportunity{Region: "Global", Country: "Peru"}
+	NormalizeOpportunity(&opp)
+	if opp.Region != RegionGlobal {
+		t.Fatalf("Region = %q, want %q", opp.Region, RegionGlobal)
+	}
+}
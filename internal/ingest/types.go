@@ -3,6 +3,7 @@ package ingest
 import (
 	"context"
 	"io"
+	"net/http"
 	"time"
 )
 
@@ -26,7 +27,8 @@ type Opportunity struct {
 	OpportunityNumber string // Opportunity number (e.g., "RFA-NS-27-001")
 	AgencyName        string // Full agency name
 	AgencyCode        string // Agency code (e.g., "HHS-NIH")
-	FunderType        string // Government, Foundation, Corporate, Multilateral
+	FunderType        string // Canonical: government, foundation, corporate, multilateral, academic, nonprofit
+	FunderTypeLabel   string // Source's own funder label, kept for display
 	DeadlineStr       string // Raw string, needs parsing
 	DeadlineAt        *time.Time
 	OpenDate          *time.Time
@@ -65,6 +67,10 @@ type Opportunity struct {
 	RawURL           string
 	ContentType      string
 	DataQualityScore map[string]interface{}
+	FollowURLs       []string // One-hop links (detail.follow_links) merged into evidence
+	DetailPage       *SourceAdapterRaw // Detail page already fetched by the crawl, reused for evidence
+	LastEnrichedAt   *time.Time        // Set once evidence enrichment has run
+	ContentHash      string            // Fingerprint of the extracted text, see contentHash
 }
 
 // RawOpportunity represents the untrusted, unnormalized data extracted from a source.
@@ -87,6 +93,9 @@ type RawOpportunity struct {
 	RollingEvidence bool
 	DeadlineEvidence []DeadlineEvidence
 	SourceEvidenceJSON map[string]interface{}
+	FollowURLs   []string // Extra pages to fetch one hop deep for evidence
+	DetailPage   *SourceAdapterRaw `json:"-"` // Detail page as fetched by the crawl, so enrichment need not refetch it
+	Eligibility  []string // Discrete eligibility entries (e.g. one per <li>)
 	Extra        map[string]string
 }
 
@@ -102,7 +111,26 @@ type FetchedDocument struct {
 
 // Fetcher retrieves raw content from a URL.
 type Fetcher interface {
-	Fetch(ctx context.Context, url string) (*FetchedDocument, error)
+	Fetch(ctx context.Context, url string, opts ...FetchOption) (*FetchedDocument, error)
+}
+
+// PostFetcher is a Fetcher that can also send a request body, for search
+// APIs that only answer POST.
+type PostFetcher interface {
+	Fetcher
+	Post(ctx context.Context, url, contentType string, body []byte, opts ...FetchOption) (*FetchedDocument, error)
+}
+
+// FetchOption adjusts the headers of a single Fetch or Post request. It is
+// applied after the domain's configured headers, so it wins over them.
+type FetchOption func(header http.Header)
+
+// WithHeader sets name to value on one request, e.g. a JSON Accept for an
+// API call on a host whose other pages are HTML.
+func WithHeader(name, value string) FetchOption {
+	return func(header http.Header) {
+		header.Set(name, value)
+	}
 }
 
 // Parser extracts structured opportunities from raw content.
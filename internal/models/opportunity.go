@@ -17,6 +17,7 @@ type Opportunity struct {
 	AgencyName        string                 `json:"agency_name"`
 	AgencyCode        string                 `json:"agency_code"`
 	FunderType        string                 `json:"funder_type"`
+	FunderTypeLabel   string                 `json:"funder_type_label,omitempty"`
 	AmountMin         float64                `json:"amount_min"`
 	AmountMax         float64                `json:"amount_max"`
 	Currency          string                 `json:"currency"`
@@ -27,6 +28,7 @@ type Opportunity struct {
 	CloseAt           *time.Time             `json:"close_at"`
 	ExpirationAt      *time.Time             `json:"expiration_at"`
 	IsRolling         bool                   `json:"is_rolling"`
+	Type              string                 `json:"type"`
 	DocType           string                 `json:"doc_type"`
 	CfdaList          []string               `json:"cfda_list"`
 	OppStatus         string                 `json:"opp_status"`
@@ -46,9 +48,20 @@ type Opportunity struct {
 	CloseDateRaw      string                 `json:"close_date_raw"` // Original text for deadline
 	CreatedAt         time.Time              `json:"created_at"`
 	UpdatedAt         time.Time              `json:"updated_at"`
+	LastEnrichedAt    *time.Time             `json:"last_enriched_at"`
+	Freshness         string                 `json:"freshness"` // "fresh", "recent" or "stale" relative to the source's TTL
 	SourceRunID       *string                `json:"source_run_id"`
 	CanonicalURL      string                 `json:"canonical_url"`
 	RawURL            string                 `json:"raw_url"`
 	ContentType       string                 `json:"content_type"`
 	DataQualityScore  map[string]interface{} `json:"data_quality_score"`
+	Explain           *RankExplanation       `json:"explain,omitempty"` // Only with ?explain=true
+}
+
+// RankExplanation says why a search result matched and where it ranked:
+// the scores the relevance ordering used and the filters the row passed.
+type RankExplanation struct {
+	VectorSimilarity *float64 `json:"vector_similarity"` // 1 - cosine distance to the query embedding; null without one
+	TextRank         *float64 `json:"text_rank"`         // ts_rank against the keyword query; null without one
+	MatchedFilters   []string `json:"matched_filters"`
 }
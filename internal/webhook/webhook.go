@@ -0,0 +1,311 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/david/grant-finder/internal/ingest"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// SignatureHeader carries "sha256=<hex HMAC of the body>" keyed by the
+	// subscription secret.
+	SignatureHeader = "X-Webhook-Signature"
+	EventHeader     = "X-Webhook-Event"
+
+	defaultQueueSize   = 256
+	defaultMaxAttempts = 4
+	defaultBackoff     = 2 * time.Second
+)
+
+var ErrNotFound = errors.New("webhook subscription not found")
+
+type Subscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	// Secret is only returned when the subscription is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+type Delivery struct {
+	ID            int64     `json:"id"`
+	EventType     string    `json:"event_type"`
+	OpportunityID *string   `json:"opportunity_id,omitempty"`
+	Status        string    `json:"status"`
+	Attempts      int       `json:"attempts"`
+	ResponseCode  *int      `json:"response_code,omitempty"`
+	Error         *string   `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Dispatcher queues opportunity events and POSTs them to matching
+// subscriptions from a single background worker, so ingestion never waits
+// on a subscriber. It implements ingest.OpportunityNotifier.
+type Dispatcher struct {
+	db          *pgxpool.Pool
+	client      *http.Client
+	queue       chan ingest.OpportunityEvent
+	maxAttempts int
+	backoff     time.Duration
+}
+
+func NewDispatcher(pool *pgxpool.Pool, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		db:          pool,
+		client:      client,
+		queue:       make(chan ingest.OpportunityEvent, defaultQueueSize),
+		maxAttempts: defaultMaxAttempts,
+		backoff:     defaultBackoff,
+	}
+}
+
+// Start runs the delivery worker until ctx is done.
+func (d *Dispatcher) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-d.queue:
+				d.dispatch(ctx, event)
+			}
+		}
+	}()
+}
+
+// Notify enqueues an event. When the queue is full the event is dropped
+// rather than blocking the caller.
+func (d *Dispatcher) Notify(event ingest.OpportunityEvent) {
+	select {
+	case d.queue <- event:
+	default:
+		log.Printf("[webhook] queue full, dropping %s for %s", event.Type, event.OpportunityID)
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context, event ingest.OpportunityEvent) {
+	subs, err := d.subscriptionsFor(ctx, event.Type)
+	if err != nil {
+		log.Printf("[webhook] loading subscriptions failed: %v", err)
+		return
+	}
+	if len(subs) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[webhook] marshal %s failed: %v", event.Type, err)
+		return
+	}
+
+	for _, sub := range subs {
+		attempts, code, deliverErr := d.deliver(ctx, sub.URL, sub.Secret, event.Type, body)
+		d.recordDelivery(ctx, sub.ID, event, attempts, code, deliverErr)
+	}
+}
+
+// deliver POSTs body with retries and exponential backoff. It returns the
+// number of attempts and the last HTTP status seen.
+func (d *Dispatcher) deliver(ctx context.Context, url, secret, eventType string, body []byte) (int, int, error) {
+	var lastErr error
+	lastCode := 0
+	wait := d.backoff
+
+	for attempt := 1; attempt <= d.maxAttempts; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+		if err != nil {
+			return attempt, 0, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(EventHeader, eventType)
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+
+		resp, err := d.client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			lastCode = resp.StatusCode
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return attempt, lastCode, nil
+			}
+			lastErr = fmt.Errorf("subscriber returned %d", resp.StatusCode)
+			// 4xx other than 429 will not get better on retry.
+			if resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+				return attempt, lastCode, lastErr
+			}
+		} else {
+			lastErr = err
+		}
+
+		if attempt == d.maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return attempt, lastCode, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	return d.maxAttempts, lastCode, lastErr
+}
+
+// Sign returns the signature header value for body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *Dispatcher) recordDelivery(ctx context.Context, subID string, event ingest.OpportunityEvent, attempts, code int, deliverErr error) {
+	status := "delivered"
+	var errText, respCode interface{}
+	if deliverErr != nil {
+		status = "failed"
+		errText = deliverErr.Error()
+		log.Printf("[webhook] %s to subscription %s failed after %d attempts: %v", event.Type, subID, attempts, deliverErr)
+	}
+	if code > 0 {
+		respCode = code
+	}
+	var oppID interface{}
+	if event.OpportunityID != "" {
+		oppID = event.OpportunityID
+	}
+
+	_, err := d.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, opportunity_id, status, attempts, response_code, error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, subID, event.Type, oppID, status, attempts, respCode, errText)
+	if err != nil {
+		log.Printf("[webhook] recording delivery failed: %v", err)
+	}
+}
+
+type target struct {
+	ID     string
+	URL    string
+	Secret string
+}
+
+func (d *Dispatcher) subscriptionsFor(ctx context.Context, eventType string) ([]target, error) {
+	rows, err := d.db.Query(ctx, `
+		SELECT id::text, url, secret
+		FROM webhook_subscriptions
+		WHERE active AND (cardinality(events) = 0 OR $1 = ANY(events))
+	`, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []target
+	for rows.Next() {
+		var t target
+		if err := rows.Scan(&t.ID, &t.URL, &t.Secret); err != nil {
+			return nil, err
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// Subscriptions
+
+// CreateSubscription stores a subscription, generating a secret when none
+// is given. The returned Subscription carries the secret.
+func (d *Dispatcher) CreateSubscription(ctx context.Context, url, secret string, events []string) (*Subscription, error) {
+	if secret == "" {
+		buf := make([]byte, 24)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("generate secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+	if events == nil {
+		events = []string{}
+	}
+
+	sub := &Subscription{URL: url, Events: events, Active: true, Secret: secret}
+	err := d.db.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (url, secret, events)
+		VALUES ($1, $2, $3)
+		RETURNING id::text, created_at
+	`, url, secret, events).Scan(&sub.ID, &sub.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (d *Dispatcher) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	rows, err := d.db.Query(ctx, `
+		SELECT id::text, url, events, active, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at DESC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []Subscription{}
+	for rows.Next() {
+		var sub Subscription
+		if err := rows.Scan(&sub.ID, &sub.URL, &sub.Events, &sub.Active, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+func (d *Dispatcher) DeleteSubscription(ctx context.Context, id string) error {
+	tag, err := d.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id::text = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the most recent delivery attempts for a subscription.
+func (d *Dispatcher) ListDeliveries(ctx context.Context, subID string, limit int) ([]Delivery, error) {
+	rows, err := d.db.Query(ctx, `
+		SELECT id, event_type, opportunity_id::text, status, attempts, response_code, error, created_at
+		FROM webhook_deliveries
+		WHERE subscription_id::text = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`, subID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var dl Delivery
+		if err := rows.Scan(&dl.ID, &dl.EventType, &dl.OpportunityID, &dl.Status, &dl.Attempts, &dl.ResponseCode, &dl.Error, &dl.CreatedAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, dl)
+	}
+	return deliveries, rows.Err()
+}
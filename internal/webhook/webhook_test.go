@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/david/grant-finder/internal/ingest"
+)
+
+func testDispatcher() *Dispatcher {
+	d := NewDispatcher(nil, http.DefaultClient)
+	d.backoff = time.Millisecond
+	return d
+}
+
+func TestDeliver_SignsBodyAndRetries(t *testing.T) {
+	var calls int32
+	body := []byte(`{"type":"opportunity.created"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		if sig := r.Header.Get(SignatureHeader); sig != Sign("s3cret", got) {
+			t.Errorf("bad signature %q", sig)
+		}
+		if r.Header.Get(EventHeader) != "opportunity.created" {
+			t.Errorf("missing event header")
+		}
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	attempts, code, err := testDispatcher().deliver(context.Background(), server.URL, "s3cret", "opportunity.created", body)
+	if err != nil || attempts != 3 || code != http.StatusOK {
+		t.Fatalf("expected success on 3rd attempt, got attempts=%d code=%d err=%v", attempts, code, err)
+	}
+}
+
+func TestDeliver_ClientErrorIsNotRetried(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	attempts, code, err := testDispatcher().deliver(context.Background(), server.URL, "s", "opportunity.created", []byte(`{}`))
+	if err == nil || attempts != 1 || code != http.StatusGone || atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("expected a single failed attempt, got attempts=%d code=%d err=%v", attempts, code, err)
+	}
+}
+
+func TestNotify_DropsWhenQueueFull(t *testing.T) {
+	d := testDispatcher()
+	d.queue = make(chan ingest.OpportunityEvent, 1)
+	d.Notify(ingest.OpportunityEvent{Type: "a"})
+	d.Notify(ingest.OpportunityEvent{Type: "b"}) // must not block
+	if len(d.queue) != 1 {
+		t.Fatalf("expected queue to hold 1 event, got %d", len(d.queue))
+	}
+}